@@ -0,0 +1,219 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package apidiff extracts the exported API of a Go module and compares two
+// snapshots of it.
+package apidiff
+
+import (
+	"context"
+	"fmt"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Symbol kinds.
+const (
+	KindFunc            = "func"
+	KindMethod          = "method"
+	KindType            = "type"
+	KindField           = "field"
+	KindInterfaceMethod = "interface method"
+	KindVar             = "var"
+	KindConst           = "const"
+)
+
+// Symbol is one exported identifier of a package.
+type Symbol struct {
+	Package   string `json:"package"`
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Signature string `json:"signature"`
+	// Dir is the package directory relative to the module root.
+	Dir string `json:"-"`
+}
+
+// Key identifies the symbol across snapshots.
+func (s Symbol) Key() string {
+	return s.Package + "." + s.Name
+}
+
+// API is a snapshot of exported symbols, keyed by Symbol.Key.
+type API map[string]Symbol
+
+// Change kinds.
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change is a difference between two API snapshots.
+type Change struct {
+	Kind     string `json:"kind"`
+	Symbol   Symbol `json:"symbol"`
+	Old      string `json:"old,omitempty"`
+	Breaking bool   `json:"breaking"`
+}
+
+// Snapshot loads every non-internal, non-main package of the module rooted
+// at dir and returns its exported API. Packages that fail to type-check are
+// included as far as their types are known; their errors are returned as
+// warnings.
+func Snapshot(ctx context.Context, dir string) (API, []string, error) {
+	// Everything is type-checked from source rather than from compiler export
+	// data, so that the result does not depend on the installed toolchain.
+	cfg := &packages.Config{
+		Context: ctx,
+		Dir:     dir,
+		Mode:    packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedImports | packages.NeedDeps,
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	api := make(API)
+	var warnings []string
+	for _, pkg := range pkgs {
+		for _, e := range pkg.Errors {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", pkg.PkgPath, e.Msg))
+		}
+		if pkg.Types == nil || pkg.Name == "main" || isInternal(pkg.PkgPath) {
+			continue
+		}
+		rel := ""
+		if len(pkg.GoFiles) > 0 {
+			if r, err := filepath.Rel(dir, filepath.Dir(pkg.GoFiles[0])); err == nil {
+				rel = filepath.ToSlash(r)
+			}
+		}
+		for _, s := range packageSymbols(pkg.Types) {
+			s.Dir = rel
+			api[s.Key()] = s
+		}
+	}
+	return api, warnings, nil
+}
+
+// Diff compares two snapshots. Changes are ordered by package and name.
+func Diff(old, new API) []Change {
+	var changes []Change
+	for key, s := range old {
+		n, ok := new[key]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Kind: Removed, Symbol: s, Breaking: true})
+		case n.Signature != s.Signature:
+			changes = append(changes, Change{Kind: Changed, Symbol: n, Old: s.Signature, Breaking: true})
+		}
+	}
+	for key, s := range new {
+		if _, ok := old[key]; !ok {
+			// New interface methods break implementations outside the module.
+			changes = append(changes, Change{Kind: Added, Symbol: s, Breaking: s.Kind == KindInterfaceMethod})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Symbol.Package != changes[j].Symbol.Package {
+			return changes[i].Symbol.Package < changes[j].Symbol.Package
+		}
+		return changes[i].Symbol.Name < changes[j].Symbol.Name
+	})
+	return changes
+}
+
+// packageSymbols lists the exported symbols of pkg, including the exported
+// methods and fields of its exported types.
+func packageSymbols(pkg *types.Package) []Symbol {
+	qualifier := types.RelativeTo(pkg)
+	scope := pkg.Scope()
+
+	var symbols []Symbol
+	add := func(name, kind, sig string) {
+		symbols = append(symbols, Symbol{Package: pkg.Path(), Name: name, Kind: kind, Signature: sig})
+	}
+
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+		switch obj := obj.(type) {
+		case *types.Func:
+			add(name, KindFunc, types.ObjectString(obj, qualifier))
+		case *types.Var:
+			add(name, KindVar, types.ObjectString(obj, qualifier))
+		case *types.Const:
+			add(name, KindConst, types.ObjectString(obj, qualifier))
+		case *types.TypeName:
+			add(name, KindType, typeSignature(obj, qualifier))
+			if obj.IsAlias() {
+				continue
+			}
+			named, ok := obj.Type().(*types.Named)
+			if !ok {
+				continue
+			}
+			for i := 0; i < named.NumMethods(); i++ {
+				m := named.Method(i)
+				if m.Exported() {
+					add(name+"."+m.Name(), KindMethod, types.ObjectString(m, qualifier))
+				}
+			}
+			switch u := named.Underlying().(type) {
+			case *types.Struct:
+				for i := 0; i < u.NumFields(); i++ {
+					f := u.Field(i)
+					if f.Exported() {
+						add(name+"."+f.Name(), KindField, "field "+name+"."+f.Name()+" "+types.TypeString(f.Type(), qualifier))
+					}
+				}
+			case *types.Interface:
+				for i := 0; i < u.NumExplicitMethods(); i++ {
+					m := u.ExplicitMethod(i)
+					if m.Exported() {
+						add(name+"."+m.Name(), KindInterfaceMethod, types.ObjectString(m, qualifier))
+					}
+				}
+			}
+		}
+	}
+	return symbols
+}
+
+// typeSignature renders a type declaration. Structs and interfaces are
+// reduced to their kind, since their members are compared individually.
+func typeSignature(obj *types.TypeName, qualifier types.Qualifier) string {
+	params := ""
+	if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+		var list []string
+		for i := 0; i < named.TypeParams().Len(); i++ {
+			tp := named.TypeParams().At(i)
+			list = append(list, tp.Obj().Name()+" "+types.TypeString(tp.Constraint(), qualifier))
+		}
+		params = "[" + strings.Join(list, ", ") + "]"
+	}
+
+	if obj.IsAlias() {
+		return "type " + obj.Name() + params + " = " + types.TypeString(obj.Type(), qualifier)
+	}
+	switch obj.Type().Underlying().(type) {
+	case *types.Struct:
+		return "type " + obj.Name() + params + " struct"
+	case *types.Interface:
+		return "type " + obj.Name() + params + " interface"
+	default:
+		return "type " + obj.Name() + params + " " + types.TypeString(obj.Type().Underlying(), qualifier)
+	}
+}
+
+// isInternal reports whether path is an internal package.
+func isInternal(path string) bool {
+	return path == "internal" || strings.HasPrefix(path, "internal/") ||
+		strings.Contains(path, "/internal/") || strings.HasSuffix(path, "/internal")
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package checkpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Batch records a batch of requests submitted to a provider's batch API, so
+// that its results can be collected and matched to commits later.
+type Batch struct {
+	ID       string `json:"id"`
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	Tier     string `json:"tier"`
+	// Verify is the --verify-citations mode; empty records from before it
+	// existed mean "mark".
+	Verify      string    `json:"verify,omitempty"`
+	Anchors     bool      `json:"anchors,omitempty"`
+	Force       bool      `json:"force,omitempty"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	// Requests maps the batch's request ids to full commit hashes.
+	Requests map[string]string `json:"requests"`
+}
+
+// BatchPath returns the file recording batch id inside gitDir.
+func BatchPath(gitDir, id string) string {
+	return filepath.Join(gitDir, "arc-git", "batches", id+".json")
+}
+
+// LoadBatch reads a batch record. A missing file yields (nil, nil).
+func LoadBatch(path string) (*Batch, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch record: %w", err)
+	}
+
+	var b Batch
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse batch record %s: %w", path, err)
+	}
+	return &b, nil
+}
+
+// Save writes the batch record atomically, creating parent directories.
+func (b *Batch) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create batch directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode batch record: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write batch record: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write batch record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package checkpoint persists the progress of long-running commands so that
+// interrupted runs can be accounted for and resumed.
+package checkpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Checkpoint records the state of the most recent run of a command.
+type Checkpoint struct {
+	Command     string    `json:"command"`
+	Range       string    `json:"range"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+	Interrupted bool      `json:"interrupted"`
+	// Reason explains an early stop ("interrupted", "run deadline exceeded").
+	Reason    string `json:"reason,omitempty"`
+	Annotated int    `json:"annotated"`
+	Skipped   int    `json:"skipped"`
+	Failed    int    `json:"failed"`
+	// Completed lists the full hashes processed in this run.
+	Completed []string `json:"completed"`
+	// Pending lists the full hashes that were selected but not processed.
+	Pending []string `json:"pending,omitempty"`
+	// Tip is the commit the last --new-since-last-run selection reached;
+	// commits reachable from it have been seen.
+	Tip string `json:"tip,omitempty"`
+}
+
+// Path returns the checkpoint file for command inside gitDir.
+func Path(gitDir, command string) string {
+	return filepath.Join(gitDir, "arc-git", "checkpoints", command+".json")
+}
+
+// Load reads a checkpoint. A missing file yields (nil, nil).
+func Load(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	return &cp, nil
+}
+
+// Save writes the checkpoint atomically, creating parent directories.
+func (cp *Checkpoint) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/keychain"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-git/internal/providers"
+	"github.com/yourorg/arc-git/internal/telemetry"
+	"github.com/yourorg/arc-sdk/ai"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// aiFlags holds the provider overrides shared by AI-backed commands.
+type aiFlags struct {
+	provider string
+	model    string
+	apiKey   string
+	cmd      *cobra.Command
+}
+
+// register adds the provider override flags to cmd.
+func (f *aiFlags) register(cmd *cobra.Command) {
+	f.cmd = cmd
+	cmd.Flags().StringVar(&f.provider, "provider", "", "AI provider (claude, anthropic, openrouter)")
+	cmd.Flags().StringVar(&f.model, "model", "", "Model to use")
+	cmd.Flags().StringVar(&f.apiKey, "api-key", "", "API key")
+}
+
+// apply returns a copy of base with the repository config and then the flag
+// overrides applied. Without a key from either, the key arc-git init stored
+// in the keychain for the provider is used.
+func (f *aiFlags) apply(ctx context.Context, base *ai.Config) *ai.Config {
+	cfg := *base
+	if p := repoConfig(ctx).Provider; p != "" {
+		cfg.Provider = p
+	}
+	if f.provider != "" {
+		cfg.Provider = f.provider
+	}
+	if f.apiKey != "" {
+		cfg.APIKey = f.apiKey
+	}
+	if f.model != "" {
+		cfg.DefaultModel = f.model
+	}
+	if cfg.APIKey == "" && cfg.Provider != "" {
+		if key, err := keychain.Get(providers.Canonical(cfg.Provider)); err == nil {
+			cfg.APIKey = key
+		}
+	}
+	return &cfg
+}
+
+// modelFor returns the --model override if set, otherwise the model the
+// repository config names for this command, otherwise def.
+func (f *aiFlags) modelFor(ctx context.Context, def string) string {
+	if f.model != "" {
+		return f.model
+	}
+	return repoConfig(ctx).Model(f.commandName(), def)
+}
+
+// modelForVariant is modelFor for a variant of the command, such as the
+// quick annotation tier, configured under "<command>-<variant>". The config's
+// "default" model does not apply, since variants usually pick a cheaper one.
+func (f *aiFlags) modelForVariant(ctx context.Context, variant, def string) string {
+	if f.model != "" {
+		return f.model
+	}
+	if m := repoConfig(ctx).Models[f.commandName()+"-"+variant]; m != "" {
+		return m
+	}
+	return def
+}
+
+// commandName returns the config key for the command, such as "annotate" or
+// "release-publish".
+func (f *aiFlags) commandName() string {
+	if f.cmd == nil {
+		return ""
+	}
+	return commandKey(f.cmd)
+}
+
+// commandKey returns the config key for cmd: its path below the root, joined
+// with dashes.
+func commandKey(cmd *cobra.Command) string {
+	var names []string
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		names = append([]string{c.Name()}, names...)
+	}
+	return strings.Join(names, "-")
+}
+
+// newAIService validates cfg against the repository policy and creates an AI
+// service from it. Under --plan no client is created and the service is nil,
+// since runPrompt sends nothing.
+func newAIService(ctx context.Context, cfg *ai.Config) (*ai.Service, error) {
+	if err := repoConfig(ctx).CheckAI(cfg.Provider, ""); err != nil {
+		return nil, err
+	}
+	if p := planFor(ctx); p != nil {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.provider = cfg.Provider
+		if err := ai.ValidateConfig(cfg); err != nil {
+			p.configError = fmt.Sprintf("invalid AI configuration: %v", err)
+		}
+		return nil, nil
+	}
+	if replayFor(ctx) != nil {
+		// Replies are recorded; there is no provider to configure.
+		return nil, nil
+	}
+	if err := ai.ValidateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("invalid AI configuration: %w", err)
+	}
+
+	client, err := ai.NewClient(*cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AI client: %w", err)
+	}
+	return ai.NewService(client, *cfg), nil
+}
+
+// runPrompt sends a single system/user prompt pair and returns the trimmed
+// reply. Models the repository policy forbids are refused, the repository's
+// redaction rules are applied to the user prompt, the request is charged to
+// the run's token budget, and the reply goes through the post-processing
+// chain. Under --batch-submit the request is queued instead (ErrQueued), and
+// in arc-git demo it is answered from recorded replies.
+func runPrompt(ctx context.Context, service *ai.Service, system, user, model string) (text string, err error) {
+	if err := repoConfig(ctx).CheckAI("", model); err != nil {
+		return "", err
+	}
+	user = repoConfig(ctx).RedactText(user)
+
+	ctx, span := telemetry.Start(ctx, "ai request", attribute.String("ai.model", model))
+	defer func() { telemetry.End(span, err) }()
+
+	modelAttr := metric.WithAttributes(attribute.String("ai.model", model))
+	inputAttr := metric.WithAttributes(attribute.String("ai.model", model), attribute.String("direction", "input"))
+	outputAttr := metric.WithAttributes(attribute.String("ai.model", model), attribute.String("direction", "output"))
+	metrics := telemetry.Metrics()
+
+	inputTokens := prompt.EstimateTokens(system) + prompt.EstimateTokens(user)
+	logging.L().Debug("AI request", "model", model, "system_bytes", len(system), "prompt_bytes", len(user), "estimated_tokens", inputTokens)
+	plan := planFor(ctx)
+	if err := spendTokens(ctx, inputTokens); err != nil {
+		if plan != nil {
+			plan.mu.Lock()
+			plan.overBudget = true
+			plan.mu.Unlock()
+		}
+		return "", err
+	}
+	if plan != nil {
+		plan.record(ctx, model, inputTokens)
+		return "", ErrPlanned
+	}
+	if batch := batchFor(ctx); batch != nil {
+		batch.queue(ctx, system, user, model)
+		return "", ErrQueued
+	}
+	if replay := replayFor(ctx); replay != nil {
+		text, err := replay.reply(user)
+		if err != nil {
+			return "", err
+		}
+		return postProcess(ctx, text), nil
+	}
+	metrics.Requests.Add(ctx, 1, modelAttr)
+	metrics.Tokens.Add(ctx, int64(inputTokens), inputAttr)
+
+	reqCtx := ctx
+	timeout := requestTimeout(ctx)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	resp, err := service.Run(reqCtx, ai.RunOptions{
+		System: system,
+		Prompt: user,
+		Model:  model,
+	})
+	logging.Timed(start, err, "AI request", "model", model)
+	if err != nil {
+		telemetry.Failure(ctx, "ai")
+		if ctx.Err() == nil && reqCtx.Err() != nil {
+			return "", fmt.Errorf("AI request timed out after %s: %w", timeout, err)
+		}
+		return "", fmt.Errorf("AI request failed: %w", err)
+	}
+
+	text = strings.TrimSpace(resp.Text)
+	outputTokens := prompt.EstimateTokens(text)
+	metrics.Tokens.Add(ctx, int64(outputTokens), outputAttr)
+	span.SetAttributes(
+		attribute.Int("ai.input_tokens_estimated", inputTokens),
+		attribute.Int("ai.output_tokens_estimated", outputTokens),
+	)
+	return postProcess(ctx, text), nil
+}
+
+// postProcess runs the repository's post-processing chain for the running
+// command on a reply. A closing paragraph of trailers is left alone, so that
+// steps such as max_chars cannot cut the Risk or Type lines commands parse.
+func postProcess(ctx context.Context, text string) string {
+	cfg := repoConfig(ctx)
+	if len(cfg.PostProcessing) == 0 {
+		return text
+	}
+	command := runningCommand(ctx)
+	note := parseNote(text)
+	i := strings.LastIndex(text, "\n\n")
+	if i < 0 || len(note.Trailers) == 0 && len(note.Anchors) == 0 {
+		return strings.TrimSpace(cfg.PostProcess(command, text))
+	}
+	return strings.TrimSpace(cfg.PostProcess(command, text[:i])) + text[i:]
+}
+
+// runningCommandKey carries the config key of the running command.
+type runningCommandKey struct{}
+
+// withRunningCommand returns ctx naming cmd as the running command.
+func withRunningCommand(ctx context.Context, cmd *cobra.Command) context.Context {
+	return context.WithValue(ctx, runningCommandKey{}, commandKey(cmd))
+}
+
+// runningCommand returns the config key of the running command, or "".
+func runningCommand(ctx context.Context) string {
+	name, _ := ctx.Value(runningCommandKey{}).(string)
+	return name
+}
+
+// defaultReplyReserve is the part of the context window kept free for the
+// reply when the repository config does not set one.
+const defaultReplyReserve = 8000
+
+// fitPrompt trims sections to the context window of model, less the reply
+// reserve, and logs the sections that were cut. Required sections are never
+// trimmed; the rest share what is left by the configured weights.
+func fitPrompt(ctx context.Context, model string, sections ...prompt.Section) []string {
+	c := repoConfig(ctx).Context
+	window := prompt.ContextWindow(model)
+	if c.MaxTokens > 0 && c.MaxTokens < window {
+		window = c.MaxTokens
+	}
+	reserve := c.Reserve
+	if reserve == 0 {
+		reserve = defaultReplyReserve
+	}
+
+	budget := prompt.Budget{Tokens: max(window-reserve, 1), Weights: c.Weights}
+	texts, cuts := budget.Fit(sections...)
+	for _, cut := range cuts {
+		logging.L().Warn("Trimmed prompt section to fit the context window", "model", model, "section", cut.Section, "tokens", cut.From, "kept", cut.To)
+	}
+	return texts
+}
@@ -5,17 +5,25 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"os/exec"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/checkpoint"
+	"github.com/yourorg/arc-git/internal/config"
+	"github.com/yourorg/arc-git/internal/diffview"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
 	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-git/internal/telemetry"
 	"github.com/yourorg/arc-sdk/ai"
-	"github.com/yourorg/arc-sdk/errors"
+	clierrors "github.com/yourorg/arc-sdk/errors"
 	"github.com/yourorg/arc-sdk/output"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
 // newAnnotateCmd creates the annotate subcommand.
@@ -24,11 +32,18 @@ func newAnnotateCmd(aiCfg *ai.Config) *cobra.Command {
 		since      int
 		from       string
 		to         string
-		provider   string
-		model      string
-		apiKey     string
+		aiOpts     aiFlags
+		diffOpts   diffFlags
+		prOpts     prFlags
 		dryRun     bool
 		force      bool
+		tier       string
+		sample     commitSample
+		newOnly    bool
+		anchors    bool
+		verify     string
+		batch      bool
+		collect    string
 		outputOpts output.OutputOptions
 	)
 
@@ -41,9 +56,11 @@ This command iterates through commits and generates technical notes using AI,
 storing them in git notes. The notes provide context and explanations that
 make git history more understandable months or years later.
 
-The annotation is stored under the "ai" notes ref, viewable with:
+The annotation is stored under the "ai" notes ref, and its one-line summary
+under "ai-summary", viewable with:
   git log --show-notes=ai
   git log --notes=ai --grep="refactor"
+  git log --notes=ai-summary --oneline
 
 For each commit, the AI analyzes:
 - The diff and file changes
@@ -51,13 +68,85 @@ For each commit, the AI analyzes:
 - Surrounding context from adjacent commits
 - Code patterns and implications
 
-This creates a searchable, AI-enriched git history.`,
+This creates a searchable, AI-enriched git history.
+
+Pressing Ctrl-C cancels in-flight AI requests, prints the summary for the
+commits completed so far, records a checkpoint under .git/arc-git, and exits
+with status 130. When --run-deadline elapses the run finalizes the same way
+and exits with status 124; --request-timeout fails only the commit whose
+request was too slow. Re-running the same command continues where it stopped.
+
+With --tier quick, only the commit message, file stats, and hunk headers are
+sent to a cheap model, which writes a one-line gist. This makes annotating a
+whole history nearly free. The default --tier deep sends the full diff;
+deep runs replace quick gists but skip commits that already have a deep
+annotation.
+
+For histories too long to backfill, --sample picks a representative subset
+of --from..--to (or of all history of --to) instead of the latest --since
+commits. Commits that already have a note of the requested tier are left out,
+so repeated runs widen coverage:
+- stratified-by-month: the largest commits of every month in turn
+- top-churn: the commits with the most changed lines
+- tagged-releases: every tagged commit, then the largest commits of each
+  release interval in turn
+--sample-size defaults to budget.max_commits from the repository config, or
+100.
+
+--new-since-last-run selects the commits of --to that became reachable since
+the previous --new-since-last-run, plus any left pending by an interrupted
+run, so that notes can be kept current from a post-merge or post-rewrite
+hook. The first run falls back to the latest --since commits.
+
+--pr annotates exactly the commits of a pull request: the base and head are
+looked up on the forge behind --remote (GitHub, with GITHUB_TOKEN or
+GH_TOKEN) and fetched, so that jobs triggered by pull request events need
+not build --from and --to themselves.
+
+With --anchors, deep annotations also record which file and hunk lines each
+statement refers to, as Anchor trailers, so that "arc-git why" can print the
+part of an annotation that explains a given line. Anchors naming lines the
+commit did not change are dropped. Combine with --force to add anchors to
+commits that are already annotated.
+
+With --batch-submit, the prompts are not sent one by one but uploaded as a
+single batch to the provider's batch API (Anthropic), which costs half as
+much and takes up to a day. The batch id is printed and recorded under
+.git/arc-git/batches; once the batch has finished, --batch-collect <id>
+writes its notes, skipping commits annotated in the meantime unless the
+batch was submitted with --force. --batch-collect honors --dry-run; the
+other selection flags are taken from the submission.
+
+Every annotation is checked for the file paths, function names, and commit
+hashes it mentions: paths and names must appear in the diff (or, for quick
+gists, the stats and hunk headers) or in the tree of the commit, and hashes
+must name commits. An annotation citing anything else is stored with
+"Confidence: low" and an Unverified trailer listing the references, which
+arc-git log shows. With --verify-citations regenerate it is first asked for
+once more without them; batch replies can only be marked, and off skips the
+check.
+
+Deep annotations see the commit's diff in the --diff-format, or the format
+diff_formats in the repository config names for annotate: unified, word
+(changed words marked inline), side-by-side (old and new lines in columns,
+with whole functions as context), or semantic (difftastic's syntax-aware
+diff, when difft is installed).
+
+When the repository config has branch rules and --tier is not given, each
+commit gets the tier, model, and structure requirement of the first rule
+covering a branch it is reachable from, so that main and release branches
+can get deep annotations from a premium model while feature branches get
+quick gists or none. Commits no rule covers use the defaults. Run with
+--tier to annotate everything alike; --batch-submit always does.`,
 		Example: `  # Annotate the last 10 commits
   arc-git annotate --since 10
 
   # Limit the scan to a specific commit range
   arc-git annotate --from HEAD~5 --to HEAD
 
+  # Annotate exactly the commits of pull request 512 (e.g. in a PR-triggered job)
+  arc-git annotate --pr 512
+
   # Switch providers when experimenting with different AI stacks
   arc-git annotate --since 20 --provider openrouter
 
@@ -67,191 +156,358 @@ This creates a searchable, AI-enriched git history.`,
   # Overwrite existing annotations when regenerating
   arc-git annotate --since 10 --force
 
+  # Cheap one-line gists for the whole history, then deep notes for a release
+  arc-git annotate --since 5000 --tier quick
+  arc-git annotate --from v2.3.0 --to v2.4.0 --tier deep
+
+  # Cover a 100k-commit history with 500 representative deep annotations
+  arc-git annotate --sample stratified-by-month --sample-size 500
+
+  # Tie each statement to the hunks it describes, for arc-git why
+  arc-git annotate --since 50 --anchors --force
+
+  # Backfill a long history at the batch discount, collecting the next day
+  arc-git annotate --since 5000 --batch-submit
+  arc-git annotate --batch-collect msgbatch_01HkcTjaV5uDC8jWR4ZsDV8d
+
+  # Annotate whatever a pull brought in (e.g. from .git/hooks/post-merge)
+  arc-git annotate --new-since-last-run --output quiet
+
+  # Preview which tier and model the branch rules pick for each commit
+  arc-git annotate --since 50 --plan
+
+  # Show the model word-level diffs, cheaper for small edits than unified ones
+  arc-git annotate --since 50 --diff-format word
+
   # Emit structured JSON for downstream tooling
   arc-git annotate --since 20 --output json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := outputOpts.Resolve(); err != nil {
 				return err
 			}
-
-			// Build effective config with flag overrides
-			cfg := *aiCfg
-			if provider != "" {
-				cfg.Provider = provider
+			if tier != tierQuick && tier != tierDeep {
+				return clierrors.NewCLIError(fmt.Sprintf("unknown tier %q", tier)).
+					WithHint("Use --tier quick or --tier deep")
+			}
+			if sample.strategy != "" && !slices.Contains(sampleStrategies, sample.strategy) {
+				return clierrors.NewCLIError(fmt.Sprintf("unknown sampling strategy %q", sample.strategy)).
+					WithHint("Use --sample " + strings.Join(sampleStrategies, ", --sample "))
+			}
+			if err := prOpts.check("from", "to", "since", "new-since-last-run"); err != nil {
+				return err
+			}
+			if newOnly && (sample.strategy != "" || from != "") {
+				return clierrors.NewCLIError("--new-since-last-run cannot be combined with --sample or --from").
+					WithHint("The previous run decides where the range starts; drop --sample and --from")
 			}
-			if apiKey != "" {
-				cfg.APIKey = apiKey
+			if !slices.Contains(citationModes, verify) {
+				return clierrors.NewCLIError(fmt.Sprintf("unknown --verify-citations mode %q", verify)).
+					WithHint("Use --verify-citations " + strings.Join(citationModes, ", --verify-citations "))
 			}
-			if model != "" {
-				cfg.DefaultModel = model
+			if anchors && tier != tierDeep {
+				return clierrors.NewCLIError("--anchors needs --tier deep").
+					WithHint("Quick gists are one line and do not see the hunks; drop --anchors or use --tier deep")
+			}
+			if batch && (collect != "" || newOnly || dryRun) {
+				return clierrors.NewCLIError("--batch-submit cannot be combined with --batch-collect, --new-since-last-run, or --dry-run").
+					WithHint("Submitted batches are paid for; preview with --plan, or collect with --batch-collect --dry-run")
+			}
+			if collect != "" {
+				return runAnnotateCollect(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), collect, dryRun, outputOpts)
+			}
+
+			diffFormat, err := diffOpts.resolve(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if prOpts.set() {
+				if from, to, err = prOpts.resolve(cmd.Context()); err != nil {
+					return err
+				}
 			}
 
-			return runAnnotate(&cfg, since, from, to, dryRun, force, outputOpts)
+			// Branch rules decide the tier unless it is given explicitly.
+			byBranch := !cmd.Flags().Changed("tier") && !batch && len(repoConfig(cmd.Context()).Branches) > 0
+
+			err = runAnnotate(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, since, from, to, tier, verify, diffFormat, sample, newOnly, byBranch, anchors, batch, dryRun, force, outputOpts)
+			if errors.Is(err, ErrInterrupted) || errors.Is(err, ErrDeadline) || errors.Is(err, ErrBudgetExceeded) {
+				cmd.SilenceUsage = true
+			}
+			return err
 		},
 	}
 
 	cmd.Flags().IntVar(&since, "since", 10, "Annotate last N commits")
 	cmd.Flags().StringVar(&from, "from", "", "Start commit (e.g., HEAD~20)")
 	cmd.Flags().StringVar(&to, "to", "HEAD", "End commit (default: HEAD)")
-	cmd.Flags().StringVar(&provider, "provider", "", "AI provider (claude, anthropic, openrouter)")
-	cmd.Flags().StringVar(&model, "model", "", "Model to use")
-	cmd.Flags().StringVar(&apiKey, "api-key", "", "API key")
+	prOpts.register(cmd)
+	aiOpts.register(cmd)
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview annotations without saving")
 	cmd.Flags().BoolVar(&force, "force", false, "Re-annotate existing commits")
+	cmd.Flags().StringVar(&tier, "tier", tierDeep, "Annotation depth: quick (stats and hunk headers, one line) or deep (full diff)")
+	cmd.Flags().StringVar(&sample.strategy, "sample", "", "Sample history instead of taking the latest commits: "+strings.Join(sampleStrategies, ", "))
+	cmd.Flags().IntVar(&sample.size, "sample-size", 0, "Commits to sample (default: budget.max_commits, or 100)")
+	cmd.Flags().BoolVar(&newOnly, "new-since-last-run", false, "Annotate only commits that became reachable since the previous such run")
+	cmd.Flags().BoolVar(&anchors, "anchors", false, "Record the file and hunk lines each statement of a deep annotation refers to")
+	cmd.Flags().BoolVar(&batch, "batch-submit", false, "Submit the prompts as one discounted provider batch instead of sending them")
+	cmd.Flags().StringVar(&collect, "batch-collect", "", "Write the notes of a finished batch submitted with --batch-submit")
+	cmd.Flags().StringVar(&verify, "verify-citations", citationsMark, "Annotations naming files, functions, or commits that do not exist: "+strings.Join(citationModes, ", "))
+	diffOpts.register(cmd)
 	outputOpts.AddOutputFlags(cmd, output.OutputTable)
 
 	return cmd
 }
 
+// Annotation tiers. Imported notes are written by notes import rather than
+// by annotate.
+const (
+	tierQuick    = "quick"
+	tierDeep     = "deep"
+	tierImported = "imported"
+	// tierNone is the tier of branch rules that leave commits unannotated.
+	tierNone = "none"
+)
+
+// quickTrailer marks notes written by the quick tier so that deep runs know
+// to replace them.
+const quickTrailer = "Tier: quick"
+
+// maxQuickHunks bounds the hunk headers sent for a quick annotation.
+const maxQuickHunks = 4000
+
+// annotationResult records the outcome for a single commit.
+type annotationResult struct {
+	Hash       string `json:"hash"`
+	Status     string `json:"status"`
+	Message    string `json:"message,omitempty"`
+	Annotation string `json:"annotation,omitempty"`
+	// Tier and BranchRule are set when a branch rule chose the tier.
+	Tier       string `json:"tier,omitempty"`
+	BranchRule string `json:"branch_rule,omitempty"`
+	// Unverified lists the references of the annotation that failed
+	// citation verification.
+	Unverified []string `json:"unverified,omitempty"`
+	failure
+}
+
 // runAnnotate implements the git annotation workflow.
-func runAnnotate(cfg *ai.Config, since int, from, to string, dryRun, force bool, out output.OutputOptions) error {
-	// Helper for conditional logging (quiet mode suppresses progress)
-	logProgress := func(format string, args ...interface{}) {
-		if !out.Is(output.OutputQuiet) && !out.Is(output.OutputJSON) && !out.Is(output.OutputYAML) {
-			fmt.Printf(format, args...)
-		}
-	}
+func runAnnotate(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, since int, from, to, tier, verify, diffFormat string, sample commitSample, newOnly, byBranch, anchors, batchSubmit, dryRun, force bool, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+	started := time.Now()
+
+	log.Info("Starting git history annotation", "tier", tier)
 
-	logProgress("Starting git history annotation...\n")
+	// The previous checkpoint carries the tip incremental runs resume from.
+	previous, err := loadCheckpoint(ctx, "annotate")
+	if err != nil {
+		log.Warn("Ignoring unreadable checkpoint", "error", err)
+	}
+	var tip string
+	if previous != nil {
+		tip = previous.Tip
+	}
 
 	// Get commits to annotate
-	commits, err := getCommits(since, from, to)
+	var commits []git.Commit
+	switch {
+	case newOnly:
+		commits, tip, err = getNewCommits(ctx, since, to, previous)
+	case sample.strategy != "":
+		commits, err = getSampledCommits(ctx, sample, from, to, tier, force)
+	default:
+		commits, err = getCommits(ctx, since, from, to)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get commits: %w", err)
 	}
 
 	if len(commits) == 0 {
-		logProgress("No commits to annotate.\n")
+		log.Info("No commits to annotate")
 		return nil
 	}
 
-	logProgress("Found %d commits to annotate\n", len(commits))
-
-	// Validate config
-	if err := ai.ValidateConfig(cfg); err != nil {
-		return fmt.Errorf("invalid AI configuration: %w", err)
+	// Commits beyond budget.max_commits are recorded as pending, so that
+	// --new-since-last-run picks them up next time.
+	var deferred []git.Commit
+	if limit := repoConfig(ctx).Budget.MaxCommits; limit > 0 && len(commits) > limit {
+		log.Warn("Limiting run to budget.max_commits", "found", len(commits), "limit", limit)
+		commits, deferred = commits[:limit], commits[limit:]
 	}
 
-	// Create AI client and service
-	client, err := ai.NewClient(*cfg)
+	log.Info("Found commits to annotate", "count", len(commits))
+
+	// Create AI service
+	service, err := newAIService(ctx, cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create AI client: %w", err)
+		return err
 	}
-	service := ai.NewService(client, *cfg)
 
 	// Process commits
 	annotated := 0
 	skipped := 0
 	failed := 0
-
-	// Track results for JSON output
-	type AnnotationResult struct {
-		Hash       string `json:"hash"`
-		Status     string `json:"status"`
-		Message    string `json:"message,omitempty"`
-		Annotation string `json:"annotation,omitempty"`
+	lowConfidence := 0
+	var (
+		results   []annotationResult
+		failures  = failureTally{Counts: make(map[string]int)}
+		completed []string
+		pending   []git.Commit
+		model     = annotateModel(ctx, aiOpts, tier, nil)
+	)
+	if err := repoConfig(ctx).CheckAI("", model); err != nil {
+		return err
+	}
+	if byBranch {
+		for i := range repoConfig(ctx).Branches {
+			rule := &repoConfig(ctx).Branches[i]
+			if rule.Tier == tierNone {
+				continue
+			}
+			if err := repoConfig(ctx).CheckAI("", annotateModel(ctx, aiOpts, tier, rule)); err != nil {
+				return err
+			}
+		}
+	}
+	var batch *runBatch
+	if batchSubmit && planFor(ctx) == nil {
+		// Fail before reading any diffs if the provider has no batch API.
+		if _, err := newBatcher(cfg, cfg.Provider); err != nil {
+			return err
+		}
+		batch = &runBatch{}
+		ctx = withBatch(ctx, batch)
 	}
-	var results []AnnotationResult
 
 	for i, commit := range commits {
-		logProgress("\n[%d/%d] Processing %s\n", i+1, len(commits), commit.Hash[:7])
-
-		// Check if already annotated (unless --force)
-		if !force && hasNote(commit.Hash, "ai") {
-			logProgress("  Already annotated (use --force to re-annotate)\n")
-			skipped++
-			results = append(results, AnnotationResult{
-				Hash:    commit.Hash[:7],
-				Status:  "skipped",
-				Message: "already annotated",
-			})
-			continue
+		if ctx.Err() != nil {
+			pending = commits[i:]
+			break
 		}
 
-		// Get commit diff
-		diff, err := getCommitDiff(commit.Hash)
-		if err != nil {
-			logProgress("  Failed to get diff: %v\n", err)
-			failed++
-			results = append(results, AnnotationResult{
-				Hash:    commit.Hash[:7],
-				Status:  "failed",
-				Message: fmt.Sprintf("failed to get diff: %v", err),
-			})
-			continue
-		}
+		log.Info("Processing commit", "commit", commit.Short(), "progress", fmt.Sprintf("%d/%d", i+1, len(commits)))
 
-		if len(diff) == 0 {
-			logProgress("  No diff (merge commit?), skipping\n")
-			skipped++
-			results = append(results, AnnotationResult{
-				Hash:    commit.Hash[:7],
-				Status:  "skipped",
-				Message: "no diff (merge commit?)",
-			})
-			continue
+		var (
+			result                  annotationResult
+			commitTier, commitModel = tier, model
+			rule                    *config.BranchRule
+			ruleErr                 error
+		)
+		if byBranch {
+			commitTier, commitModel, rule, ruleErr = branchSettings(ctx, aiOpts, commit, tier, model)
+		}
+		switch {
+		case ruleErr != nil && ctx.Err() != nil:
+			result.Status = "pending"
+		case ruleErr != nil:
+			log.Warn("Failed to resolve branch rule", "commit", commit.Short(), "error", ruleErr)
+			result = annotationResult{Hash: commit.Short(), Status: "failed", Message: fmt.Sprintf("failed to resolve branch rule: %v", ruleErr), failure: classifyFailure(ruleErr)}
+		case commitTier == tierNone:
+			log.Info("Branch rule skips commit", "commit", commit.Short(), "rule", rule.Name)
+			result = annotationResult{Hash: commit.Short(), Status: "skipped", Message: "no annotation under branch rule " + rule.Name}
+		default:
+			result = annotateCommit(withBatchCommit(withPlanSubject(ctx, commit.Short()), commit.Hash), service, commitModel, commitTier, verify, diffFormat, rule, commit, anchors && commitTier == tierDeep, dryRun, force)
+		}
+		if rule != nil {
+			result.Tier, result.BranchRule = commitTier, rule.Name
+		}
+		if result.Status == "pending" {
+			// Cancelled or out of budget while this commit was in flight
+			pending = commits[i:]
+			break
+		}
+		results = append(results, result)
+		completed = append(completed, commit.Hash)
+		if len(result.Unverified) > 0 && result.Annotation != "" {
+			lowConfidence++
 		}
 
-		// Generate annotation
-		logProgress("  Generating AI annotation...\n")
-		annotation, err := generateAnnotation(service, commit, diff)
-		if err != nil {
-			logProgress("  Failed to generate annotation: %v\n", err)
+		switch result.Status {
+		case "skipped":
+			skipped++
+		case "failed":
 			failed++
-			results = append(results, AnnotationResult{
-				Hash:    commit.Hash[:7],
-				Status:  "failed",
-				Message: fmt.Sprintf("failed to generate annotation: %v", err),
-			})
-			continue
-		}
-
-		// Preview or save
-		if dryRun {
-			logProgress("\n--- Annotation for %s ---\n%s\n", commit.Hash[:7], annotation)
-			results = append(results, AnnotationResult{
-				Hash:       commit.Hash[:7],
-				Status:     "preview",
-				Annotation: annotation,
-			})
-		} else {
-			if err := addNote(commit.Hash, "ai", annotation); err != nil {
-				logProgress("  Failed to add note: %v\n", err)
-				failed++
-				results = append(results, AnnotationResult{
-					Hash:    commit.Hash[:7],
-					Status:  "failed",
-					Message: fmt.Sprintf("failed to add note: %v", err),
-				})
-				continue
+			failures.add(result.failure)
+		case "preview":
+			if !isStructured(out) {
+				fmt.Printf("\n--- Annotation for %s ---\n%s\n\n", commit.Short(), result.Annotation)
 			}
-			logProgress("  Annotated successfully\n")
-			results = append(results, AnnotationResult{
-				Hash:       commit.Hash[:7],
-				Status:     "success",
-				Annotation: annotation,
-			})
+			annotated++
+		default:
+			annotated++
 		}
+	}
+
+	// Under --plan nothing was generated; the plan is the output.
+	if planFor(ctx) != nil {
+		return nil
+	}
+	if batch != nil {
+		return submitAnnotateBatch(ctx, cfg, batch, results, len(pending), model, tier, verify, anchors, force, out)
+	}
 
-		annotated++
+	interrupted := len(pending) > 0
+	reason := stopReason(ctx)
+	if interrupted && reason == nil {
+		reason = ErrBudgetExceeded
+	}
+	if interrupted {
+		log.Warn("Stopping early, flushing partial results", "reason", reason, "completed", len(completed), "pending", len(pending))
+	}
+	for _, commit := range pending {
+		results = append(results, annotationResult{Hash: commit.Short(), Status: "pending"})
+	}
+
+	// Record the run so it can be inspected and resumed; the run context may
+	// already be cancelled, so git is invoked without it.
+	if !dryRun {
+		cp := &checkpoint.Checkpoint{
+			Command:     "annotate",
+			Range:       describeRange(since, from, to, sample, newOnly),
+			StartedAt:   started,
+			FinishedAt:  time.Now(),
+			Interrupted: interrupted,
+			Reason:      errString(reason),
+			Annotated:   annotated,
+			Skipped:     skipped,
+			Failed:      failed,
+			Completed:   completed,
+			Tip:         tip,
+		}
+		for _, commit := range pending {
+			cp.Pending = append(cp.Pending, commit.Hash)
+		}
+		for _, commit := range deferred {
+			cp.Pending = append(cp.Pending, commit.Hash)
+		}
+		cp.Pending = carryPending(previous, completed, cp.Pending)
+		if err := saveCheckpoint(context.WithoutCancel(ctx), cp); err != nil {
+			log.Warn("Failed to save checkpoint", "error", err)
+		}
+		if annotated > 0 {
+			mirrorAfterRun(ctx)
+		}
 	}
 
 	// Output results
 	switch {
 	case out.Is(output.OutputJSON):
 		result := map[string]interface{}{
-			"total":     len(commits),
-			"annotated": annotated,
-			"skipped":   skipped,
-			"failed":    failed,
-			"dry_run":   dryRun,
-			"results":   results,
-		}
-		encoder := json.NewEncoder(os.Stdout)
-		encoder.SetIndent("", "  ")
-		if err := encoder.Encode(result); err != nil {
-			return errors.NewCLIError(fmt.Sprintf("failed to encode JSON: %v", err)).
-				WithHint("Try --output table instead")
+			"total":          len(commits),
+			"annotated":      annotated,
+			"skipped":        skipped,
+			"failed":         failed,
+			"failures":       failures.Counts,
+			"pending":        len(pending),
+			"interrupted":    interrupted,
+			"stop_reason":    errString(reason),
+			"dry_run":        dryRun,
+			"tier":           tier,
+			"low_confidence": lowConfidence,
+			"results":        results,
+		}
+		if err := writeJSON(result); err != nil {
+			return err
 		}
 	case out.Is(output.OutputQuiet):
 		// Quiet mode: suppress summary
@@ -260,8 +516,25 @@ func runAnnotate(cfg *ai.Config, since int, from, to string, dryRun, force bool,
 		fmt.Printf("Annotated: %d\n", annotated)
 		fmt.Printf("Skipped: %d\n", skipped)
 		fmt.Printf("Failed: %d\n", failed)
+		failures.print()
+		if lowConfidence > 0 {
+			fmt.Printf("Low confidence: %d (cite references that do not exist; see their Unverified trailers)\n", lowConfidence)
+		}
+		if interrupted {
+			fmt.Printf("Pending: %d\n", len(pending))
+		}
 
-		if dryRun {
+		if interrupted {
+			switch reason {
+			case ErrDeadline:
+				fmt.Println("\nRun deadline reached. Re-run the same command to continue;")
+			case ErrBudgetExceeded:
+				fmt.Println("\nToken budget (budget.max_tokens) exhausted. Re-run the same command to continue;")
+			default:
+				fmt.Println("\nRun was interrupted. Re-run the same command to continue;")
+			}
+			fmt.Println("commits that were already annotated are skipped.")
+		} else if dryRun {
 			fmt.Println("\n(Dry run - no notes were added)")
 			fmt.Println("Run without --dry-run to save annotations")
 		} else {
@@ -269,105 +542,446 @@ func runAnnotate(cfg *ai.Config, since int, from, to string, dryRun, force bool,
 		}
 	}
 
+	if interrupted {
+		return fmt.Errorf("%w: %d of %d commits pending", reason, len(pending), len(commits))
+	}
 	return nil
 }
 
-// Commit represents a git commit for annotation.
-type Commit struct {
-	Hash    string
-	Message string
-	Author  string
-	Date    string
+// describeRange renders the commit selection for checkpoints.
+func describeRange(since int, from, to string, sample commitSample, newOnly bool) string {
+	if newOnly {
+		return fmt.Sprintf("new since last run on %s", to)
+	}
+	if sample.strategy != "" {
+		rangeArg := to
+		if from != "" {
+			rangeArg = fmt.Sprintf("%s..%s", from, to)
+		}
+		return fmt.Sprintf("%s sample of %s", sample.strategy, rangeArg)
+	}
+	if from != "" {
+		return fmt.Sprintf("%s..%s", from, to)
+	}
+	return fmt.Sprintf("last %d", since)
+}
+
+// loadCheckpoint reads the repository's checkpoint for command, or nil when
+// it has none.
+func loadCheckpoint(ctx context.Context, command string) (*checkpoint.Checkpoint, error) {
+	gitDir, err := git.Dir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return checkpoint.Load(checkpoint.Path(gitDir, command))
 }
 
-// getCommits gets the list of commits to annotate.
-func getCommits(since int, from, to string) ([]Commit, error) {
-	var commits []Commit
+// saveCheckpoint writes cp to the repository's checkpoint file for its command.
+func saveCheckpoint(ctx context.Context, cp *checkpoint.Checkpoint) error {
+	gitDir, err := git.Dir(ctx)
+	if err != nil {
+		return err
+	}
+	return cp.Save(checkpoint.Path(gitDir, cp.Command))
+}
 
-	// Build git log command
-	args := []string{"log", "--format=%H%n%an <%ae>%n%ad%n%s", "--no-merges"}
+// annotateCommit generates and, unless dryRun, stores the annotation for a
+// single commit.
+func annotateCommit(ctx context.Context, service *ai.Service, model, tier, verify, diffFormat string, rule *config.BranchRule, commit git.Commit, anchors, dryRun, force bool) (result annotationResult) {
+	ctx, span := telemetry.Start(ctx, "annotate commit", attribute.String("git.commit", commit.Hash))
+	defer func() {
+		span.SetAttributes(attribute.String("arc_git.status", result.Status))
+		if result.Status == "failed" {
+			telemetry.Failure(ctx, "annotate")
+			span.SetStatus(codes.Error, result.Message)
+		}
+		span.End()
+	}()
+
+	log := logging.L()
+	result.Hash = commit.Short()
+
+	// Check if already annotated (unless --force); deep runs upgrade quick
+	// gists.
+	if !force {
+		if note, err := git.ShowNote(ctx, commit.Hash, "ai"); err == nil && (tier == tierQuick || !isQuickNote(note)) {
+			log.Info("Already annotated (use --force to re-annotate)", "commit", commit.Short())
+			result.Status = "skipped"
+			result.Message = "already annotated"
+			return result
+		}
+	}
 
+	// Annotations describe whole commits, so the third_party policy keeps
+	// commits with third-party code out of them altogether. Commits that
+	// cannot be checked are not annotated either.
+	if !thirdPartyAllowed(ctx) {
+		patch, err := git.Diff(ctx, commit.Hash)
+		if ctx.Err() != nil {
+			result.Status = "pending"
+			return result
+		}
+		if err != nil {
+			log.Warn("Failed to check for third-party code", "commit", commit.Short(), "error", err)
+			result.Status = "failed"
+			result.Message = fmt.Sprintf("failed to check for third-party code: %v", err)
+			result.failure = classifyFailure(err)
+			return result
+		}
+		if found := thirdPartyIn(ctx, commit.Hash, patch); len(found) > 0 {
+			log.Info("Contains third-party code, skipping", "commit", commit.Short(), "files", len(found))
+			result.Status = "skipped"
+			result.Message = "contains third-party code: " + describeThirdParty(found)
+			return result
+		}
+	}
+
+	// Get commit diff, or for the quick tier just its shape
+	var (
+		diff, hunks string
+		err         error
+	)
+	if tier == tierQuick {
+		diff, hunks, err = quickContext(ctx, commit.Hash)
+	} else {
+		diff, err = diffview.Commit(ctx, diffFormat, commit.Hash)
+	}
+	if ctx.Err() != nil {
+		result.Status = "pending"
+		return result
+	}
+	if err != nil {
+		log.Warn("Failed to get diff", "commit", commit.Short(), "error", err)
+		result.Status = "failed"
+		result.Message = fmt.Sprintf("failed to get diff: %v", err)
+		result.failure = classifyFailure(err)
+		return result
+	}
+
+	if len(diff) == 0 {
+		log.Info("No diff (merge commit?), skipping", "commit", commit.Short())
+		result.Status = "skipped"
+		result.Message = "no diff (merge commit?)"
+		return result
+	}
+
+	// Generate annotation
+	log.Info("Generating AI annotation", "commit", commit.Short())
+	generate := func(unverified []string) (string, error) {
+		if tier == tierQuick {
+			return generateQuickAnnotation(ctx, service, model, commit, diff, hunks, unverified)
+		}
+		return generateAnnotation(ctx, service, model, commit, diff, anchors, unverified)
+	}
+	annotation, err := generate(nil)
+	if ctx.Err() != nil || errors.Is(err, ErrBudgetExceeded) {
+		result.Status = "pending"
+		return result
+	}
+	if errors.Is(err, ErrPlanned) {
+		result.Status = "planned"
+		return result
+	}
+	if errors.Is(err, ErrQueued) {
+		result.Status = "queued"
+		return result
+	}
+	if err == nil {
+		annotation, result.Unverified = verifyCitations(ctx, verify, commit.Hash, diff+hunks, annotation, generate)
+	}
+	if err == nil && tier == tierDeep {
+		err = checkStructured(ctx, annotation, rule)
+	}
+	if err == nil && anchors {
+		annotation = checkAnchors(ctx, commit, annotation)
+	}
+	if err != nil {
+		log.Warn("Failed to generate annotation", "commit", commit.Short(), "error", err)
+		result.Status = "failed"
+		result.Message = fmt.Sprintf("failed to generate annotation: %v", err)
+		result.failure = classifyFailure(err)
+		return result
+	}
+	result.Annotation = annotation
+
+	// Preview or save
+	if dryRun {
+		result.Status = "preview"
+		return result
+	}
+
+	// The annotation has already been paid for, so store it even if the run
+	// is being cancelled.
+	if err := storeAnnotation(context.WithoutCancel(ctx), commit.Hash, annotation); err != nil {
+		log.Warn("Failed to add note", "commit", commit.Short(), "error", err)
+		result.Status = "failed"
+		result.Message = fmt.Sprintf("failed to add note: %v", err)
+		result.failure = classifyFailure(err)
+		result.Annotation = ""
+		return result
+	}
+	log.Info("Annotated successfully", "commit", commit.Short())
+	result.Status = "success"
+	return result
+}
+
+// getCommits gets the list of commits to annotate.
+func getCommits(ctx context.Context, since int, from, to string) ([]git.Commit, error) {
+	args := []string{"--no-merges"}
 	if from != "" {
 		args = append(args, fmt.Sprintf("%s..%s", from, to))
 	} else {
 		args = append(args, fmt.Sprintf("-n%d", since))
 	}
+	return git.Log(ctx, args...)
+}
+
+// carryPending returns pending followed by the commits previous left
+// pending that this run neither completed nor left pending itself. Runs that
+// do not select them, such as --since and --sample, would otherwise drop
+// them: they are behind the saved tip, so --new-since-last-run only finds
+// them through the checkpoint.
+func carryPending(previous *checkpoint.Checkpoint, completed, pending []string) []string {
+	if previous == nil {
+		return pending
+	}
+	seen := make(map[string]bool, len(completed)+len(pending))
+	for _, hash := range completed {
+		seen[hash] = true
+	}
+	for _, hash := range pending {
+		seen[hash] = true
+	}
+	for _, hash := range previous.Pending {
+		if !seen[hash] {
+			seen[hash] = true
+			pending = append(pending, hash)
+		}
+	}
+	return pending
+}
 
-	cmd := exec.Command("git", args...)
-	out, err := cmd.Output()
+// getNewCommits returns the commits of to that are not reachable from the
+// previous run's tip, together with the commits that run left pending, and
+// the tip to record. Without a usable tip it falls back to the latest since
+// commits.
+func getNewCommits(ctx context.Context, since int, to string, previous *checkpoint.Checkpoint) ([]git.Commit, string, error) {
+	tip, err := revParse(ctx, to)
 	if err != nil {
-		return nil, fmt.Errorf("git log failed: %w", err)
+		return nil, "", fmt.Errorf("unknown revision %q", to)
 	}
 
-	// Parse output
-	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
-	for i := 0; i+3 <= len(lines); i += 4 {
-		if lines[i] == "" {
-			break
-		}
-		commits = append(commits, Commit{
-			Hash:    lines[i],
-			Author:  lines[i+1],
-			Date:    lines[i+2],
-			Message: lines[i+3],
-		})
+	log := logging.L()
+	var commits []git.Commit
+	switch {
+	case previous == nil || previous.Tip == "":
+		log.Info("No previous run recorded, taking the latest commits", "since", since)
+		commits, err = getCommits(ctx, since, "", to)
+	case !git.Exists(ctx, previous.Tip):
+		// Pruned, e.g. after a force-push and gc.
+		log.Warn("Previous tip no longer exists, taking the latest commits", "tip", shortHash(previous.Tip), "since", since)
+		commits, err = getCommits(ctx, since, "", to)
+	default:
+		commits, err = git.Log(ctx, "--no-merges", tip, "^"+previous.Tip)
+	}
+	if err != nil {
+		return nil, "", err
 	}
 
-	return commits, nil
+	if previous != nil && len(previous.Pending) > 0 {
+		seen := make(map[string]bool, len(commits))
+		for _, c := range commits {
+			seen[c.Hash] = true
+		}
+		var pending []string
+		for _, hash := range previous.Pending {
+			if !seen[hash] && git.Exists(ctx, hash) {
+				pending = append(pending, hash)
+			}
+		}
+		if len(pending) > 0 {
+			leftover, err := git.Log(ctx, append([]string{"--no-walk"}, pending...)...)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to read pending commits: %w", err)
+			}
+			commits = append(commits, leftover...)
+		}
+	}
+	return commits, tip, nil
 }
 
-// getCommitDiff gets the diff for a specific commit.
-func getCommitDiff(hash string) (string, error) {
-	cmd := exec.Command("git", "show", "--format=", hash)
-	out, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("git show failed: %w", err)
+// getSampledCommits samples from..to, or all history of to, leaving out
+// commits that already have a note of the requested tier unless force is set.
+func getSampledCommits(ctx context.Context, sample commitSample, from, to, tier string, force bool) ([]git.Commit, error) {
+	if sample.size <= 0 {
+		sample.size = repoConfig(ctx).Budget.MaxCommits
+	}
+	if sample.size <= 0 {
+		sample.size = defaultSampleSize
+	}
+
+	exclude := make(map[string]bool)
+	if !force {
+		noted, err := git.NotedCommits(ctx, "ai")
+		if err != nil {
+			return nil, err
+		}
+		exclude = noted
+		if tier == tierDeep {
+			// Quick gists are upgraded by deep runs, so they stay eligible.
+			quick, err := quickNotedCommits(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for hash := range quick {
+				delete(exclude, hash)
+			}
+		}
+	}
+
+	rangeArg := to
+	if from != "" {
+		rangeArg = fmt.Sprintf("%s..%s", from, to)
 	}
-	return string(out), nil
+	return sampleCommits(ctx, sample, rangeArg, exclude)
 }
 
-// hasNote checks if a commit has a note under the given ref.
-func hasNote(hash, ref string) bool {
-	cmd := exec.Command("git", "notes", "--ref", ref, "show", hash)
-	return cmd.Run() == nil
+// generateAnnotation generates an AI annotation for a commit.
+// unverified names the references of a previous draft to leave out.
+func generateAnnotation(ctx context.Context, service *ai.Service, model string, commit git.Commit, diff string, anchors bool, unverified []string) (string, error) {
+	// The prompt without message and diff is what must fit; the message and
+	// diff are trimmed to share the rest.
+	systemPrompt, userPrompt := prompt.AnnotateCommit(commit.Short(), "", commit.Author, commit.Date, "", anchors)
+	correction := ""
+	if len(unverified) > 0 {
+		correction = prompt.AnnotateCorrection(unverified)
+	}
+	systemPrompt += correction
+	fitted := fitPrompt(ctx, model,
+		prompt.Section{Name: prompt.SectionSystem, Text: systemPrompt + userPrompt, Required: true},
+		prompt.Section{Name: prompt.SectionMetadata, Text: commit.Message},
+		prompt.Section{Name: prompt.SectionDiff, Text: diff, Trim: prompt.TrimDiff},
+	)
+	systemPrompt, userPrompt = prompt.AnnotateCommit(commit.Short(), fitted[1], commit.Author, commit.Date, fitted[2], anchors)
+	return runPrompt(ctx, service, systemPrompt+correction, userPrompt, model)
 }
 
-// addNote adds a note to a commit under the given ref.
-func addNote(hash, ref, note string) error {
-	// Write note to temp file
-	tmpFile, err := os.CreateTemp("", "arc-git-note-*.txt")
+// generateQuickAnnotation generates a one-line quick-tier gist for a commit,
+// marked with quickTrailer. unverified names the references of a previous
+// draft to leave out.
+func generateQuickAnnotation(ctx context.Context, service *ai.Service, model string, commit git.Commit, stats, hunks string, unverified []string) (string, error) {
+	systemPrompt, userPrompt := prompt.AnnotateQuick(commit.Short(), commit.Message, stats, hunks)
+	if len(unverified) > 0 {
+		systemPrompt += prompt.AnnotateCorrection(unverified)
+	}
+	gist, err := runPrompt(ctx, service, systemPrompt, userPrompt, model)
 	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
+		return "", err
 	}
-	defer os.Remove(tmpFile.Name())
+	return gist + "\n\n" + quickTrailer, nil
+}
 
-	if _, err := tmpFile.WriteString(note); err != nil {
-		return fmt.Errorf("failed to write note: %w", err)
+// quickContext returns a commit's file stats and hunk headers; stats is
+// empty when the commit changes nothing.
+func quickContext(ctx context.Context, hash string) (stats, hunks string, err error) {
+	files, err := git.NumStat(ctx, hash)
+	if err != nil || len(files) == 0 {
+		return "", "", err
+	}
+	hunks, err = git.HunkHeaders(ctx, hash)
+	if err != nil {
+		return "", "", err
 	}
-	tmpFile.Close()
 
-	// Add note using git notes
-	cmd := exec.Command("git", "notes", "--ref", ref, "add", "-F", tmpFile.Name(), hash)
-	if out, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git notes failed: %w\nOutput: %s", err, out)
+	var b strings.Builder
+	for _, f := range files {
+		if f.Binary {
+			fmt.Fprintf(&b, "%s (binary)\n", f.Path)
+		} else {
+			fmt.Fprintf(&b, "%s +%d -%d\n", f.Path, f.Added, f.Deleted)
+		}
 	}
+	return b.String(), truncate(hunks, maxQuickHunks), nil
+}
 
+// checkStructured returns a policy violation if the policy or the branch
+// rule, when not nil, requires structured annotations and annotation lacks
+// valid Risk or Labels trailers.
+func checkStructured(ctx context.Context, annotation string, rule *config.BranchRule) error {
+	const detail = "the annotation has no valid Risk and Labels trailers"
+	var violation *config.PolicyViolation
+	switch policy := repoConfig(ctx).Policy; {
+	case policy.RequireStructured:
+		violation = policy.Violation("require_structured", detail)
+	case rule != nil && rule.RequireStructured:
+		violation = rule.Violation("require_structured", detail)
+	default:
+		return nil
+	}
+	note := parseNote(annotation)
+	if riskRank(note.Risk()) < 0 || len(note.Labels()) == 0 {
+		return violation
+	}
 	return nil
 }
 
-// generateAnnotation generates an AI annotation for a commit.
-func generateAnnotation(service *ai.Service, commit Commit, diff string) (string, error) {
-	systemPrompt, userPrompt := prompt.AnnotateCommit(commit.Hash[:7], commit.Message, commit.Author, commit.Date, diff)
-
-	ctx := context.Background()
-	resp, err := service.Run(ctx, ai.RunOptions{
-		System: systemPrompt,
-		Prompt: userPrompt,
-		Model:  prompt.AnnotateCommitModel,
-	})
+// annotateModel returns the model annotate uses for tier, or for the tier of
+// rule when it is not nil: --model, then the rule's model, then the model
+// configured for the tier.
+func annotateModel(ctx context.Context, aiOpts *aiFlags, tier string, rule *config.BranchRule) string {
+	if rule != nil {
+		if rule.Model != "" && aiOpts.model == "" {
+			return rule.Model
+		}
+		tier = rule.Tier
+	}
+	if tier == tierQuick {
+		return aiOpts.modelForVariant(ctx, tierQuick, prompt.AnnotateQuickModel)
+	}
+	return aiOpts.modelFor(ctx, prompt.AnnotateCommitModel)
+}
+
+// branchSettings returns the tier and model for commit under the first branch
+// rule covering a branch it is reachable from, together with the rule. Without
+// such a rule, tier and model are returned unchanged.
+func branchSettings(ctx context.Context, aiOpts *aiFlags, commit git.Commit, tier, model string) (string, string, *config.BranchRule, error) {
+	branches, err := git.BranchesContaining(ctx, commit.Hash)
+	if err != nil {
+		return "", "", nil, err
+	}
+	rule := repoConfig(ctx).BranchRule(branches)
+	if rule == nil {
+		return tier, model, nil, nil
+	}
+	return rule.Tier, annotateModel(ctx, aiOpts, tier, rule), rule, nil
+}
+
+// checkAnchors drops the Anchor trailers of annotation that do not name
+// lines the commit added or changed.
+func checkAnchors(ctx context.Context, commit git.Commit, annotation string) string {
+	files, err := git.ChangedRanges(ctx, commit.Hash)
 	if err != nil {
-		return "", fmt.Errorf("AI request failed: %w", err)
+		logging.L().Warn("Failed to read changed lines, dropping anchors", "commit", commit.Short(), "error", err)
 	}
+	return pruneAnchors(annotation, func(a noteAnchor) bool {
+		for _, f := range files {
+			if f.Path != a.Path {
+				continue
+			}
+			for _, changed := range f.New {
+				for _, r := range a.Ranges {
+					if r.Start <= changed.End && changed.Start <= r.End {
+						return true
+					}
+				}
+			}
+		}
+		logging.L().Debug("Dropping anchor outside the commit's changes", "commit", commit.Short(), "path", a.Path)
+		return false
+	})
+}
 
-	return strings.TrimSpace(resp.Text), nil
+// isQuickNote reports whether note was written by the quick tier.
+func isQuickNote(note string) bool {
+	return parseNote(note).Tier() == tierQuick
 }
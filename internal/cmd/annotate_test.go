@@ -0,0 +1,59 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-git/internal/checkpoint"
+	"github.com/yourorg/arc-git/internal/config"
+	"github.com/yourorg/arc-git/internal/git"
+)
+
+func TestCarryPending(t *testing.T) {
+	previous := &checkpoint.Checkpoint{Pending: []string{"a", "b", "c", "d"}}
+	tests := []struct {
+		name      string
+		previous  *checkpoint.Checkpoint
+		completed []string
+		pending   []string
+		want      []string
+	}{
+		{name: "no previous run", completed: []string{"x"}, pending: []string{"y"}, want: []string{"y"}},
+		{
+			// A --since run over other commits keeps every earlier pending one.
+			name:      "unrelated run",
+			previous:  previous,
+			completed: []string{"x"},
+			want:      []string{"a", "b", "c", "d"},
+		},
+		{
+			name:      "completed and still pending",
+			previous:  previous,
+			completed: []string{"a", "x"},
+			pending:   []string{"c", "y"},
+			want:      []string{"c", "y", "b", "d"},
+		},
+	}
+	for _, tt := range tests {
+		if got := carryPending(tt.previous, tt.completed, tt.pending); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: carryPending = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAnnotateThirdPartyFailsClosed(t *testing.T) {
+	r := newTestRepo(t)
+	r.commit("Start", map[string]string{"main.go": "package main\n"})
+	ctx := withRepoConfig(r.ctx, &config.Config{Policy: config.Policy{ThirdParty: config.ThirdParty{Mode: "exclude"}}})
+
+	// A commit whose diff cannot be read is not sent unchecked.
+	missing := git.Commit{Hash: strings.Repeat("0", 40)}
+	result := annotateCommit(ctx, nil, "model", tierDeep, citationsOff, "", nil, missing, false, true, false)
+	if result.Status != "failed" || !strings.Contains(result.Message, "third-party") {
+		t.Errorf("annotateCommit of an unreadable commit = %s: %s, want it failed by the third-party check", result.Status, result.Message)
+	}
+}
@@ -0,0 +1,477 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	clierrors "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// Anomaly kinds.
+const (
+	anomalyOddHours      = "odd_hours"
+	anomalyForcePush     = "force_push"
+	anomalyVendored      = "vendored_addition"
+	anomalyFirstTimeAuth = "first_time_auth"
+)
+
+// maxAnomalyPaths caps the paths listed for one anomaly and sent for its
+// context.
+const maxAnomalyPaths = 30
+
+var (
+	// defaultAuthPaths match path elements of authentication and
+	// authorization code.
+	defaultAuthPaths = []string{
+		"auth", "auth[._-]*", "*[._-]auth", "*[._-]auth[._-]*", "authn*", "authz*",
+		"authenticat*", "authoriz*", "oauth*", "login*", "session*", "*password*",
+		"*passwd*", "permission*", "rbac*", "sso*", "saml*", "jwt*",
+	}
+	// defaultSensitivePaths match path elements of code and configuration
+	// that build, ship, or secure the software, in addition to auth code.
+	defaultSensitivePaths = append([]string{
+		".github", ".gitlab-ci.yml", ".circleci", "Jenkinsfile", "Dockerfile",
+		"Makefile", "go.mod", "go.sum", "package.json", "package-lock.json",
+		"yarn.lock", "pnpm-lock.yaml", "Cargo.lock", "requirements*.txt",
+		"*crypto*", "*secret*", "*.pem", "CODEOWNERS",
+	}, defaultAuthPaths...)
+	// defaultVendorPaths match path elements of vendored or generated
+	// third-party code.
+	defaultVendorPaths = []string{
+		"vendor", "third_party", "third-party", "thirdparty", "node_modules",
+		"external", "*.min.js", "*.min.css",
+	}
+)
+
+// newAnomaliesCmd creates the anomalies subcommand.
+func newAnomaliesCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts       anomaliesOptions
+		since      string
+		quietHours string
+		aiOpts     aiFlags
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "anomalies",
+		Short: "Flag unusual repository activity for supply-chain review",
+		Long: `Flag unusual repository activity for supply-chain review.
+
+The window is scanned for:
+
+- odd_hours: commits made during --quiet-hours, in the author's own time
+  zone, that touch sensitive paths (CI, build and dependency manifests,
+  secrets, crypto, and auth code)
+- force_push: remote-tracking branches that were rewritten, as recorded in
+  their reflogs when fetched
+- vendored_addition: commits adding more than --vendor-lines lines under
+  vendored paths
+- first_time_auth: commits touching auth code by authors with no commits
+  before the window
+
+Path patterns match any path element, case-insensitively, and patterns
+containing a slash match the whole path. Force pushes are only seen if this
+clone fetched the branch before and after the rewrite, so run the command
+from a clone that fetches regularly.
+
+The AI then puts each flag in context: what the change does, the likely
+benign explanation, and what a reviewer should verify. Use --no-narrative to
+list the flags without calling the AI.`,
+		Example: `  # Flags from the last week, with context
+  arc-git anomalies --since 1w
+
+  # Flags only, as JSON for a security dashboard
+  arc-git anomalies --since 1w --no-narrative --output json
+
+  # Treat evenings as quiet hours and watch a custom auth package
+  arc-git anomalies --quiet-hours 20-7 --auth-path 'internal/iam/*'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			start, end, err := parseQuietHours(quietHours)
+			if err != nil {
+				return clierrors.NewCLIError(err.Error()).
+					WithHint("Pass hours as start-end in 24-hour time, e.g. --quiet-hours 0-6 or 22-6")
+			}
+			opts.since, opts.quietStart, opts.quietEnd = gitSince(since), start, end
+
+			return runAnomalies(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, opts, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "1w", "Only consider activity more recent than this (1y, 6m, 2w, 30d, or a git date)")
+	cmd.Flags().StringVar(&quietHours, "quiet-hours", "0-6", "Hours counted as odd, as start-end in the author's time zone")
+	cmd.Flags().StringSliceVar(&opts.sensitive, "sensitive-path", defaultSensitivePaths, "Path patterns whose changes during quiet hours are flagged")
+	cmd.Flags().StringSliceVar(&opts.auth, "auth-path", defaultAuthPaths, "Path patterns of auth code watched for first-time contributors")
+	cmd.Flags().StringSliceVar(&opts.vendor, "vendor-path", defaultVendorPaths, "Path patterns of vendored code")
+	cmd.Flags().IntVar(&opts.vendorLines, "vendor-lines", 2000, "Flag commits adding more than this many lines of vendored code")
+	cmd.Flags().BoolVar(&opts.noNarrative, "no-narrative", false, "Skip the AI context")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// anomaliesOptions holds the anomalies flags.
+type anomaliesOptions struct {
+	since       string
+	quietStart  int
+	quietEnd    int
+	sensitive   []string
+	auth        []string
+	vendor      []string
+	vendorLines int
+	noNarrative bool
+}
+
+// quiet reports whether hour falls in the quiet hours.
+func (o anomaliesOptions) quiet(hour int) bool {
+	if o.quietStart <= o.quietEnd {
+		return hour >= o.quietStart && hour < o.quietEnd
+	}
+	return hour >= o.quietStart || hour < o.quietEnd
+}
+
+// anomaly is one flagged event.
+type anomaly struct {
+	Kind    string   `json:"kind"`
+	Time    string   `json:"time"`
+	Commit  string   `json:"commit,omitempty"`
+	Ref     string   `json:"ref,omitempty"`
+	Author  string   `json:"author,omitempty"`
+	Reason  string   `json:"reason"`
+	Paths   []string `json:"paths,omitempty"`
+	Context string   `json:"context,omitempty"`
+
+	at      time.Time
+	change  *git.Change
+	dropped []git.Change
+	added   []git.Change
+}
+
+// runAnomalies implements the anomalies workflow.
+func runAnomalies(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, opts anomaliesOptions, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	cutoff, err := git.ParseDate(ctx, opts.since)
+	if err != nil {
+		return fmt.Errorf("failed to read --since: %w", err)
+	}
+
+	log.Info("Scanning history", "since", opts.since)
+	anomalies, err := commitAnomalies(ctx, opts)
+	if err != nil {
+		return err
+	}
+	pushes, err := forcePushes(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	anomalies = append(anomalies, pushes...)
+	sort.SliceStable(anomalies, func(i, j int) bool { return anomalies[i].at.After(anomalies[j].at) })
+	log.Info("Found anomalies", "count", len(anomalies))
+
+	if !opts.noNarrative && len(anomalies) > 0 {
+		service, err := newAIService(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		model := aiOpts.modelFor(ctx, prompt.AnomaliesModel)
+		if err := repoConfig(ctx).CheckAI("", model); err != nil {
+			return err
+		}
+		for i := range anomalies {
+			a := &anomalies[i]
+			subject := a.Commit
+			if a.Ref != "" {
+				subject = a.Ref
+			}
+			log.Info("Putting anomaly in context", "kind", a.Kind, "subject", subject)
+			systemPrompt, userPrompt := prompt.Anomaly(describeAnomaly(ctx, a))
+			text, err := runPrompt(withPlanSubject(ctx, subject), service, systemPrompt, userPrompt, model)
+			if errors.Is(err, ErrPlanned) {
+				continue
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return err
+				}
+				log.Warn("Failed to put anomaly in context", "kind", a.Kind, "subject", subject, "error", err)
+				continue
+			}
+			a.Context = strings.TrimSpace(text)
+		}
+		if planFor(ctx) != nil {
+			return nil
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"since":     opts.since,
+			"anomalies": anomalies,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		if len(anomalies) == 0 {
+			fmt.Printf("No anomalies found since %s.\n", opts.since)
+			return nil
+		}
+		for _, a := range anomalies {
+			subject := a.Commit
+			if a.Ref != "" {
+				subject = a.Ref + " " + a.Commit
+			}
+			fmt.Printf("%-18s %s  %s", a.Kind, subject, a.Time)
+			if a.Author != "" {
+				fmt.Printf("  %s", a.Author)
+			}
+			fmt.Println()
+			fmt.Printf("  %s\n", a.Reason)
+			if len(a.Paths) > 0 {
+				fmt.Printf("  Paths:   %s\n", strings.Join(a.Paths, ", "))
+			}
+			if a.Context != "" {
+				fmt.Printf("  Context: %s\n", a.Context)
+			}
+			fmt.Println()
+		}
+	}
+
+	return nil
+}
+
+// commitAnomalies flags the commits in the window made at odd hours to
+// sensitive paths, adding large amounts of vendored code, or touching auth
+// code for an author's first time.
+func commitAnomalies(ctx context.Context, opts anomaliesOptions) ([]anomaly, error) {
+	changes, err := git.LogChanges(ctx, "--no-merges", "--date=iso-strict", "--since", opts.since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	stats, err := git.LogNumStat(ctx, "--no-merges", "--since", opts.since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read line counts: %w", err)
+	}
+	earlier, err := git.Authors(ctx, "--until", opts.since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read earlier authors: %w", err)
+	}
+	known := make(map[string]bool, len(earlier))
+	for _, a := range earlier {
+		_, email := canonicalIdentity(ctx, a.MappedName, a.MappedEmail)
+		known[email] = true
+	}
+
+	var anomalies []anomaly
+	for i := range changes {
+		c := &changes[i]
+		// Dates are in the author's time zone, which is what odd hours are
+		// about.
+		local, err := time.Parse(time.RFC3339, c.Date)
+		if err != nil {
+			local = c.Time
+		}
+		flag := func(kind, reason string, paths []string) {
+			anomalies = append(anomalies, anomaly{
+				Kind:   kind,
+				Time:   local.Format("2006-01-02 15:04 -0700"),
+				Commit: c.Short(),
+				Author: c.Author,
+				Reason: reason,
+				Paths:  capPaths(paths),
+				at:     c.Time,
+				change: c,
+			})
+		}
+
+		if opts.quiet(local.Hour()) {
+			if paths := matchPaths(c.Files, opts.sensitive); len(paths) > 0 {
+				flag(anomalyOddHours, fmt.Sprintf("Committed at %s in the author's time zone, touching %d sensitive paths", local.Format("15:04"), len(paths)), paths)
+			}
+		}
+
+		var vendored []string
+		added := 0
+		for _, s := range stats[c.Hash] {
+			if len(matchPaths([]string{s.Path}, opts.vendor)) > 0 {
+				vendored = append(vendored, s.Path)
+				added += s.Added
+			}
+		}
+		if added > opts.vendorLines {
+			flag(anomalyVendored, fmt.Sprintf("Adds %d lines of vendored code in %d files", added, len(vendored)), vendored)
+		}
+
+		_, email := canonicalIdentity(ctx, c.Name, c.Email)
+		if !known[email] {
+			if paths := matchPaths(c.Files, opts.auth); len(paths) > 0 {
+				flag(anomalyFirstTimeAuth, fmt.Sprintf("Touches %d auth paths; the author has no commits before the window", len(paths)), paths)
+			}
+		}
+	}
+	return anomalies, nil
+}
+
+// forcePushes flags the rewrites of remote-tracking branches since cutoff
+// recorded in their reflogs.
+func forcePushes(ctx context.Context, cutoff time.Time) ([]anomaly, error) {
+	refs, err := git.Refs(ctx, "refs/remotes")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote-tracking branches: %w", err)
+	}
+
+	var anomalies []anomaly
+	for _, ref := range refs {
+		if strings.HasSuffix(ref, "/HEAD") {
+			continue
+		}
+		entries, err := git.Reflog(ctx, ref)
+		if err != nil {
+			logging.L().Debug("No reflog for ref", "ref", ref, "error", err)
+			continue
+		}
+		for _, e := range entries {
+			if e.Time.Before(cutoff) {
+				break
+			}
+			if !strings.Contains(e.Subject, "forced-update") || e.Old == "" {
+				continue
+			}
+			a := anomaly{
+				Kind:   anomalyForcePush,
+				Time:   e.Time.Format("2006-01-02 15:04 -0700"),
+				Commit: shortHash(e.New),
+				Ref:    strings.TrimPrefix(ref, "refs/remotes/"),
+				Reason: fmt.Sprintf("Rewritten from %s to %s", shortHash(e.Old), shortHash(e.New)),
+				at:     e.Time,
+			}
+			if git.Exists(ctx, e.Old) {
+				a.dropped, _ = git.LogChanges(ctx, "--no-merges", e.New+".."+e.Old)
+				a.added, _ = git.LogChanges(ctx, "--no-merges", e.Old+".."+e.New)
+				a.Reason += fmt.Sprintf(", dropping %d commits and adding %d", len(a.dropped), len(a.added))
+			}
+			anomalies = append(anomalies, a)
+		}
+	}
+	return anomalies, nil
+}
+
+// describeAnomaly renders an anomaly for the anomaly prompt.
+func describeAnomaly(ctx context.Context, a *anomaly) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Flag: %s\nReason: %s\n", a.Kind, a.Reason)
+	if len(a.Paths) > 0 {
+		fmt.Fprintf(&b, "Flagged paths: %s\n", strings.Join(a.Paths, ", "))
+	}
+	b.WriteString("\n")
+
+	describe := func(c git.Change) {
+		fmt.Fprintf(&b, "--- Commit %s by %s on %s\n", c.Short(), c.Author, c.Date)
+		fmt.Fprintf(&b, "Message: %s\n", c.Message)
+		if c.Body != "" {
+			fmt.Fprintf(&b, "%s\n", c.Body)
+		}
+		if len(c.Files) > 0 {
+			fmt.Fprintf(&b, "Files: %s\n", strings.Join(capPaths(c.Files), ", "))
+		}
+		if note, err := git.ShowNote(ctx, c.Hash, "ai"); err == nil {
+			fmt.Fprintf(&b, "Annotation: %s\n", parseNote(note).Text)
+		}
+		b.WriteString("\n")
+	}
+	list := func(label string, changes []git.Change) {
+		if len(changes) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "%s:\n", label)
+		for i, c := range changes {
+			if i == maxAnomalyPaths {
+				fmt.Fprintf(&b, "- ... and %d more\n", len(changes)-i)
+				break
+			}
+			fmt.Fprintf(&b, "- %s %s (%s)\n", c.Short(), c.Message, c.Author)
+		}
+		b.WriteString("\n")
+	}
+
+	if a.change != nil {
+		describe(*a.change)
+	}
+	list("Commits dropped by the rewrite", a.dropped)
+	list("Commits added by the rewrite", a.added)
+	return b.String()
+}
+
+// matchPaths returns the files with a path element matching one of
+// patterns, case-insensitively. Patterns containing a slash match the whole
+// path instead.
+func matchPaths(files, patterns []string) []string {
+	var matched []string
+	for _, f := range files {
+		lower := strings.ToLower(f)
+		elements := strings.Split(lower, "/")
+	patterns:
+		for _, p := range patterns {
+			p = strings.ToLower(p)
+			if strings.Contains(p, "/") {
+				if ok, _ := path.Match(p, lower); ok {
+					matched = append(matched, f)
+					break
+				}
+				continue
+			}
+			for _, e := range elements {
+				if ok, _ := path.Match(p, e); ok {
+					matched = append(matched, f)
+					break patterns
+				}
+			}
+		}
+	}
+	return matched
+}
+
+// capPaths returns at most maxAnomalyPaths of paths, noting how many were
+// left out.
+func capPaths(paths []string) []string {
+	if len(paths) <= maxAnomalyPaths {
+		return paths
+	}
+	return append(append([]string{}, paths[:maxAnomalyPaths]...), fmt.Sprintf("... and %d more", len(paths)-maxAnomalyPaths))
+}
+
+// parseQuietHours parses a start-end range of hours, which may wrap past
+// midnight.
+func parseQuietHours(s string) (start, end int, err error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid --quiet-hours %q", s)
+	}
+	start, errStart := strconv.Atoi(strings.TrimSpace(from))
+	end, errEnd := strconv.Atoi(strings.TrimSpace(to))
+	if errStart != nil || errEnd != nil || start < 0 || start > 23 || end < 0 || end > 24 || start == end {
+		return 0, 0, fmt.Errorf("invalid --quiet-hours %q", s)
+	}
+	return start, end, nil
+}
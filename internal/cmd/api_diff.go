@@ -0,0 +1,262 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/apidiff"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// newAPIDiffCmd creates the api-diff subcommand.
+func newAPIDiffCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		from        string
+		to          string
+		noNarrative bool
+		aiOpts      aiFlags
+		outputOpts  output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "api-diff",
+		Short: "Diff the exported Go API between two revisions and write a migration guide",
+		Long: `Compare the exported API of a Go module between two revisions.
+
+Both revisions are checked out into temporary worktrees and loaded with
+go/packages. Exported functions, types, methods, struct fields, interface
+methods, variables, and constants are compared; internal and main packages
+are ignored. Removed and changed symbols, and methods added to interfaces,
+are reported as breaking.
+
+Each change is attributed to the most likely commit that introduced it: the
+newest commit in the range touching the symbol's name in its package for
+removals and changes, and the oldest for additions. The AI then writes a
+migration guide for downstream consumers. Use --no-narrative to skip it.
+
+Module dependencies must be resolvable (from the module cache or proxy)
+for both revisions.`,
+		Example: `  # What does upgrading from v1.5.0 to the current tree involve?
+  arc-git api-diff --from v1.5.0 --to HEAD
+
+  # API changes only, as JSON
+  arc-git api-diff --from v1.5.0 --no-narrative --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if from == "" {
+				return errors.NewCLIError("--from is required").
+					WithHint("Pass the release to compare against, e.g. --from v1.5.0")
+			}
+
+			return runAPIDiff(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, from, to, noNarrative, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Older revision (e.g., v1.5.0)")
+	cmd.Flags().StringVar(&to, "to", "HEAD", "Newer revision")
+	cmd.Flags().BoolVar(&noNarrative, "no-narrative", false, "Skip the AI migration guide")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// apiChange is an API change with the commit it is attributed to.
+type apiChange struct {
+	apidiff.Change
+	Commit  string `json:"commit,omitempty"`
+	Subject string `json:"subject,omitempty"`
+}
+
+// runAPIDiff implements the api-diff workflow.
+func runAPIDiff(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, from, to string, noNarrative bool, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	module, err := git.Run(ctx, "show", to+":go.mod")
+	if err != nil {
+		return errors.NewCLIError(fmt.Sprintf("no go.mod at the root of %s", to)).
+			WithHint("api-diff compares Go modules; run it in a repository with go.mod at the top level")
+	}
+	modulePath := modulePathOf(module)
+
+	oldAPI, err := snapshotAt(ctx, from)
+	if err != nil {
+		return err
+	}
+	newAPI, err := snapshotAt(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	diff := apidiff.Diff(oldAPI, newAPI)
+	log.Info("Compared exported API", "from", from, "to", to, "changes", len(diff))
+
+	changes := make([]apiChange, 0, len(diff))
+	breaking := 0
+	for _, d := range diff {
+		c := apiChange{Change: d}
+		c.Commit, c.Subject = introducingCommit(ctx, from, to, d)
+		if d.Breaking {
+			breaking++
+		}
+		changes = append(changes, c)
+	}
+
+	var narrative string
+	if !noNarrative && len(changes) > 0 {
+		service, err := newAIService(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		log.Info("Generating migration guide", "breaking", breaking)
+		systemPrompt, userPrompt := prompt.APIDiff(modulePath, from, to, describeAPIChanges(changes))
+		narrative, err = runPrompt(ctx, service, systemPrompt, userPrompt, aiOpts.modelFor(ctx, prompt.APIDiffModel))
+		if err != nil {
+			return fmt.Errorf("failed to generate migration guide: %w", err)
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"module":    modulePath,
+			"from":      from,
+			"to":        to,
+			"breaking":  breaking,
+			"changes":   changes,
+			"narrative": narrative,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		if len(changes) == 0 {
+			fmt.Printf("No exported API changes between %s and %s.\n", from, to)
+			return nil
+		}
+		fmt.Printf("%d API changes between %s and %s (%d breaking)\n", len(changes), from, to, breaking)
+		pkg := ""
+		for _, c := range changes {
+			if c.Symbol.Package != pkg {
+				pkg = c.Symbol.Package
+				fmt.Printf("\n%s\n", pkg)
+			}
+			mark := map[string]string{apidiff.Added: "+", apidiff.Removed: "-", apidiff.Changed: "~"}[c.Kind]
+			if c.Breaking {
+				mark += "!"
+			}
+			fmt.Printf("  %-2s %s", mark, c.Symbol.Signature)
+			if c.Commit != "" {
+				fmt.Printf("  (%s %s)", c.Commit, c.Subject)
+			}
+			fmt.Println()
+			if c.Old != "" {
+				fmt.Printf("     was: %s\n", c.Old)
+			}
+		}
+		if narrative != "" {
+			fmt.Printf("\n%s\n", narrative)
+		}
+	}
+
+	return nil
+}
+
+// snapshotAt loads the exported API of rev from a temporary worktree.
+func snapshotAt(ctx context.Context, rev string) (apidiff.API, error) {
+	log := logging.L()
+
+	tmp, err := os.MkdirTemp("", "arc-git-api-")
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(tmp, "tree")
+	defer os.RemoveAll(tmp)
+
+	if err := git.AddWorktree(ctx, dir, rev); err != nil {
+		return nil, fmt.Errorf("failed to check out %s: %w", rev, err)
+	}
+	defer func() {
+		if err := git.RemoveWorktree(context.WithoutCancel(ctx), dir); err != nil {
+			log.Warn("Failed to remove worktree", "path", dir, "error", err)
+		}
+	}()
+
+	log.Info("Loading packages", "rev", rev)
+	api, warnings, err := apidiff.Snapshot(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", rev, err)
+	}
+	for _, w := range warnings {
+		log.Warn("Package error", "rev", rev, "error", w)
+	}
+	return api, nil
+}
+
+// introducingCommit returns the commit in from..to most likely to have made
+// change: the newest one touching the symbol's name in its package for
+// removals and changes, the oldest for additions.
+func introducingCommit(ctx context.Context, from, to string, change apidiff.Change) (hash, subject string) {
+	name := change.Symbol.Name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	pattern := `(^|[^A-Za-z0-9_])` + name + `([^A-Za-z0-9_]|$)`
+	path := change.Symbol.Dir
+	if path == "" {
+		path = "."
+	}
+
+	commits, err := git.Log(ctx, "-G", pattern, fmt.Sprintf("%s..%s", from, to), "--", path)
+	if err != nil || len(commits) == 0 {
+		return "", ""
+	}
+	c := commits[0]
+	if change.Kind == apidiff.Added {
+		c = commits[len(commits)-1]
+	}
+	return c.Short(), c.Message
+}
+
+// describeAPIChanges renders changes for the api-diff prompt.
+func describeAPIChanges(changes []apiChange) string {
+	var b strings.Builder
+	for _, c := range changes {
+		label := c.Kind
+		if c.Breaking {
+			label += ", breaking"
+		}
+		fmt.Fprintf(&b, "- [%s] %s: %s\n", label, c.Symbol.Package, c.Symbol.Signature)
+		if c.Old != "" {
+			fmt.Fprintf(&b, "  was: %s\n", c.Old)
+		}
+		if c.Commit != "" {
+			fmt.Fprintf(&b, "  commit: %s %s\n", c.Commit, c.Subject)
+		}
+	}
+	return b.String()
+}
+
+// modulePathOf returns the module path declared in a go.mod file.
+func modulePathOf(gomod string) string {
+	for _, line := range strings.Split(gomod, "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,365 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourorg/arc-git/internal/checkpoint"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/providers"
+	"github.com/yourorg/arc-sdk/ai"
+	clierrors "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// ErrQueued is returned by runPrompt under --batch-submit instead of sending
+// the request; the request is queued for the batch instead.
+var ErrQueued = errors.New("AI request queued for a batch")
+
+// batchKey carries the run's batch queue through the command context.
+type batchKey struct{}
+
+// batchCommitKey carries the full hash of the commit AI requests made with
+// the context are for.
+type batchCommitKey struct{}
+
+// runBatch collects the AI requests a --batch-submit run queues.
+type runBatch struct {
+	mu       sync.Mutex
+	requests []providers.BatchRequest
+	// commits maps request ids to the full hash of the commit they were
+	// queued for.
+	commits map[string]string
+}
+
+// withBatch returns ctx queueing AI requests on b.
+func withBatch(ctx context.Context, b *runBatch) context.Context {
+	return context.WithValue(ctx, batchKey{}, b)
+}
+
+// batchFor returns the run's batch queue, or nil when not batching.
+func batchFor(ctx context.Context) *runBatch {
+	b, _ := ctx.Value(batchKey{}).(*runBatch)
+	return b
+}
+
+// withBatchCommit returns ctx queueing AI requests for the commit hash.
+func withBatchCommit(ctx context.Context, hash string) context.Context {
+	return context.WithValue(ctx, batchCommitKey{}, hash)
+}
+
+// queue adds a request to the batch for the commit of ctx.
+func (b *runBatch) queue(ctx context.Context, system, user, model string) {
+	hash, _ := ctx.Value(batchCommitKey{}).(string)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.commits == nil {
+		b.commits = make(map[string]string)
+	}
+	id := fmt.Sprintf("r%d", len(b.requests))
+	b.requests = append(b.requests, providers.BatchRequest{ID: id, Model: model, System: system, Prompt: user})
+	b.commits[id] = hash
+}
+
+// newBatcher returns the batch client for provider, with the API key of cfg
+// when it is the configured provider and from the environment otherwise.
+func newBatcher(cfg *ai.Config, provider string) (providers.Batcher, error) {
+	if _, err := providers.NewBatcher(provider, ""); err != nil {
+		return nil, clierrors.NewCLIError(err.Error()).
+			WithHint("Batches are submitted with --provider anthropic; other providers can annotate without --batch-submit")
+	}
+	key := ""
+	if providers.Canonical(cfg.Provider) == providers.Canonical(provider) {
+		key = cfg.APIKey
+	}
+	if key == "" {
+		var variable string
+		if key, variable = providers.EnvKey(provider); key == "" {
+			return nil, clierrors.NewCLIError(fmt.Sprintf("no API key for %s", provider)).
+				WithHint("Pass --api-key or set " + variable)
+		}
+	}
+	return providers.NewBatcher(provider, key)
+}
+
+// batchPath returns the record file of batch id in the repository.
+func batchPath(ctx context.Context, id string) (string, error) {
+	gitDir, err := git.Dir(ctx)
+	if err != nil {
+		return "", err
+	}
+	return checkpoint.BatchPath(gitDir, id), nil
+}
+
+// submitAnnotateBatch submits the requests an annotate --batch-submit run
+// queued and records which commit each belongs to.
+func submitAnnotateBatch(ctx context.Context, cfg *ai.Config, batch *runBatch, results []annotationResult, pending int, model, tier, verify string, anchors, force bool, out output.OutputOptions) error {
+	log := logging.L()
+	if ctx.Err() != nil {
+		return fmt.Errorf("%w: batch not submitted", stopReason(ctx))
+	}
+
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.Status]++
+	}
+
+	id := ""
+	if len(batch.requests) > 0 {
+		batcher, err := newBatcher(cfg, cfg.Provider)
+		if err != nil {
+			return err
+		}
+		log.Info("Submitting batch", "provider", batcher.Name(), "requests", len(batch.requests))
+		if id, err = batcher.SubmitBatch(ctx, batch.requests); err != nil {
+			return fmt.Errorf("failed to submit batch: %w", err)
+		}
+
+		record := &checkpoint.Batch{
+			ID:          id,
+			Provider:    batcher.Name(),
+			Model:       model,
+			Tier:        tier,
+			Verify:      verify,
+			Anchors:     anchors,
+			Force:       force,
+			SubmittedAt: time.Now(),
+			Requests:    batch.commits,
+		}
+		path, err := batchPath(ctx, id)
+		if err == nil {
+			err = record.Save(path)
+		}
+		if err != nil {
+			// The batch runs regardless; without the record its results
+			// cannot be matched to commits.
+			return fmt.Errorf("batch %s submitted but not recorded: %w", id, err)
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"batch_id": id,
+			"queued":   counts["queued"],
+			"skipped":  counts["skipped"],
+			"failed":   counts["failed"],
+			"pending":  pending,
+			"tier":     tier,
+			"results":  results,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		fmt.Printf("\n=== Batch Submitted ===\n")
+		fmt.Printf("Queued: %d\n", counts["queued"])
+		fmt.Printf("Skipped: %d\n", counts["skipped"])
+		fmt.Printf("Failed: %d\n", counts["failed"])
+		if pending > 0 {
+			fmt.Printf("Pending: %d (token budget exhausted)\n", pending)
+		}
+		if id == "" {
+			fmt.Println("\nNothing to submit.")
+			return nil
+		}
+		fmt.Printf("\nBatch: %s\n", id)
+		fmt.Println("Batches usually finish within an hour and always within a day. Collect the notes with:")
+		fmt.Printf("  arc-git annotate --batch-collect %s\n", id)
+	}
+	return nil
+}
+
+// runAnnotateCollect writes the notes of a finished annotate batch.
+func runAnnotateCollect(ctx context.Context, cfg *ai.Config, id string, dryRun bool, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	if planFor(ctx) != nil {
+		// The requests were paid for at submission.
+		return nil
+	}
+
+	path, err := batchPath(ctx, id)
+	if err != nil {
+		return err
+	}
+	record, err := checkpoint.LoadBatch(path)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return clierrors.NewCLIError(fmt.Sprintf("no batch %q was submitted from this repository", id)).
+			WithHint("List submitted batches with: ls .git/arc-git/batches")
+	}
+
+	batcher, err := newBatcher(cfg, record.Provider)
+	if err != nil {
+		return err
+	}
+	state, err := batcher.GetBatch(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to read batch: %w", err)
+	}
+	if !state.Ended {
+		return clierrors.NewCLIError(fmt.Sprintf("batch %s is still processing (%d of %d requests done)", id, state.Succeeded+state.Errored+state.Expired, len(record.Requests))).
+			WithHint("Collect again later; submitted batches finish within 24 hours")
+	}
+	replies, err := batcher.BatchResults(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to read batch results: %w", err)
+	}
+	log.Info("Collecting batch", "batch", id, "results", len(replies))
+
+	var results []annotationResult
+	for _, reply := range replies {
+		hash := record.Requests[reply.ID]
+		if hash == "" {
+			log.Warn("Ignoring result for unknown request", "batch", id, "request", reply.ID)
+			continue
+		}
+		results = append(results, collectAnnotation(ctx, record, hash, reply, dryRun))
+	}
+
+	counts := make(map[string]int)
+	failures := failureTally{Counts: make(map[string]int)}
+	for _, r := range results {
+		counts[r.Status]++
+		if r.Status == "failed" {
+			failures.add(r.failure)
+		}
+		if len(r.Unverified) > 0 && r.Annotation != "" {
+			counts["low_confidence"]++
+		}
+	}
+	if !dryRun {
+		if counts["success"] > 0 {
+			mirrorAfterRun(ctx)
+		}
+		if err := os.Remove(path); err != nil {
+			log.Warn("Failed to remove batch record", "error", err)
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"batch_id":       id,
+			"total":          len(results),
+			"annotated":      counts["success"] + counts["preview"],
+			"skipped":        counts["skipped"],
+			"failed":         counts["failed"],
+			"failures":       failures.Counts,
+			"dry_run":        dryRun,
+			"tier":           record.Tier,
+			"low_confidence": counts["low_confidence"],
+			"results":        results,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress summary
+	default:
+		for _, r := range results {
+			if r.Status == "preview" {
+				fmt.Printf("\n--- Annotation for %s ---\n%s\n\n", r.Hash, r.Annotation)
+			}
+		}
+		fmt.Printf("\n=== Batch Collected ===\n")
+		fmt.Printf("Annotated: %d\n", counts["success"]+counts["preview"])
+		fmt.Printf("Skipped: %d\n", counts["skipped"])
+		fmt.Printf("Failed: %d\n", counts["failed"])
+		if counts["low_confidence"] > 0 {
+			fmt.Printf("Low confidence: %d (cite references that do not exist; see their Unverified trailers)\n", counts["low_confidence"])
+		}
+		failures.print()
+		if dryRun {
+			fmt.Println("\n(Dry run - no notes were added)")
+			fmt.Println("Run without --dry-run to save annotations")
+		} else {
+			fmt.Println("\nView annotations with: git log --show-notes=ai")
+		}
+	}
+	return nil
+}
+
+// collectAnnotation checks and, unless dryRun, stores the reply of a batch
+// for one commit, as annotateCommit does for replies it waited for.
+func collectAnnotation(ctx context.Context, record *checkpoint.Batch, hash string, reply providers.BatchResult, dryRun bool) annotationResult {
+	log := logging.L()
+	result := annotationResult{Hash: shortHash(hash)}
+
+	fail := func(message string, err error) annotationResult {
+		log.Warn("Failed to collect annotation", "commit", result.Hash, "error", err)
+		result.Status, result.Message = "failed", message
+		result.failure = classifyFailure(err)
+		return result
+	}
+	if reply.Error != "" {
+		err := fmt.Errorf("AI request failed: %s", reply.Error)
+		return fail(fmt.Sprintf("failed to generate annotation: %v", err), err)
+	}
+
+	// Notes may have been written while the batch ran.
+	if !record.Force {
+		if note, err := git.ShowNote(ctx, hash, "ai"); err == nil && (record.Tier == tierQuick || !isQuickNote(note)) {
+			result.Status, result.Message = "skipped", "already annotated"
+			return result
+		}
+	}
+
+	annotation := postProcess(ctx, strings.TrimSpace(reply.Text))
+	if record.Tier == tierQuick {
+		annotation += "\n\n" + quickTrailer
+	}
+	// Batch replies cannot be regenerated; unverified ones are marked.
+	// Quick replies are checked against the stats and hunk headers their
+	// prompt had, as annotateCommit does.
+	if record.Verify != citationsOff {
+		var evidence, hunks string
+		var err error
+		if record.Tier == tierQuick {
+			evidence, hunks, err = quickContext(ctx, hash)
+		} else {
+			evidence, err = git.Diff(ctx, hash)
+		}
+		if err != nil {
+			return fail(fmt.Sprintf("failed to get diff: %v", err), err)
+		}
+		evidence += hunks
+		annotation, result.Unverified = verifyCitations(ctx, citationsMark, hash, evidence, annotation, nil)
+	}
+	if record.Tier != tierQuick {
+		if err := checkStructured(ctx, annotation, nil); err != nil {
+			return fail(fmt.Sprintf("failed to generate annotation: %v", err), err)
+		}
+		if record.Anchors {
+			commits, err := git.Log(ctx, "--no-walk", hash)
+			if err == nil && len(commits) == 0 {
+				err = fmt.Errorf("commit %s not found", result.Hash)
+			}
+			if err != nil {
+				return fail(fmt.Sprintf("failed to read commit: %v", err), err)
+			}
+			annotation = checkAnchors(ctx, commits[0], annotation)
+		}
+	}
+	result.Annotation = annotation
+
+	if dryRun {
+		result.Status = "preview"
+		return result
+	}
+	if err := storeAnnotation(context.WithoutCancel(ctx), hash, annotation); err != nil {
+		result.Annotation = ""
+		return fail(fmt.Sprintf("failed to add note: %v", err), err)
+	}
+	result.Status = "success"
+	return result
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-git/internal/checkpoint"
+	"github.com/yourorg/arc-git/internal/providers"
+)
+
+func TestBatchQueueFullHashes(t *testing.T) {
+	// Two commits whose hashes share their abbreviation are still told
+	// apart.
+	hashes := []string{
+		"abc1234" + strings.Repeat("0", 33),
+		"abc1234" + strings.Repeat("1", 33),
+	}
+	var b runBatch
+	for _, hash := range hashes {
+		ctx := withBatchCommit(withPlanSubject(context.Background(), shortHash(hash)), hash)
+		b.queue(ctx, "system", "user "+hash, "model")
+	}
+	if len(b.requests) != 2 || b.commits[b.requests[0].ID] != hashes[0] || b.commits[b.requests[1].ID] != hashes[1] {
+		t.Errorf("queued requests %+v for commits %v, want one per full hash", b.requests, b.commits)
+	}
+}
+
+func TestCollectQuickAnnotationEvidence(t *testing.T) {
+	r := newTestRepo(t)
+	r.commit("Add helpers", map[string]string{
+		"helpers.go": "package helpers\n\nfunc keep() {}\n\nfunc oldHelper() {\n\treturn\n}\n",
+	})
+	hash := r.commit("Drop oldHelper", map[string]string{
+		"helpers.go": "package helpers\n\nfunc keep() {}\n",
+	})
+
+	// The deleted function is only in the full diff, which a quick prompt
+	// does not include; the file is in its stats.
+	reply := providers.BatchResult{ID: "r0", Text: "Removes `oldHelper()` from `helpers.go`."}
+	tests := []struct {
+		tier string
+		want []string
+	}{
+		{tier: tierQuick, want: []string{"oldHelper"}},
+		{tier: tierDeep, want: nil},
+	}
+	for _, tt := range tests {
+		record := &checkpoint.Batch{Tier: tt.tier, Verify: citationsMark, Requests: map[string]string{"r0": hash}}
+		result := collectAnnotation(r.ctx, record, hash, reply, true)
+		if result.Status != "preview" {
+			t.Fatalf("%s: collectAnnotation status %q: %s", tt.tier, result.Status, result.Message)
+		}
+		if !slices.Equal(result.Unverified, tt.want) {
+			t.Errorf("%s: Unverified = %q, want %q", tt.tier, result.Unverified, tt.want)
+		}
+	}
+}
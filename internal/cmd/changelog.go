@@ -0,0 +1,515 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/diffview"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// fragmentType is a kind of changelog fragment and the changelog heading its
+// entries are listed under.
+type fragmentType struct {
+	Name  string
+	Title string
+}
+
+// fragmentTypes are towncrier's default fragment types, in changelog order.
+var fragmentTypes = []fragmentType{
+	{"feature", "Features"},
+	{"bugfix", "Bug Fixes"},
+	{"deprecation", "Deprecations"},
+	{"removal", "Removals"},
+	{"doc", "Documentation"},
+	{"misc", "Miscellaneous"},
+}
+
+// defaultFragmentDir is where fragments are kept, relative to the work tree.
+const defaultFragmentDir = "changelog.d"
+
+// unsafeNameChars are replaced when a branch name becomes a fragment name.
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// newChangelogCmd creates the changelog command group.
+func newChangelogCmd(aiCfg *ai.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "changelog",
+		Short: "Maintain a fragment-based changelog",
+		Long: `Maintain a changelog from per-pull-request news fragments, in the style of
+towncrier.
+
+Each pull request adds a fragment file, <name>.<type>.md, to the fragment
+directory; at release time the fragments are compiled into a new changelog
+section and removed. Fragment types, in changelog order: ` + fragmentTypeNames() + `.
+
+See also arc-git release publish, which attaches the assembled section to the
+forge release.`,
+	}
+
+	cmd.AddCommand(
+		newChangelogFragmentCmd(aiCfg),
+		newChangelogAssembleCmd(),
+	)
+
+	return cmd
+}
+
+// fragmentOptions holds the flags of changelog fragment.
+type fragmentOptions struct {
+	from   string
+	to     string
+	name   string
+	kind   string
+	dir    string
+	dryRun bool
+	// diffFormat is how the branch's diff is shown to the AI.
+	diffFormat string
+}
+
+// newChangelogFragmentCmd creates the changelog fragment subcommand.
+func newChangelogFragmentCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts       fragmentOptions
+		aiOpts     aiFlags
+		diffOpts   diffFlags
+		prOpts     prFlags
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "fragment",
+		Short: "Write the changelog fragment of the current branch",
+		Long: `Write the changelog fragment of a branch from its commits and diff.
+
+The AI writes a user-facing entry of one to three sentences and picks the
+fragment type, unless --type is given. The fragment is named after --name,
+usually the pull request or issue number, or after the branch. Re-running the
+command as the branch evolves replaces the branch's fragment, including one
+of a different type. --diff-format sets how the diff is shown to the AI.
+
+With --pr, the branch is the pull request's, from its base to its head as
+the forge reports them, and the fragment is named after its number.`,
+		Example: `  # Fragment for the current branch against main
+  arc-git changelog fragment
+
+  # Named after pull request 512, as a bug fix
+  arc-git changelog fragment --name 512 --type bugfix
+
+  # From the pull request itself, e.g. in a PR-triggered job
+  arc-git changelog fragment --pr 512
+
+  # Preview without writing
+  arc-git changelog fragment --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if opts.kind != "" && !isFragmentType(opts.kind) {
+				return errors.NewCLIError(fmt.Sprintf("unknown fragment type %q", opts.kind)).
+					WithHint("Use one of " + fragmentTypeNames())
+			}
+			if err := prOpts.check("from", "to"); err != nil {
+				return err
+			}
+			var err error
+			if opts.diffFormat, err = diffOpts.resolve(cmd.Context()); err != nil {
+				return err
+			}
+			if prOpts.set() {
+				if opts.from, opts.to, err = prOpts.resolve(cmd.Context()); err != nil {
+					return err
+				}
+				if opts.name == "" {
+					opts.name = fmt.Sprint(prOpts.number)
+				}
+			}
+
+			return runChangelogFragment(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, opts, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.from, "from", "main", "Base the branch was started from")
+	cmd.Flags().StringVar(&opts.to, "to", "HEAD", "Tip of the branch")
+	prOpts.register(cmd)
+	cmd.Flags().StringVar(&opts.name, "name", "", "Fragment name, usually the pull request or issue number (default: the branch name)")
+	cmd.Flags().StringVar(&opts.kind, "type", "", "Fragment type (default: chosen by the AI): "+fragmentTypeNames())
+	cmd.Flags().StringVar(&opts.dir, "dir", defaultFragmentDir, "Fragment directory")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the fragment without writing it")
+	aiOpts.register(cmd)
+	diffOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// runChangelogFragment implements the changelog fragment workflow.
+func runChangelogFragment(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, opts fragmentOptions, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	name := opts.name
+	if name == "" {
+		branch, err := git.Run(ctx, "rev-parse", "--abbrev-ref", opts.to)
+		if err != nil {
+			return fmt.Errorf("unknown revision %q", opts.to)
+		}
+		if branch = strings.TrimSpace(branch); branch == "HEAD" {
+			return errors.NewCLIError("cannot name the fragment after a detached HEAD").
+				WithHint("Pass --name, e.g. the pull request number")
+		}
+		name = strings.Trim(unsafeNameChars.ReplaceAllString(branch, "-"), "-.")
+	}
+
+	rangeSpec := fmt.Sprintf("%s..%s", opts.from, opts.to)
+	commits, err := git.Log(ctx, "--no-merges", "--reverse", rangeSpec)
+	if err != nil {
+		return fmt.Errorf("failed to get commits: %w", err)
+	}
+	if len(commits) == 0 {
+		return errors.NewCLIError(fmt.Sprintf("no commits in %s", rangeSpec)).
+			WithHint("Check that --from names the base the branch was started from")
+	}
+
+	var list strings.Builder
+	for _, c := range commits {
+		body, _ := git.Body(ctx, c.Hash)
+		fmt.Fprintf(&list, "- %s %s\n", c.Short(), strings.ReplaceAll(body, "\n", "\n  "))
+		if note, err := git.ShowNote(ctx, c.Hash, "ai"); err == nil {
+			fmt.Fprintf(&list, "  Annotation: %s\n", parseNote(note).Text)
+		}
+	}
+	base, err := git.MergeBase(ctx, opts.from, opts.to)
+	if err != nil {
+		return fmt.Errorf("failed to find the base of %s: %w", rangeSpec, err)
+	}
+	diff, err := diffview.Between(ctx, opts.diffFormat, base, opts.to)
+	if err != nil {
+		return fmt.Errorf("failed to diff %s: %w", rangeSpec, err)
+	}
+	diff = guardDiff(ctx, opts.to, diff)
+
+	service, err := newAIService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Generating changelog fragment", "name", name, "commits", len(commits))
+	var types []string
+	for _, t := range fragmentTypes {
+		types = append(types, t.Name)
+	}
+	model := aiOpts.modelFor(ctx, prompt.ChangelogFragmentModel)
+	systemPrompt, userPrompt := prompt.ChangelogFragment(types, "", "")
+	fitted := fitPrompt(ctx, model,
+		prompt.Section{Name: prompt.SectionSystem, Text: systemPrompt + userPrompt, Required: true},
+		prompt.Section{Name: prompt.SectionMetadata, Text: list.String()},
+		prompt.Section{Name: prompt.SectionDiff, Text: diff, Trim: prompt.TrimDiff},
+	)
+	systemPrompt, userPrompt = prompt.ChangelogFragment(types, fitted[1], fitted[2])
+	reply, err := runPrompt(ctx, service, systemPrompt, userPrompt, model)
+	if err != nil {
+		return fmt.Errorf("failed to generate changelog fragment: %w", err)
+	}
+
+	note := parseNote(reply)
+	if note.Text == "" {
+		return fmt.Errorf("failed to generate changelog fragment: empty reply")
+	}
+	kind := opts.kind
+	if kind == "" {
+		kind = strings.ToLower(note.Trailers["type"])
+	}
+	if !isFragmentType(kind) {
+		log.Warn("No valid fragment type in the reply, using misc", "type", kind)
+		kind = "misc"
+	}
+	path := filepath.Join(opts.dir, name+"."+kind+".md")
+
+	var replaced []string
+	if !opts.dryRun {
+		// The branch's previous fragment may have had another type.
+		for _, t := range fragmentTypes {
+			old := filepath.Join(opts.dir, name+"."+t.Name+".md")
+			if old == path {
+				continue
+			}
+			if err := os.Remove(old); err == nil {
+				replaced = append(replaced, old)
+			}
+		}
+		if err := os.MkdirAll(opts.dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", opts.dir, err)
+		}
+		if err := os.WriteFile(path, []byte(note.Text+"\n"), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"path":     path,
+			"name":     name,
+			"type":     kind,
+			"text":     note.Text,
+			"replaced": replaced,
+			"dry_run":  opts.dryRun,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		fmt.Printf("%s\n\n%s\n", path, note.Text)
+		for _, old := range replaced {
+			fmt.Printf("\nRemoved %s\n", old)
+		}
+		if opts.dryRun {
+			fmt.Println("\n(Dry run - fragment not written)")
+		}
+	}
+
+	return nil
+}
+
+// assembleOptions holds the flags of changelog assemble.
+type assembleOptions struct {
+	version   string
+	date      string
+	dir       string
+	changelog string
+	keep      bool
+	dryRun    bool
+}
+
+// newChangelogAssembleCmd creates the changelog assemble subcommand.
+func newChangelogAssembleCmd() *cobra.Command {
+	var (
+		opts       assembleOptions
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "assemble",
+		Short: "Compile the changelog fragments into a release section",
+		Long: `Compile the changelog fragments into a section for a release and insert it
+above the newest section of the changelog.
+
+Entries are grouped under a heading per fragment type. Fragments named with
+a number are credited to that pull request or issue, as "(#512)". The
+fragments are removed afterwards, unless --keep is given; commit the
+changelog and the removals together.`,
+		Example: `  # Compile the fragments for 2.4.0 into CHANGELOG.md
+  arc-git changelog assemble --version 2.4.0
+
+  # Preview the section
+  arc-git changelog assemble --version 2.4.0 --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if opts.version == "" {
+				return errors.NewCLIError("--version is required").
+					WithHint("Pass the version being released, e.g. --version 2.4.0")
+			}
+
+			return runChangelogAssemble(opts, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.version, "version", "", "Version being released (e.g., 2.4.0)")
+	cmd.Flags().StringVar(&opts.date, "date", time.Now().Format(time.DateOnly), "Release date for the section heading")
+	cmd.Flags().StringVar(&opts.dir, "dir", defaultFragmentDir, "Fragment directory")
+	cmd.Flags().StringVar(&opts.changelog, "changelog", "CHANGELOG.md", "Changelog file to insert the section into")
+	cmd.Flags().BoolVar(&opts.keep, "keep", false, "Keep the fragments after assembling")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the section without writing anything")
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// fragment is a changelog fragment file.
+type fragment struct {
+	Path string `json:"path"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// runChangelogAssemble implements the changelog assemble workflow.
+func runChangelogAssemble(opts assembleOptions, out output.OutputOptions) error {
+	fragments, err := readFragments(opts.dir)
+	if err != nil {
+		return err
+	}
+	if len(fragments) == 0 {
+		return errors.NewCLIError(fmt.Sprintf("no changelog fragments in %s", opts.dir)).
+			WithHint("Add fragments with arc-git changelog fragment")
+	}
+	if changelogSection(opts.changelog, opts.version) != "" {
+		return errors.NewCLIError(fmt.Sprintf("%s already has a section for %s", opts.changelog, opts.version)).
+			WithHint("Remove that section, or pass the next version")
+	}
+
+	section := renderChangelogSection(opts.version, opts.date, fragments)
+	if !opts.dryRun {
+		if err := insertChangelogSection(opts.changelog, section); err != nil {
+			return err
+		}
+		if !opts.keep {
+			for _, f := range fragments {
+				if err := os.Remove(f.Path); err != nil {
+					return fmt.Errorf("failed to remove %s: %w", f.Path, err)
+				}
+			}
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"version":   opts.version,
+			"changelog": opts.changelog,
+			"section":   section,
+			"fragments": fragments,
+			"dry_run":   opts.dryRun,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		fmt.Print(section)
+		if opts.dryRun {
+			fmt.Println("\n(Dry run - changelog not changed)")
+		} else {
+			fmt.Printf("\nAdded %d entries to %s\n", len(fragments), opts.changelog)
+		}
+	}
+
+	return nil
+}
+
+// readFragments reads the fragments in dir, ignoring other files.
+func readFragments(dir string) ([]fragment, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var fragments []fragment
+	for _, e := range entries {
+		base := strings.TrimSuffix(e.Name(), ".md")
+		i := strings.LastIndex(base, ".")
+		if e.IsDir() || base == e.Name() || i <= 0 || !isFragmentType(base[i+1:]) {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if text := strings.TrimSpace(string(data)); text != "" {
+			fragments = append(fragments, fragment{Path: path, Name: base[:i], Type: base[i+1:], Text: text})
+		}
+	}
+	// Numbered fragments in pull request order, then the rest by name.
+	sort.SliceStable(fragments, func(i, j int) bool {
+		a, errA := strconv.Atoi(fragments[i].Name)
+		b, errB := strconv.Atoi(fragments[j].Name)
+		switch {
+		case errA == nil && errB == nil:
+			return a < b
+		case errA == nil || errB == nil:
+			return errA == nil
+		}
+		return fragments[i].Name < fragments[j].Name
+	})
+	return fragments, nil
+}
+
+// renderChangelogSection renders the changelog section for a release.
+func renderChangelogSection(version, date string, fragments []fragment) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s (%s)\n", version, date)
+	for _, t := range fragmentTypes {
+		first := true
+		for _, f := range fragments {
+			if f.Type != t.Name {
+				continue
+			}
+			if first {
+				fmt.Fprintf(&b, "\n### %s\n\n", t.Title)
+				first = false
+			}
+			entry := strings.ReplaceAll(f.Text, "\n", "\n  ")
+			if _, err := strconv.Atoi(f.Name); err == nil {
+				entry += " (#" + f.Name + ")"
+			}
+			fmt.Fprintf(&b, "- %s\n", entry)
+		}
+	}
+	return b.String()
+}
+
+// insertChangelogSection inserts section above the first release section of
+// the changelog at path, creating the file if needed.
+func insertChangelogSection(path, section string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return os.WriteFile(path, []byte("# Changelog\n\n"+section), 0o644)
+	}
+	if err != nil {
+		return err
+	}
+
+	content := string(data)
+	at := len(content)
+	if strings.HasPrefix(content, "## ") {
+		at = 0
+	} else if i := strings.Index(content, "\n## "); i >= 0 {
+		at = i + 1
+	} else if content != "" && !strings.HasSuffix(content, "\n\n") {
+		content = strings.TrimRight(content, "\n") + "\n\n"
+		at = len(content)
+	}
+	if at < len(content) {
+		section += "\n"
+	}
+	return os.WriteFile(path, []byte(content[:at]+section+content[at:]), 0o644)
+}
+
+// isFragmentType reports whether name is one of fragmentTypes.
+func isFragmentType(name string) bool {
+	for _, t := range fragmentTypes {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fragmentTypeNames lists the fragment types for help and hints.
+func fragmentTypeNames() string {
+	var names []string
+	for _, t := range fragmentTypes {
+		names = append(names, t.Name)
+	}
+	return strings.Join(names, ", ")
+}
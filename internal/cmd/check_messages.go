@@ -0,0 +1,370 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/diffview"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	clierrors "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// maxReviewDiff bounds the diff sent with each commit message review; the
+// message matters more than the last hunks.
+const maxReviewDiff = 60000
+
+var (
+	// conventionalSubject matches a conventional-commit subject, capturing
+	// its type.
+	conventionalSubject = regexp.MustCompile(`^(\w+)(?:\([^)]+\))?!?: \S`)
+	// wipSubject matches subjects of commits meant to be squashed away.
+	wipSubject = regexp.MustCompile(`(?i)^(?:fixup!|squash!|amend!|wip\b)`)
+)
+
+// defaultCommitTypes are the conventional-commit types accepted by default.
+var defaultCommitTypes = []string{"build", "chore", "ci", "docs", "feat", "fix", "perf", "refactor", "revert", "style", "test"}
+
+// checkMessagesOptions holds the check-messages flags.
+type checkMessagesOptions struct {
+	from         string
+	to           string
+	conventional bool
+	types        []string
+	maxSubject   int
+	noAI         bool
+	diffFormat   string
+}
+
+// newCheckMessagesCmd creates the check-messages subcommand.
+func newCheckMessagesCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts       checkMessagesOptions
+		aiOpts     aiFlags
+		diffOpts   diffFlags
+		prOpts     prFlags
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "check-messages",
+		Short: "Check the commit messages of a branch, for CI",
+		Long: `Check the commit messages of --from..--to, typically a pull request's
+commits, and exit non-zero when any of them needs work. --pr takes the
+range from a pull request on the forge instead.
+
+Every message is first checked against the conventions:
+- the subject is present, at most --max-subject characters, and does not
+  end with a period
+- a blank line separates the subject from the body
+- the commit is not a fixup!, squash!, amend!, or WIP commit
+- with --conventional, the subject is a conventional commit
+  ("type(scope): summary") of one of --types
+
+The AI then reads each message together with its diff and judges whether it
+actually describes the change, giving a verdict of good, vague, inaccurate,
+or incomplete. Commits that break a convention or are not judged good fail
+the check, with feedback and a suggested rewrite. A review that could not be
+completed is reported but does not fail the check. Use --no-ai for the
+convention checks alone. Merge commits are not checked. --diff-format sets
+how the diffs are shown to the AI.`,
+		Example: `  # Gate a pull request in CI
+  arc-git check-messages --from origin/main --to HEAD
+
+  # The same, with the range looked up from the pull request
+  arc-git check-messages --pr 512
+
+  # Require conventional commits too
+  arc-git check-messages --from origin/main --conventional
+
+  # Conventions only, without AI requests
+  arc-git check-messages --from origin/main --no-ai
+
+  # Machine-readable feedback for a bot comment
+  arc-git check-messages --from origin/main --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if opts.maxSubject <= 0 {
+				return clierrors.NewCLIError("--max-subject must be positive").
+					WithHint("Subjects are commonly limited to 50 or 72 characters")
+			}
+			if err := prOpts.check("from", "to"); err != nil {
+				return err
+			}
+			var err error
+			if opts.diffFormat, err = diffOpts.resolve(cmd.Context()); err != nil {
+				return err
+			}
+			if prOpts.set() {
+				if opts.from, opts.to, err = prOpts.resolve(cmd.Context()); err != nil {
+					return err
+				}
+			}
+			// A failed check is the command's result, not a usage mistake.
+			cmd.SilenceUsage = true
+
+			return runCheckMessages(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, opts, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.from, "from", "origin/main", "Base of the commits to check (e.g., the target branch)")
+	cmd.Flags().StringVar(&opts.to, "to", "HEAD", "End of the commits to check")
+	prOpts.register(cmd)
+	cmd.Flags().BoolVar(&opts.conventional, "conventional", false, "Require conventional-commit subjects")
+	cmd.Flags().StringSliceVar(&opts.types, "types", defaultCommitTypes, "Conventional-commit types accepted with --conventional")
+	cmd.Flags().IntVar(&opts.maxSubject, "max-subject", 72, "Longest accepted subject line")
+	cmd.Flags().BoolVar(&opts.noAI, "no-ai", false, "Only check the conventions, without AI review")
+	aiOpts.register(cmd)
+	diffOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// messageCheck is the outcome of checking one commit message.
+type messageCheck struct {
+	Hash     string   `json:"hash"`
+	Subject  string   `json:"subject"`
+	Status   string   `json:"status"`
+	Problems []string `json:"problems,omitempty"`
+	Verdict  string   `json:"verdict,omitempty"`
+	Feedback string   `json:"feedback,omitempty"`
+	// Suggested is the AI's rewrite of a message that fails the check.
+	Suggested string `json:"suggested,omitempty"`
+	// ReviewError is why the AI review could not be completed.
+	ReviewError string `json:"review_error,omitempty"`
+	failure
+}
+
+// runCheckMessages implements the check-messages workflow.
+func runCheckMessages(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, opts checkMessagesOptions, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	if planFor(ctx) != nil && opts.noAI {
+		// Nothing to send, and the checks are the output.
+		return nil
+	}
+
+	rangeSpec := fmt.Sprintf("%s..%s", opts.from, opts.to)
+	commits, err := git.Log(ctx, "--no-merges", "--reverse", rangeSpec)
+	if err != nil {
+		return fmt.Errorf("failed to get commits: %w", err)
+	}
+	log.Info("Checking commit messages", "range", rangeSpec, "commits", len(commits))
+
+	var (
+		service *ai.Service
+		model   = aiOpts.modelFor(ctx, prompt.CheckMessagesModel)
+	)
+	if !opts.noAI && len(commits) > 0 {
+		if service, err = newAIService(ctx, cfg); err != nil {
+			return err
+		}
+		if err := repoConfig(ctx).CheckAI("", model); err != nil {
+			return err
+		}
+	}
+
+	var checks []messageCheck
+	for _, c := range commits {
+		if ctx.Err() != nil {
+			return stopReason(ctx)
+		}
+		check, err := checkMessage(ctx, service, model, c, opts)
+		if err != nil {
+			return err
+		}
+		checks = append(checks, check)
+	}
+	if planFor(ctx) != nil {
+		return nil
+	}
+
+	failed := 0
+	for _, c := range checks {
+		if c.Status == checkFail {
+			failed++
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		if err := writeJSON(map[string]interface{}{
+			"from":    opts.from,
+			"to":      opts.to,
+			"total":   len(checks),
+			"failed":  failed,
+			"commits": checks,
+		}); err != nil {
+			return err
+		}
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: exit status only
+	default:
+		if len(checks) == 0 {
+			fmt.Printf("No commits in %s.\n", rangeSpec)
+			return nil
+		}
+		for _, c := range checks {
+			fmt.Printf("[%-4s] %s %s\n", c.Status, c.Hash, c.Subject)
+			for _, p := range c.Problems {
+				fmt.Printf("       %s\n", p)
+			}
+			if c.Feedback != "" && c.Status == checkFail {
+				fmt.Printf("       %s: %s\n", c.Verdict, c.Feedback)
+			}
+			if c.ReviewError != "" {
+				fmt.Printf("       review failed: %s\n", c.ReviewError)
+			}
+			if c.Suggested != "" {
+				fmt.Println("       suggested message:")
+				for _, line := range strings.Split(c.Suggested, "\n") {
+					fmt.Printf("         %s\n", line)
+				}
+			}
+		}
+		fmt.Printf("\n%d of %d commit messages passed\n", len(checks)-failed, len(checks))
+	}
+
+	if failed > 0 {
+		return clierrors.NewCLIError(fmt.Sprintf("%d of %d commit messages need work", failed, len(checks))).
+			WithHint("Reword them with git rebase -i " + opts.from + ", using the feedback above")
+	}
+	return nil
+}
+
+// checkMessage checks one commit's message against the conventions and, with
+// a service, has the AI review it. Only a cancelled run is an error; a
+// failed review is recorded on the check.
+func checkMessage(ctx context.Context, service *ai.Service, model string, c git.Commit, opts checkMessagesOptions) (messageCheck, error) {
+	log := logging.L()
+	check := messageCheck{Hash: c.Short(), Subject: c.Message, Status: checkOK}
+
+	message, err := git.Body(ctx, c.Hash)
+	if err != nil {
+		return check, fmt.Errorf("failed to read %s: %w", c.Short(), err)
+	}
+	// git joins a subject that runs into the body; show its first line.
+	check.Subject, _, _ = strings.Cut(message, "\n")
+	check.Problems = messageProblems(message, opts)
+
+	if !opts.noAI {
+		diff, err := diffview.Commit(ctx, opts.diffFormat, c.Hash)
+		if err != nil {
+			return check, fmt.Errorf("failed to read %s: %w", c.Short(), err)
+		}
+		diff = guardDiff(ctx, c.Hash, diff)
+		systemPrompt, userPrompt := prompt.CheckMessage(c.Short(), messageConventions(opts), "", "")
+		fitted := fitPrompt(ctx, model,
+			prompt.Section{Name: prompt.SectionSystem, Text: systemPrompt + userPrompt, Required: true},
+			prompt.Section{Name: prompt.SectionMetadata, Text: message},
+			prompt.Section{Name: prompt.SectionDiff, Text: prompt.TrimDiff(diff, maxReviewDiff), Trim: prompt.TrimDiff},
+		)
+		systemPrompt, userPrompt = prompt.CheckMessage(c.Short(), messageConventions(opts), fitted[1], fitted[2])
+
+		log.Info("Reviewing commit message", "commit", c.Short())
+		text, err := runPrompt(withPlanSubject(ctx, c.Short()), service, systemPrompt, userPrompt, model)
+		switch {
+		case errors.Is(err, ErrPlanned):
+			return check, nil
+		case ctx.Err() != nil:
+			return check, stopReason(ctx)
+		case err != nil:
+			log.Warn("Failed to review commit message", "commit", c.Short(), "error", err)
+			check.ReviewError = err.Error()
+			check.failure = classifyFailure(err)
+		default:
+			check.Verdict, check.Feedback, check.Suggested = parseMessageReview(text)
+			if check.Verdict == "" {
+				check.ReviewError = "the review had no verdict"
+			}
+		}
+	}
+
+	if len(check.Problems) > 0 || (check.Verdict != "" && check.Verdict != "good") {
+		check.Status = checkFail
+	} else {
+		check.Suggested = ""
+		if check.ReviewError != "" {
+			check.Status = checkWarn
+		}
+	}
+	return check, nil
+}
+
+// messageProblems returns the conventions message breaks.
+func messageProblems(message string, opts checkMessagesOptions) []string {
+	lines := strings.Split(message, "\n")
+	subject := strings.TrimSpace(lines[0])
+
+	var problems []string
+	if subject == "" {
+		return []string{"the subject is empty"}
+	}
+	if n := len([]rune(subject)); n > opts.maxSubject {
+		problems = append(problems, fmt.Sprintf("the subject is %d characters, over the limit of %d", n, opts.maxSubject))
+	}
+	if strings.HasSuffix(subject, ".") {
+		problems = append(problems, "the subject ends with a period")
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		problems = append(problems, "no blank line between the subject and the body")
+	}
+	if wipSubject.MatchString(subject) {
+		problems = append(problems, "the commit is a fixup or work in progress; squash it before merging")
+	} else if opts.conventional {
+		switch m := conventionalSubject.FindStringSubmatch(subject); {
+		case m == nil:
+			problems = append(problems, `the subject is not a conventional commit ("type(scope): summary")`)
+		case !slices.Contains(opts.types, m[1]):
+			problems = append(problems, fmt.Sprintf("the commit type %q is not one of %s", m[1], strings.Join(opts.types, ", ")))
+		}
+	}
+	return problems
+}
+
+// messageConventions describes the conventions for the review prompt.
+func messageConventions(opts checkMessagesOptions) string {
+	conventions := fmt.Sprintf("- Subject line of at most %d characters, imperative mood, no trailing period\n- Blank line after the subject; wrap the body at 72 characters\n", opts.maxSubject)
+	if opts.conventional {
+		conventions += fmt.Sprintf("- Conventional-commit subject: \"type(scope): summary\", with type one of %s\n", strings.Join(opts.types, ", "))
+	}
+	return conventions
+}
+
+// parseMessageReview splits a review into its verdict, feedback, and
+// suggested rewrite.
+func parseMessageReview(text string) (verdict, feedback, suggested string) {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if v, ok := strings.CutPrefix(trimmed, "Verdict:"); ok {
+			verdict = strings.ToLower(strings.Trim(strings.TrimSpace(v), ".*"))
+		} else if v, ok := strings.CutPrefix(trimmed, "Feedback:"); ok {
+			feedback = strings.TrimSpace(v)
+		} else if v, ok := strings.CutPrefix(trimmed, "Suggested:"); ok {
+			rest := append([]string{strings.TrimSpace(v)}, lines[i+1:]...)
+			suggested = strings.Trim(strings.TrimSpace(strings.Join(rest, "\n")), "`")
+			suggested = strings.TrimSpace(suggested)
+			break
+		}
+	}
+	if strings.EqualFold(feedback, "none") {
+		feedback = ""
+	}
+	return verdict, feedback, suggested
+}
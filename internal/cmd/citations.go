@@ -0,0 +1,244 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+)
+
+// What annotate does with annotations naming files, functions, or commits
+// that the commit and the repository do not have.
+const (
+	citationsMark       = "mark"
+	citationsRegenerate = "regenerate"
+	citationsOff        = "off"
+)
+
+// citationModes lists the --verify-citations values.
+var citationModes = []string{citationsMark, citationsRegenerate, citationsOff}
+
+// Trailers recording the references of an annotation that failed
+// verification.
+const (
+	confidenceTrailer = "Confidence: low"
+	unverifiedTrailer = "Unverified"
+)
+
+// maxCitations bounds the references checked per annotation.
+const maxCitations = 40
+
+var (
+	// codeSpanPattern matches Markdown code spans, which models use for the
+	// names they cite.
+	codeSpanPattern = regexp.MustCompile("`([^`\n]+)`")
+	// urlPattern matches URLs, whose parts are not references.
+	urlPattern = regexp.MustCompile(`[a-z][a-z0-9+.-]*://\S+`)
+	// callPattern matches function references written as calls, e.g.
+	// "parseNote()" or "git.Diff()".
+	callPattern = regexp.MustCompile(`\b([A-Za-z_]\w*(?:\.[A-Za-z_]\w*)*)\(\)`)
+	// citedPathPattern matches file names with a source file extension, with
+	// or without directories; citedFilePattern matches one as a whole.
+	citedPathPattern = regexp.MustCompile(`(?:^|[\s("'])((?:[\w.-]+/)*[\w-]+\.` + citedExtensions + `)\b`)
+	citedFilePattern = regexp.MustCompile(`^(?:[\w.-]+/)*[\w-]+\.` + citedExtensions + `$`)
+	// citedHashPattern matches abbreviated or full commit hashes.
+	citedHashPattern = regexp.MustCompile(`\b[0-9a-f]{7,40}\b`)
+	// identifierPattern matches a possibly qualified identifier, as a whole
+	// code span.
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)*(?:\(\))?$`)
+)
+
+// citedExtensions are the file extensions that make a word a file name.
+const citedExtensions = `(?:go|py|js|jsx|ts|tsx|rs|java|kt|rb|c|h|cc|cpp|hpp|cs|swift|php|sh|sql|proto|ya?ml|json|toml|md|html|css|mod|sum|lock|xml|gradle)`
+
+// citation is a reference an annotation makes to the repository.
+type citation struct {
+	kind string // "path", "symbol", or "commit"
+	text string
+}
+
+// extractCitations returns the file paths, function names, and commit hashes
+// that text mentions, in order and without repeats.
+func extractCitations(text string) []citation {
+	text = urlPattern.ReplaceAllString(text, " ")
+	var (
+		found []citation
+		seen  = make(map[string]bool)
+	)
+	add := func(kind, s string) {
+		s = strings.Trim(s, ".,;:")
+		if s == "" || seen[s] || len(found) == maxCitations {
+			return
+		}
+		seen[s] = true
+		found = append(found, citation{kind: kind, text: s})
+	}
+	classify := func(s string, span bool) {
+		switch {
+		case isCitedHash(s):
+			add("commit", s)
+		case citedFilePattern.MatchString(s):
+			// Product names like Node.js are not files of the repository.
+			if strings.Contains(s, "/") || !strings.HasSuffix(s, ".js") || !unicode.IsUpper(rune(s[0])) {
+				add("path", s)
+			}
+		case span && strings.Contains(s, "/") && !strings.ContainsAny(s, " \t"):
+			add("path", s)
+		case span && identifierPattern.MatchString(s) && (strings.ContainsAny(s, "._(") || hasInnerUpper(s)):
+			add("symbol", strings.TrimSuffix(s, "()"))
+		}
+	}
+
+	for _, m := range codeSpanPattern.FindAllStringSubmatch(text, -1) {
+		classify(strings.TrimSpace(m[1]), true)
+	}
+	prose := codeSpanPattern.ReplaceAllString(text, " ")
+	for _, m := range callPattern.FindAllStringSubmatch(prose, -1) {
+		add("symbol", m[1])
+	}
+	prose = callPattern.ReplaceAllString(prose, " ")
+	for _, m := range citedPathPattern.FindAllStringSubmatch(prose, -1) {
+		classify(m[1], false)
+	}
+	for _, h := range citedHashPattern.FindAllString(prose, -1) {
+		classify(h, false)
+	}
+	return found
+}
+
+// isCitedHash reports whether s looks like a commit hash rather than a
+// number or a word spelled with the letters a to f.
+func isCitedHash(s string) bool {
+	return citedHashPattern.FindString(s) == s && strings.ContainsAny(s, "0123456789") && strings.ContainsAny(s, "abcdef")
+}
+
+// hasInnerUpper reports whether s has an upper-case letter after its first
+// character, as camelCase and PascalCase names do.
+func hasInnerUpper(s string) bool {
+	for _, r := range s[1:] {
+		if r >= 'A' && r <= 'Z' {
+			return true
+		}
+	}
+	return false
+}
+
+// unverifiedCitations returns the references of annotation's text that
+// neither evidence (the diff, or the stats and hunk headers the annotation
+// was written from) nor the tree of hash contains, and the hashes that name
+// no commit.
+func unverifiedCitations(ctx context.Context, hash, evidence, annotation string) []string {
+	citations := extractCitations(parseNote(annotation).Text)
+	if len(citations) == 0 {
+		return nil
+	}
+	log := logging.L()
+
+	var files []string
+	if tree, err := git.Run(ctx, "ls-tree", "-r", "-z", "--full-tree", "--name-only", hash); err == nil {
+		files = strings.Split(strings.TrimSuffix(tree, "\x00"), "\x00")
+	} else {
+		log.Warn("Failed to list files, not verifying paths", "commit", shortHash(hash), "error", err)
+	}
+
+	var unverified []string
+	for _, c := range citations {
+		ok := true
+		switch c.kind {
+		case "commit":
+			ok = git.Exists(ctx, c.text)
+		case "path":
+			ok = strings.Contains(evidence, c.text) || files == nil || pathInTree(files, c.text)
+		case "symbol":
+			name := c.text[strings.LastIndex(c.text, ".")+1:]
+			ok = hasWord(evidence, name)
+			if !ok {
+				found, err := git.ContainsWord(ctx, hash, name)
+				ok = found || err != nil
+			}
+		}
+		if !ok {
+			unverified = append(unverified, c.text)
+		}
+	}
+	return unverified
+}
+
+// hasWord reports whether s contains word with no word character, as \w
+// matches, on either side of it.
+func hasWord(s, word string) bool {
+	if word == "" {
+		return false
+	}
+	for i := 0; ; {
+		j := strings.Index(s[i:], word)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(word)
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+// isWordRune reports whether r is a word character as \w matches it.
+func isWordRune(r rune) bool {
+	return r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+// pathInTree reports whether p names one of files, or the end of one of
+// their paths.
+func pathInTree(files []string, p string) bool {
+	p = strings.Trim(p, "/")
+	for _, f := range files {
+		if f == p || strings.HasSuffix(f, "/"+p) || strings.HasPrefix(f, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// markUnverified records in annotation's trailers that it is of low
+// confidence and which of its references could not be verified.
+func markUnverified(annotation string, unverified []string) string {
+	return addTrailers(annotation, confidenceTrailer, unverifiedTrailer+": "+strings.Join(unverified, ", "))
+}
+
+// verifyCitations checks the references of annotation for commit hash
+// against evidence and the repository. In regenerate mode, an annotation with
+// unverified references is asked for once more without them. Annotations
+// that still have some are marked with markUnverified; they are returned
+// with those references.
+func verifyCitations(ctx context.Context, mode, hash, evidence, annotation string, regenerate func(unverified []string) (string, error)) (string, []string) {
+	if mode == citationsOff {
+		return annotation, nil
+	}
+	log := logging.L()
+
+	unverified := unverifiedCitations(ctx, hash, evidence, annotation)
+	if len(unverified) > 0 && mode == citationsRegenerate && regenerate != nil {
+		log.Info("Regenerating annotation without unverified references", "commit", shortHash(hash), "references", strings.Join(unverified, ", "))
+		retry, err := regenerate(unverified)
+		if err != nil {
+			log.Warn("Failed to regenerate annotation, keeping the first draft", "commit", shortHash(hash), "error", err)
+		} else {
+			annotation, unverified = retry, unverifiedCitations(ctx, hash, evidence, retry)
+		}
+	}
+	if len(unverified) > 0 {
+		log.Warn("Annotation cites references that do not exist, marking it low-confidence", "commit", shortHash(hash), "references", strings.Join(unverified, ", "))
+		annotation = markUnverified(annotation, unverified)
+	}
+	return annotation, unverified
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExtractCitations(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []citation
+	}{
+		{
+			name: "code spans",
+			text: "Moves `parseNote` into `internal/cmd/note.go` and calls `git.Diff` from `checkAnchors()`.",
+			want: []citation{
+				{kind: "symbol", text: "parseNote"},
+				{kind: "path", text: "internal/cmd/note.go"},
+				{kind: "symbol", text: "git.Diff"},
+				{kind: "symbol", text: "checkAnchors"},
+			},
+		},
+		{
+			name: "prose",
+			text: "Splits main.go, adds runBatch.queue() and reverts 3f9c2ab1.",
+			want: []citation{
+				{kind: "symbol", text: "runBatch.queue"},
+				{kind: "path", text: "main.go"},
+				{kind: "commit", text: "3f9c2ab1"},
+			},
+		},
+		{
+			name: "repeats",
+			text: "Touches `main.go`, then main.go again, and main() twice: main().",
+			want: []citation{
+				{kind: "path", text: "main.go"},
+				{kind: "symbol", text: "main"},
+			},
+		},
+		{
+			// Words spelled with a to f and plain numbers are not hashes.
+			name: "hex words",
+			text: "Replaces the deadbeef and cafebabe markers, a decade of faded defaced code, in 12345678 places.",
+		},
+		{
+			name: "version strings",
+			text: "Upgrades to v1.2.3 and go1.22.4, tagged 2.0.0-rc1, and pins sha 1.2.3-beta.",
+		},
+		{
+			name: "product names",
+			text: "Targets Node.js and Vue.js; keeps `Next.js` out.",
+		},
+		{
+			name: "urls",
+			text: "See https://example.com/pkg/main.go and https://github.com/org/repo/commit/3f9c2ab1.",
+		},
+		{
+			name: "lower-case js files",
+			text: "Fixes app.js and web/Index.js.",
+			want: []citation{
+				{kind: "path", text: "app.js"},
+				{kind: "path", text: "web/Index.js"},
+			},
+		},
+		{
+			// Plain words in code spans are left alone.
+			name: "plain spans",
+			text: "Sets `timeout` to `30s` for `run the tests`.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractCitations(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractCitations(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractCitationsLimit(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 2*maxCitations; i++ {
+		b.WriteString("f" + strings.Repeat("x", i) + ".go ")
+	}
+	if got := extractCitations(b.String()); len(got) != maxCitations {
+		t.Errorf("extractCitations found %d citations, want the first %d", len(got), maxCitations)
+	}
+}
+
+func TestHasWord(t *testing.T) {
+	tests := []struct {
+		s, word string
+		want    bool
+	}{
+		{s: "func parse() {}", word: "parse", want: true},
+		{s: "parse", word: "parse", want: true},
+		{s: "x.parse(y)", word: "parse", want: true},
+		{s: "func parseNote() {}", word: "parse"},
+		{s: "func reparse() {}", word: "parse"},
+		{s: "my_parse", word: "parse"},
+		{s: "parse2", word: "parse"},
+		// A later occurrence counts when an earlier one is part of a word.
+		{s: "parseNote parse", word: "parse", want: true},
+		{s: "éparse", word: "parse", want: true},
+		{s: "parse", word: ""},
+	}
+	for _, tt := range tests {
+		if got := hasWord(tt.s, tt.word); got != tt.want {
+			t.Errorf("hasWord(%q, %q) = %v, want %v", tt.s, tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestUnverifiedCitations(t *testing.T) {
+	r := newTestRepo(t)
+	first := r.commit("Add the session store", map[string]string{
+		"internal/auth/session.go": "package auth\n\nfunc NewStore() *Store { return nil }\n",
+		"README.md":                "# Auth\n",
+	})
+	hash := r.commit("Expire sessions", map[string]string{
+		"internal/auth/expiry.go": "package auth\n\nfunc sweepExpired() {}\n",
+	})
+	evidence := r.git("show", "--format=", hash)
+
+	annotation := strings.Join([]string{
+		"Adds `sweepExpired()` to internal/auth/expiry.go, next to `NewStore` in session.go,",
+		"building on " + shortHash(first) + ". Documents it in README.md.",
+		"Also calls `auth.Rotate()`, edits `internal/auth/tokens.go` and reverts 0123abc.",
+		"",
+		"Risk: low",
+		"Labels: feature",
+		"Anchor: missing.go:1 | Trailers are not citations.",
+	}, "\n")
+
+	got := unverifiedCitations(r.ctx, hash, evidence, annotation)
+	want := []string{"auth.Rotate", "internal/auth/tokens.go", "0123abc"}
+	if !slices.Equal(got, want) {
+		t.Errorf("unverifiedCitations = %q, want %q", got, want)
+	}
+
+	if got := unverifiedCitations(r.ctx, hash, evidence, "Bumps the deadbeef marker to v1.2.3."); got != nil {
+		t.Errorf("unverifiedCitations of hash-like words = %q, want none", got)
+	}
+}
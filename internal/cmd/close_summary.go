@@ -0,0 +1,222 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/forge"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/ledger"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// maxCloseSummaryStat caps the per-commit diffstat included in the prompt.
+const maxCloseSummaryStat = 2000
+
+// newCloseSummaryCmd creates the close-summary subcommand.
+func newCloseSummaryCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		issue      int
+		post       bool
+		remote     string
+		aiOpts     aiFlags
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "close-summary",
+		Short: "Summarize how an issue was resolved from its commits",
+		Long: `Summarize how an issue was actually resolved, based on every commit that
+references it.
+
+Commits are collected across all local and remote-tracking branches by
+searching commit messages for the issue reference (#842, GH-842, or an
+issues/842 URL). Existing AI annotations on those commits are included as
+additional context.
+
+With --post, the summary is published as a comment on the issue through the
+forge provider detected from the remote URL. GitHub requires GITHUB_TOKEN
+or GH_TOKEN to be set.`,
+		Example: `  # Preview the closing summary for issue 842
+  arc-git close-summary --issue 842
+
+  # Post the summary as a comment on the issue
+  arc-git close-summary --issue 842 --post
+
+  # Emit structured JSON for automation
+  arc-git close-summary --issue 842 --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if issue <= 0 {
+				return errors.NewCLIError("--issue is required").
+					WithHint("Pass the issue number, e.g. --issue 842")
+			}
+
+			return runCloseSummary(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, issue, post, remote, outputOpts)
+		},
+	}
+
+	cmd.Flags().IntVar(&issue, "issue", 0, "Issue number to summarize")
+	cmd.Flags().BoolVar(&post, "post", false, "Post the summary as the issue's closing comment")
+	cmd.Flags().StringVar(&remote, "remote", "origin", "Remote used to detect the forge provider")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// issueCommit is a commit referencing the issue, as reported in JSON output.
+type issueCommit struct {
+	Hash     string   `json:"hash"`
+	Message  string   `json:"message"`
+	Author   string   `json:"author"`
+	Date     string   `json:"date"`
+	Branches []string `json:"branches,omitempty"`
+}
+
+// runCloseSummary implements the close-summary workflow.
+func runCloseSummary(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, issue int, post bool, remote string, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+	number := strconv.Itoa(issue)
+
+	commits, err := issueCommits(ctx, number)
+	if err != nil {
+		return fmt.Errorf("failed to find commits for issue #%d: %w", issue, err)
+	}
+	if len(commits) == 0 {
+		return errors.NewCLIError(fmt.Sprintf("no commits reference issue #%d", issue)).
+			WithHint("Commits are matched on #N, GH-N, or issues/N in the message; try git fetch --all first")
+	}
+
+	var (
+		described strings.Builder
+		found     []issueCommit
+	)
+	for _, c := range commits {
+		branches := containingBranches(ctx, c.Hash)
+		found = append(found, issueCommit{
+			Hash:     c.Short(),
+			Message:  c.Message,
+			Author:   c.Author,
+			Date:     c.Date,
+			Branches: branches,
+		})
+		described.WriteString(describeIssueCommit(ctx, c, branches))
+	}
+
+	log.Info("Found commits referencing issue", "issue", issue, "count", len(commits))
+
+	service, err := newAIService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Generating closing summary")
+	systemPrompt, userPrompt := prompt.CloseSummary(number, described.String())
+	summary, err := runPrompt(ctx, service, systemPrompt, userPrompt, aiOpts.modelFor(ctx, prompt.CloseSummaryModel))
+	if err != nil {
+		return fmt.Errorf("failed to generate summary: %w", err)
+	}
+
+	if post {
+		url, err := git.RemoteURL(ctx, remote)
+		if err != nil {
+			return fmt.Errorf("failed to resolve remote %q: %w", remote, err)
+		}
+		provider, err := forge.Detect(url)
+		if err != nil {
+			return errors.NewCLIError(fmt.Sprintf("cannot post summary: %v", err)).
+				WithHint("Run without --post to print the summary instead")
+		}
+		log.Info("Posting closing comment", "forge", provider.Name(), "issue", issue)
+		if err := provider.CommentIssue(ctx, issue, summary); err != nil {
+			return fmt.Errorf("failed to post summary: %w", err)
+		}
+		recordLedger(ctx, ledger.Entry{
+			Action: "close-summary",
+			Target: "#" + number,
+			Detail: map[string]string{"forge": provider.Name(), "commits": strconv.Itoa(len(commits))},
+		})
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"issue":   issue,
+			"commits": found,
+			"summary": summary,
+			"posted":  post,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		fmt.Printf("=== Issue #%d: %d commits ===\n", issue, len(commits))
+		for _, c := range found {
+			fmt.Printf("  %s %s\n", c.Hash, c.Message)
+		}
+		fmt.Printf("\n%s\n", summary)
+		if post {
+			fmt.Printf("\nPosted closing comment on issue #%d\n", issue)
+		}
+	}
+
+	return nil
+}
+
+// issueCommits returns every commit on any ref whose message references issue.
+func issueCommits(ctx context.Context, issue string) ([]git.Commit, error) {
+	pattern := fmt.Sprintf(`(#|GH-|issues/)%s([^0-9]|$)`, issue)
+	return git.Log(ctx, "--all", "--no-merges", "--reverse", "-i", "-E", "--grep", pattern)
+}
+
+// containingBranches lists the branches that contain a commit.
+func containingBranches(ctx context.Context, hash string) []string {
+	out, err := git.Run(ctx, "branch", "-a", "--format=%(refname:short)", "--contains", hash)
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(out)
+}
+
+// describeIssueCommit renders a commit for the close-summary prompt.
+func describeIssueCommit(ctx context.Context, c git.Commit, branches []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- Commit %s by %s on %s\n", c.Short(), c.Author, c.Date)
+	if len(branches) > 0 {
+		fmt.Fprintf(&b, "Branches: %s\n", strings.Join(branches, ", "))
+	}
+	if body, err := git.Body(ctx, c.Hash); err == nil {
+		fmt.Fprintf(&b, "Message:\n%s\n", body)
+	} else {
+		fmt.Fprintf(&b, "Message: %s\n", c.Message)
+	}
+	if stat, err := git.Run(ctx, "show", "--stat", "--format=", c.Hash); err == nil {
+		b.WriteString("Files:\n" + truncate(strings.TrimSpace(stat), maxCloseSummaryStat) + "\n")
+	}
+	if note, err := git.ShowNote(ctx, c.Hash, "ai"); err == nil {
+		fmt.Fprintf(&b, "Annotation: %s\n", note)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// truncate shortens s to at most n bytes, marking the cut.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "\n[truncated]"
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// Placeholders git format-patch --cover-letter leaves for the author.
+const (
+	subjectPlaceholder = "*** SUBJECT HERE ***"
+	blurbPlaceholder   = "*** BLURB HERE ***"
+)
+
+// Limits on the per-patch context and range-diff included in the prompt.
+const (
+	maxPatchStat = 1500
+	maxRangeDiff = 20000
+)
+
+// newCoverLetterCmd creates the cover-letter subcommand.
+func newCoverLetterCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		from       string
+		to         string
+		reroll     int
+		previous   string
+		outputDir  string
+		aiOpts     aiFlags
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cover-letter",
+		Short: "Generate a patch-series cover letter",
+		Long: `Generate a cover letter for a patch series sent with git send-email.
+
+The letter contains a series subject, a summary of the motivation and
+approach, and a one-line blurb for every patch. With --previous pointing at
+the previous version of the series, git range-diff is used to add a
+"Changes in vN" changelog describing what changed since that version.
+
+With --output-directory, git format-patch --cover-letter is run into that
+directory (unless a cover letter is already there) and the generated subject
+and blurb replace its placeholders, so the directory is ready for
+git send-email.`,
+		Example: `  # Print a cover letter for the commits on top of main
+  arc-git cover-letter --from main
+
+  # Second version of the series, with a changelog against v1
+  arc-git cover-letter --from main --reroll-count 2 --previous my-series-v1
+
+  # Write patches and the filled-in cover letter for send-email
+  arc-git cover-letter --from main --reroll-count 2 --previous my-series-v1 \
+    --output-directory outgoing/`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if from == "" {
+				return errors.NewCLIError("--from is required").
+					WithHint("Pass the upstream base of the series, e.g. --from main")
+			}
+
+			return runCoverLetter(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, from, to, reroll, previous, outputDir, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Upstream base of the series (e.g., main)")
+	cmd.Flags().StringVar(&to, "to", "HEAD", "Tip of the series")
+	cmd.Flags().IntVar(&reroll, "reroll-count", 1, "Version of the series (as for git format-patch -v)")
+	cmd.Flags().StringVar(&previous, "previous", "", "Tip of the previous version of the series, for the changelog")
+	cmd.Flags().StringVar(&outputDir, "output-directory", "", "Run format-patch into this directory and fill in its cover letter")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// runCoverLetter implements the cover-letter workflow.
+func runCoverLetter(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, from, to string, reroll int, previous, outputDir string, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	rangeSpec := fmt.Sprintf("%s..%s", from, to)
+	commits, err := git.Log(ctx, "--no-merges", "--reverse", rangeSpec)
+	if err != nil {
+		return fmt.Errorf("failed to get commits: %w", err)
+	}
+	if len(commits) == 0 {
+		return errors.NewCLIError(fmt.Sprintf("no commits in %s", rangeSpec)).
+			WithHint("Check that --from names the base the series was built on")
+	}
+
+	var patches strings.Builder
+	for i, c := range commits {
+		fmt.Fprintf(&patches, "--- Patch %d/%d: %s\n", i+1, len(commits), c.Message)
+		if body, err := git.Body(ctx, c.Hash); err == nil {
+			fmt.Fprintf(&patches, "%s\n", body)
+		}
+		if stat, err := git.Run(ctx, "show", "--stat", "--format=", c.Hash); err == nil {
+			fmt.Fprintf(&patches, "%s\n", truncate(strings.TrimSpace(stat), maxPatchStat))
+		}
+		patches.WriteString("\n")
+	}
+
+	var rangeDiff string
+	if previous != "" {
+		log.Info("Comparing against previous version", "previous", previous)
+		oldRange := fmt.Sprintf("%s..%s", from, previous)
+		rangeDiff, err = git.RangeDiff(ctx, oldRange, rangeSpec)
+		if err != nil {
+			return fmt.Errorf("failed to compare with %s: %w", previous, err)
+		}
+		if rangeDiff, err = guardRangeDiff(ctx, oldRange, rangeSpec, rangeDiff); err != nil {
+			return err
+		}
+		rangeDiff = truncate(rangeDiff, maxRangeDiff)
+	}
+
+	service, err := newAIService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	version := "v" + strconv.Itoa(reroll)
+	log.Info("Generating cover letter", "patches", len(commits), "version", version)
+	systemPrompt, userPrompt := prompt.CoverLetter(version, patches.String(), rangeDiff)
+	letter, err := runPrompt(ctx, service, systemPrompt, userPrompt, aiOpts.modelFor(ctx, prompt.CoverLetterModel))
+	if err != nil {
+		return fmt.Errorf("failed to generate cover letter: %w", err)
+	}
+	subject, blurb := splitCoverLetter(letter)
+
+	var written string
+	if outputDir != "" {
+		written, err = writeCoverLetter(ctx, outputDir, rangeSpec, reroll, subject, blurb)
+		if err != nil {
+			return err
+		}
+		log.Info("Wrote cover letter", "path", written)
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"range":   rangeSpec,
+			"version": version,
+			"patches": len(commits),
+			"subject": subject,
+			"body":    blurb,
+			"path":    written,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		if written != "" {
+			fmt.Printf("Cover letter written to %s\n", written)
+			return nil
+		}
+		fmt.Printf("Subject: [PATCH %s 0/%d] %s\n\n%s\n", version, len(commits), subject, blurb)
+	}
+
+	return nil
+}
+
+// splitCoverLetter separates the "Subject:" line from the body.
+func splitCoverLetter(letter string) (subject, body string) {
+	first, rest, _ := strings.Cut(letter, "\n")
+	if s, ok := strings.CutPrefix(strings.TrimSpace(first), "Subject:"); ok {
+		return strings.TrimSpace(s), strings.TrimSpace(rest)
+	}
+	return "", strings.TrimSpace(letter)
+}
+
+// writeCoverLetter fills in the format-patch cover letter in dir, running
+// format-patch first when no cover letter exists yet.
+func writeCoverLetter(ctx context.Context, dir, rangeSpec string, reroll int, subject, blurb string) (string, error) {
+	path, err := findCoverLetter(dir, reroll)
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		args := []string{"format-patch", "--cover-letter", "-o", dir}
+		if reroll > 1 {
+			args = append(args, "-v", strconv.Itoa(reroll))
+		}
+		if _, err := git.Run(ctx, append(args, rangeSpec)...); err != nil {
+			return "", fmt.Errorf("failed to run format-patch: %w", err)
+		}
+		if path, err = findCoverLetter(dir, reroll); err != nil || path == "" {
+			return "", fmt.Errorf("format-patch did not produce a cover letter in %s", dir)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read cover letter: %w", err)
+	}
+	text := string(data)
+	if !strings.Contains(text, subjectPlaceholder) && !strings.Contains(text, blurbPlaceholder) {
+		return "", errors.NewCLIError(fmt.Sprintf("%s has already been edited", path)).
+			WithHint("Remove it to regenerate, or edit it by hand")
+	}
+	if subject != "" {
+		text = strings.Replace(text, subjectPlaceholder, subject, 1)
+	}
+	text = strings.Replace(text, blurbPlaceholder, blurb, 1)
+
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write cover letter: %w", err)
+	}
+	return path, nil
+}
+
+// findCoverLetter returns the format-patch cover letter of version reroll
+// of the series in dir, if any. Versions after the first are prefixed with
+// their number, as format-patch -v names them.
+func findCoverLetter(dir string, reroll int) (string, error) {
+	name := "0000-cover-letter.patch"
+	if reroll > 1 {
+		name = fmt.Sprintf("v%d-%s", reroll, name)
+	}
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteCoverLetterReroll(t *testing.T) {
+	r := newTestRepo(t)
+	base := r.commit("Start", map[string]string{"a.txt": "a\n"})
+	r.commit("Change a", map[string]string{"a.txt": "b\n"})
+	dir := t.TempDir()
+
+	first, err := writeCoverLetter(r.ctx, dir, base+"..HEAD", 1, "First version", "The first blurb.")
+	if err != nil {
+		t.Fatalf("writeCoverLetter v1: %v", err)
+	}
+	if filepath.Base(first) != "0000-cover-letter.patch" {
+		t.Errorf("v1 cover letter = %s", first)
+	}
+
+	// The edited first version does not stand in for the second.
+	second, err := writeCoverLetter(r.ctx, dir, base+"..HEAD", 2, "Second version", "The second blurb.")
+	if err != nil {
+		t.Fatalf("writeCoverLetter v2 next to an edited v1: %v", err)
+	}
+	if filepath.Base(second) != "v2-0000-cover-letter.patch" {
+		t.Errorf("v2 cover letter = %s", second)
+	}
+	data, err := os.ReadFile(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Second version") || !strings.Contains(string(data), "The second blurb.") {
+		t.Errorf("v2 cover letter was not filled in:\n%s", data)
+	}
+
+	if _, err := writeCoverLetter(r.ctx, dir, base+"..HEAD", 2, "Again", "Again."); err == nil || !strings.Contains(err.Error(), "already been edited") {
+		t.Errorf("writeCoverLetter over an edited v2: error = %v, want it refused", err)
+	}
+}
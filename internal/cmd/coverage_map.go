@@ -0,0 +1,184 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/yourorg/arc-git/internal/git"
+)
+
+// coveredFile is the part of a file one test covers. No lines means the
+// whole file.
+type coveredFile struct {
+	path  string
+	lines []git.LineRange
+}
+
+// matches reports whether the covered file is p. Coverage tools record
+// module import paths or absolute paths, so p matches their end.
+func (f coveredFile) matches(p string) bool {
+	return f.path == p || strings.HasSuffix(f.path, "/"+p)
+}
+
+// overlaps reports whether the covered lines include a line either side of
+// c's hunks touches. Changes without hunks, such as renames, overlap any
+// coverage of the file.
+func (f coveredFile) overlaps(c git.FileChange) bool {
+	if len(f.lines) == 0 || len(c.Old)+len(c.New) == 0 {
+		return true
+	}
+	for _, covered := range f.lines {
+		for _, changed := range append(append([]git.LineRange{}, c.Old...), c.New...) {
+			if covered.Start <= changed.End && changed.Start <= covered.End {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadCoverageMap reads a per-test coverage map from a JSON file mapping test
+// names to "path" or "path:start-end" entries, or from a directory of Go
+// coverprofiles or LCOV files, one per test and named after it.
+func loadCoverageMap(name string) (map[string][]coveredFile, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return readCoverageJSON(name)
+	}
+
+	entries, err := os.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+	coverage := make(map[string][]coveredFile)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(name, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		test := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+		text := string(data)
+		switch {
+		case strings.HasPrefix(text, "mode:"):
+			coverage[test] = parseCoverprofile(text)
+		case strings.Contains(text, "SF:"):
+			coverage[test] = parseLCOV(text)
+		default:
+			return nil, fmt.Errorf("%s is neither a Go coverprofile nor an LCOV file", e.Name())
+		}
+	}
+	return coverage, nil
+}
+
+// readCoverageJSON reads a coverage map from a JSON file.
+func readCoverageJSON(name string) (map[string][]coveredFile, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string][]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("not a JSON object of test names to covered files: %w", err)
+	}
+	coverage := make(map[string][]coveredFile, len(raw))
+	for test, entries := range raw {
+		for _, entry := range entries {
+			f := coveredFile{path: entry}
+			if p, lines, ok := strings.Cut(entry, ":"); ok {
+				start, end, _ := strings.Cut(lines, "-")
+				s, err := strconv.Atoi(start)
+				if err != nil {
+					return nil, fmt.Errorf("test %s: invalid line range in %q", test, entry)
+				}
+				e := s
+				if end != "" {
+					if e, err = strconv.Atoi(end); err != nil {
+						return nil, fmt.Errorf("test %s: invalid line range in %q", test, entry)
+					}
+				}
+				f = coveredFile{path: p, lines: []git.LineRange{{Start: s, End: e}}}
+			}
+			coverage[test] = append(coverage[test], f)
+		}
+	}
+	return coverage, nil
+}
+
+// parseCoverprofile returns the covered blocks of a Go coverprofile, whose
+// lines read "file:startLine.col,endLine.col statements count".
+func parseCoverprofile(text string) []coveredFile {
+	byPath := make(map[string]int)
+	var files []coveredFile
+	for _, line := range strings.Split(text, "\n")[1:] {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[2] == "0" {
+			continue
+		}
+		file, block, ok := strings.Cut(fields[0], ":")
+		if !ok {
+			continue
+		}
+		from, to, _ := strings.Cut(block, ",")
+		start, err1 := strconv.Atoi(strings.Split(from, ".")[0])
+		end, err2 := strconv.Atoi(strings.Split(to, ".")[0])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		i, ok := byPath[file]
+		if !ok {
+			i = len(files)
+			byPath[file] = i
+			files = append(files, coveredFile{path: file})
+		}
+		files[i].lines = append(files[i].lines, git.LineRange{Start: start, End: end})
+	}
+	return files
+}
+
+// parseLCOV returns the covered lines of an LCOV tracefile, from its SF
+// (source file) and DA (line, hit count) records.
+func parseLCOV(text string) []coveredFile {
+	var (
+		files []coveredFile
+		cur   *coveredFile
+	)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			files = append(files, coveredFile{path: filepath.ToSlash(line[3:])})
+			cur = &files[len(files)-1]
+		case cur != nil && strings.HasPrefix(line, "DA:"):
+			fields := strings.Split(line[3:], ",")
+			if len(fields) < 2 || fields[1] == "0" {
+				continue
+			}
+			if n, err := strconv.Atoi(fields[0]); err == nil {
+				cur.lines = append(cur.lines, git.LineRange{Start: n, End: n})
+			}
+		case line == "end_of_record":
+			cur = nil
+		}
+	}
+	// A file without covered lines would otherwise count as wholly covered.
+	kept := files[:0]
+	for _, f := range files {
+		if len(f.lines) > 0 {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
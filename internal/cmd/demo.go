@@ -0,0 +1,222 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/config"
+	"github.com/yourorg/arc-git/internal/demo"
+	"github.com/yourorg/arc-git/internal/diffview"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-sdk/ai"
+	clierrors "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+	"golang.org/x/term"
+)
+
+// demoOptions holds the flags of the demo command.
+type demoOptions struct {
+	dir  string
+	keep bool
+	yes  bool
+}
+
+// newDemoCmd creates the demo subcommand.
+func newDemoCmd() *cobra.Command {
+	var opts demoOptions
+
+	cmd := &cobra.Command{
+		Use:   "demo",
+		Short: "Tour arc-git on a sample repository, without an API key",
+		Long: `Build a small sample repository, a Go rate limiter with a short history,
+annotate it, and walk through viewing and searching the annotations:
+
+1. the sample history
+2. annotating it, as arc-git annotate does
+3. the annotations inline in the history, as arc-git log shows them
+4. filtering them by label and risk
+5. the one-line summaries, through plain git log
+6. searching the annotations
+7. asking why a line looks the way it does, as arc-git why does
+
+Nothing is sent anywhere: the annotations are recorded replies of a model,
+replayed for the sample's commits, so the tour needs no API key, network, or
+configuration, and leaves the current repository alone. Each step prints the
+command it stands for, to try on a repository of your own after arc-git init.
+
+The sample repository is built in a temporary directory that is removed at
+the end, unless --keep is given; --dir builds it at a path of your choosing,
+which is kept. Between steps the tour waits for Enter, unless --yes is given
+or stdin is not a terminal.`,
+		Example: `  # Take the tour
+  arc-git demo
+
+  # Print the whole tour at once
+  arc-git demo --yes
+
+  # Keep the sample repository to explore it further
+  arc-git demo --dir /tmp/ratelimit`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return runDemo(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.dir, "dir", "", "Build the sample repository here and keep it (must not exist or be empty)")
+	cmd.Flags().BoolVar(&opts.keep, "keep", false, "Keep the temporary sample repository after the tour")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Run every step without waiting for Enter")
+
+	return cmd
+}
+
+// demoStep is one step of the tour.
+type demoStep struct {
+	title   string
+	command string
+	run     func() error
+}
+
+// runDemo implements the demo workflow.
+func runDemo(ctx context.Context, opts demoOptions) error {
+	if planFor(ctx) != nil {
+		// Nothing here is worth planning: the tour sends no requests.
+		return nil
+	}
+
+	dir, temporary := opts.dir, opts.dir == ""
+	if temporary {
+		tmp, err := os.MkdirTemp("", "arc-git-demo-")
+		if err != nil {
+			return fmt.Errorf("failed to create a temporary directory: %w", err)
+		}
+		dir = tmp
+		if !opts.keep {
+			defer os.RemoveAll(tmp)
+		}
+	}
+	if _, err := demo.Build(ctx, dir); err != nil {
+		return clierrors.NewCLIError(fmt.Sprintf("failed to build the sample repository: %v", err)).
+			WithHint("Pass --dir with a path that does not exist yet, or leave it out")
+	}
+
+	// The tour runs in the sample repository, with no config of its own and
+	// the recorded replies in place of a provider.
+	ctx = git.WithDir(ctx, dir)
+	ctx = withRepoConfig(ctx, &config.Config{})
+	ctx = withReplay(ctx, demo.Replies())
+	logging.SuppressProgress()
+
+	// The zero value of output options renders tables.
+	var table output.OutputOptions
+	file, line := demo.Spotlight()
+	since := demo.Commits()
+
+	steps := []demoStep{
+		{
+			title:   fmt.Sprintf("The sample repository: a rate limiter with %d commits", since),
+			command: "git log --oneline",
+			run: func() error {
+				return printGit(ctx, "log", "--format=%h %ad %s  (%aN)", "--date=short")
+			},
+		},
+		{
+			title:   "Annotate the history (with recorded replies instead of a model)",
+			command: fmt.Sprintf("arc-git annotate --since %d", since),
+			run: func() error {
+				return runAnnotate(ctx, &ai.Config{}, &aiFlags{}, since, "", "HEAD", tierDeep, citationsMark, diffview.Unified, commitSample{}, false, false, false, false, false, false, table)
+			},
+		},
+		{
+			title:   "Read the history with its annotations",
+			command: "arc-git log",
+			run: func() error {
+				return runLog(ctx, nil, nil, nil, 0, 200, table)
+			},
+		},
+		{
+			title:   "Filter on the Risk and Labels trailers: risky bug fixes",
+			command: "arc-git log --label bugfix --risk '>=medium'",
+			run: func() error {
+				risk, err := parseRiskFilter(">=medium")
+				if err != nil {
+					return err
+				}
+				return runLog(ctx, nil, []string{"bugfix"}, risk, 0, 200, table)
+			},
+		},
+		{
+			title:   "One-line summaries, stored under refs/notes/ai-summary for plain git",
+			command: "git log --notes=ai-summary --oneline",
+			run: func() error {
+				return printGit(ctx, "log", "--notes="+summaryRef, "--oneline")
+			},
+		},
+		{
+			title:   "Search the annotations: git log --grep matches notes too",
+			command: "arc-git log -- -i --grep=goroutine",
+			run: func() error {
+				return runLog(ctx, []string{"-i", "--grep=goroutine"}, nil, nil, 0, 200, table)
+			},
+		},
+		{
+			title:   "Ask why a line exists",
+			command: fmt.Sprintf("arc-git why %s %d", file, line),
+			run: func() error {
+				return runWhy(ctx, file, line, "", table)
+			},
+		},
+	}
+
+	// Without a terminal there is no one to press Enter; stdin is left unread.
+	w := newWizard(ctx, os.Stdin, os.Stderr, opts.yes || !term.IsTerminal(int(os.Stdin.Fd())))
+	for i, s := range steps {
+		if i > 0 {
+			w.pause()
+			if err := w.stopped(); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("\n==> [%d/%d] %s\n$ %s\n\n", i+1, len(steps), s.title, s.command)
+		if err := s.run(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("\n==> Next steps")
+	if temporary && !opts.keep {
+		fmt.Println("The sample repository is removed now; run with --keep to explore it further.")
+	} else {
+		fmt.Printf("The sample repository is at %s\n", dir)
+	}
+	fmt.Println("To annotate a repository of your own, run arc-git init inside it to set up")
+	fmt.Println("an AI provider, then arc-git annotate.")
+	return nil
+}
+
+// printGit runs git with args and prints its output.
+func printGit(ctx context.Context, args ...string) error {
+	out, err := git.Run(ctx, args...)
+	if err != nil {
+		return err
+	}
+	fmt.Println(strings.TrimRight(out, "\n"))
+	return nil
+}
+
+// pause waits for Enter between the steps of arc-git demo, when stdin is a
+// terminal and the defaults are not taken.
+func (w *wizard) pause() {
+	if w.yes || !w.terminal || w.err != nil {
+		return
+	}
+	fmt.Fprint(w.out, "\nPress Enter to continue...")
+	w.read()
+}
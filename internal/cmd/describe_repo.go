@@ -0,0 +1,422 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	clierrors "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// Limits on the facts sent for a repository overview.
+const (
+	maxRepoModules    = 40
+	maxModuleSamples  = 6
+	maxRepoKeyCommits = 12
+	maxRepoNotes      = 15
+	maxRepoChanges    = 200
+	maxRepoReadme     = 4000
+	maxRepoManifest   = 1500
+	maxRepoNote       = 400
+)
+
+// describeRepoMarker ends every overview document, recording the commit it
+// describes so that later runs only need the history since.
+var describeRepoMarker = regexp.MustCompile(`<!-- arc-git describe-repo: ([0-9a-f]{7,64}) -->\s*$`)
+
+// repoManifests are the build manifests whose heads are sent as facts.
+var repoManifests = []string{"go.mod", "package.json", "Cargo.toml", "pyproject.toml", "setup.py", "pom.xml", "build.gradle", "Gemfile", "composer.json"}
+
+// entryPointPattern matches files a reader would start from.
+var entryPointPattern = regexp.MustCompile(`(^|/)(main\.(go|rs|py|c|cc|cpp|java|kt|swift)|__main__\.py|index\.(js|mjs|ts)|server\.(js|ts|py|go)|app\.(js|ts|py)|manage\.py|Dockerfile|Makefile|Procfile|docker-compose\.ya?ml)$`)
+
+// newDescribeRepoCmd creates the describe-repo subcommand.
+func newDescribeRepoCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts       describeRepoOptions
+		aiOpts     aiFlags
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "describe-repo",
+		Short: "Generate an architecture overview document for the repository",
+		Long: `Generate an overview document describing the repository's purpose, modules
+and their responsibilities, entry points, and evolution.
+
+The AI is given a sample of the tree at --rev (directories at --depth with
+their languages and example files), likely entry points, the README and
+build manifests, and the history of the last --since: the most active
+modules, the largest commits, and existing AI annotations.
+
+The document ends with a marker recording the commit it describes. When
+--out already holds such a document, it is refreshed incrementally: only the
+commits made since are sent, and the AI updates the parts they affect while
+keeping the rest, including manual edits. Pass --full to regenerate it.`,
+		Example: `  # Print an overview of the repository
+  arc-git describe-repo
+
+  # Write it into the docs, and keep it current from CI
+  arc-git describe-repo --out docs/OVERVIEW.md
+
+  # Regenerate from scratch with a deeper module breakdown
+  arc-git describe-repo --out docs/OVERVIEW.md --full --depth 3`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if opts.depth < 1 {
+				return clierrors.NewCLIError("--depth must be at least 1").
+					WithHint("Use --depth 1 for top-level directories only")
+			}
+			opts.since = gitSince(opts.since)
+
+			return runDescribeRepo(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, opts, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.rev, "rev", "HEAD", "Revision to describe")
+	cmd.Flags().IntVar(&opts.depth, "depth", 2, "Directory depth at which modules are listed")
+	cmd.Flags().StringVar(&opts.since, "since", "1y", "History to sample (1y, 6m, 2w, 30d, or a git date)")
+	cmd.Flags().StringVar(&opts.outFile, "out", "", "Write the document to this file, refreshing it if it exists (e.g., docs/OVERVIEW.md)")
+	cmd.Flags().BoolVar(&opts.full, "full", false, "Regenerate the document instead of refreshing it")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// describeRepoOptions holds the describe-repo flags.
+type describeRepoOptions struct {
+	rev     string
+	depth   int
+	since   string
+	outFile string
+	full    bool
+}
+
+// repoModule is a directory of the tree listed in the facts.
+type repoModule struct {
+	Path      string   `json:"path"`
+	Files     int      `json:"files"`
+	Languages []string `json:"languages,omitempty"`
+	Samples   []string `json:"samples,omitempty"`
+	// Commits counts the sampled history's commits touching the module.
+	Commits int `json:"commits"`
+}
+
+// runDescribeRepo implements the describe-repo workflow.
+func runDescribeRepo(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, opts describeRepoOptions, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	tip, err := revParse(ctx, opts.rev)
+	if err != nil {
+		return clierrors.NewCLIError(fmt.Sprintf("unknown revision %q", opts.rev)).
+			WithHint("Pass a commit, branch, or tag with --rev")
+	}
+
+	// An existing document is refreshed from the commit its marker names.
+	var existing, base string
+	if opts.outFile != "" && !opts.full {
+		data, err := os.ReadFile(opts.outFile)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to read %s: %w", opts.outFile, err)
+		}
+		if m := describeRepoMarker.FindStringSubmatch(string(data)); m != nil {
+			existing = strings.TrimSpace(describeRepoMarker.ReplaceAllString(string(data), ""))
+			base = m[1]
+		} else if len(data) > 0 {
+			log.Warn("Existing document has no describe-repo marker, regenerating it", "file", opts.outFile)
+		}
+		if base != "" && !git.Exists(ctx, base) {
+			log.Warn("Commit the document describes no longer exists, regenerating it", "commit", base)
+			existing, base = "", ""
+		}
+	}
+
+	mode := "full"
+	if base != "" {
+		mode = "refresh"
+		if same, _ := revParse(ctx, base); same == tip {
+			return writeDescribeRepo(opts, out, "unchanged", tip, existing, nil, 0)
+		}
+	}
+
+	log.Info("Sampling repository", "rev", shortHash(tip), "depth", opts.depth, "since", opts.since)
+	modules, facts, err := describeRepoFacts(ctx, tip, opts)
+	if err != nil {
+		return err
+	}
+
+	service, err := newAIService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	model := aiOpts.modelFor(ctx, prompt.DescribeRepoModel)
+
+	var (
+		systemPrompt, userPrompt string
+		newCommits               int
+	)
+	if mode == "refresh" {
+		changes, err := git.LogChanges(ctx, "--no-merges", base+".."+tip)
+		if err != nil {
+			return fmt.Errorf("failed to read history since %s: %w", shortHash(base), err)
+		}
+		newCommits = len(changes)
+		if newCommits == 0 {
+			// Only merges since; the content is current, the marker is not.
+			return writeDescribeRepo(opts, out, "unchanged", tip, existing, modules, 0)
+		}
+		log.Info("Refreshing overview", "since", shortHash(base), "commits", newCommits)
+		// The document being refreshed must be sent whole.
+		systemPrompt, userPrompt = prompt.DescribeRepoRefresh(existing, "", "")
+		fitted := fitPrompt(ctx, model,
+			prompt.Section{Name: prompt.SectionSystem, Text: systemPrompt + userPrompt, Required: true},
+			prompt.Section{Name: prompt.SectionRepo, Text: facts},
+			prompt.Section{Name: prompt.SectionMetadata, Text: describeRepoChanges(ctx, changes)},
+		)
+		systemPrompt, userPrompt = prompt.DescribeRepoRefresh(existing, fitted[1], fitted[2])
+	} else {
+		log.Info("Generating overview", "modules", len(modules))
+		systemPrompt, userPrompt = prompt.DescribeRepo("")
+		fitted := fitPrompt(ctx, model,
+			prompt.Section{Name: prompt.SectionSystem, Text: systemPrompt + userPrompt, Required: true},
+			prompt.Section{Name: prompt.SectionRepo, Text: facts},
+		)
+		systemPrompt, userPrompt = prompt.DescribeRepo(fitted[1])
+	}
+
+	doc, err := runPrompt(ctx, service, systemPrompt, userPrompt, model)
+	if err != nil {
+		return fmt.Errorf("failed to generate overview: %w", err)
+	}
+	return writeDescribeRepo(opts, out, mode, tip, doc, modules, newCommits)
+}
+
+// writeDescribeRepo stores and reports the document for tip.
+func writeDescribeRepo(opts describeRepoOptions, out output.OutputOptions, mode, tip, doc string, modules []repoModule, newCommits int) error {
+	doc = strings.TrimSpace(doc) + fmt.Sprintf("\n\n<!-- arc-git describe-repo: %s -->\n", tip)
+
+	if opts.outFile != "" {
+		if err := os.MkdirAll(filepath.Dir(opts.outFile), 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(opts.outFile), err)
+		}
+		if err := os.WriteFile(opts.outFile, []byte(doc), 0o644); err != nil {
+			return fmt.Errorf("failed to write overview: %w", err)
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"revision":    tip,
+			"mode":        mode,
+			"new_commits": newCommits,
+			"modules":     modules,
+			"document":    doc,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		switch {
+		case opts.outFile == "":
+			fmt.Print(doc)
+		case mode == "unchanged":
+			fmt.Printf("%s is up to date with %s\n", opts.outFile, shortHash(tip))
+		case mode == "refresh":
+			fmt.Printf("Refreshed %s with %d new commits (now at %s)\n", opts.outFile, newCommits, shortHash(tip))
+		default:
+			fmt.Printf("Wrote overview of %s to %s\n", shortHash(tip), opts.outFile)
+		}
+	}
+	return nil
+}
+
+// describeRepoFacts samples the tree and history at tip and renders them for
+// the prompt.
+func describeRepoFacts(ctx context.Context, tip string, opts describeRepoOptions) ([]repoModule, string, error) {
+	tree, err := git.Run(ctx, "ls-tree", "-r", "--name-only", tip)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list files: %w", err)
+	}
+	var files []string
+	for _, f := range strings.Split(tree, "\n") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+
+	changes, err := git.LogChanges(ctx, "--no-merges", "--since", opts.since, tip)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read history: %w", err)
+	}
+	modules := repoModules(files, changes, opts.depth)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Revision %s; %d files; %d non-merge commits in the sampled history (since %s).\n", shortHash(tip), len(files), len(changes), opts.since)
+
+	b.WriteString("\n## Modules (files, languages, activity, examples)\n")
+	for _, m := range modules {
+		fmt.Fprintf(&b, "- %s: %d files", m.Path, m.Files)
+		if len(m.Languages) > 0 {
+			fmt.Fprintf(&b, ", %s", strings.Join(m.Languages, "/"))
+		}
+		fmt.Fprintf(&b, ", %d recent commits; e.g. %s\n", m.Commits, strings.Join(m.Samples, ", "))
+	}
+
+	var entries []string
+	for _, f := range files {
+		if entryPointPattern.MatchString(f) {
+			entries = append(entries, f)
+		}
+	}
+	if len(entries) > 0 {
+		b.WriteString("\n## Likely entry points\n")
+		for i, f := range entries {
+			if i == maxRepoModules {
+				fmt.Fprintf(&b, "- ... %d more\n", len(entries)-i)
+				break
+			}
+			fmt.Fprintf(&b, "- %s\n", f)
+		}
+	}
+
+	for _, f := range files {
+		if strings.EqualFold(f, "README.md") || strings.EqualFold(f, "README") || strings.EqualFold(f, "README.rst") {
+			if src, err := git.FileAt(ctx, tip, f); err == nil {
+				fmt.Fprintf(&b, "\n## %s\n%s\n", f, truncate(string(src), maxRepoReadme))
+			}
+			break
+		}
+	}
+	for _, name := range repoManifests {
+		if src, err := git.FileAt(ctx, tip, name); err == nil {
+			fmt.Fprintf(&b, "\n## %s\n%s\n", name, truncate(string(src), maxRepoManifest))
+		}
+	}
+
+	if len(changes) > 0 {
+		lines, err := git.ChangedLines(ctx, "--no-merges", "--since", opts.since, tip)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to measure changes: %w", err)
+		}
+		key := append([]git.Change{}, changes...)
+		sort.SliceStable(key, func(i, j int) bool { return lines[key[i].Hash] > lines[key[j].Hash] })
+		if len(key) > maxRepoKeyCommits {
+			key = key[:maxRepoKeyCommits]
+		}
+		b.WriteString("\n## Largest commits\n")
+		b.WriteString(describeRepoChanges(ctx, key))
+
+		b.WriteString("\n## Recent annotations\n")
+		noted := 0
+		for _, c := range changes {
+			if noted == maxRepoNotes {
+				break
+			}
+			if note, err := git.ShowNote(ctx, c.Hash, "ai"); err == nil {
+				fmt.Fprintf(&b, "- %s %s (%s): %s\n", c.Short(), c.Message, c.Date, truncate(parseNote(note).Text, maxRepoNote))
+				noted++
+			}
+		}
+		if noted == 0 {
+			b.WriteString("(none)\n")
+		}
+	}
+
+	return modules, b.String(), nil
+}
+
+// repoModules groups files into directories at depth, largest first, with
+// the commits of changes that touched each.
+func repoModules(files []string, changes []git.Change, depth int) []repoModule {
+	byPath := make(map[string]*repoModule)
+	languages := make(map[string]map[string]int)
+	for _, f := range files {
+		area := areaOf(f, depth)
+		m, ok := byPath[area]
+		if !ok {
+			m = &repoModule{Path: area}
+			byPath[area] = m
+			languages[area] = make(map[string]int)
+		}
+		m.Files++
+		if len(m.Samples) < maxModuleSamples {
+			m.Samples = append(m.Samples, path.Base(f))
+		}
+		if ext := strings.TrimPrefix(path.Ext(f), "."); ext != "" {
+			languages[area][ext]++
+		}
+	}
+	for _, c := range changes {
+		for _, area := range areasOf(c.Files, depth) {
+			if m, ok := byPath[area]; ok {
+				m.Commits++
+			}
+		}
+	}
+
+	list := make([]repoModule, 0, len(byPath))
+	for area, m := range byPath {
+		exts := make([]string, 0, len(languages[area]))
+		for ext := range languages[area] {
+			exts = append(exts, ext)
+		}
+		sort.Slice(exts, func(i, j int) bool {
+			if languages[area][exts[i]] != languages[area][exts[j]] {
+				return languages[area][exts[i]] > languages[area][exts[j]]
+			}
+			return exts[i] < exts[j]
+		})
+		if len(exts) > 3 {
+			exts = exts[:3]
+		}
+		m.Languages = exts
+		list = append(list, *m)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Files != list[j].Files {
+			return list[i].Files > list[j].Files
+		}
+		return list[i].Path < list[j].Path
+	})
+	if len(list) > maxRepoModules {
+		list = list[:maxRepoModules]
+	}
+	return list
+}
+
+// describeRepoChanges renders commits with the files they touched and their
+// annotations.
+func describeRepoChanges(ctx context.Context, changes []git.Change) string {
+	var b strings.Builder
+	for i, c := range changes {
+		if i == maxRepoChanges {
+			fmt.Fprintf(&b, "- ... %d more commits\n", len(changes)-i)
+			break
+		}
+		fmt.Fprintf(&b, "- %s %s (%s; %s)\n", c.Short(), c.Message, c.Date, strings.Join(areasOf(c.Files, 2), ", "))
+		if note, err := git.ShowNote(ctx, c.Hash, "ai"); err == nil {
+			fmt.Fprintf(&b, "  Annotation: %s\n", truncate(parseNote(note).Text, maxRepoNote))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/fixture"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// newDevtoolsCmd creates the hidden devtools command group, for developing
+// arc-git itself and reproducing bugs.
+func newDevtoolsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "devtools",
+		Short:  "Tools for developing arc-git and reproducing bugs",
+		Hidden: true,
+	}
+
+	cmd.AddCommand(newMakeFixtureCmd())
+
+	return cmd
+}
+
+// newMakeFixtureCmd creates the devtools make-fixture subcommand.
+func newMakeFixtureCmd() *cobra.Command {
+	var (
+		opts       fixture.Options
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "make-fixture [dir]",
+		Short: "Build a synthetic git repository",
+		Long: `Build a synthetic git repository for integration tests and bug reports.
+
+The history is generated from --seed, so the same flags always produce the
+same repository, commit hashes included; a bug report can name the command
+instead of attaching a repository. Profiles:
+- clean: a linear history of small, conventionally described changes
+- messy: merges, renames, binary files, unicode paths, names, and messages,
+  huge generated diffs, symlinks, executable scripts, duplicate identities,
+  backdated commits, and sloppy or fixup messages
+
+Release tags are added every 50 commits, and with --notes about a third of
+the commits get deep or quick annotations under refs/notes/ai. The
+repository is created in dir (default: arc-git-fixture), which must not
+exist or be empty.`,
+		Example: `  # The fixture most integration tests run against
+  arc-git devtools make-fixture --commits 200 --profile messy
+
+  # A small clean repository with annotations, for checking output
+  arc-git devtools make-fixture --commits 30 --profile clean --notes /tmp/clean
+
+  # A different history of the same shape
+  arc-git devtools make-fixture --commits 200 --profile messy --seed 7 /tmp/messy-7`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if !slices.Contains(fixture.Profiles, opts.Profile) {
+				return errors.NewCLIError(fmt.Sprintf("unknown profile %q", opts.Profile)).
+					WithHint("Use --profile " + strings.Join(fixture.Profiles, " or --profile "))
+			}
+			if opts.Commits <= 0 {
+				return errors.NewCLIError("--commits must be positive")
+			}
+			dir := "arc-git-fixture"
+			if len(args) == 1 {
+				dir = args[0]
+			}
+
+			return runMakeFixture(cmd.Context(), dir, opts, outputOpts)
+		},
+	}
+
+	cmd.Flags().IntVar(&opts.Commits, "commits", 200, "Number of commits, merges included")
+	cmd.Flags().StringVar(&opts.Profile, "profile", "messy", "Kind of history: "+strings.Join(fixture.Profiles, ", "))
+	cmd.Flags().Int64Var(&opts.Seed, "seed", 1, "Seed the history is generated from")
+	cmd.Flags().BoolVar(&opts.Notes, "notes", false, "Annotate about a third of the commits")
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// runMakeFixture implements the devtools make-fixture workflow.
+func runMakeFixture(ctx context.Context, dir string, opts fixture.Options, out output.OutputOptions) error {
+	progressFor(out)
+	logging.L().Info("Building fixture repository", "dir", dir, "profile", opts.Profile, "commits", opts.Commits, "seed", opts.Seed)
+
+	summary, err := fixture.Build(ctx, dir, opts)
+	if err != nil {
+		return fmt.Errorf("failed to build fixture: %w", err)
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(summary)
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		fmt.Printf("Built %s (%s, seed %d): %d commits, HEAD %s\n", summary.Dir, summary.Profile, summary.Seed, summary.Commits, shortHash(summary.Head))
+		fmt.Printf("  merges %d, renames %d, deletes %d, binary changes %d, huge diffs %d, unicode paths %d, tags %d, notes %d\n",
+			summary.Merges, summary.Renames, summary.Deletes, summary.Binary, summary.HugeDiffs, summary.UnicodePaths, summary.Tags, summary.Notes)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/diffview"
+	"github.com/yourorg/arc-git/internal/logging"
+	clierrors "github.com/yourorg/arc-sdk/errors"
+)
+
+// diffFlags holds the --diff-format flag of commands that show diffs to the
+// AI.
+type diffFlags struct {
+	format string
+	cmd    *cobra.Command
+}
+
+// register adds the --diff-format flag to cmd.
+func (f *diffFlags) register(cmd *cobra.Command) {
+	f.cmd = cmd
+	cmd.Flags().StringVar(&f.format, "diff-format", "", "How diffs are shown to the AI: "+strings.Join(diffview.Formats, ", ")+" (default: the repository config's, else unified)")
+}
+
+// resolve returns the --diff-format, or else the format the repository
+// config names for the command, or else unified. The semantic format falls
+// back to unified when difftastic is not installed.
+func (f *diffFlags) resolve(ctx context.Context) (string, error) {
+	format, source := f.format, "--diff-format"
+	if format == "" && f.cmd != nil {
+		format, source = repoConfig(ctx).DiffFormat(commandKey(f.cmd)), "diff_formats in the repository config"
+	}
+	if format == "" {
+		return diffview.Unified, nil
+	}
+	if !slices.Contains(diffview.Formats, format) {
+		return "", clierrors.NewCLIError(fmt.Sprintf("unknown diff format %q in %s", format, source)).
+			WithHint("Use one of: " + strings.Join(diffview.Formats, ", "))
+	}
+	if err := diffview.Available(format); err != nil {
+		logging.L().Warn("Showing unified diffs instead", "format", format, "reason", err)
+		return diffview.Unified, nil
+	}
+	return format, nil
+}
@@ -0,0 +1,449 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/checkpoint"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	clierrors "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// Doctor check statuses.
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+	checkSkip = "skip"
+)
+
+// minGitVersion is the oldest git release arc-git is tested against.
+var minGitVersion = [2]int{2, 25}
+
+// doctorCheck is the outcome of a single diagnostic.
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+// newDoctorCmd creates the doctor subcommand.
+func newDoctorCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		offline    bool
+		aiOpts     aiFlags
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the arc-git environment",
+		Long: `Diagnose the arc-git environment and print actionable fixes.
+
+Checks cover:
+- git version and repository health (work tree, HEAD, shallow clones)
+- AI provider configuration, connectivity, model availability, and API key
+- the "ai" notes ref and the git config that displays, fetches, and
+  preserves it across rebases
+- installed git hooks that run arc-git
+- integrity of arc-git's state under .git/arc-git
+- conflicting environment settings
+
+The provider check sends one tiny request to the configured model; use
+--offline to skip it. The command exits non-zero when any check fails.`,
+		Example: `  # Run all checks
+  arc-git doctor
+
+  # Skip the provider round-trip
+  arc-git doctor --offline
+
+  # Attach machine-readable results to a support ticket
+  arc-git doctor --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+
+			return runDoctor(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, offline, outputOpts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&offline, "offline", false, "Skip checks that contact the AI provider")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// runDoctor implements the doctor workflow.
+func runDoctor(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, offline bool, out output.OutputOptions) error {
+	var checks []doctorCheck
+	checks = append(checks, checkGitVersion(ctx))
+	checks = append(checks, checkRepository(ctx)...)
+	checks = append(checks, checkProvider(ctx, cfg, aiOpts, offline)...)
+	checks = append(checks, checkNotesRef(ctx)...)
+	checks = append(checks, checkHooks(ctx))
+	checks = append(checks, checkState(ctx))
+	checks = append(checks, checkConflicts()...)
+
+	failed := 0
+	for _, c := range checks {
+		if c.Status == checkFail {
+			failed++
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		if err := writeJSON(map[string]interface{}{
+			"checks": checks,
+			"failed": failed,
+		}); err != nil {
+			return err
+		}
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: exit status only
+	default:
+		for _, c := range checks {
+			fmt.Printf("[%-4s] %-24s %s\n", c.Status, c.Name, c.Detail)
+			if c.Fix != "" && c.Status != checkOK {
+				fmt.Printf("       %-24s fix: %s\n", "", c.Fix)
+			}
+		}
+	}
+
+	if failed > 0 {
+		return clierrors.NewCLIError(fmt.Sprintf("%d doctor checks failed", failed)).
+			WithHint("Apply the suggested fixes and re-run arc-git doctor")
+	}
+	return nil
+}
+
+// checkGitVersion verifies that git is installed and recent enough.
+func checkGitVersion(ctx context.Context) doctorCheck {
+	c := doctorCheck{Name: "git version"}
+	out, err := git.Run(ctx, "version")
+	if err != nil {
+		c.Status, c.Detail = checkFail, "git is not installed or not on PATH"
+		c.Fix = "Install git and make sure it is on PATH"
+		return c
+	}
+
+	c.Detail = strings.TrimSpace(out)
+	m := regexp.MustCompile(`(\d+)\.(\d+)`).FindStringSubmatch(out)
+	if m == nil {
+		c.Status = checkWarn
+		return c
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	if major < minGitVersion[0] || (major == minGitVersion[0] && minor < minGitVersion[1]) {
+		c.Status = checkWarn
+		c.Fix = fmt.Sprintf("Upgrade git to %d.%d or newer", minGitVersion[0], minGitVersion[1])
+		return c
+	}
+	c.Status = checkOK
+	return c
+}
+
+// checkRepository verifies that the working directory is a usable repository.
+func checkRepository(ctx context.Context) []doctorCheck {
+	repo := doctorCheck{Name: "repository"}
+	if _, err := git.Run(ctx, "rev-parse", "--is-inside-work-tree"); err != nil {
+		repo.Status, repo.Detail = checkFail, "not inside a git work tree"
+		repo.Fix = "Run arc-git from within a git repository"
+		return []doctorCheck{repo}
+	}
+
+	head, err := git.Run(ctx, "rev-parse", "--verify", "HEAD")
+	if err != nil {
+		repo.Status, repo.Detail = checkWarn, "repository has no commits yet"
+		repo.Fix = "Create a commit before annotating"
+		return []doctorCheck{repo}
+	}
+	repo.Status, repo.Detail = checkOK, "HEAD at "+shortHash(strings.TrimSpace(head))
+
+	shallow := doctorCheck{Name: "history depth", Status: checkOK, Detail: "full history available"}
+	if out, err := git.Run(ctx, "rev-parse", "--is-shallow-repository"); err == nil && strings.TrimSpace(out) == "true" {
+		shallow.Status, shallow.Detail = checkWarn, "shallow clone; older commits are unavailable"
+		shallow.Fix = "git fetch --unshallow (or use fetch-depth: 0 in CI)"
+	}
+
+	return []doctorCheck{repo, shallow}
+}
+
+// checkProvider validates the AI configuration and, unless offline, sends a
+// minimal request to confirm connectivity, credentials, and the model.
+func checkProvider(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, offline bool) []doctorCheck {
+	config := doctorCheck{Name: "ai config"}
+	if err := ai.ValidateConfig(cfg); err != nil {
+		config.Status, config.Detail = checkFail, err.Error()
+		config.Fix = "Set the provider and API key in your arc config or pass --provider/--api-key"
+		return []doctorCheck{config}
+	}
+	config.Status = checkOK
+	config.Detail = fmt.Sprintf("provider %q", cfg.Provider)
+	if cfg.APIKey == "" {
+		config.Status = checkWarn
+		config.Detail += ", no API key set"
+		config.Fix = "Pass --api-key or configure a key for the provider"
+	}
+
+	model := aiOpts.modelFor(ctx, prompt.AnnotateCommitModel)
+	reach := doctorCheck{Name: "ai provider"}
+	if offline {
+		reach.Status, reach.Detail = checkSkip, "skipped (--offline)"
+		return []doctorCheck{config, reach}
+	}
+
+	service, err := newAIService(ctx, cfg)
+	if err != nil {
+		reach.Status, reach.Detail = checkFail, err.Error()
+		return []doctorCheck{config, reach}
+	}
+	_, err = runPrompt(ctx, service, "You are a health check.", "Reply with OK.", model)
+	if errors.Is(err, ErrPlanned) {
+		reach.Status, reach.Detail = checkSkip, "skipped (--plan)"
+		return []doctorCheck{config, reach}
+	}
+	if err != nil {
+		reach.Status, reach.Detail = checkFail, err.Error()
+		reach.Fix = providerFix(err)
+		return []doctorCheck{config, reach}
+	}
+	reach.Status, reach.Detail = checkOK, fmt.Sprintf("model %s responded", model)
+	return []doctorCheck{config, reach}
+}
+
+// providerFix suggests a remedy for a failed provider round-trip.
+func providerFix(err error) string {
+	return classifyFailure(err).Hint
+}
+
+// checkNotesRef reports on the notes ref and the config that surfaces it.
+func checkNotesRef(ctx context.Context) []doctorCheck {
+	ref := doctorCheck{Name: "notes ref"}
+	noted, err := git.NotedCommits(ctx, "ai")
+	switch {
+	case err != nil:
+		ref.Status, ref.Detail = checkFail, err.Error()
+		ref.Fix = "Inspect the ref with git notes --ref ai list"
+	case len(noted) == 0:
+		ref.Status, ref.Detail = checkWarn, "refs/notes/ai does not exist yet"
+		ref.Fix = "Run arc-git annotate, or fetch notes: git fetch origin refs/notes/ai:refs/notes/ai"
+	default:
+		ref.Status, ref.Detail = checkOK, fmt.Sprintf("%d annotated commits", len(noted))
+	}
+
+	display := doctorCheck{Name: "notes display", Status: checkOK, Detail: "git log shows AI notes"}
+	if !configCovers(ctx, "notes.displayRef", "refs/notes/ai") {
+		display.Status, display.Detail = checkWarn, "git log does not show AI notes by default"
+		display.Fix = "git config --add notes.displayRef refs/notes/ai"
+	}
+
+	rewrite := doctorCheck{Name: "notes rewrite", Status: checkOK, Detail: "notes follow rebased and amended commits"}
+	switch {
+	case !configCovers(ctx, "notes.rewriteRef", "refs/notes/ai"):
+		rewrite.Status, rewrite.Detail = checkWarn, "notes are dropped when commits are rebased or amended"
+		rewrite.Fix = "git config --add notes.rewriteRef 'refs/notes/ai*'"
+	case !configCovers(ctx, "notes.rewriteRef", "refs/notes/"+summaryRef):
+		rewrite.Status, rewrite.Detail = checkWarn, "summaries are dropped when commits are rebased or amended"
+		rewrite.Fix = "git config --add notes.rewriteRef refs/notes/" + summaryRef
+	}
+
+	fetch := doctorCheck{Name: "notes fetch", Status: checkOK, Detail: "notes are fetched from origin"}
+	if _, err := git.RemoteURL(ctx, "origin"); err != nil {
+		fetch.Status, fetch.Detail = checkSkip, "no origin remote"
+	} else if !configCovers(ctx, "remote.origin.fetch", "refs/notes/ai") {
+		fetch.Status, fetch.Detail = checkWarn, "notes are not fetched from origin"
+		fetch.Fix = "git config --add remote.origin.fetch '+refs/notes/*:refs/notes/*'"
+	}
+
+	summaries := doctorCheck{Name: "notes summaries", Status: checkOK, Detail: "every annotation has a one-line summary"}
+	if summarized, err := git.NotedCommits(ctx, summaryRef); err != nil || len(noted) == 0 {
+		summaries.Status, summaries.Detail = checkSkip, "no annotations"
+	} else if missing := len(noted) - countNoted(noted, summarized); missing > 0 {
+		summaries.Status, summaries.Detail = checkWarn, fmt.Sprintf("%d annotations have no summary under refs/notes/%s", missing, summaryRef)
+		summaries.Fix = "arc-git notes migrate"
+	}
+
+	return []doctorCheck{ref, display, rewrite, fetch, summaries}
+}
+
+// countNoted returns how many commits of noted are also in other.
+func countNoted(noted, other map[string]bool) int {
+	n := 0
+	for hash := range noted {
+		if other[hash] {
+			n++
+		}
+	}
+	return n
+}
+
+// checkHooks reports which git hooks invoke arc-git, and whether hooks
+// installed through a manager actually run.
+func checkHooks(ctx context.Context) doctorCheck {
+	c := doctorCheck{Name: "hooks"}
+	setup, err := detectHooks(ctx, "")
+	if err != nil {
+		c.Status, c.Detail = checkSkip, "could not locate hooks directory"
+		return c
+	}
+	if setup.Manager != managerGit {
+		data, _ := os.ReadFile(setup.File)
+		if setup.Manager == managerHusky {
+			data = nil
+			for _, hook := range installedHooks {
+				script, _ := os.ReadFile(filepath.Join(setup.File, hook))
+				data = append(data, script...)
+			}
+		}
+		switch {
+		case !strings.Contains(string(data), "arc-git"):
+			c.Status, c.Detail = checkOK, fmt.Sprintf("no arc-git hooks installed through %s", setup.Manager)
+			c.Fix = "arc-git hooks install"
+		case !setup.Active:
+			c.Status, c.Detail = checkWarn, fmt.Sprintf("arc-git hooks are configured in %s but %s is not installed in this clone, so they never run", setup.File, setup.Manager)
+			c.Fix = setup.Manager + " install"
+			if setup.Manager == managerHusky {
+				c.Fix = "npm install"
+			}
+		default:
+			c.Status, c.Detail = checkOK, fmt.Sprintf("arc-git runs through %s (%s)", setup.Manager, setup.File)
+		}
+		return c
+	}
+	dir := setup.File
+
+	var installed []string
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if e.IsDir() || strings.HasSuffix(e.Name(), ".sample") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err == nil && strings.Contains(string(data), "arc-git") {
+			installed = append(installed, e.Name())
+		}
+	}
+
+	if len(installed) == 0 {
+		c.Status, c.Detail = checkOK, "no arc-git hooks installed in "+dir
+		c.Fix = "arc-git hooks install"
+		return c
+	}
+	c.Status, c.Detail = checkOK, fmt.Sprintf("arc-git runs from %s in %s", strings.Join(installed, ", "), dir)
+	return c
+}
+
+// hooksDir returns the directory git executes hooks from.
+func hooksDir(ctx context.Context) (string, error) {
+	out, err := git.Run(ctx, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(strings.TrimSpace(out))
+}
+
+// checkState verifies that arc-git's persisted state can be read.
+func checkState(ctx context.Context) doctorCheck {
+	c := doctorCheck{Name: "state"}
+	gitDir, err := git.Dir(ctx)
+	if err != nil {
+		c.Status, c.Detail = checkSkip, "not in a repository"
+		return c
+	}
+
+	dir := filepath.Join(gitDir, "arc-git", "checkpoints")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		c.Status, c.Detail = checkOK, "no saved state"
+		return c
+	}
+
+	var broken []string
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		if _, err := checkpoint.Load(filepath.Join(dir, e.Name())); err != nil {
+			broken = append(broken, e.Name())
+		}
+	}
+	if len(broken) > 0 {
+		c.Status = checkFail
+		c.Detail = "unreadable checkpoints: " + strings.Join(broken, ", ")
+		c.Fix = "Delete the listed files from " + dir + "; they are recreated on the next run"
+		return c
+	}
+	c.Status, c.Detail = checkOK, fmt.Sprintf("%d checkpoints readable", len(entries))
+	return c
+}
+
+// checkConflicts flags environment settings that contradict each other.
+func checkConflicts() []doctorCheck {
+	var checks []doctorCheck
+
+	if gh, ghAlt := os.Getenv("GITHUB_TOKEN"), os.Getenv("GH_TOKEN"); gh != "" && ghAlt != "" && gh != ghAlt {
+		checks = append(checks, doctorCheck{
+			Name:   "forge token",
+			Status: checkWarn,
+			Detail: "GITHUB_TOKEN and GH_TOKEN are both set to different values; GITHUB_TOKEN wins",
+			Fix:    "Unset one of them",
+		})
+	}
+
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" {
+		checks = append(checks, doctorCheck{
+			Name:   "telemetry",
+			Status: checkWarn,
+			Detail: "an OTLP endpoint is configured but OTEL_SDK_DISABLED=true",
+			Fix:    "Unset OTEL_SDK_DISABLED to export telemetry, or drop the endpoint",
+		})
+	}
+
+	if len(checks) == 0 {
+		checks = append(checks, doctorCheck{Name: "config conflicts", Status: checkOK, Detail: "none found"})
+	}
+	return checks
+}
+
+// configCovers reports whether any value of a multi-valued git config key
+// names ref, or is a glob ending in * over it. Values that are refspecs are
+// compared by their source, so "+refs/notes/*:refs/notes/*" covers
+// refs/notes/ai.
+func configCovers(ctx context.Context, key, ref string) bool {
+	out, err := git.Run(ctx, "config", "--get-all", key)
+	if err != nil {
+		return false
+	}
+	for _, value := range strings.Split(out, "\n") {
+		value = strings.TrimPrefix(strings.TrimSpace(value), "+")
+		if i := strings.Index(value, ":"); i >= 0 {
+			value = value[:i]
+		}
+		switch {
+		case value == "":
+		case value == ref:
+			return true
+		case strings.HasSuffix(value, "*") && strings.HasPrefix(ref, strings.TrimSuffix(value, "*")):
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import "testing"
+
+func TestConfigCovers(t *testing.T) {
+	tests := []struct {
+		values []string
+		ref    string
+		want   bool
+	}{
+		{values: []string{"refs/notes/ai"}, ref: "refs/notes/ai", want: true},
+		{values: []string{"refs/notes/ai*"}, ref: "refs/notes/ai", want: true},
+		{values: []string{"refs/notes/ai*"}, ref: "refs/notes/ai-summary", want: true},
+		{values: []string{"refs/notes/*"}, ref: "refs/notes/ai", want: true},
+		{values: []string{"refs/notes/commits", "refs/notes/ai"}, ref: "refs/notes/ai", want: true},
+		// Other notes refs that start with the name do not cover it.
+		{values: []string{"refs/notes/ai-summary", "refs/notes/ai-symbols"}, ref: "refs/notes/ai"},
+		{values: []string{"refs/notes/ai"}, ref: "refs/notes/ai-summary"},
+		{values: []string{"refs/notes/a"}, ref: "refs/notes/ai"},
+		{values: []string{"+refs/notes/*:refs/notes/*"}, ref: "refs/notes/ai", want: true},
+		{values: []string{"+refs/heads/*:refs/remotes/origin/*"}, ref: "refs/notes/ai"},
+		{values: []string{"+refs/heads/*:refs/notes/ai"}, ref: "refs/notes/ai"},
+		{ref: "refs/notes/ai"},
+	}
+	for _, tt := range tests {
+		r := newTestRepo(t)
+		for _, v := range tt.values {
+			r.git("config", "--add", "notes.rewriteRef", v)
+		}
+		if got := configCovers(r.ctx, "notes.rewriteRef", tt.ref); got != tt.want {
+			t.Errorf("configCovers(%q, %q) = %v, want %v", tt.values, tt.ref, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/spf13/cobra"
+)
+
+// Process exit codes for runs that stopped early, following the shell
+// conventions of 128+SIGINT and timeout(1).
+const (
+	ExitInterrupted = 130
+	ExitDeadline    = 124
+)
+
+var (
+	// ErrInterrupted is returned by commands that stopped early because of
+	// SIGINT or SIGTERM, after flushing partial results.
+	ErrInterrupted = errors.New("interrupted")
+	// ErrDeadline is returned by commands that stopped early because
+	// --run-deadline elapsed, after flushing partial results.
+	ErrDeadline = errors.New("run deadline exceeded")
+)
+
+// ExitCode maps an error returned by the root command to a process exit code.
+// Timeouts of single requests, which wrap context.DeadlineExceeded, are
+// ordinary failures; only ErrDeadline, or Execute seeing the run deadline
+// expire, yields ExitDeadline.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, ErrDeadline):
+		return ExitDeadline
+	case errors.Is(err, ErrInterrupted), errors.Is(err, context.Canceled):
+		return ExitInterrupted
+	default:
+		return 1
+	}
+}
+
+// runStateKey carries the runState of Execute.
+type runStateKey struct{}
+
+// runState is what the root command sets up for a run and Execute tears
+// down, whether or not the command succeeded: cobra skips post-run hooks
+// after an error.
+type runState struct {
+	deadline context.Context
+	cancel   context.CancelFunc
+}
+
+// runStateFor returns the runState of the run, or a detached one when the
+// command is not run through Execute.
+func runStateFor(ctx context.Context) *runState {
+	if s, ok := ctx.Value(runStateKey{}).(*runState); ok {
+		return s
+	}
+	return &runState{}
+}
+
+// setDeadline records the context of --run-deadline and its cancel func.
+func (s *runState) setDeadline(ctx context.Context, cancel context.CancelFunc) {
+	s.deadline, s.cancel = ctx, cancel
+}
+
+// expired reports whether the run deadline elapsed.
+func (s *runState) expired() bool {
+	return s.deadline != nil && errors.Is(s.deadline.Err(), context.DeadlineExceeded)
+}
+
+// release cancels the run deadline's context.
+func (s *runState) release() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// Execute runs root with ctx and returns the process exit code. An error
+// after the run deadline expired maps to ExitDeadline, however the command
+// reported it.
+func Execute(ctx context.Context, root *cobra.Command) int {
+	state := &runState{}
+	defer state.release()
+	err := root.ExecuteContext(context.WithValue(ctx, runStateKey{}, state))
+	if err != nil && state.expired() {
+		return ExitDeadline
+	}
+	return ExitCode(err)
+}
+
+// stopReason returns the error describing why ctx ended early, or nil if it
+// is still live.
+func stopReason(ctx context.Context) error {
+	switch {
+	case ctx.Err() == nil:
+		return nil
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return ErrDeadline
+	default:
+		return ErrInterrupted
+	}
+}
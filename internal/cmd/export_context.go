@@ -0,0 +1,564 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	clierrors "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// Limits on what export-context gathers before fitting the document to the
+// budget.
+const (
+	maxExportDigestCommits = 8
+	maxExportGist          = 160
+	maxExportAreas         = 15
+	maxExportUnstable      = 8
+	maxExportAnnotations   = 60
+	maxExportNote          = 1500
+	maxExportReadme        = 6000
+)
+
+// Sections of an exported context document, most important first.
+const (
+	exportOverview    = "overview"
+	exportDigests     = "digests"
+	exportHotspots    = "hotspots"
+	exportAnnotations = "annotations"
+)
+
+// exportWeights are the shares of the budget the sections get when the
+// document does not fit.
+var exportWeights = map[string]float64{
+	exportOverview:    3,
+	exportDigests:     2,
+	exportHotspots:    1,
+	exportAnnotations: 3,
+}
+
+// overviewCandidates are looked for, relative to the work tree root, when
+// --overview is not given.
+var overviewCandidates = []string{"docs/OVERVIEW.md", "OVERVIEW.md", "docs/ARCHITECTURE.md", "ARCHITECTURE.md"}
+
+// tokenBudgetPattern matches budgets like "50000", "50k", or "50k-tokens".
+var tokenBudgetPattern = regexp.MustCompile(`(?i)^(\d+(?:\.\d+)?)([km]?)(?:[- ]?tokens?)?$`)
+
+// newExportContextCmd creates the export-context subcommand.
+func newExportContextCmd() *cobra.Command {
+	var (
+		opts       exportContextOptions
+		budget     string
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export-context",
+		Short: "Compile repository knowledge into one document for external assistants",
+		Long: `Compile the most important knowledge about the repository into a single
+Markdown document that fits a token budget, for pasting into an external AI
+assistant or attaching to an incident channel.
+
+The document is assembled from what arc-git already knows, without calling
+the AI:
+- Overview: the document written by "arc-git describe-repo" (--overview, or
+  the first of docs/OVERVIEW.md, OVERVIEW.md, docs/ARCHITECTURE.md, and
+  ARCHITECTURE.md that exists), otherwise the README and a module listing
+- Recent digests: one entry per week of the last --since, with the areas
+  that changed and the largest commits with their annotation gists
+- Hotspots: the most changed areas, and those with revert, fix-chain, and
+  follow-up signals as reported by "arc-git instability"
+- Key annotations: deep annotations of the period, highest risk and largest
+  change first
+
+When everything does not fit --budget, the sections share it by importance
+and lose their least important entries first. The redaction rules of the
+repository config are applied to the whole document.`,
+		Example: `  # A context document of at most about 50k tokens
+  arc-git export-context --out context.md --budget 50k-tokens
+
+  # A short briefing on the last two weeks for an incident channel
+  arc-git export-context --since 2w --budget 8k
+
+  # Use an overview kept elsewhere, and report section sizes as JSON
+  arc-git export-context --overview docs/design/overview.md --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			tokens, err := parseTokenBudget(budget)
+			if err != nil {
+				return clierrors.NewCLIError(err.Error()).
+					WithHint("Use a token count such as --budget 50000, --budget 50k, or --budget 50k-tokens")
+			}
+			opts.tokens = tokens
+			opts.since = gitSince(opts.since)
+
+			return runExportContext(cmd.Context(), opts, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.outFile, "out", "", "Write the document to this file instead of stdout (e.g., context.md)")
+	cmd.Flags().StringVar(&budget, "budget", "50k", "Estimated size limit of the document (e.g., 50000, 50k, or 50k-tokens)")
+	cmd.Flags().StringVar(&opts.since, "since", "90d", "History to digest (1y, 6m, 2w, 30d, or a git date)")
+	cmd.Flags().StringVar(&opts.overview, "overview", "", "Overview document to include (default: docs/OVERVIEW.md, OVERVIEW.md, or ARCHITECTURE.md if present)")
+	cmd.Flags().IntVar(&opts.depth, "depth", 2, "Directory depth used to group files into areas")
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// exportContextOptions holds the export-context flags.
+type exportContextOptions struct {
+	outFile  string
+	tokens   int
+	since    string
+	overview string
+	depth    int
+}
+
+// exportSection reports the size of one section of the document.
+type exportSection struct {
+	Name   string `json:"name"`
+	Tokens int    `json:"tokens"`
+	// FullTokens is the size before the section was trimmed to the budget.
+	FullTokens int `json:"full_tokens"`
+}
+
+// parseTokenBudget parses budgets like "50000", "50k", or "50k-tokens".
+func parseTokenBudget(s string) (int, error) {
+	m := tokenBudgetPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("invalid budget %q", s)
+	}
+	n, _ := strconv.ParseFloat(m[1], 64)
+	switch strings.ToLower(m[2]) {
+	case "k":
+		n *= 1000
+	case "m":
+		n *= 1000000
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("budget %q is too small", s)
+	}
+	return int(n), nil
+}
+
+// runExportContext implements the export-context workflow.
+func runExportContext(ctx context.Context, opts exportContextOptions, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	tip, err := revParse(ctx, "HEAD")
+	if err != nil {
+		return clierrors.NewCLIError("the repository has no commits yet").
+			WithHint("Commit something first; there is no history to export")
+	}
+	top, err := git.Run(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return fmt.Errorf("failed to find the work tree: %w", err)
+	}
+	top = strings.TrimSpace(top)
+
+	log.Info("Reading history", "since", opts.since)
+	changes, err := git.LogChanges(ctx, "--no-merges", "--since", opts.since, tip)
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+	if len(changes) == 0 {
+		log.Warn("No commits in the period, exporting the overview only (widen it with --since)", "since", opts.since)
+	}
+	noted, err := git.LogNotes(ctx, "ai", "--no-merges", "--since", opts.since, tip)
+	if err != nil {
+		return fmt.Errorf("failed to read annotations: %w", err)
+	}
+	notes := make(map[string]string, len(noted))
+	for _, c := range noted {
+		if c.Note != "" {
+			notes[c.Hash] = c.Note
+		}
+	}
+	lines, err := git.ChangedLines(ctx, "--no-merges", "--since", opts.since, tip)
+	if err != nil {
+		return fmt.Errorf("failed to measure changes: %w", err)
+	}
+
+	overview, err := exportOverviewSection(ctx, top, tip, changes, opts)
+	if err != nil {
+		return err
+	}
+
+	header := fmt.Sprintf("# %s: repository context\n\nCompiled by arc-git export-context on %s from commit %s, covering the history since %s (%d commits, %d annotated). Later sections are summaries of the history; annotations were written by AI from each commit's diff.\n",
+		filepath.Base(top), time.Now().Format("2006-01-02"), shortHash(tip), opts.since, len(changes), len(notes))
+	sections := []prompt.Section{
+		{Name: "header", Text: header, Required: true},
+		{Name: exportOverview, Text: overview},
+		{Name: exportDigests, Text: exportDigestSection(changes, notes, lines, opts.depth), Trim: trimEntries},
+		{Name: exportHotspots, Text: exportHotspotSection(changes, lines, opts.depth)},
+		{Name: exportAnnotations, Text: exportAnnotationSection(changes, notes, lines), Trim: trimEntries},
+	}
+	budget := prompt.Budget{Tokens: opts.tokens, Weights: exportWeights}
+	texts, cuts := budget.Fit(sections...)
+	for _, cut := range cuts {
+		log.Info("Trimmed section to fit the budget", "section", cut.Section, "tokens", cut.From, "kept", cut.To)
+	}
+
+	var (
+		doc    strings.Builder
+		report []exportSection
+	)
+	for i, text := range texts {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if i > 0 {
+			doc.WriteString("\n")
+		}
+		doc.WriteString(text)
+		if i > 0 {
+			report = append(report, exportSection{Name: sections[i].Name, Tokens: prompt.EstimateTokens(text), FullTokens: prompt.EstimateTokens(sections[i].Text)})
+		}
+	}
+	document := repoConfig(ctx).RedactText(doc.String())
+	tokens := prompt.EstimateTokens(document)
+
+	if opts.outFile != "" {
+		if err := os.WriteFile(opts.outFile, []byte(document), 0o644); err != nil {
+			return fmt.Errorf("failed to write context document: %w", err)
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"revision": tip,
+			"since":    opts.since,
+			"budget":   opts.tokens,
+			"tokens":   tokens,
+			"sections": report,
+			"document": document,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		if opts.outFile == "" {
+			fmt.Print(document)
+			return nil
+		}
+		fmt.Printf("Wrote context for %s to %s (~%d of %d tokens)\n", shortHash(tip), opts.outFile, tokens, opts.tokens)
+		for _, s := range report {
+			if s.Tokens < s.FullTokens {
+				fmt.Printf("  %-12s ~%d tokens (trimmed from ~%d)\n", s.Name, s.Tokens, s.FullTokens)
+			} else {
+				fmt.Printf("  %-12s ~%d tokens\n", s.Name, s.Tokens)
+			}
+		}
+	}
+	return nil
+}
+
+// exportOverviewSection returns the overview document, or without one the
+// README and a listing of the modules at tip.
+func exportOverviewSection(ctx context.Context, top, tip string, changes []git.Change, opts exportContextOptions) (string, error) {
+	candidates := overviewCandidates
+	if opts.overview != "" {
+		candidates = []string{opts.overview}
+	}
+	for _, name := range candidates {
+		file := name
+		if opts.overview == "" {
+			file = filepath.Join(top, name)
+		}
+		data, err := os.ReadFile(file)
+		if errors.Is(err, fs.ErrNotExist) && opts.overview == "" {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read overview: %w", err)
+		}
+		doc := strings.TrimSpace(describeRepoMarker.ReplaceAllString(string(data), ""))
+		return "## Overview\n\n" + nestHeadings(doc) + "\n", nil
+	}
+	logging.L().Info("No overview document found, using the README (write one with arc-git describe-repo --out docs/OVERVIEW.md)")
+
+	tree, err := git.Run(ctx, "ls-tree", "-r", "--name-only", tip)
+	if err != nil {
+		return "", fmt.Errorf("failed to list files: %w", err)
+	}
+	files := strings.Fields(tree)
+
+	var b strings.Builder
+	b.WriteString("## Overview\n\n")
+	for _, f := range files {
+		if strings.EqualFold(f, "README.md") || strings.EqualFold(f, "README") || strings.EqualFold(f, "README.rst") {
+			if src, err := git.FileAt(ctx, tip, f); err == nil {
+				fmt.Fprintf(&b, "From %s:\n\n%s\n\n", f, strings.TrimSpace(truncate(string(src), maxExportReadme)))
+			}
+			break
+		}
+	}
+	b.WriteString("Modules (files, main languages, commits in the period):\n")
+	for _, m := range repoModules(files, changes, opts.depth) {
+		fmt.Fprintf(&b, "- `%s`: %d files", m.Path, m.Files)
+		if len(m.Languages) > 0 {
+			fmt.Fprintf(&b, ", %s", strings.Join(m.Languages, "/"))
+		}
+		fmt.Fprintf(&b, ", %d commits\n", m.Commits)
+	}
+	return b.String(), nil
+}
+
+// exportDigestSection renders one digest per week of changes, newest first:
+// the areas that changed and the largest commits with their annotation gists.
+func exportDigestSection(changes []git.Change, notes map[string]string, lines map[string]int, depth int) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	var (
+		weeks  []string
+		byWeek = make(map[string][]git.Change)
+	)
+	for _, c := range changes {
+		day := c.Time.AddDate(0, 0, -((int(c.Time.Weekday()) + 6) % 7))
+		week := day.Format("2006-01-02")
+		if _, ok := byWeek[week]; !ok {
+			weeks = append(weeks, week)
+		}
+		byWeek[week] = append(byWeek[week], c)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(weeks)))
+
+	var b strings.Builder
+	b.WriteString("## Recent digests\n")
+	for _, week := range weeks {
+		commits := byWeek[week]
+		authors := make(map[string]bool)
+		areas := make(map[string]int)
+		for _, c := range commits {
+			authors[c.Email] = true
+			for _, a := range areasOf(c.Files, depth) {
+				areas[a]++
+			}
+		}
+		fmt.Fprintf(&b, "\n### Week of %s\n\n%d commits by %d authors; areas: %s\n", week, len(commits), len(authors), topAreas(areas, 5))
+
+		largest := append([]git.Change{}, commits...)
+		sort.SliceStable(largest, func(i, j int) bool { return lines[largest[i].Hash] > lines[largest[j].Hash] })
+		for i, c := range largest {
+			if i == maxExportDigestCommits {
+				fmt.Fprintf(&b, "- ... %d more commits\n", len(largest)-i)
+				break
+			}
+			fmt.Fprintf(&b, "- %s %s", c.Short(), c.Message)
+			if note, ok := notes[c.Hash]; ok {
+				fmt.Fprintf(&b, ": %s", noteGist(note))
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// exportHotspotSection renders the most changed areas and the unstable ones.
+func exportHotspotSection(changes []git.Change, lines map[string]int, depth int) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	type area struct {
+		path    string
+		commits int
+		lines   int
+		authors map[string]bool
+	}
+	byPath := make(map[string]*area)
+	for _, c := range changes {
+		for _, p := range areasOf(c.Files, depth) {
+			a, ok := byPath[p]
+			if !ok {
+				a = &area{path: p, authors: make(map[string]bool)}
+				byPath[p] = a
+			}
+			a.commits++
+			a.lines += lines[c.Hash]
+			a.authors[c.Email] = true
+		}
+	}
+	ranked := make([]*area, 0, len(byPath))
+	for _, a := range byPath {
+		ranked = append(ranked, a)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].commits != ranked[j].commits {
+			return ranked[i].commits > ranked[j].commits
+		}
+		return ranked[i].path < ranked[j].path
+	})
+	if len(ranked) > maxExportAreas {
+		ranked = ranked[:maxExportAreas]
+	}
+
+	var b strings.Builder
+	b.WriteString("## Hotspots\n\nMost changed areas:\n")
+	for _, a := range ranked {
+		fmt.Fprintf(&b, "- `%s`: %d commits, %d lines changed, %d authors\n", a.path, a.commits, a.lines, len(a.authors))
+	}
+
+	// Instability detection reads the history oldest first.
+	oldest := make([]git.Change, len(changes))
+	for i, c := range changes {
+		oldest[len(changes)-1-i] = c
+	}
+	unstable := rankAreas(detectInstability(oldest, depth, 24*time.Hour), maxExportUnstable)
+	if len(unstable) > 0 {
+		b.WriteString("\nUnstable areas (reverts, fix chains, and rapid follow-ups):\n")
+		for _, a := range unstable {
+			fmt.Fprintf(&b, "- `%s`: score %d (%d reverts, %d fix chains, %d follow-ups)", a.Path, a.Score, a.Reverts, a.FixChains, a.FollowUps)
+			if len(a.Signals) > 0 {
+				s := a.Signals[len(a.Signals)-1]
+				fmt.Fprintf(&b, "; latest %s %s", s.Hash, s.Message)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// exportAnnotationSection renders the deep annotations of changes, highest
+// risk and largest change first.
+func exportAnnotationSection(changes []git.Change, notes map[string]string, lines map[string]int) string {
+	type entry struct {
+		change git.Change
+		note   annotationNote
+	}
+	var entries []entry
+	for _, c := range changes {
+		note, ok := notes[c.Hash]
+		if !ok || isQuickNote(note) {
+			continue
+		}
+		entries = append(entries, entry{change: c, note: parseNote(note)})
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		ri, rj := riskRank(entries[i].note.Risk()), riskRank(entries[j].note.Risk())
+		if ri != rj {
+			return ri > rj
+		}
+		return lines[entries[i].change.Hash] > lines[entries[j].change.Hash]
+	})
+	if len(entries) > maxExportAnnotations {
+		entries = entries[:maxExportAnnotations]
+	}
+
+	var b strings.Builder
+	b.WriteString("## Key annotations\n")
+	for _, e := range entries {
+		c := e.change
+		fmt.Fprintf(&b, "\n### %s %s\n\n%s, %s", c.Short(), c.Message, c.Date, c.Name)
+		if risk := e.note.Risk(); risk != "" {
+			fmt.Fprintf(&b, "; risk %s", risk)
+		}
+		if labels := e.note.Labels(); len(labels) > 0 {
+			fmt.Fprintf(&b, "; labels %s", strings.Join(labels, ", "))
+		}
+		fmt.Fprintf(&b, "\n\n%s\n", strings.TrimSpace(truncate(e.note.Text, maxExportNote)))
+	}
+	return b.String()
+}
+
+// nestHeadings moves the Markdown headings of doc one level down, outside
+// fenced code blocks, so that they sit under the section's own heading.
+func nestHeadings(doc string) string {
+	lines := strings.Split(doc, "\n")
+	fenced := false
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "```"):
+			fenced = !fenced
+		case !fenced && strings.HasPrefix(line, "#"):
+			if h := strings.TrimLeft(line, "#"); h == "" || h[0] == ' ' {
+				lines[i] = "#" + line
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// topAreas renders the n areas with the most commits.
+func topAreas(areas map[string]int, n int) string {
+	names := make([]string, 0, len(areas))
+	for a := range areas {
+		names = append(names, a)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if areas[names[i]] != areas[names[j]] {
+			return areas[names[i]] > areas[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > n {
+		names = names[:n]
+	}
+	for i, a := range names {
+		names[i] = fmt.Sprintf("%s (%d)", a, areas[a])
+	}
+	return strings.Join(names, ", ")
+}
+
+// noteGist returns the first line of an annotation's text, shortened to
+// maxExportGist.
+func noteGist(note string) string {
+	gist, _, _ := strings.Cut(strings.TrimSpace(parseNote(note).Text), "\n")
+	if len(gist) > maxExportGist {
+		gist = strings.TrimSpace(gist[:maxExportGist]) + "..."
+	}
+	return gist
+}
+
+// trimEntries keeps the heading and the leading "###" entries of a section
+// that fit in maxChars, so that entries are dropped whole; it falls back to
+// TrimLines when not even the first entry fits.
+func trimEntries(text string, maxChars int) string {
+	parts := strings.SplitAfter(text, "\n\n### ")
+	if len(parts) < 2 || len(parts[0])+len(parts[1]) > maxChars {
+		return prompt.TrimLines(text, maxChars)
+	}
+	// Each part but the last ends with the "\n\n### " opening the next one.
+	kept := strings.TrimSuffix(parts[0], "\n### ")
+	size := len(kept)
+	n := 0
+	for _, p := range parts[1:] {
+		entry := "\n### " + strings.TrimSuffix(p, "\n### ")
+		if size+len(entry) > maxChars {
+			break
+		}
+		kept += entry
+		size += len(entry)
+		n++
+	}
+	if !strings.HasSuffix(kept, "\n") {
+		kept += "\n"
+	}
+	if omitted := len(parts) - 1 - n; omitted > 0 {
+		kept += fmt.Sprintf("\n[%d more entries omitted to fit the token budget]\n", omitted)
+	}
+	return kept
+}
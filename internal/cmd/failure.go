@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yourorg/arc-git/internal/config"
+	"github.com/yourorg/arc-git/internal/git"
+)
+
+// Failure codes reported with failed results, so that automation can branch
+// on the kind of failure rather than parse messages.
+const (
+	failureAuth            = "auth"
+	failureRateLimit       = "rate_limit"
+	failureContextTooLarge = "context_too_large"
+	failureModel           = "model_unavailable"
+	failureTimeout         = "timeout"
+	failureNetwork         = "network"
+	failureGit             = "git"
+	failurePolicy          = "policy"
+	failureUnknown         = "unknown"
+)
+
+// failureHints suggests a remedy for each failure code.
+var failureHints = map[string]string{
+	failureAuth:            "The API key was rejected; generate a new key or check which provider it belongs to",
+	failureRateLimit:       "The provider is rate limiting requests; re-run later, completed commits are skipped",
+	failureContextTooLarge: "The prompt is too large for the model; set context.max_tokens in .arc-git.yaml to its window, use --tier quick for this commit, or pass --model with a larger context window",
+	failureModel:           "The model may be unavailable for this provider; pass --model with a supported model",
+	failureTimeout:         "The request took too long; raise --request-timeout or use --tier quick for very large commits",
+	failureNetwork:         "Check network access and proxy settings (HTTPS_PROXY) for the provider endpoint",
+	failureGit:             "Check that the commit exists locally (git fetch) and re-run with --verbose to see the git command",
+	failurePolicy:          "The repository policy blocked this; the message names the rule and the config that defines it",
+	failureUnknown:         "Re-run with --debug for request details",
+}
+
+// failure classifies a failed result.
+type failure struct {
+	Code string `json:"code,omitempty"`
+	Hint string `json:"hint,omitempty"`
+}
+
+// classifyFailure returns the failure code and hint for err. Providers do
+// not share error types, so AI failures are recognized by their messages.
+func classifyFailure(err error) failure {
+	code := failureUnknown
+	var (
+		gitErr    *git.Error
+		violation *config.PolicyViolation
+	)
+	msg := strings.ToLower(err.Error())
+	switch {
+	case errors.As(err, &gitErr):
+		code = failureGit
+	case errors.As(err, &violation):
+		code = failurePolicy
+	case containsAny(msg, "401", "403", "unauthorized", "invalid x-api-key", "authentication", "api key"):
+		code = failureAuth
+	case containsAny(msg, "429", "rate limit", "rate_limit", "too many requests", "overloaded", "529"):
+		code = failureRateLimit
+	case containsAny(msg, "context length", "context window", "prompt is too long", "too many tokens", "maximum context", "413", "request too large"):
+		code = failureContextTooLarge
+	case containsAny(msg, "404", "model"):
+		code = failureModel
+	case errors.Is(err, context.DeadlineExceeded) || containsAny(msg, "timed out", "timeout", "deadline"):
+		code = failureTimeout
+	case containsAny(msg, "connection", "no such host", "network", "tls", "eof"):
+		code = failureNetwork
+	}
+	return failure{Code: code, Hint: failureHints[code]}
+}
+
+// containsAny reports whether s contains any of substrs.
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// failureTally counts failed results by code, keeping the order in which
+// codes first appeared.
+type failureTally struct {
+	Counts map[string]int
+	order  []failure
+}
+
+// add counts f.
+func (t *failureTally) add(f failure) {
+	if t.Counts == nil {
+		t.Counts = make(map[string]int)
+	}
+	if t.Counts[f.Code] == 0 {
+		t.order = append(t.order, f)
+	}
+	t.Counts[f.Code]++
+}
+
+// print lists each failure code with its count and hint.
+func (t *failureTally) print() {
+	for _, f := range t.order {
+		fmt.Printf("  %s: %d - %s\n", f.Code, t.Counts[f.Code], f.Hint)
+	}
+}
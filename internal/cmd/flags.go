@@ -0,0 +1,374 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// Limits on the stale flags and references included in the prompt.
+const (
+	maxStaleFlags     = 15
+	maxFlagReferences = 10
+)
+
+// newFlagsCmd creates the flags subcommand.
+func newFlagsCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		since       string
+		pattern     string
+		staleDays   int
+		noNarrative bool
+		aiOpts      aiFlags
+		outputOpts  output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "flags",
+		Short: "Track feature-flag lifecycles and flag long-lived temporary flags",
+		Long: `Track feature flags through history and flag the ones that outlived their
+welcome.
+
+Flags are the matches of --pattern in added and removed diff lines. If the
+pattern has a capture group, the first group is the flag name. For every flag
+the command reports when it was introduced, whether it is still referenced at
+HEAD (and where), and when it was removed. Flags still referenced after
+--stale-days are reported as stale, and the AI comments on the likely cleanup
+work for each one. Use --no-narrative to skip the AI step.
+
+The pattern is used both by git log -G and by Go, so stick to the
+POSIX extended syntax both understand.`,
+		Example: `  # Flag lifecycles over the last six months
+  arc-git flags --since 6m --pattern 'feature\.[A-Za-z_]+'
+
+  # LaunchDarkly-style keys, capturing just the key
+  arc-git flags --pattern 'variation\("([a-z0-9-]+)"' --stale-days 60
+
+  # Lifecycles only, as JSON
+  arc-git flags --no-narrative --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return errors.NewCLIError(fmt.Sprintf("invalid --pattern: %v", err)).
+					WithHint("Use a POSIX extended regular expression, e.g. 'feature\\.[A-Za-z_]+'")
+			}
+
+			return runFlags(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, gitSince(since), re, staleDays, noNarrative, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "1y", "Only scan history more recent than this (1y, 6m, 2w, 30d, or a git date)")
+	cmd.Flags().StringVar(&pattern, "pattern", `feature\.[A-Za-z_]+`, "Regular expression matching flag references")
+	cmd.Flags().IntVar(&staleDays, "stale-days", 90, "Age in days after which a live flag is reported as stale")
+	cmd.Flags().BoolVar(&noNarrative, "no-narrative", false, "Skip the AI cleanup commentary")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// flagEvent is a commit in a flag's lifecycle.
+type flagEvent struct {
+	Hash    string `json:"hash"`
+	Date    string `json:"date"`
+	Message string `json:"message"`
+
+	time time.Time
+}
+
+// featureFlag is the lifecycle of one flag.
+type featureFlag struct {
+	Name       string     `json:"name"`
+	Introduced *flagEvent `json:"introduced,omitempty"`
+	Removed    *flagEvent `json:"removed,omitempty"`
+	Alive      bool       `json:"alive"`
+	AgeDays    int        `json:"age_days,omitempty"`
+	Stale      bool       `json:"stale"`
+	References int        `json:"references"`
+	Files      []string   `json:"files,omitempty"`
+	Changes    int        `json:"changes"`
+
+	lastRemoval *flagEvent
+	refs        []string
+}
+
+// runFlags implements the flags workflow.
+func runFlags(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, since string, re *regexp.Regexp, staleDays int, noNarrative bool, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	log.Info("Scanning history for flag changes", "since", since, "pattern", re.String())
+	flags, err := scanFlagHistory(ctx, since, re)
+	if err != nil {
+		return err
+	}
+	if err := scanLiveFlags(ctx, re, flags); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var list []*featureFlag
+	for _, f := range flags {
+		if f.Alive && f.Introduced == nil {
+			f.Introduced = firstIntroduction(ctx, f.Name)
+		}
+		switch {
+		case f.Alive && f.Introduced != nil:
+			f.AgeDays = int(now.Sub(f.Introduced.time).Hours() / 24)
+			f.Stale = f.AgeDays >= staleDays
+		case !f.Alive:
+			f.Removed = f.lastRemoval
+		}
+		list = append(list, f)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Stale != b.Stale {
+			return a.Stale
+		}
+		if a.Alive != b.Alive {
+			return a.Alive
+		}
+		if a.AgeDays != b.AgeDays {
+			return a.AgeDays > b.AgeDays
+		}
+		return a.Name < b.Name
+	})
+
+	var stale []*featureFlag
+	for _, f := range list {
+		if f.Stale {
+			stale = append(stale, f)
+		}
+	}
+	log.Info("Found flags", "total", len(list), "stale", len(stale))
+
+	var narrative string
+	if !noNarrative && len(stale) > 0 {
+		service, err := newAIService(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		log.Info("Generating cleanup commentary", "flags", min(len(stale), maxStaleFlags))
+		systemPrompt, userPrompt := prompt.Flags(describeStaleFlags(stale))
+		narrative, err = runPrompt(ctx, service, systemPrompt, userPrompt, aiOpts.modelFor(ctx, prompt.FlagsModel))
+		if err != nil {
+			return fmt.Errorf("failed to generate commentary: %w", err)
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"since":      since,
+			"pattern":    re.String(),
+			"stale_days": staleDays,
+			"flags":      list,
+			"narrative":  narrative,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		if len(list) == 0 {
+			fmt.Println("No flags matched the pattern.")
+			return nil
+		}
+		fmt.Printf("%-36s %-8s %6s %5s  %s\n", "FLAG", "STATUS", "AGE", "REFS", "INTRODUCED / REMOVED")
+		for _, f := range list {
+			status, age, event := "removed", "-", ""
+			switch {
+			case f.Stale:
+				status = "stale"
+			case f.Alive:
+				status = "live"
+			}
+			if f.Alive && f.Introduced != nil {
+				age = fmt.Sprintf("%dd", f.AgeDays)
+			}
+			if f.Introduced != nil {
+				event = f.Introduced.Hash + " " + f.Introduced.Date
+			}
+			if f.Removed != nil {
+				event += " -> " + f.Removed.Hash + " " + f.Removed.Date
+			}
+			fmt.Printf("%-36s %-8s %6s %5d  %s\n", f.Name, status, age, f.References, event)
+		}
+		if narrative != "" {
+			fmt.Printf("\n%s\n", narrative)
+		}
+	}
+
+	return nil
+}
+
+// scanFlagHistory records, for every flag referenced in the added or removed
+// lines of commits since since, where it was introduced and last removed.
+func scanFlagHistory(ctx context.Context, since string, re *regexp.Regexp) (map[string]*featureFlag, error) {
+	out, err := git.Run(ctx, "log", "--no-merges", "--reverse", "--since", since, "-U0", "--no-color",
+		"--format=%x1e%H%x1f%at%x1f%ad%x1f%s", "-G", re.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan history: %w", err)
+	}
+
+	flags := make(map[string]*featureFlag)
+	for _, record := range strings.Split(out, "\x1e") {
+		header, patch, _ := strings.Cut(record, "\n")
+		fields := strings.Split(header, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		ts, _ := strconv.ParseInt(fields[1], 10, 64)
+		event := &flagEvent{Hash: shortHash(fields[0]), Date: fields[2], Message: fields[3], time: time.Unix(ts, 0)}
+
+		added := make(map[string]int)
+		removed := make(map[string]int)
+		for _, line := range strings.Split(patch, "\n") {
+			switch {
+			case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			case strings.HasPrefix(line, "+"):
+				for _, name := range flagNames(re, line[1:]) {
+					added[name]++
+				}
+			case strings.HasPrefix(line, "-"):
+				for _, name := range flagNames(re, line[1:]) {
+					removed[name]++
+				}
+			}
+		}
+
+		touch := func(name string) *featureFlag {
+			f, ok := flags[name]
+			if !ok {
+				f = &featureFlag{Name: name}
+				flags[name] = f
+			}
+			f.Changes++
+			return f
+		}
+		for name, n := range added {
+			f := touch(name)
+			if f.Introduced == nil {
+				f.Introduced = event
+			}
+			if removed[name] > n {
+				f.lastRemoval = event
+			}
+		}
+		for name, n := range removed {
+			if _, ok := added[name]; ok {
+				continue
+			}
+			f := touch(name)
+			if n > 0 {
+				f.lastRemoval = event
+			}
+		}
+	}
+	return flags, nil
+}
+
+// scanLiveFlags marks the flags still referenced at HEAD, adding flags that
+// predate the scanned history.
+func scanLiveFlags(ctx context.Context, re *regexp.Regexp, flags map[string]*featureFlag) error {
+	out, err := git.Grep(ctx, re.String(), "HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to search HEAD: %w", err)
+	}
+	if out == "" {
+		return nil
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		rest := strings.TrimPrefix(line, "HEAD:")
+		parts := strings.SplitN(rest, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		path, lineNo, text := parts[0], parts[1], parts[2]
+		for _, name := range flagNames(re, text) {
+			f, ok := flags[name]
+			if !ok {
+				f = &featureFlag{Name: name}
+				flags[name] = f
+			}
+			f.Alive = true
+			f.References++
+			f.Files = appendUnique(f.Files, path)
+			f.refs = append(f.refs, fmt.Sprintf("%s:%s: %s", path, lineNo, strings.TrimSpace(text)))
+		}
+	}
+	return nil
+}
+
+// firstIntroduction finds the oldest commit that added name anywhere in
+// history, for live flags introduced before the scanned window.
+func firstIntroduction(ctx context.Context, name string) *flagEvent {
+	out, err := git.Run(ctx, "log", "--reverse", "--format=%H%x1f%at%x1f%ad%x1f%s", "-S", name, "HEAD")
+	if err != nil {
+		return nil
+	}
+	first, _, _ := strings.Cut(out, "\n")
+	fields := strings.Split(first, "\x1f")
+	if len(fields) != 4 {
+		return nil
+	}
+	ts, _ := strconv.ParseInt(fields[1], 10, 64)
+	return &flagEvent{Hash: shortHash(fields[0]), Date: fields[2], Message: fields[3], time: time.Unix(ts, 0)}
+}
+
+// flagNames returns the flag names matched in text: the first capture group
+// when the pattern has one, otherwise the whole match.
+func flagNames(re *regexp.Regexp, text string) []string {
+	var names []string
+	for _, m := range re.FindAllStringSubmatch(text, -1) {
+		name := m[0]
+		if len(m) > 1 && m[1] != "" {
+			name = m[1]
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// describeStaleFlags renders stale flags for the flags prompt.
+func describeStaleFlags(flags []*featureFlag) string {
+	var b strings.Builder
+	for i, f := range flags {
+		if i == maxStaleFlags {
+			fmt.Fprintf(&b, "(%d more stale flags omitted)\n", len(flags)-maxStaleFlags)
+			break
+		}
+		fmt.Fprintf(&b, "--- %s\n", f.Name)
+		if f.Introduced != nil {
+			fmt.Fprintf(&b, "Introduced %d days ago by %s %q\n", f.AgeDays, f.Introduced.Hash, f.Introduced.Message)
+		}
+		fmt.Fprintf(&b, "Referenced %d times in %d files:\n", f.References, len(f.Files))
+		for j, ref := range f.refs {
+			if j == maxFlagReferences {
+				fmt.Fprintf(&b, "  ... %d more\n", len(f.refs)-maxFlagReferences)
+				break
+			}
+			fmt.Fprintf(&b, "  %s\n", truncate(ref, 200))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-git/internal/config"
+	"github.com/yourorg/arc-git/internal/git"
+)
+
+// refreshedTrailer is the trailer key notes refresh records the commit it
+// refreshed an annotation at under.
+const refreshedTrailer = "refreshed"
+
+// maxFreshnessFiles bounds the files of a commit that staleness is measured
+// on, so that sweeping commits do not exceed the command line.
+const maxFreshnessFiles = 200
+
+// freshness is how much of the code an annotation describes has changed
+// since it was written, compared to HEAD.
+type freshness struct {
+	// Since is the commit changes are counted from: the annotated commit, or
+	// the one a refresh was made at.
+	Since string `json:"since"`
+	// Rewritten is the share of the lines the commit's files had at Since
+	// that later commits changed or removed.
+	Rewritten float64 `json:"rewritten"`
+	// Files lists the files that changed since, most rewritten first.
+	Files      []string `json:"files,omitempty"`
+	Historical bool     `json:"historical"`
+	Reason     string   `json:"reason,omitempty"`
+}
+
+// annotationFreshness measures the freshness of the annotation note of the
+// commit hash under rules.
+func annotationFreshness(ctx context.Context, hash string, note annotationNote, rules config.Freshness) (freshness, error) {
+	since := hash
+	if at := note.Trailers[refreshedTrailer]; at != "" && git.Exists(ctx, at) {
+		since = at
+	}
+	f := freshness{Since: shortHash(since)}
+
+	files, err := git.TouchedFiles(ctx, hash)
+	if err != nil || len(files) == 0 {
+		return f, err
+	}
+	files = files[:min(len(files), maxFreshnessFiles)]
+	before, err := git.DiffNumStat(ctx, "", since, files...)
+	if err != nil {
+		return f, err
+	}
+	after, err := git.DiffNumStat(ctx, since, "HEAD", files...)
+	if err != nil {
+		return f, err
+	}
+
+	lines := make(map[string]int)
+	total := 0
+	for _, s := range before {
+		lines[s.Path] = s.Added
+		total += s.Added
+	}
+	if total == 0 {
+		return f, nil
+	}
+	removed := 0
+	share := make(map[string]float64)
+	for _, s := range after {
+		if s.Binary || s.Added+s.Deleted == 0 {
+			continue
+		}
+		removed += s.Deleted
+		share[s.Path] = float64(s.Deleted) / float64(max(lines[s.Path], 1))
+		f.Files = append(f.Files, s.Path)
+	}
+	sort.SliceStable(f.Files, func(i, j int) bool { return share[f.Files[i]] > share[f.Files[j]] })
+	f.Rewritten = float64(min(removed, total)) / float64(total)
+
+	switch {
+	case f.Rewritten >= rules.Threshold():
+		f.Historical = true
+		f.Reason = fmt.Sprintf("%.0f%% of the code it describes was rewritten since", f.Rewritten*100)
+	case len(f.Files) > 0 && rules.TTL > 0:
+		written, err := git.CommitTime(ctx, since)
+		if err == nil && rules.Expired(written, time.Now()) {
+			f.Historical = true
+			f.Reason = fmt.Sprintf("older than %s, and its code changed since", describeTTL(time.Duration(rules.TTL)))
+		}
+	}
+	return f, nil
+}
+
+// describeTTL renders a TTL in days, or in hours when it is shorter than two
+// days.
+func describeTTL(d time.Duration) string {
+	if d < 48*time.Hour {
+		return fmt.Sprintf("%.0f hours", d.Hours())
+	}
+	return fmt.Sprintf("%.0f days", d.Hours()/24)
+}
+
+// freshnessFor returns the freshness of the annotation of hash when the
+// repository config has a freshness block, or nil. Failures to measure it
+// leave the annotation unmarked.
+func freshnessFor(ctx context.Context, hash string, note annotationNote) *freshness {
+	rules := repoConfig(ctx).Freshness
+	if !rules.Enabled() {
+		return nil
+	}
+	f, err := annotationFreshness(ctx, hash, note, rules)
+	if err != nil {
+		return nil
+	}
+	return &f
+}
+
+// historicalNotice is the line printed above historical annotations.
+func historicalNotice(f *freshness) string {
+	return "Historical context only: " + f.Reason
+}
+
+// refreshNote returns note with its text replaced by text and a Refreshed
+// trailer for the commit at, keeping its other trailers.
+func refreshNote(note, text, at string) string {
+	note = strings.TrimSpace(note)
+	parsed := parseNote(note)
+	var kept []string
+	if i := strings.LastIndex(note, "\n\n"); i >= 0 && (len(parsed.Trailers) > 0 || len(parsed.Anchors) > 0) {
+		for _, line := range strings.Split(note[i+2:], "\n") {
+			m := trailerPattern.FindStringSubmatch(strings.TrimSpace(line))
+			if m != nil && strings.EqualFold(m[1], refreshedTrailer) {
+				continue
+			}
+			kept = append(kept, line)
+		}
+	}
+	out := strings.TrimSpace(text)
+	if len(kept) > 0 {
+		out += "\n\n" + strings.Join(kept, "\n")
+	}
+	return addTrailers(out, "Refreshed: "+at)
+}
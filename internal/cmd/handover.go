@@ -0,0 +1,577 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// Limits on the sections of a handover document.
+const (
+	maxHandoverContributors = 10
+	maxHandoverRecent       = 10
+	maxHandoverKeyCommits   = 8
+	maxHandoverRisks        = 5
+	maxHandoverCoupling     = 10
+	maxHandoverNote         = 600
+)
+
+// Handover document formats.
+const (
+	formatMarkdown = "markdown"
+	formatHTML     = "html"
+)
+
+// newHandoverCmd creates the handover subcommand.
+func newHandoverCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		pathSpec    string
+		owner       string
+		since       string
+		format      string
+		outFile     string
+		noNarrative bool
+		aiOpts      aiFlags
+		outputOpts  output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "handover",
+		Short: "Compile an ownership handover document for a path",
+		Long: `Compile everything a new owner of part of the codebase needs to know.
+
+The history of --path is mined for:
+- Contributors: who changed it, how often, and most recently when
+- Open risk areas: subdirectories with revert, fix-chain, and follow-up
+  signals, as reported by "arc-git instability"
+- Recent changes: the latest commits touching the path
+- Key commits: the largest changes, with their annotations when present
+- Coupling map: areas outside the path that usually change together with it
+
+The AI writes a history narrative from these facts (skip it with
+--no-narrative), and the result is rendered as a Markdown or HTML document.
+--owner names the incoming owner; it is matched as an email prefix to show
+how familiar they already are with the path.`,
+		Example: `  # Handover document for the billing service
+  arc-git handover --path services/billing --owner alice@
+
+  # As HTML, written to a file
+  arc-git handover --path services/billing --owner alice@ --format html --out billing-handover.html
+
+  # Facts only, as JSON
+  arc-git handover --path services/billing --no-narrative --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if pathSpec == "" {
+				return errors.NewCLIError("--path is required").
+					WithHint("Pass the directory being handed over, e.g. --path services/billing")
+			}
+			if format != formatMarkdown && format != formatHTML {
+				return errors.NewCLIError(fmt.Sprintf("unknown --format %q", format)).
+					WithHint("Use --format markdown or --format html")
+			}
+
+			return runHandover(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, handoverOptions{
+				path:        strings.Trim(path.Clean(pathSpec), "/"),
+				owner:       owner,
+				since:       gitSince(since),
+				format:      format,
+				outFile:     outFile,
+				noNarrative: noNarrative,
+			}, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&pathSpec, "path", "", "Directory being handed over (e.g., services/billing)")
+	cmd.Flags().StringVar(&owner, "owner", "", "Incoming owner, matched as an email prefix (e.g., alice@)")
+	cmd.Flags().StringVar(&since, "since", "2y", "Only consider history more recent than this (1y, 6m, 2w, 30d, or a git date)")
+	cmd.Flags().StringVar(&format, "format", formatMarkdown, "Document format: markdown or html")
+	cmd.Flags().StringVar(&outFile, "out", "", "Write the document to this file instead of stdout")
+	cmd.Flags().BoolVar(&noNarrative, "no-narrative", false, "Skip the AI history narrative")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// handoverOptions holds the handover flags.
+type handoverOptions struct {
+	path        string
+	owner       string
+	since       string
+	format      string
+	outFile     string
+	noNarrative bool
+}
+
+// handoverContributor is one author of changes to the handed-over path.
+type handoverContributor struct {
+	Author  string `json:"author"`
+	Email   string `json:"email"`
+	Commits int    `json:"commits"`
+	Last    string `json:"last"`
+
+	last time.Time
+}
+
+// handoverCommit is a commit cited in the handover document.
+type handoverCommit struct {
+	Hash    string `json:"hash"`
+	Date    string `json:"date"`
+	Author  string `json:"author"`
+	Message string `json:"message"`
+	Lines   int    `json:"lines,omitempty"`
+	Note    string `json:"note,omitempty"`
+}
+
+// handoverCoupling is an area outside the path that changes with it.
+type handoverCoupling struct {
+	Area    string `json:"area"`
+	Commits int    `json:"commits"`
+	Percent int    `json:"percent"`
+}
+
+// handoverReport is the data behind a handover document.
+type handoverReport struct {
+	Path         string                `json:"path"`
+	Owner        string                `json:"owner,omitempty"`
+	OwnerCommits int                   `json:"owner_commits"`
+	Since        string                `json:"since"`
+	Commits      int                   `json:"commits"`
+	First        string                `json:"first,omitempty"`
+	Last         string                `json:"last,omitempty"`
+	Contributors []handoverContributor `json:"contributors"`
+	Risks        []*instabilityArea    `json:"risks"`
+	Recent       []handoverCommit      `json:"recent"`
+	KeyCommits   []handoverCommit      `json:"key_commits"`
+	Coupling     []handoverCoupling    `json:"coupling"`
+	Narrative    string                `json:"narrative,omitempty"`
+}
+
+// runHandover implements the handover workflow.
+func runHandover(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, opts handoverOptions, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	log.Info("Scanning history", "path", opts.path, "since", opts.since)
+	// --full-diff lists every file of each commit, not just those under the
+	// path, which the coupling map needs.
+	changes, err := git.LogChanges(ctx, "--no-merges", "--reverse", "--full-diff", "--since", opts.since, "--", opts.path)
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+	if len(changes) == 0 {
+		return errors.NewCLIError(fmt.Sprintf("no commits touch %s since %s", opts.path, opts.since)).
+			WithHint("Check the path is relative to the repository root, or widen --since")
+	}
+
+	report := &handoverReport{
+		Path:    opts.path,
+		Owner:   opts.owner,
+		Since:   opts.since,
+		Commits: len(changes),
+		First:   changes[0].Date,
+		Last:    changes[len(changes)-1].Date,
+	}
+	report.Contributors, report.OwnerCommits = handoverContributors(ctx, changes, opts.owner)
+	report.Risks = handoverRisks(changes, opts.path)
+	report.Coupling = handoverCouplingMap(changes, opts.path)
+
+	for i := len(changes) - 1; i >= 0 && len(report.Recent) < maxHandoverRecent; i-- {
+		report.Recent = append(report.Recent, handoverCommitOf(changes[i]))
+	}
+	report.KeyCommits, err = handoverKeyCommits(ctx, changes, opts)
+	if err != nil {
+		return err
+	}
+	log.Info("Compiled handover facts", "commits", len(changes), "risks", len(report.Risks), "coupled", len(report.Coupling))
+
+	if !opts.noNarrative {
+		service, err := newAIService(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		log.Info("Generating history narrative")
+		owner := opts.owner
+		if owner == "" {
+			owner = "(unspecified)"
+		}
+		systemPrompt, userPrompt := prompt.Handover(opts.path, owner, describeHandover(report))
+		report.Narrative, err = runPrompt(ctx, service, systemPrompt, userPrompt, aiOpts.modelFor(ctx, prompt.HandoverModel))
+		if err != nil {
+			return fmt.Errorf("failed to generate narrative: %w", err)
+		}
+	}
+
+	doc := renderHandover(report)
+	if opts.format == formatHTML {
+		doc = markdownToHTML("Handover: "+report.Path, doc)
+	}
+
+	if opts.outFile != "" {
+		if err := os.WriteFile(opts.outFile, []byte(doc), 0o644); err != nil {
+			return fmt.Errorf("failed to write handover document: %w", err)
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"report":   report,
+			"format":   opts.format,
+			"document": doc,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		if opts.outFile != "" {
+			fmt.Printf("Wrote handover document for %s to %s\n", opts.path, opts.outFile)
+			return nil
+		}
+		fmt.Print(doc)
+	}
+
+	return nil
+}
+
+// handoverContributors counts commits per person, most active first, and the
+// commits by the person whose email starts with owner.
+func handoverContributors(ctx context.Context, changes []git.Change, owner string) ([]handoverContributor, int) {
+	byEmail := make(map[string]*handoverContributor)
+	ownerCommits := 0
+	for _, c := range changes {
+		name, email := canonicalIdentity(ctx, c.Name, c.Email)
+		if owner != "" && strings.HasPrefix(email, strings.ToLower(owner)) {
+			ownerCommits++
+		}
+		h, ok := byEmail[email]
+		if !ok {
+			h = &handoverContributor{Author: fmt.Sprintf("%s <%s>", name, email), Email: email}
+			byEmail[email] = h
+		}
+		h.Commits++
+		if c.Time.After(h.last) {
+			h.last, h.Last = c.Time, c.Date
+		}
+	}
+
+	list := make([]handoverContributor, 0, len(byEmail))
+	for _, h := range byEmail {
+		list = append(list, *h)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Commits != list[j].Commits {
+			return list[i].Commits > list[j].Commits
+		}
+		return list[i].Email < list[j].Email
+	})
+	return list, ownerCommits
+}
+
+// handoverRisks runs instability detection over the files under root,
+// grouping by its immediate subdirectories.
+func handoverRisks(changes []git.Change, root string) []*instabilityArea {
+	scoped := make([]git.Change, 0, len(changes))
+	for _, c := range changes {
+		inside, _ := splitByPath(c.Files, root)
+		if len(inside) > 0 {
+			c.Files = inside
+			scoped = append(scoped, c)
+		}
+	}
+	depth := strings.Count(root, "/") + 2
+	if root == "." {
+		depth = 1
+	}
+	return rankAreas(detectInstability(scoped, depth, 24*time.Hour), maxHandoverRisks)
+}
+
+// handoverCouplingMap counts, for each area outside root, the commits that
+// changed it together with root.
+func handoverCouplingMap(changes []git.Change, root string) []handoverCoupling {
+	counts := make(map[string]int)
+	for _, c := range changes {
+		_, outside := splitByPath(c.Files, root)
+		for _, area := range areasOf(outside, 2) {
+			counts[area]++
+		}
+	}
+
+	list := make([]handoverCoupling, 0, len(counts))
+	for area, n := range counts {
+		list = append(list, handoverCoupling{Area: area, Commits: n, Percent: n * 100 / len(changes)})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Commits != list[j].Commits {
+			return list[i].Commits > list[j].Commits
+		}
+		return list[i].Area < list[j].Area
+	})
+	if len(list) > maxHandoverCoupling {
+		list = list[:maxHandoverCoupling]
+	}
+	return list
+}
+
+// handoverKeyCommits returns the commits with the most changed lines under
+// the path, oldest first, with their annotations.
+func handoverKeyCommits(ctx context.Context, changes []git.Change, opts handoverOptions) ([]handoverCommit, error) {
+	lines, err := git.ChangedLines(ctx, "--no-merges", "--since", opts.since, "--", opts.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure changes: %w", err)
+	}
+
+	ranked := make([]int, len(changes))
+	for i := range changes {
+		ranked[i] = i
+	}
+	sort.SliceStable(ranked, func(a, b int) bool {
+		return lines[changes[ranked[a]].Hash] > lines[changes[ranked[b]].Hash]
+	})
+	if len(ranked) > maxHandoverKeyCommits {
+		ranked = ranked[:maxHandoverKeyCommits]
+	}
+	sort.Ints(ranked)
+
+	key := make([]handoverCommit, 0, len(ranked))
+	for _, i := range ranked {
+		c := handoverCommitOf(changes[i])
+		c.Lines = lines[changes[i].Hash]
+		if note, err := git.ShowNote(ctx, changes[i].Hash, "ai"); err == nil {
+			c.Note = truncate(note, maxHandoverNote)
+		}
+		key = append(key, c)
+	}
+	return key, nil
+}
+
+// handoverCommitOf converts a change for the report.
+func handoverCommitOf(c git.Change) handoverCommit {
+	return handoverCommit{Hash: c.Short(), Date: c.Date, Author: c.Author, Message: c.Message}
+}
+
+// splitByPath partitions files into those under root and the rest.
+func splitByPath(files []string, root string) (inside, outside []string) {
+	for _, f := range files {
+		if root == "." || f == root || strings.HasPrefix(f, root+"/") {
+			inside = append(inside, f)
+		} else {
+			outside = append(outside, f)
+		}
+	}
+	return inside, outside
+}
+
+// describeHandover renders the report facts for the handover prompt.
+func describeHandover(r *handoverReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d commits from %s to %s.\n", r.Commits, r.First, r.Last)
+	if r.Owner != "" {
+		fmt.Fprintf(&b, "The incoming owner authored %d of them.\n", r.OwnerCommits)
+	}
+
+	b.WriteString("\n## Contributors\n")
+	for i, c := range r.Contributors {
+		if i == maxHandoverContributors {
+			break
+		}
+		fmt.Fprintf(&b, "- %s: %d commits, last %s\n", c.Author, c.Commits, c.Last)
+	}
+
+	b.WriteString("\n## Key commits (largest changes)\n")
+	for _, c := range r.KeyCommits {
+		fmt.Fprintf(&b, "- %s %s (%s, %d lines)\n", c.Hash, c.Message, c.Date, c.Lines)
+		if c.Note != "" {
+			fmt.Fprintf(&b, "  Annotation: %s\n", strings.ReplaceAll(c.Note, "\n", "\n  "))
+		}
+	}
+
+	b.WriteString("\n## Recent changes\n")
+	for _, c := range r.Recent {
+		fmt.Fprintf(&b, "- %s %s (%s)\n", c.Hash, c.Message, c.Date)
+	}
+
+	if len(r.Risks) > 0 {
+		b.WriteString("\n## Risk areas\n")
+		b.WriteString(strings.ReplaceAll(describeAreas(r.Risks), "## ", "### "))
+	}
+
+	if len(r.Coupling) > 0 {
+		b.WriteString("\n## Coupled areas\n")
+		for _, c := range r.Coupling {
+			fmt.Fprintf(&b, "- %s changed in %d%% of commits (%d)\n", c.Area, c.Percent, c.Commits)
+		}
+	}
+	return b.String()
+}
+
+// renderHandover renders the report as a Markdown document.
+func renderHandover(r *handoverReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Handover: %s\n\n", r.Path)
+	if r.Owner != "" {
+		fmt.Fprintf(&b, "- Incoming owner: %s (%d prior commits to this path)\n", r.Owner, r.OwnerCommits)
+	}
+	fmt.Fprintf(&b, "- History: %d commits, %s to %s\n", r.Commits, r.First, r.Last)
+	fmt.Fprintf(&b, "- Generated: %s\n\n", time.Now().Format("2006-01-02"))
+
+	if r.Narrative != "" {
+		fmt.Fprintf(&b, "## History\n\n%s\n\n", strings.TrimSpace(r.Narrative))
+	}
+
+	b.WriteString("## Contributors\n\n")
+	for i, c := range r.Contributors {
+		if i == maxHandoverContributors {
+			fmt.Fprintf(&b, "- ... %d more\n", len(r.Contributors)-i)
+			break
+		}
+		fmt.Fprintf(&b, "- %s: %d commits, last on %s\n", c.Author, c.Commits, c.Last)
+	}
+
+	b.WriteString("\n## Open risk areas\n\n")
+	if len(r.Risks) == 0 {
+		b.WriteString("No revert, fix-chain, or follow-up signals.\n")
+	}
+	for _, a := range r.Risks {
+		fmt.Fprintf(&b, "- `%s`: score %d (%d reverts, %d fix chains, %d follow-ups)\n", a.Path, a.Score, a.Reverts, a.FixChains, a.FollowUps)
+		for i, s := range a.Signals {
+			if i == 3 {
+				break
+			}
+			fmt.Fprintf(&b, "  - %s `%s` %s\n", s.Kind, s.Hash, s.Message)
+		}
+	}
+
+	b.WriteString("\n## Recent changes\n\n")
+	for _, c := range r.Recent {
+		fmt.Fprintf(&b, "- `%s` %s (%s, %s)\n", c.Hash, c.Message, c.Author, c.Date)
+	}
+
+	b.WriteString("\n## Key commits\n\n")
+	for _, c := range r.KeyCommits {
+		fmt.Fprintf(&b, "- `%s` %s (%d lines, %s)\n", c.Hash, c.Message, c.Lines, c.Date)
+		if c.Note != "" {
+			summary, _, _ := strings.Cut(c.Note, "\n")
+			fmt.Fprintf(&b, "  - %s\n", summary)
+		}
+	}
+
+	b.WriteString("\n## Coupling map\n\n")
+	if len(r.Coupling) == 0 {
+		b.WriteString("Changes to this path rarely touch other areas.\n")
+	}
+	for _, c := range r.Coupling {
+		fmt.Fprintf(&b, "- `%s`: changed together in %d%% of commits (%d)\n", c.Area, c.Percent, c.Commits)
+	}
+	return b.String()
+}
+
+// markdownToHTML converts the subset of Markdown used by handover documents
+// (headings, nested bullet and numbered lists, paragraphs, and code spans)
+// into a standalone HTML page.
+func markdownToHTML(title, md string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n</head>\n<body>\n", html.EscapeString(title))
+
+	// open holds the list tags currently open, outermost first; each has an
+	// unclosed <li>.
+	var open []string
+	closeLists := func(depth int) {
+		for len(open) > depth {
+			fmt.Fprintf(&b, "</li>\n</%s>\n", open[len(open)-1])
+			open = open[:len(open)-1]
+		}
+	}
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			fmt.Fprintf(&b, "<p>%s</p>\n", inlineHTML(strings.Join(para, " ")))
+			para = nil
+		}
+	}
+
+	for _, line := range strings.Split(md, "\n") {
+		trimmed := strings.TrimSpace(line)
+		indent := (len(line) - len(strings.TrimLeft(line, " "))) / 2
+		switch {
+		case trimmed == "":
+			flush()
+			closeLists(0)
+		case strings.HasPrefix(trimmed, "#"):
+			flush()
+			closeLists(0)
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			level = min(level, 6)
+			fmt.Fprintf(&b, "<h%d>%s</h%d>\n", level, inlineHTML(strings.TrimSpace(trimmed[level:])), level)
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") || numberedItem(trimmed) != "":
+			flush()
+			tag, text := "ul", trimmed[2:]
+			if item := numberedItem(trimmed); item != "" {
+				tag, text = "ol", item
+			}
+			indent = min(indent, len(open))
+			closeLists(indent + 1)
+			if len(open) == indent+1 {
+				b.WriteString("</li>\n")
+			} else {
+				fmt.Fprintf(&b, "<%s>\n", tag)
+				open = append(open, tag)
+			}
+			fmt.Fprintf(&b, "<li>%s", inlineHTML(text))
+		default:
+			closeLists(0)
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+	closeLists(0)
+
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// numberedItem returns the text of a "1. text" list item, or "".
+func numberedItem(line string) string {
+	digits := len(line) - len(strings.TrimLeft(line, "0123456789"))
+	if digits == 0 || !strings.HasPrefix(line[digits:], ". ") {
+		return ""
+	}
+	return line[digits+2:]
+}
+
+// inlineHTML escapes text and renders `code` and **bold** spans.
+func inlineHTML(text string) string {
+	var b strings.Builder
+	for i, part := range strings.Split(text, "`") {
+		if i%2 == 1 {
+			b.WriteString("<code>" + html.EscapeString(part) + "</code>")
+			continue
+		}
+		for j, s := range strings.Split(part, "**") {
+			if j%2 == 1 {
+				b.WriteString("<strong>" + html.EscapeString(s) + "</strong>")
+			} else {
+				b.WriteString(html.EscapeString(s))
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,536 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+	"gopkg.in/yaml.v3"
+)
+
+// hookCommand is what the installed hooks run.
+const hookCommand = "arc-git annotate --new-since-last-run --output quiet"
+
+// hookMarker comments the lines added to hook scripts.
+const hookMarker = "# arc-git: keep annotations current"
+
+// preCommitHookID identifies the hook added to pre-commit configs.
+const preCommitHookID = "arc-git-annotate"
+
+// installedHooks are the git hooks arc-git runs from: whatever a pull or a
+// rebase brings in gets annotated.
+var installedHooks = []string{"post-merge", "post-rewrite"}
+
+// Hook managers.
+const (
+	managerGit       = "git"
+	managerHusky     = "husky"
+	managerLefthook  = "lefthook"
+	managerPreCommit = "pre-commit"
+)
+
+// hookManagers lists the hook managers hooks install supports.
+var hookManagers = []string{managerGit, managerHusky, managerLefthook, managerPreCommit}
+
+// lefthookConfigs are the file names lefthook reads its config from.
+var lefthookConfigs = []string{"lefthook.yml", ".lefthook.yml", "lefthook.yaml", ".lefthook.yaml"}
+
+// newHooksCmd creates the hooks command group.
+func newHooksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "Manage the git hooks that run arc-git",
+		Long: `Manage the git hooks that keep annotations current by running
+"` + hookCommand + `" after every pull and rebase.`,
+	}
+
+	cmd.AddCommand(newHooksInstallCmd())
+
+	return cmd
+}
+
+// newHooksInstallCmd creates the hooks install subcommand.
+func newHooksInstallCmd() *cobra.Command {
+	var (
+		manager    string
+		dryRun     bool
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install the post-merge and post-rewrite hooks",
+		Long: `Install the post-merge and post-rewrite hooks that run
+"` + hookCommand + `", through whatever manages the
+repository's hooks, so that they actually run:
+- husky (a .husky directory, or core.hooksPath pointing into one): the hooks
+  are added to .husky/post-merge and .husky/post-rewrite
+- lefthook (lefthook.yml or a variant): an arc-git command is added to the
+  post-merge and post-rewrite stanzas; run "lefthook install" afterwards
+- pre-commit (.pre-commit-config.yaml): a local hook for the post-merge and
+  post-rewrite stages is added, and the stages to default_install_hook_types;
+  run "pre-commit install" afterwards
+- otherwise, hook scripts are written to the directory git runs hooks from,
+  which is core.hooksPath when it is set and .git/hooks if not
+
+Existing hooks are kept: the command is appended to hook scripts, and config
+files are edited rather than replaced (comments survive, but the YAML is
+re-indented). Hooks that already run arc-git are left alone. Use --manager to
+override the detection.`,
+		Example: `  # Install through whatever manages the hooks
+  arc-git hooks install
+
+  # See what would change first
+  arc-git hooks install --dry-run
+
+  # Write plain git hooks even though a manager config exists
+  arc-git hooks install --manager git`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if manager != "" && !slices.Contains(hookManagers, manager) {
+				return errors.NewCLIError(fmt.Sprintf("unknown hook manager %q", manager)).
+					WithHint("Use --manager " + strings.Join(hookManagers, ", --manager "))
+			}
+
+			return runHooksInstall(cmd.Context(), manager, dryRun, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&manager, "manager", "", "Hook manager to install through: "+strings.Join(hookManagers, ", ")+" (default: detected)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be installed without changing anything")
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// hookSetup is how a repository's hooks are run.
+type hookSetup struct {
+	Manager string `json:"manager"`
+	// File is the hooks directory, or the manager's config file.
+	File string `json:"file"`
+	// Active is false when the manager is configured but git does not run
+	// its hooks, e.g. when husky was never installed in this clone.
+	Active bool `json:"active"`
+}
+
+// hookAction is the outcome of installing one hook.
+type hookAction struct {
+	Hook   string `json:"hook"`
+	File   string `json:"file"`
+	Status string `json:"status"`
+	// Added is what was, or under --dry-run would be, added to File.
+	Added string `json:"added,omitempty"`
+}
+
+// detectHooks works out which hook manager runs the repository's hooks;
+// manager, when not empty, overrides the detection.
+func detectHooks(ctx context.Context, manager string) (hookSetup, error) {
+	out, err := git.Run(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return hookSetup{}, errors.NewCLIError("not inside a git work tree").
+			WithHint("Run the command inside the repository")
+	}
+	top := strings.TrimSpace(out)
+	dir, err := hooksDir(ctx)
+	if err != nil {
+		return hookSetup{}, fmt.Errorf("failed to locate the hooks directory: %w", err)
+	}
+	hooksPath, _ := git.Run(ctx, "config", "core.hooksPath")
+	hooksPath = strings.TrimSpace(hooksPath)
+
+	lefthook := ""
+	for _, name := range lefthookConfigs {
+		if fileExists(filepath.Join(top, name)) {
+			lefthook = filepath.Join(top, name)
+			break
+		}
+	}
+	husky := filepath.Join(top, ".husky")
+	preCommit := filepath.Join(top, ".pre-commit-config.yaml")
+
+	if manager == "" {
+		switch {
+		case strings.Contains(hooksPath, ".husky") || dirExists(husky):
+			manager = managerHusky
+		case lefthook != "":
+			manager = managerLefthook
+		case fileExists(preCommit):
+			manager = managerPreCommit
+		default:
+			manager = managerGit
+		}
+	}
+
+	setup := hookSetup{Manager: manager, Active: true}
+	switch manager {
+	case managerHusky:
+		setup.File = husky
+		setup.Active = strings.Contains(hooksPath, ".husky")
+	case managerLefthook:
+		setup.File = lefthook
+		if setup.File == "" {
+			setup.File = filepath.Join(top, lefthookConfigs[0])
+		}
+		setup.Active = hookRunsManager(dir, "lefthook")
+	case managerPreCommit:
+		setup.File = preCommit
+		setup.Active = hookRunsManager(dir, "pre-commit")
+	default:
+		setup.File = dir
+	}
+	return setup, nil
+}
+
+// hookRunsManager reports whether any hook script in dir hands over to the
+// named manager.
+func hookRunsManager(dir, name string) bool {
+	for _, hook := range installedHooks {
+		if data, err := os.ReadFile(filepath.Join(dir, hook)); err == nil && strings.Contains(string(data), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// runHooksInstall implements the hooks install workflow.
+func runHooksInstall(ctx context.Context, manager string, dryRun bool, out output.OutputOptions) error {
+	setup, actions, next, err := installHooks(ctx, manager, dryRun)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"manager": setup.Manager,
+			"file":    setup.File,
+			"dry_run": dryRun,
+			"actions": actions,
+			"next":    next,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		fmt.Printf("Hook manager: %s (%s)\n\n", setup.Manager, setup.File)
+		for _, a := range actions {
+			fmt.Printf("%-13s %s: %s\n", a.Hook, a.Status, a.File)
+			if dryRun && a.Added != "" {
+				for _, line := range strings.Split(strings.TrimRight(a.Added, "\n"), "\n") {
+					fmt.Printf("    + %s\n", line)
+				}
+			}
+		}
+		if next != "" {
+			fmt.Printf("\n%s.\n", next)
+		}
+		if dryRun {
+			fmt.Println("\n(Dry run - no hooks were installed)")
+		}
+	}
+	return nil
+}
+
+// installHooks installs the hooks through manager, or the detected hook
+// manager when it is empty, and returns what it did and what the user still
+// has to do, if anything.
+func installHooks(ctx context.Context, manager string, dryRun bool) (setup hookSetup, actions []hookAction, next string, err error) {
+	setup, err = detectHooks(ctx, manager)
+	if err != nil {
+		return setup, nil, "", err
+	}
+
+	switch setup.Manager {
+	case managerLefthook:
+		actions, err = installLefthook(setup.File, dryRun)
+		next = `Run "lefthook install" so that git runs the new hooks`
+	case managerPreCommit:
+		actions, err = installPreCommit(setup.File, dryRun)
+		next = `Run "pre-commit install" so that git runs the new hook types`
+	case managerHusky:
+		actions, err = installScripts(setup.File, huskyHeader(setup.File), dryRun)
+		if !setup.Active {
+			next = `husky is not set up in this clone; run "npm install" (or "npx husky") so that git runs .husky hooks`
+		}
+	default:
+		actions, err = installScripts(setup.File, "#!/bin/sh\n", dryRun)
+	}
+	if err != nil {
+		return setup, nil, "", err
+	}
+	changed := slices.ContainsFunc(actions, func(a hookAction) bool { return a.Status != "already installed" })
+	if !changed {
+		next = ""
+	}
+	return setup, actions, next, nil
+}
+
+// huskyHeader returns what new husky hook scripts start with: husky 8
+// sources its helper, husky 9 needs nothing.
+func huskyHeader(dir string) string {
+	if fileExists(filepath.Join(dir, "_", "husky.sh")) {
+		return "#!/usr/bin/env sh\n. \"$(dirname -- \"$0\")/_/husky.sh\"\n\n"
+	}
+	return ""
+}
+
+// installScripts adds the command to the hook scripts in dir, creating the
+// scripts that do not exist with header.
+func installScripts(dir, header string, dryRun bool) ([]hookAction, error) {
+	added := hookMarker + "\n" + hookCommand + " || true\n"
+	var actions []hookAction
+	for _, hook := range installedHooks {
+		path := filepath.Join(dir, hook)
+		action := hookAction{Hook: hook, File: path}
+
+		existing, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		content := string(existing)
+		switch {
+		case strings.Contains(content, "arc-git"):
+			action.Status = "already installed"
+			actions = append(actions, action)
+			continue
+		case err != nil:
+			content = header + added
+			action.Status = "created"
+		default:
+			if !strings.HasSuffix(content, "\n") {
+				content += "\n"
+			}
+			content += "\n" + added
+			action.Status = "appended"
+		}
+		action.Added = added
+		if dryRun {
+			action.Status = "would be " + action.Status
+			actions = append(actions, action)
+			continue
+		}
+
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		// WriteFile keeps the mode of existing files, which may not be
+		// executable.
+		if err := os.Chmod(path, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to make %s executable: %w", path, err)
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// installLefthook adds an arc-git command to the lefthook stanzas of the
+// hooks.
+func installLefthook(path string, dryRun bool) ([]hookAction, error) {
+	doc, err := loadYAML(path)
+	if err != nil {
+		return nil, err
+	}
+	root := doc.Content[0]
+
+	var actions []hookAction
+	for _, hook := range installedHooks {
+		action := hookAction{Hook: hook, File: path}
+		stanza := mappingValue(root, hook)
+		if stanza != nil && strings.Contains(encodeYAML(stanza), "arc-git") {
+			action.Status = "already installed"
+			actions = append(actions, action)
+			continue
+		}
+
+		command := parseYAML(fmt.Sprintf("run: %s || true\n", hookCommand))
+		if stanza == nil || stanza.Kind != yaml.MappingNode {
+			stanza = &yaml.Node{Kind: yaml.MappingNode}
+			setMappingValue(root, hook, stanza)
+		}
+		commands := mappingValue(stanza, "commands")
+		if commands == nil || commands.Kind != yaml.MappingNode {
+			commands = &yaml.Node{Kind: yaml.MappingNode}
+			setMappingValue(stanza, "commands", commands)
+		}
+		setMappingValue(commands, "arc-git", command)
+
+		action.Status = "added"
+		action.Added = encodeYAML(&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{scalarNode(hook), stanza}})
+		actions = append(actions, action)
+	}
+	return actions, saveYAML(path, doc, actions, dryRun)
+}
+
+// installPreCommit adds a local arc-git hook for the post-merge and
+// post-rewrite stages to a pre-commit config, and makes "pre-commit install"
+// install those hook types.
+func installPreCommit(path string, dryRun bool) ([]hookAction, error) {
+	doc, err := loadYAML(path)
+	if err != nil {
+		return nil, err
+	}
+	root := doc.Content[0]
+
+	action := hookAction{Hook: strings.Join(installedHooks, ", "), File: path}
+	if strings.Contains(encodeYAML(root), preCommitHookID) {
+		action.Status = "already installed"
+		return []hookAction{action}, nil
+	}
+
+	repos := mappingValue(root, "repos")
+	if repos == nil || repos.Kind != yaml.SequenceNode {
+		repos = &yaml.Node{Kind: yaml.SequenceNode}
+		setMappingValue(root, "repos", repos)
+	}
+	repo := parseYAML(fmt.Sprintf(`repo: local
+hooks:
+  - id: %s
+    name: arc-git annotate
+    entry: %s
+    language: system
+    stages: [%s]
+    always_run: true
+    pass_filenames: false
+`, preCommitHookID, hookCommand, strings.Join(installedHooks, ", ")))
+	repos.Content = append(repos.Content, repo)
+	action.Added = encodeYAML(&yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{repo}})
+
+	// Without default_install_hook_types pre-commit installs only the
+	// pre-commit hook type.
+	types := mappingValue(root, "default_install_hook_types")
+	if types == nil || types.Kind != yaml.SequenceNode {
+		types = parseYAML("[pre-commit]")
+		setMappingValue(root, "default_install_hook_types", types)
+	}
+	for _, hook := range installedHooks {
+		if !slices.ContainsFunc(types.Content, func(n *yaml.Node) bool { return n.Value == hook }) {
+			types.Content = append(types.Content, scalarNode(hook))
+		}
+	}
+	action.Added += encodeYAML(&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{scalarNode("default_install_hook_types"), types}})
+
+	action.Status = "added"
+	actions := []hookAction{action}
+	return actions, saveYAML(path, doc, actions, dryRun)
+}
+
+// loadYAML parses the YAML file at path into a document whose root is a
+// mapping; a missing or empty file yields an empty mapping.
+func loadYAML(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s is not a YAML mapping", path)
+	}
+	return &doc, nil
+}
+
+// saveYAML writes doc to path unless dryRun or nothing was added, marking
+// the added actions as pending under dryRun.
+func saveYAML(path string, doc *yaml.Node, actions []hookAction, dryRun bool) error {
+	added := false
+	for i := range actions {
+		if actions[i].Status == "added" {
+			added = true
+			if dryRun {
+				actions[i].Status = "would be added"
+			}
+		}
+	}
+	if !added || dryRun {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// parseYAML parses a snippet known to be valid into its root node.
+func parseYAML(text string) *yaml.Node {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(text), &doc); err != nil {
+		panic(fmt.Sprintf("invalid YAML snippet: %v", err))
+	}
+	return doc.Content[0]
+}
+
+// encodeYAML renders node for display and searching.
+func encodeYAML(node *yaml.Node) string {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// scalarNode returns a string scalar node.
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// mappingValue returns the value of key in mapping m, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue sets key in mapping m to value.
+func setMappingValue(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, scalarNode(key), value)
+}
+
+// fileExists reports whether path is a regular file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// dirExists reports whether path is a directory.
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
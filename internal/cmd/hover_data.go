@@ -0,0 +1,195 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Formats of hover-data.
+const (
+	hoverJSON = "json"
+	hoverTSV  = "tsv"
+)
+
+// maxHoverSummary bounds the annotation summary of a line, in characters.
+const maxHoverSummary = 240
+
+// newHoverDataCmd creates the hover-data subcommand.
+func newHoverDataCmd() *cobra.Command {
+	var (
+		file   string
+		rev    string
+		format string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "hover-data",
+		Short: "Dump per-line annotation hovers of a file for editor plugins",
+		Long: `Print, for every line of a file, the commit that last changed it, a summary
+of that commit's AI annotation, and its risk and labels, in one call, so that
+simple editor plugins can show annotation hovers without running a server.
+
+Blame is run once over the whole file. The summary of a line is made of the
+annotation's anchor statements that cover it, for annotations written with
+"arc-git annotate --anchors", and otherwise of the annotation's first
+sentence. With a freshness block in the repository config, lines whose
+annotation is historical context only are flagged as such.
+
+The file is read from the work tree, or at --rev. --format json prints one
+object with a "lines" array, indexed by line number minus one; --format tsv
+prints one line per line of the file with the columns line, commit, risk,
+labels (comma-separated), historical, and summary, with tabs and newlines in
+the summary replaced by spaces. Lines that are not committed yet have an
+empty commit.`,
+		Example: `  # Hovers for a file, as an editor plugin would request them
+  arc-git hover-data --file pkg/auth/session.go --format json
+
+  # The same file as of the last release, for tools that read lines
+  arc-git hover-data --file pkg/auth/session.go --rev v2.4.0 --format tsv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file == "" {
+				return errors.NewCLIError("--file is required").
+					WithHint("Pass the file to dump hovers for, e.g. --file pkg/auth/session.go")
+			}
+			if format != hoverJSON && format != hoverTSV {
+				return errors.NewCLIError(fmt.Sprintf("unknown --format %q", format)).
+					WithHint("Use --format json or --format tsv")
+			}
+
+			return runHoverData(cmd.Context(), file, rev, format)
+		},
+	}
+
+	cmd.Flags().StringVar(&file, "file", "", "File to dump hovers for")
+	cmd.Flags().StringVar(&rev, "rev", "", "Read the file at this revision instead of the work tree")
+	cmd.Flags().StringVar(&format, "format", hoverJSON, "Output format: json or tsv")
+
+	return cmd
+}
+
+// hoverLine is the hover of one line.
+type hoverLine struct {
+	Line       int      `json:"line"`
+	Commit     string   `json:"commit,omitempty"`
+	Subject    string   `json:"subject,omitempty"`
+	Author     string   `json:"author,omitempty"`
+	Date       string   `json:"date,omitempty"`
+	Committed  bool     `json:"committed"`
+	Annotated  bool     `json:"annotated"`
+	Summary    string   `json:"summary,omitempty"`
+	Risk       string   `json:"risk,omitempty"`
+	Labels     []string `json:"labels,omitempty"`
+	Historical bool     `json:"historical,omitempty"`
+}
+
+// hoverCommit is what the hovers of a commit's lines share.
+type hoverCommit struct {
+	note       annotationNote
+	annotated  bool
+	summary    string
+	historical bool
+}
+
+// runHoverData implements the hover-data workflow.
+func runHoverData(ctx context.Context, file, rev, format string) error {
+	blame, err := git.BlameFile(ctx, rev, file)
+	if err != nil {
+		return errors.NewCLIError(fmt.Sprintf("failed to blame %s", file)).
+			WithHint(err.Error())
+	}
+
+	commits, err := hoverCommits(ctx, blame)
+	if err != nil {
+		return errors.NewCLIError("failed to read annotations").
+			WithHint(err.Error())
+	}
+
+	lines := make([]hoverLine, 0, len(blame))
+	for i, b := range blame {
+		h := hoverLine{Line: i + 1, Committed: b.Committed()}
+		if h.Committed {
+			h.Commit, h.Subject, h.Author, h.Date = shortHash(b.Hash), b.Summary, b.Author, b.Time.Format("2006-01-02")
+			if c := commits[b.Hash]; c != nil && c.annotated {
+				h.Annotated, h.Risk, h.Labels, h.Historical = true, c.note.Risk(), c.note.Labels(), c.historical
+				h.Summary = hoverSummary(c, b)
+			}
+		}
+		lines = append(lines, h)
+	}
+
+	if format == hoverTSV {
+		var out strings.Builder
+		for _, h := range lines {
+			historical := ""
+			if h.Historical {
+				historical = "historical"
+			}
+			summary := strings.Join(strings.Fields(h.Summary), " ")
+			fmt.Fprintf(&out, "%d\t%s\t%s\t%s\t%s\t%s\n", h.Line, h.Commit, h.Risk, strings.Join(h.Labels, ","), historical, summary)
+		}
+		_, err := os.Stdout.WriteString(out.String())
+		return err
+	}
+	return writeJSON(map[string]interface{}{
+		"file":  file,
+		"rev":   rev,
+		"lines": lines,
+	})
+}
+
+// hoverCommits reads the annotations of the commits blame names, with one
+// git log for all of them, by full hash.
+func hoverCommits(ctx context.Context, blame []git.BlameLine) (map[string]*hoverCommit, error) {
+	commits := make(map[string]*hoverCommit)
+	var hashes []string
+	for _, b := range blame {
+		if b.Committed() && commits[b.Hash] == nil {
+			commits[b.Hash] = &hoverCommit{}
+			hashes = append(hashes, b.Hash)
+		}
+	}
+	if len(hashes) == 0 {
+		return commits, nil
+	}
+
+	noted, err := git.LogNotes(ctx, "ai", append([]string{"--no-walk=unsorted"}, hashes...)...)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range noted {
+		c := commits[n.Hash]
+		if c == nil || n.Note == "" {
+			continue
+		}
+		c.note, c.annotated = parseNote(n.Note), true
+		c.summary = cutText(firstSentence(c.note.Text), maxHoverSummary)
+		if f := freshnessFor(ctx, n.Hash, c.note); f != nil {
+			c.historical = f.Historical
+		}
+	}
+	return commits, nil
+}
+
+// hoverSummary returns the summary of a line: the statements of the anchors
+// covering it, or the first sentence of the commit's annotation.
+func hoverSummary(c *hoverCommit, b git.BlameLine) string {
+	var statements []string
+	for _, a := range c.note.Anchors {
+		if a.Covers(b.Path, b.Line) {
+			statements = append(statements, a.Statement)
+		}
+	}
+	if len(statements) == 0 {
+		return c.summary
+	}
+	return cutText(strings.Join(statements, " "), maxHoverSummary)
+}
@@ -0,0 +1,395 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// identityPattern parses "Name <email>".
+var identityPattern = regexp.MustCompile(`^\s*(.*?)\s*<([^<>\s]+@[^<>\s]+)>\s*$`)
+
+// Sources of an identity mapping.
+const (
+	sourceMailmap = "mailmap"
+	sourceConfig  = "config"
+)
+
+// newIdentitiesCmd creates the identities command.
+func newIdentitiesCmd() *cobra.Command {
+	var (
+		since      string
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "identities",
+		Short: "Review how commit authors are merged into people",
+		Long: `Show how commit author identities are resolved into people.
+
+Author analytics such as handover contributor lists count people, not email
+addresses. An author is first mapped through the repository's .mailmap, then
+through the identities rules of .arc-git.yaml:
+
+  identities:
+    - name: Alice Smith
+      email: alice@example.com
+      match: ["alice@*", "asmith@*.old-corp.example", "Alice S"]
+
+Match patterns are case-insensitive globs against the email, or against the
+name when they contain no "@". The first matching rule wins.
+
+Identities that look like the same person (same name, or same email user)
+but are not merged are listed as possible duplicates, with the merge command
+that would combine them.`,
+		Example: `  # Review resolved identities across all history
+  arc-git identities
+
+  # Only authors active in the last year, as JSON
+  arc-git identities --since 1y --output json
+
+  # Merge two addresses into one person via .mailmap
+  arc-git identities merge "Alice Smith <alice@example.com>" alice@old-corp.example "Alice S <alice.s@gmail.com>"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+
+			return runIdentities(cmd.Context(), gitSince(since), outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "Only consider commits more recent than this (1y, 6m, 2w, 30d, or a git date)")
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	cmd.AddCommand(newIdentitiesMergeCmd())
+
+	return cmd
+}
+
+// identityAlias is a recorded author identity folded into a person.
+type identityAlias struct {
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Commits int    `json:"commits"`
+	Source  string `json:"source,omitempty"`
+}
+
+// identity is one person with every identity they committed under.
+type identity struct {
+	Name    string          `json:"name"`
+	Email   string          `json:"email"`
+	Commits int             `json:"commits"`
+	Aliases []identityAlias `json:"aliases"`
+}
+
+// String renders the identity in "Name <email>" form.
+func (i identity) String() string {
+	return fmt.Sprintf("%s <%s>", i.Name, i.Email)
+}
+
+// identitySuggestion is a set of identities that look like one person.
+type identitySuggestion struct {
+	Reason     string   `json:"reason"`
+	Identities []string `json:"identities"`
+}
+
+// runIdentities implements the identities workflow.
+func runIdentities(ctx context.Context, since string, out output.OutputOptions) error {
+	args := []string{"HEAD"}
+	if since != "" {
+		args = append(args, "--since", since)
+	}
+	authors, err := git.Authors(ctx, args...)
+	if err != nil {
+		return fmt.Errorf("failed to read authors: %w", err)
+	}
+
+	people := resolveIdentities(ctx, authors)
+	suggestions := suggestIdentityMerges(people)
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"identities":  people,
+			"suggestions": suggestions,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		if len(people) == 0 {
+			fmt.Println("No commits found.")
+			return nil
+		}
+		fmt.Printf("%-48s %7s  %s\n", "IDENTITY", "COMMITS", "ALIASES")
+		for _, p := range people {
+			var aliases []string
+			for _, a := range p.Aliases {
+				if strings.EqualFold(a.Email, p.Email) && a.Name == p.Name {
+					continue
+				}
+				s := fmt.Sprintf("%s <%s> (%d", a.Name, a.Email, a.Commits)
+				if a.Source != "" {
+					s += ", " + a.Source
+				}
+				aliases = append(aliases, s+")")
+			}
+			// cutText adds "..." to what it keeps, which must fit the column.
+			fmt.Printf("%-48s %7d  %s\n", cutText(p.String(), 45), p.Commits, strings.Join(aliases, ", "))
+		}
+		if len(suggestions) > 0 {
+			fmt.Printf("\nPossible duplicates:\n")
+			for _, s := range suggestions {
+				fmt.Printf("  %s:\n    arc-git identities merge", s.Reason)
+				for _, id := range s.Identities {
+					fmt.Printf(" %q", id)
+				}
+				fmt.Println()
+			}
+		}
+	}
+
+	return nil
+}
+
+// canonicalIdentity returns the person an author is counted as. name and
+// email are expected to be .mailmap-mapped already, as git log's %aN and %aE
+// are; the repository config's identity rules are applied on top.
+func canonicalIdentity(ctx context.Context, name, email string) (string, string) {
+	name, email, _ = repoConfig(ctx).Identity(name, email)
+	return name, strings.ToLower(email)
+}
+
+// resolveIdentities groups recorded authors into people, most commits first.
+func resolveIdentities(ctx context.Context, authors []git.Author) []identity {
+	byEmail := make(map[string]*identity)
+	var order []string
+	for _, a := range authors {
+		name, email := canonicalIdentity(ctx, a.MappedName, a.MappedEmail)
+		alias := identityAlias{Name: a.Name, Email: a.Email, Commits: a.Commits}
+		switch {
+		case !strings.EqualFold(a.MappedEmail, email) || a.MappedName != name:
+			alias.Source = sourceConfig
+		case !strings.EqualFold(a.Email, a.MappedEmail) || a.Name != a.MappedName:
+			alias.Source = sourceMailmap
+		}
+
+		p, ok := byEmail[email]
+		if !ok {
+			// Authors arrive most commits first, so the first one names the
+			// person unless a rule did.
+			p = &identity{Name: name, Email: email}
+			byEmail[email] = p
+			order = append(order, email)
+		}
+		p.Commits += a.Commits
+		p.Aliases = append(p.Aliases, alias)
+	}
+
+	people := make([]identity, 0, len(order))
+	for _, email := range order {
+		people = append(people, *byEmail[email])
+	}
+	sort.SliceStable(people, func(i, j int) bool {
+		return people[i].Commits > people[j].Commits
+	})
+	return people
+}
+
+// suggestIdentityMerges finds people that share a normalized name or email
+// user and are probably the same person.
+func suggestIdentityMerges(people []identity) []identitySuggestion {
+	type key struct{ reason, value string }
+	groups := make(map[key][]string)
+	var keys []key
+	add := func(k key, p identity) {
+		if len(k.value) <= 2 {
+			return
+		}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], p.String())
+	}
+	for _, p := range people {
+		add(key{"same name", normalizeName(p.Name)}, p)
+		add(key{"same email user", emailUser(p.Email)}, p)
+	}
+
+	// A pair sharing both name and email user is suggested once.
+	seen := make(map[string]bool)
+	var suggestions []identitySuggestion
+	for _, k := range keys {
+		ids := groups[k]
+		set := strings.Join(ids, "\n")
+		if len(ids) < 2 || seen[set] {
+			continue
+		}
+		seen[set] = true
+		suggestions = append(suggestions, identitySuggestion{Reason: fmt.Sprintf("%s %q", k.reason, k.value), Identities: ids})
+	}
+	return suggestions
+}
+
+// normalizeName folds a name to lower-case letters and digits.
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// emailUser returns the user part of an email, dropping GitHub's numeric
+// noreply prefix ("12345+alice@users.noreply.github.com").
+func emailUser(email string) string {
+	user, _, _ := strings.Cut(strings.ToLower(email), "@")
+	if i := strings.LastIndex(user, "+"); i >= 0 && strings.HasSuffix(email, "users.noreply.github.com") {
+		user = user[i+1:]
+	}
+	return user
+}
+
+// newIdentitiesMergeCmd creates the identities merge subcommand.
+func newIdentitiesMergeCmd() *cobra.Command {
+	var (
+		file       string
+		dryRun     bool
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "merge <canonical> <alias>...",
+		Short: "Merge author identities into one person via .mailmap",
+		Long: `Record in .mailmap that each alias is the same person as canonical.
+
+canonical is "Name <email>". Each alias is either an email, which maps
+every commit with that email, or "Name <email>", which maps only commits
+with that exact name and email. Entries already in the file are skipped.
+
+Since git itself reads .mailmap, the merge also applies to git shortlog,
+git log --use-mailmap, and blame.`,
+		Example: `  # Fold an old work address and a personal address into one person
+  arc-git identities merge "Alice Smith <alice@example.com>" alice@old-corp.example "Alice S <alice.s@gmail.com>"
+
+  # Preview the entries without writing them
+  arc-git identities merge "Alice Smith <alice@example.com>" alice@old-corp.example --dry-run`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+
+			return runIdentitiesMerge(cmd.Context(), args[0], args[1:], file, dryRun, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&file, "file", "", "Mailmap file to update (default: .mailmap at the work tree root)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the entries without writing them")
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// runIdentitiesMerge implements the identities merge workflow.
+func runIdentitiesMerge(ctx context.Context, canonical string, aliases []string, file string, dryRun bool, out output.OutputOptions) error {
+	m := identityPattern.FindStringSubmatch(canonical)
+	if m == nil || m[1] == "" {
+		return errors.NewCLIError(fmt.Sprintf("invalid canonical identity %q", canonical)).
+			WithHint(`Use "Name <email>", e.g. "Alice Smith <alice@example.com>"`)
+	}
+	proper := fmt.Sprintf("%s <%s>", m[1], m[2])
+
+	var entries []string
+	for _, alias := range aliases {
+		switch am := identityPattern.FindStringSubmatch(alias); {
+		case am != nil && am[1] != "":
+			entries = append(entries, fmt.Sprintf("%s %s <%s>", proper, am[1], am[2]))
+		case am != nil:
+			entries = append(entries, fmt.Sprintf("%s <%s>", proper, am[2]))
+		case strings.Contains(alias, "@") && !strings.ContainsAny(alias, "<> \t"):
+			entries = append(entries, fmt.Sprintf("%s <%s>", proper, alias))
+		default:
+			return errors.NewCLIError(fmt.Sprintf("invalid alias %q", alias)).
+				WithHint(`Use an email, or "Name <email>" to map one exact identity`)
+		}
+	}
+
+	if file == "" {
+		top, err := git.Run(ctx, "rev-parse", "--show-toplevel")
+		if err != nil {
+			return errors.NewCLIError("not inside a git work tree").
+				WithHint("Run the command inside the repository, or pass --file")
+		}
+		file = filepath.Join(strings.TrimSpace(top), ".mailmap")
+	}
+
+	existing, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	// git compares mailmap emails case-insensitively, so duplicates are too.
+	present := make(map[string]bool)
+	for _, line := range strings.Split(string(existing), "\n") {
+		present[strings.ToLower(strings.Join(strings.Fields(line), " "))] = true
+	}
+	var added []string
+	for _, e := range entries {
+		if !present[strings.ToLower(e)] {
+			added = append(added, e)
+			present[strings.ToLower(e)] = true
+		}
+	}
+
+	if !dryRun && len(added) > 0 {
+		text := string(existing)
+		if text != "" && !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		text += strings.Join(added, "\n") + "\n"
+		if err := os.WriteFile(file, []byte(text), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"file":    file,
+			"added":   added,
+			"dry_run": dryRun,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		if len(added) == 0 {
+			fmt.Printf("%s already maps these identities.\n", file)
+			return nil
+		}
+		verb := "Added"
+		if dryRun {
+			verb = "Would add"
+		}
+		fmt.Printf("%s %d entries to %s:\n", verb, len(added), file)
+		for _, e := range added {
+			fmt.Printf("  %s\n", e)
+		}
+	}
+
+	return nil
+}
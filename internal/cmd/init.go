@@ -0,0 +1,563 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/config"
+	"github.com/yourorg/arc-git/internal/diffview"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/keychain"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-git/internal/providers"
+	"github.com/yourorg/arc-sdk/ai"
+	clierrors "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+// initOptions holds the flags of the init command.
+type initOptions struct {
+	yes      bool
+	hooks    bool
+	skipTest bool
+}
+
+// newInitCmd creates the init subcommand.
+func newInitCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts       initOptions
+		aiOpts     aiFlags
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Set up arc-git for a repository, step by step",
+		Long: `Walk through setting up arc-git for the current repository:
+
+1. the AI provider: ` + strings.Join(providers.Names, " or ") + `
+2. its API key, checked with the provider and stored in the keychain (the
+   macOS keychain, or the Secret Service through secret-tool), where AI
+   commands find it when no key is configured otherwise; a key already set
+   in the environment is used as it is
+3. the default model and the model of quick gists, checked against the
+   models the provider serves and the repository policy
+4. the repository config (` + config.FileName + `), which gets the provider and
+   models; an existing config is edited and keeps its other settings
+5. optionally, the git hooks that annotate new commits after every pull and
+   rebase, as arc-git hooks install sets them up
+6. a test annotation of HEAD, which is shown but not stored
+
+The answers default to the current setup, so init can be re-run to change
+one of them. --provider, --model, and --api-key answer their questions
+ahead; with --yes the defaults are taken without asking, for scripts and
+dev containers, and --hooks then installs the hooks.`,
+		Example: `  # Guided setup
+  arc-git init
+
+  # Unattended, with the key from ANTHROPIC_API_KEY, hooks included
+  arc-git init --yes --provider anthropic --hooks
+
+  # Switch a repository to another default model
+  arc-git init --model claude-haiku-4-5 --skip-test`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if aiOpts.provider != "" && !slices.Contains(providers.Names, providers.Canonical(aiOpts.provider)) {
+				return clierrors.NewCLIError(fmt.Sprintf("unsupported provider %q", aiOpts.provider)).
+					WithHint("Use --provider " + strings.Join(providers.Names, " or --provider "))
+			}
+			// A failed step is reported with the others, not a usage mistake.
+			cmd.SilenceUsage = true
+
+			return runInit(cmd.Context(), aiCfg, &aiOpts, opts, outputOpts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Take the defaults instead of asking")
+	cmd.Flags().BoolVar(&opts.hooks, "hooks", false, "Install the git hooks (the default answer; with --yes, install them)")
+	cmd.Flags().BoolVar(&opts.skipTest, "skip-test", false, "Skip the test annotation of HEAD")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// initStep is the outcome of one step of init.
+type initStep struct {
+	Step   string `json:"step"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+// runInit implements the init workflow.
+func runInit(ctx context.Context, base *ai.Config, aiOpts *aiFlags, opts initOptions, out output.OutputOptions) error {
+	if planFor(ctx) != nil {
+		// Nothing here is worth planning: init stores keys and writes config.
+		return nil
+	}
+	top, err := git.Run(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return clierrors.NewCLIError("arc-git init sets up a git repository").
+			WithHint("Run it inside the repository's work tree")
+	}
+	top = strings.TrimSpace(top)
+
+	w := newWizard(ctx, os.Stdin, os.Stderr, opts.yes)
+	if !w.yes && !w.terminal {
+		return clierrors.NewCLIError("arc-git init asks questions, but stdin is not a terminal").
+			WithHint("Pass --yes to take the defaults, with --provider, --model, and --api-key for the answers")
+	}
+
+	var steps []initStep
+	cfg := aiOpts.apply(ctx, base)
+
+	// Provider
+	provider := providers.Canonical(aiOpts.provider)
+	if provider == "" {
+		current := providers.Canonical(cfg.Provider)
+		if !slices.Contains(providers.Names, current) {
+			current = providers.Names[0]
+		}
+		provider = w.choose("AI provider", providers.Names, current)
+	}
+	if err := w.stopped(); err != nil {
+		return err
+	}
+	providerStep := initStep{Step: "provider", Status: checkOK, Detail: provider}
+	if err := repoConfig(ctx).CheckAI(provider, ""); err != nil {
+		providerStep.Status, providerStep.Detail = checkFail, err.Error()
+	}
+	steps = append(steps, providerStep)
+
+	// API key
+	configured := ""
+	if providers.Canonical(cfg.Provider) == provider {
+		configured = cfg.APIKey
+	}
+	key, keyStep := initKey(ctx, w, provider, aiOpts.apiKey, configured)
+	if err := w.stopped(); err != nil {
+		return err
+	}
+	steps = append(steps, keyStep)
+
+	// Models
+	repoCfg := repoConfig(ctx)
+	model := aiOpts.model
+	if model == "" {
+		model = w.ask("Default model", repoCfg.Model("default", prompt.AnnotateCommitModel))
+	}
+	quick := repoCfg.Models["annotate-quick"]
+	if quick == "" {
+		quick = prompt.AnnotateQuickModel
+	}
+	quick = w.ask("Model for quick gists (annotate --tier quick)", quick)
+	if err := w.stopped(); err != nil {
+		return err
+	}
+	steps = append(steps, initModels(ctx, provider, key, model, quick))
+
+	// Repository config
+	file := filepath.Join(top, config.FileName)
+	configStep := initStep{Step: "repository config", Status: checkSkip, Detail: "not written"}
+	if w.confirm(fmt.Sprintf("Write the provider and models to %s?", config.FileName), true) {
+		changed, err := writeInitConfig(file, provider, model, quick)
+		switch {
+		case err != nil:
+			configStep.Status, configStep.Detail = checkFail, err.Error()
+		case !changed:
+			configStep.Status, configStep.Detail = checkOK, file+" already has them"
+		default:
+			configStep.Status, configStep.Detail = checkOK, "wrote "+file
+		}
+		if err == nil {
+			written, err := config.Load(file)
+			if err != nil {
+				configStep.Status, configStep.Detail = checkFail, err.Error()
+				configStep.Fix = "Fix " + file + " by hand and re-run arc-git init"
+			} else {
+				ctx = withRepoConfig(ctx, written)
+			}
+		}
+	}
+	if err := w.stopped(); err != nil {
+		return err
+	}
+	steps = append(steps, configStep)
+
+	// Hooks
+	hooksStep := initStep{Step: "hooks", Status: checkSkip, Detail: "not installed (arc-git hooks install adds them later)"}
+	if w.confirm("Install git hooks that annotate new commits after every pull and rebase?", opts.hooks) {
+		setup, actions, next, err := installHooks(ctx, "", false)
+		switch {
+		case err != nil:
+			hooksStep.Status, hooksStep.Detail = checkFail, err.Error()
+		case !slices.ContainsFunc(actions, func(a hookAction) bool { return a.Status != "already installed" }):
+			hooksStep.Status, hooksStep.Detail = checkOK, "already installed through "+setup.Manager
+		default:
+			hooksStep.Status, hooksStep.Detail = checkOK, fmt.Sprintf("installed through %s (%s)", setup.Manager, setup.File)
+			hooksStep.Fix = next
+		}
+	}
+	if err := w.stopped(); err != nil {
+		return err
+	}
+	steps = append(steps, hooksStep)
+
+	// Test annotation
+	testStep, annotation := initTest(ctx, w, base, aiOpts, provider, key, opts.skipTest)
+	if err := w.stopped(); err != nil {
+		return err
+	}
+	steps = append(steps, testStep)
+
+	failed := 0
+	for _, s := range steps {
+		if s.Status == checkFail {
+			failed++
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		if err := writeJSON(map[string]interface{}{
+			"steps":      steps,
+			"annotation": annotation,
+			"failed":     failed,
+		}); err != nil {
+			return err
+		}
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: exit status only
+	default:
+		fmt.Println()
+		for _, s := range steps {
+			fmt.Printf("[%-4s] %-18s %s\n", s.Status, s.Step, s.Detail)
+			if s.Fix != "" && s.Status != checkOK {
+				fmt.Printf("       %-18s fix: %s\n", "", s.Fix)
+			} else if s.Fix != "" {
+				fmt.Printf("       %-18s next: %s\n", "", s.Fix)
+			}
+		}
+		if annotation != "" {
+			fmt.Printf("\n--- Test annotation of HEAD (not stored) ---\n%s\n", annotation)
+		}
+		if failed == 0 {
+			fmt.Println("\nSet up. Annotate the latest commits with: arc-git annotate --since 10")
+		}
+	}
+
+	if failed > 0 {
+		return clierrors.NewCLIError(fmt.Sprintf("%d setup steps failed", failed)).
+			WithHint("Apply the suggested fixes and re-run arc-git init")
+	}
+	return nil
+}
+
+// initKey settles the API key of provider: the --api-key, one from the
+// environment, the keychain, or the user's arc config, or else one the user
+// types. Keys not from the environment are checked with the provider and
+// stored in the keychain.
+func initKey(ctx context.Context, w *wizard, provider, flagKey, configured string) (string, initStep) {
+	step := initStep{Step: "api key"}
+	env, variable := providers.EnvKey(provider)
+
+	key := flagKey
+	switch {
+	case key != "":
+	case env != "":
+		step.Status, step.Detail = checkOK, "from "+variable
+		return env, step
+	default:
+		if stored, err := keychain.Get(provider); err == nil {
+			if !w.confirm(fmt.Sprintf("A key for %s is stored in the keychain. Replace it?", provider), false) {
+				step.Status, step.Detail = checkOK, "stored in the keychain"
+				return stored, step
+			}
+		} else if configured != "" {
+			step.Status, step.Detail = checkOK, "from the arc config"
+			return configured, step
+		}
+		if !w.yes {
+			key = w.secret(fmt.Sprintf("%s API key (not shown)", provider))
+		}
+	}
+	if key == "" {
+		step.Status, step.Detail = checkWarn, "no API key"
+		step.Fix = "Set " + variable + ", pass --api-key, or re-run arc-git init"
+		return "", step
+	}
+
+	checked := "checked"
+	if catalog, err := providers.New(provider, key); err == nil {
+		if err := catalog.Check(ctx); err != nil {
+			// Only a rejected key is kept out of the keychain.
+			f := classifyFailure(err)
+			if f.Code == failureAuth {
+				step.Status, step.Detail = checkFail, "the provider rejected the key: "+err.Error()
+				step.Fix = f.Hint
+				return "", step
+			}
+			step.Status, step.Fix = checkWarn, f.Hint
+			checked = "not checked (" + err.Error() + ")"
+		}
+	}
+	if err := keychain.Set(provider, key); err != nil {
+		step.Status, step.Detail = checkWarn, checked+", not stored: "+err.Error()
+		step.Fix = "Set " + variable + " in your shell profile instead"
+		return key, step
+	}
+	if step.Status == "" {
+		step.Status = checkOK
+	}
+	step.Detail = checked + " and stored in the keychain"
+	return key, step
+}
+
+// initModels checks the chosen models against the policy and, when there
+// is a key to ask with, against the models the provider serves.
+func initModels(ctx context.Context, provider, key string, models ...string) initStep {
+	step := initStep{Step: "models", Status: checkOK, Detail: strings.Join(models, ", ")}
+	for _, m := range models {
+		if err := repoConfig(ctx).CheckAI("", m); err != nil {
+			step.Status, step.Detail = checkFail, err.Error()
+			step.Fix = "Pick a model the repository policy allows"
+			return step
+		}
+	}
+	if key == "" {
+		return step
+	}
+	catalog, err := providers.New(provider, key)
+	if err != nil {
+		return step
+	}
+	served, err := catalog.Models(ctx)
+	if err != nil {
+		step.Status = checkWarn
+		step.Detail += " (could not list the provider's models: " + err.Error() + ")"
+		return step
+	}
+	var missing []string
+	for _, m := range models {
+		if _, ok := providers.Find(served, m); !ok {
+			missing = append(missing, m)
+		}
+	}
+	if len(missing) > 0 {
+		step.Status = checkWarn
+		step.Detail += fmt.Sprintf(" (%s does not serve %s)", provider, strings.Join(missing, ", "))
+		step.Fix = "List the served models with arc-git providers status"
+	}
+	return step
+}
+
+// writeInitConfig sets the provider and models in the repository config at
+// file, creating it if needed, and reports whether it changed.
+func writeInitConfig(file, provider, model, quick string) (bool, error) {
+	doc, err := loadYAML(file)
+	if err != nil {
+		return false, err
+	}
+	before := encodeYAML(doc)
+
+	root := doc.Content[0]
+	setMappingValue(root, "provider", scalarNode(provider))
+	models := mappingValue(root, "models")
+	if models == nil || models.Kind != yaml.MappingNode {
+		models = &yaml.Node{Kind: yaml.MappingNode}
+		setMappingValue(root, "models", models)
+	}
+	setMappingValue(models, "default", scalarNode(model))
+	setMappingValue(models, "annotate-quick", scalarNode(quick))
+
+	after := encodeYAML(doc)
+	if after == before {
+		return false, nil
+	}
+	if err := os.WriteFile(file, []byte(after), 0o644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	return true, nil
+}
+
+// initTest annotates HEAD without storing the note, proving that the
+// provider, key, and model work together.
+func initTest(ctx context.Context, w *wizard, base *ai.Config, aiOpts *aiFlags, provider, key string, skip bool) (initStep, string) {
+	step := initStep{Step: "test annotation", Status: checkSkip}
+	switch {
+	case skip:
+		step.Detail = "skipped (--skip-test)"
+		return step, ""
+	case key == "":
+		step.Detail = "skipped (no API key)"
+		return step, ""
+	case !w.confirm("Annotate HEAD as a test? The annotation is shown, not stored", true):
+		step.Detail = "skipped"
+		return step, ""
+	}
+	commits, err := git.Log(ctx, "-1", "HEAD")
+	if err != nil || len(commits) == 0 {
+		step.Detail = "skipped (no commits yet)"
+		return step, ""
+	}
+
+	cfg := *base
+	cfg.Provider, cfg.APIKey = provider, key
+	service, err := newAIService(ctx, &cfg)
+	if err != nil {
+		step.Status, step.Detail = checkFail, err.Error()
+		return step, ""
+	}
+	model := annotateModel(ctx, aiOpts, tierDeep, nil)
+	result := annotateCommit(ctx, service, model, tierDeep, citationsMark, diffview.Unified, nil, commits[0], false, true, true)
+	switch result.Status {
+	case "preview":
+		step.Status, step.Detail = checkOK, fmt.Sprintf("%s annotated %s", model, result.Hash)
+		return step, result.Annotation
+	case "failed":
+		step.Status, step.Detail = checkFail, result.Message
+		step.Fix = result.Hint
+	default:
+		step.Detail = fmt.Sprintf("%s %s: %s", result.Status, result.Hash, result.Message)
+	}
+	return step, ""
+}
+
+// wizard asks the questions of init.
+type wizard struct {
+	ctx      context.Context
+	lines    chan string
+	out      io.Writer
+	yes      bool
+	terminal bool
+	// err is set once the run is cancelled or stdin ends; the remaining
+	// questions then get their defaults.
+	err error
+}
+
+// newWizard returns a wizard reading answers from in and asking on out.
+// With yes, every question takes its default without being asked.
+func newWizard(ctx context.Context, in *os.File, out io.Writer, yes bool) *wizard {
+	// A mode check would take /dev/null, a character device, for one.
+	w := &wizard{ctx: ctx, out: out, yes: yes, lines: make(chan string), terminal: term.IsTerminal(int(in.Fd()))}
+	if !yes {
+		go func() {
+			defer close(w.lines)
+			scanner := bufio.NewScanner(in)
+			for scanner.Scan() {
+				w.lines <- scanner.Text()
+			}
+		}()
+	}
+	return w
+}
+
+// stopped returns why the wizard stopped asking, if it did.
+func (w *wizard) stopped() error {
+	switch {
+	case w.err == nil:
+		return nil
+	case w.ctx.Err() != nil:
+		return stopReason(w.ctx)
+	default:
+		return clierrors.NewCLIError("stdin ended before every question was answered").
+			WithHint("Pass --yes to take the defaults")
+	}
+}
+
+// read returns the next answer, or "" once the run is cancelled or stdin
+// has ended.
+func (w *wizard) read() string {
+	if w.yes || w.err != nil {
+		return ""
+	}
+	select {
+	case line, ok := <-w.lines:
+		if !ok {
+			w.err = io.EOF
+		}
+		return strings.TrimSpace(line)
+	case <-w.ctx.Done():
+		w.err = w.ctx.Err()
+		return ""
+	}
+}
+
+// ask asks for a value, offering def.
+func (w *wizard) ask(question, def string) string {
+	if w.yes {
+		return def
+	}
+	fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+	if answer := w.read(); answer != "" {
+		return answer
+	}
+	return def
+}
+
+// choose asks for one of options, offering def.
+func (w *wizard) choose(question string, options []string, def string) string {
+	for !w.yes && w.err == nil {
+		answer := w.ask(question+" ("+strings.Join(options, ", ")+")", def)
+		if slices.Contains(options, providers.Canonical(answer)) {
+			return providers.Canonical(answer)
+		}
+		fmt.Fprintf(w.out, "Please answer one of: %s\n", strings.Join(options, ", "))
+	}
+	return def
+}
+
+// confirm asks a yes/no question, offering def.
+func (w *wizard) confirm(question string, def bool) bool {
+	if w.yes || w.err != nil {
+		return def
+	}
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	fmt.Fprintf(w.out, "%s [%s]: ", question, hint)
+	switch strings.ToLower(w.read()) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	default:
+		return def
+	}
+}
+
+// secret asks for a value without echoing it, where stty can turn the echo
+// off.
+func (w *wizard) secret(question string) string {
+	fmt.Fprintf(w.out, "%s: ", question)
+	if w.terminal {
+		off := exec.Command("stty", "-echo")
+		off.Stdin = os.Stdin
+		if off.Run() == nil {
+			defer func() {
+				on := exec.Command("stty", "echo")
+				on.Stdin = os.Stdin
+				_ = on.Run()
+				fmt.Fprintln(w.out)
+			}()
+		}
+	}
+	return w.read()
+}
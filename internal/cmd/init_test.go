@@ -0,0 +1,47 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNewWizardTerminal(t *testing.T) {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	pipe, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pipe.Close()
+	defer writer.Close()
+
+	for name, in := range map[string]*os.File{"/dev/null": devNull, "a pipe": pipe} {
+		if w := newWizard(context.Background(), in, io.Discard, true); w.terminal {
+			t.Errorf("newWizard takes %s for a terminal", name)
+		}
+	}
+}
+
+func TestPauseWithoutTerminal(t *testing.T) {
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	var out strings.Builder
+	w := newWizard(context.Background(), devNull, &out, false)
+	w.pause()
+	if out.Len() != 0 || w.stopped() != nil {
+		t.Errorf("pause on /dev/null printed %q and stopped with %v, want it to return at once", out.String(), w.stopped())
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// Weights applied to each instability signal when ranking areas.
+const (
+	revertWeight   = 3
+	fixChainWeight = 2
+	followUpWeight = 1
+)
+
+// maxAreaCitations caps the commits cited per area.
+const maxAreaCitations = 8
+
+var (
+	// fixPattern matches commit subjects that patch up earlier work.
+	fixPattern = regexp.MustCompile(`(?i)^(fixup!|squash!|amend!|fix\b|fixes\b|fixed\b|hotfix\b|oops\b|typo\b|actually\b|really\b|another fix|follow[- ]?up)|fix (the )?fix|fix (previous|last) commit`)
+	// revertPattern extracts the reverted hash from a revert commit body.
+	revertPattern = regexp.MustCompile(`This reverts commit ([0-9a-f]{7,40})`)
+)
+
+// newInstabilityCmd creates the instability subcommand.
+func newInstabilityCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		since       string
+		top         int
+		depth       int
+		window      time.Duration
+		noNarrative bool
+		aiOpts      aiFlags
+		outputOpts  output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "instability",
+		Short: "Detect unstable areas from revert and fix-up patterns",
+		Long: `Detect unstable areas of the codebase by mining commit history.
+
+Three signals are collected and attributed to the directories a commit touched:
+- Reverts: commits that back out earlier work
+- Fix chains: consecutive fix-up commits ("fix", "fix fix", "fixup!") on the same files
+- Rapid follow-ups: patches to the same files shortly after a previous change
+
+Areas are ranked by a weighted score and the top results are passed to the AI,
+which writes a narrative about probable root causes citing the supporting
+commits. Use --no-narrative to skip the AI step and only report the signals.`,
+		Example: `  # Report unstable areas over the last six months
+  arc-git instability --since "6 months ago"
+
+  # Group by deeper paths and treat changes within 48h as follow-ups
+  arc-git instability --depth 3 --window 48h
+
+  # Signals only, as JSON, without calling the AI
+  arc-git instability --no-narrative --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+
+			return runInstability(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, since, top, depth, window, noNarrative, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "1 year ago", "Only consider commits more recent than this date")
+	cmd.Flags().IntVar(&top, "top", 10, "Number of areas to report")
+	cmd.Flags().IntVar(&depth, "depth", 2, "Directory depth used to group files into areas")
+	cmd.Flags().DurationVar(&window, "window", 24*time.Hour, "Maximum gap for a change to count as a rapid follow-up")
+	cmd.Flags().BoolVar(&noNarrative, "no-narrative", false, "Skip the AI narrative")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// areaSignal is a single piece of evidence attributed to an area.
+type areaSignal struct {
+	Kind    string `json:"kind"`
+	Hash    string `json:"hash"`
+	Message string `json:"message"`
+	Related string `json:"related,omitempty"`
+}
+
+// instabilityArea aggregates the signals for one area of the codebase.
+type instabilityArea struct {
+	Path      string       `json:"path"`
+	Score     int          `json:"score"`
+	Reverts   int          `json:"reverts"`
+	FixChains int          `json:"fix_chains"`
+	FollowUps int          `json:"follow_ups"`
+	Signals   []areaSignal `json:"signals"`
+}
+
+// add records a signal against the area.
+func (a *instabilityArea) add(s areaSignal) {
+	switch s.Kind {
+	case "revert":
+		a.Reverts++
+		a.Score += revertWeight
+	case "fix-chain":
+		a.FixChains++
+		a.Score += fixChainWeight
+	case "follow-up":
+		a.FollowUps++
+		a.Score += followUpWeight
+	}
+	a.Signals = append(a.Signals, s)
+}
+
+// runInstability implements the instability workflow.
+func runInstability(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, since string, top, depth int, window time.Duration, noNarrative bool, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	log.Info("Scanning history", "since", since)
+	changes, err := git.LogChanges(ctx, "--no-merges", "--reverse", "--since", since)
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+
+	areas := rankAreas(detectInstability(changes, depth, window), top)
+	if len(areas) == 0 {
+		switch {
+		case out.Is(output.OutputJSON):
+			return writeJSON(map[string]interface{}{"commits": len(changes), "areas": areas})
+		case out.Is(output.OutputQuiet):
+		default:
+			fmt.Println("No instability signals found.")
+		}
+		return nil
+	}
+
+	var narrative string
+	if !noNarrative {
+		service, err := newAIService(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		log.Info("Generating instability narrative", "areas", len(areas))
+		systemPrompt, userPrompt := prompt.Instability(describeAreas(areas))
+		narrative, err = runPrompt(ctx, service, systemPrompt, userPrompt, aiOpts.modelFor(ctx, prompt.InstabilityModel))
+		if err != nil {
+			return fmt.Errorf("failed to generate narrative: %w", err)
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"commits":   len(changes),
+			"areas":     areas,
+			"narrative": narrative,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		fmt.Printf("=== Unstable Areas (%d commits scanned) ===\n", len(changes))
+		fmt.Printf("%-40s %6s %8s %10s %10s\n", "AREA", "SCORE", "REVERTS", "FIX CHAINS", "FOLLOW-UPS")
+		for _, a := range areas {
+			fmt.Printf("%-40s %6d %8d %10d %10d\n", a.Path, a.Score, a.Reverts, a.FixChains, a.FollowUps)
+		}
+		if narrative != "" {
+			fmt.Printf("\n%s\n", narrative)
+		}
+	}
+
+	return nil
+}
+
+// detectInstability scans changes (oldest first) and attributes revert,
+// fix-chain, and follow-up signals to areas.
+func detectInstability(changes []git.Change, depth int, window time.Duration) map[string]*instabilityArea {
+	areas := make(map[string]*instabilityArea)
+	record := func(files []string, s areaSignal) {
+		for _, area := range areasOf(files, depth) {
+			a, ok := areas[area]
+			if !ok {
+				a = &instabilityArea{Path: area}
+				areas[area] = a
+			}
+			a.add(s)
+		}
+	}
+
+	// lastTouch tracks the most recent change to each file.
+	lastTouch := make(map[string]git.Change)
+
+	for _, c := range changes {
+		switch {
+		case strings.HasPrefix(c.Message, "Revert ") || revertPattern.MatchString(c.Body):
+			s := areaSignal{Kind: "revert", Hash: c.Short(), Message: c.Message}
+			if m := revertPattern.FindStringSubmatch(c.Body); m != nil {
+				s.Related = shortHash(m[1])
+			}
+			record(c.Files, s)
+		case fixPattern.MatchString(c.Message):
+			// A fix chain is a fix-up landing on files whose previous
+			// change was itself a fix-up.
+			var chained []string
+			for _, f := range c.Files {
+				if prev, ok := lastTouch[f]; ok && fixPattern.MatchString(prev.Message) {
+					chained = append(chained, f)
+				}
+			}
+			if len(chained) > 0 {
+				record(chained, areaSignal{Kind: "fix-chain", Hash: c.Short(), Message: c.Message, Related: lastTouch[chained[0]].Short()})
+			}
+		}
+
+		var rapid []string
+		for _, f := range c.Files {
+			if prev, ok := lastTouch[f]; ok && c.Time.Sub(prev.Time) <= window && prev.Hash != c.Hash {
+				rapid = append(rapid, f)
+			}
+		}
+		if len(rapid) > 0 {
+			record(rapid, areaSignal{Kind: "follow-up", Hash: c.Short(), Message: c.Message, Related: lastTouch[rapid[0]].Short()})
+		}
+
+		for _, f := range c.Files {
+			lastTouch[f] = c
+		}
+	}
+
+	return areas
+}
+
+// rankAreas orders areas by score and keeps the top n.
+func rankAreas(areas map[string]*instabilityArea, n int) []*instabilityArea {
+	ranked := make([]*instabilityArea, 0, len(areas))
+	for _, a := range areas {
+		ranked = append(ranked, a)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Path < ranked[j].Path
+	})
+	if n > 0 && len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// areasOf maps files to their distinct directory prefixes at the given depth.
+func areasOf(files []string, depth int) []string {
+	seen := make(map[string]bool)
+	var areas []string
+	for _, f := range files {
+		area := areaOf(f, depth)
+		if !seen[area] {
+			seen[area] = true
+			areas = append(areas, area)
+		}
+	}
+	return areas
+}
+
+// areaOf returns the directory prefix of file at the given depth. Files at
+// the repository root map to ".".
+func areaOf(file string, depth int) string {
+	dir := path.Dir(file)
+	if dir == "." || depth <= 0 {
+		return "."
+	}
+	parts := strings.Split(dir, "/")
+	if len(parts) > depth {
+		parts = parts[:depth]
+	}
+	return strings.Join(parts, "/")
+}
+
+// describeAreas renders ranked areas and their evidence for the prompt.
+func describeAreas(areas []*instabilityArea) string {
+	var b strings.Builder
+	for _, a := range areas {
+		fmt.Fprintf(&b, "## %s (score %d: %d reverts, %d fix chains, %d follow-ups)\n", a.Path, a.Score, a.Reverts, a.FixChains, a.FollowUps)
+		for i, s := range a.Signals {
+			if i == maxAreaCitations {
+				fmt.Fprintf(&b, "- ... %d more\n", len(a.Signals)-i)
+				break
+			}
+			if s.Related != "" {
+				fmt.Fprintf(&b, "- [%s] %s %s (after %s)\n", s.Kind, s.Hash, s.Message, s.Related)
+			} else {
+				fmt.Fprintf(&b, "- [%s] %s %s\n", s.Kind, s.Hash, s.Message)
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// shortHash abbreviates a full commit hash.
+func shortHash(hash string) string {
+	return git.Commit{Hash: hash}.Short()
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/ledger"
+	"github.com/yourorg/arc-git/internal/logging"
+)
+
+// recordLedger appends e to the repository ledger. Failures are logged
+// rather than returned because the action itself has already happened.
+func recordLedger(ctx context.Context, e ledger.Entry) {
+	gitDir, err := git.Dir(context.WithoutCancel(ctx))
+	if err == nil {
+		err = ledger.Append(ledger.Path(gitDir), e)
+	}
+	if err != nil {
+		logging.L().Warn("Failed to record action in ledger", "action", e.Action, "error", err)
+	}
+}
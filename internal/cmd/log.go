@@ -0,0 +1,213 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// newLogCmd creates the log subcommand.
+func newLogCmd() *cobra.Command {
+	var (
+		labels     []string
+		risk       string
+		limit      int
+		width      int
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "log [<revision-range>] [-- <git log options>]",
+		Short: "Show history with AI annotations inline",
+		Long: `Show git log output with each commit's AI annotation printed under it.
+
+Deep annotations end with Risk and Labels trailers, which can be filtered on:
+- --label keeps commits carrying any of the given labels
+- --risk keeps commits at a risk level, given as low, medium, or high for an
+  exact match, or with a comparison such as ">=medium" or "<high"
+
+Commits without a matching annotation are left out while filtering, including
+quick-tier gists and notes written before the trailers were added.
+
+With a freshness block in the repository config, annotations of commits whose
+code has since been rewritten are marked as historical context only; refresh
+them with "arc-git notes refresh --stale-paths".
+
+Everything after -- is passed to git log, so its options (--author, --grep,
+paths, and so on) can be combined with the filters. --limit applies after
+filtering, unlike git log's -n.`,
+		Example: `  # Recent history with annotations
+  arc-git log --limit 20
+
+  # Risky bug fixes on a release branch
+  arc-git log release/2.4 --label bugfix --risk '>=medium'
+
+  # Full annotations for one author's changes to a directory
+  arc-git log --truncate 0 -- --author=alice -- internal/auth`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			match, err := parseRiskFilter(risk)
+			if err != nil {
+				return err
+			}
+
+			return runLog(cmd.Context(), args, labels, match, limit, width, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&labels, "label", nil, "Only commits labeled with any of these (repeatable)")
+	cmd.Flags().StringVar(&risk, "risk", "", "Only commits at this risk level (e.g. high, '>=medium')")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 0, "Maximum commits to show after filtering (0 = no limit)")
+	cmd.Flags().IntVar(&width, "truncate", 200, "Truncate annotations to this many characters (0 = full)")
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// parseRiskFilter parses a --risk value into a predicate on risk levels, or
+// nil for an empty value.
+func parseRiskFilter(s string) (func(level string) bool, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	op := "="
+	for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(s, candidate) {
+			op, s = candidate, strings.TrimSpace(s[len(candidate):])
+			break
+		}
+	}
+	want := riskRank(strings.ToLower(s))
+	if want < 0 {
+		return nil, errors.NewCLIError(fmt.Sprintf("unknown risk level %q", s)).
+			WithHint("Use low, medium, or high, optionally prefixed by >=, <=, >, or <")
+	}
+
+	return func(level string) bool {
+		got := riskRank(level)
+		if got < 0 {
+			return false
+		}
+		switch op {
+		case ">=":
+			return got >= want
+		case "<=":
+			return got <= want
+		case ">":
+			return got > want
+		case "<":
+			return got < want
+		default:
+			return got == want
+		}
+	}, nil
+}
+
+// logEntry is a commit shown by arc-git log.
+type logEntry struct {
+	Hash       string   `json:"hash"`
+	Date       string   `json:"date"`
+	Author     string   `json:"author"`
+	Message    string   `json:"message"`
+	Annotation string   `json:"annotation,omitempty"`
+	Tier       string   `json:"tier,omitempty"`
+	Risk       string   `json:"risk,omitempty"`
+	Labels     []string `json:"labels,omitempty"`
+	Unverified []string `json:"unverified,omitempty"`
+	// Freshness is set when the repository config has a freshness block.
+	Freshness *freshness `json:"freshness,omitempty"`
+}
+
+// runLog implements the log workflow.
+func runLog(ctx context.Context, args, labels []string, risk func(string) bool, limit, width int, out output.OutputOptions) error {
+	for i := range labels {
+		labels[i] = strings.ToLower(strings.TrimSpace(labels[i]))
+	}
+	filtering := len(labels) > 0 || risk != nil
+
+	// --date=short comes first so that a --date passed through wins.
+	commits, err := git.LogNotes(ctx, "ai", append([]string{"--date=short"}, args...)...)
+	if err != nil {
+		return errors.NewCLIError("failed to read history").
+			WithHint(err.Error())
+	}
+
+	var entries []logEntry
+	for _, c := range commits {
+		if limit > 0 && len(entries) == limit {
+			break
+		}
+		entry := logEntry{Hash: c.Short(), Date: c.Date, Author: c.Author, Message: c.Message}
+		if c.Note != "" {
+			note := parseNote(c.Note)
+			entry.Annotation, entry.Tier, entry.Risk, entry.Labels = note.Text, note.Tier(), note.Risk(), note.Labels()
+			entry.Unverified = note.Unverified()
+			entry.Freshness = freshnessFor(ctx, c.Hash, note)
+		}
+		if filtering {
+			if risk != nil && !risk(entry.Risk) {
+				continue
+			}
+			if len(labels) > 0 && !slices.ContainsFunc(entry.Labels, func(l string) bool { return slices.Contains(labels, l) }) {
+				continue
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"commits": entries,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		if len(entries) == 0 && filtering {
+			fmt.Println("No annotated commits match the filters.")
+			return nil
+		}
+		for _, e := range entries {
+			fmt.Printf("%s %s %s  %s\n", e.Hash, e.Date, e.Message, e.Author)
+			if e.Annotation == "" {
+				continue
+			}
+			text := strings.Join(strings.Fields(e.Annotation), " ")
+			if runes := []rune(text); width > 0 && len(runes) > width {
+				text = strings.TrimSpace(string(runes[:width])) + "..."
+			}
+			if e.Freshness != nil && e.Freshness.Historical {
+				fmt.Printf("    (%s)\n", historicalNotice(e.Freshness))
+			}
+			fmt.Printf("    %s\n", text)
+			var tags []string
+			if e.Risk != "" {
+				tags = append(tags, "risk: "+e.Risk)
+			}
+			if len(e.Labels) > 0 {
+				tags = append(tags, "labels: "+strings.Join(e.Labels, ", "))
+			}
+			if len(e.Unverified) > 0 {
+				tags = append(tags, "unverified: "+strings.Join(e.Unverified, ", "))
+			}
+			if len(tags) > 0 {
+				fmt.Printf("    [%s]\n", strings.Join(tags, "; "))
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yourorg/arc-git/internal/git"
+)
+
+// summaryRef is the notes ref holding a one-line summary of each "ai"
+// annotation, so that "git log --notes=ai-summary --oneline" stays readable
+// while the full annotation stays under "ai".
+const summaryRef = "ai-summary"
+
+// summaryLength bounds one-line summaries, in characters.
+const summaryLength = 100
+
+// Risk levels recorded in the Risk trailer of deep annotations, lowest first.
+var riskLevels = []string{"low", "medium", "high"}
+
+// trailerPattern matches a "Key: value" trailer line.
+var trailerPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z-]*): *(.+)$`)
+
+// anchorTrailer is the trailer key of hunk anchors, which may repeat.
+const anchorTrailer = "anchor"
+
+// annotationNote is an "ai" note split into its text and trailers. Deep
+// annotations end with Risk and Labels trailers and quick gists with a Tier
+// trailer, in the style of git commit trailers. Deep annotations written
+// with --anchors also carry one Anchor trailer per statement.
+type annotationNote struct {
+	Text     string
+	Trailers map[string]string
+	Anchors  []noteAnchor
+}
+
+// noteAnchor ties a statement of an annotation to the lines it is about, as
+// "Anchor: <path>:<start>-<end>[,<start>-<end>...] | <statement>". Line
+// numbers are those of the file after the commit.
+type noteAnchor struct {
+	Path      string          `json:"path"`
+	Ranges    []git.LineRange `json:"ranges"`
+	Statement string          `json:"statement"`
+}
+
+// parseAnchor parses the value of an Anchor trailer.
+func parseAnchor(value string) (noteAnchor, bool) {
+	location, statement, ok := strings.Cut(value, " | ")
+	if !ok {
+		return noteAnchor{}, false
+	}
+	i := strings.LastIndex(location, ":")
+	if i <= 0 {
+		return noteAnchor{}, false
+	}
+	a := noteAnchor{Path: strings.TrimSpace(location[:i]), Statement: strings.TrimSpace(statement)}
+	for _, part := range strings.Split(location[i+1:], ",") {
+		first, last, found := strings.Cut(strings.TrimSpace(part), "-")
+		if !found {
+			last = first
+		}
+		start, err1 := strconv.Atoi(first)
+		end, err2 := strconv.Atoi(last)
+		if err1 != nil || err2 != nil || start <= 0 || end < start {
+			return noteAnchor{}, false
+		}
+		a.Ranges = append(a.Ranges, git.LineRange{Start: start, End: end})
+	}
+	return a, a.Statement != ""
+}
+
+// Covers reports whether the anchor refers to line of path.
+func (a noteAnchor) Covers(path string, line int) bool {
+	if a.Path != path {
+		return false
+	}
+	for _, r := range a.Ranges {
+		if line >= r.Start && line <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNote splits note into text and trailers. The trailers are the last
+// paragraph, if every line of it is a "Key: value" pair.
+func parseNote(note string) annotationNote {
+	note = strings.TrimSpace(note)
+	n := annotationNote{Text: note, Trailers: map[string]string{}}
+
+	text, last := "", note
+	if i := strings.LastIndex(note, "\n\n"); i >= 0 {
+		text, last = note[:i], note[i+2:]
+	}
+	trailers := make(map[string]string)
+	var anchors []noteAnchor
+	for _, line := range strings.Split(last, "\n") {
+		m := trailerPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			return n
+		}
+		key, value := strings.ToLower(m[1]), strings.TrimSpace(m[2])
+		if key == anchorTrailer {
+			if a, ok := parseAnchor(value); ok {
+				anchors = append(anchors, a)
+			}
+			continue
+		}
+		trailers[key] = value
+	}
+	n.Text, n.Trailers, n.Anchors = strings.TrimSpace(text), trailers, anchors
+	return n
+}
+
+// pruneAnchors drops the Anchor trailers of note that are malformed or that
+// keep reports false for, leaving the rest of the note as it was.
+func pruneAnchors(note string, keep func(noteAnchor) bool) string {
+	note = strings.TrimSpace(note)
+	i := strings.LastIndex(note, "\n\n")
+	if i < 0 {
+		return note
+	}
+	var kept []string
+	for _, line := range strings.Split(note[i+2:], "\n") {
+		m := trailerPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m != nil && strings.EqualFold(m[1], anchorTrailer) {
+			if a, ok := parseAnchor(strings.TrimSpace(m[2])); !ok || !keep(a) {
+				continue
+			}
+		}
+		kept = append(kept, line)
+	}
+	if len(kept) == 0 {
+		return note[:i]
+	}
+	return note[:i+2] + strings.Join(kept, "\n")
+}
+
+// addTrailers appends trailer lines to the trailer paragraph of note, or adds
+// one.
+func addTrailers(note string, lines ...string) string {
+	note = strings.TrimSpace(note)
+	if len(lines) == 0 {
+		return note
+	}
+	last := note
+	if i := strings.LastIndex(note, "\n\n"); i >= 0 {
+		last = note[i+2:]
+	}
+	trailers := note != last
+	for _, line := range strings.Split(last, "\n") {
+		trailers = trailers && trailerPattern.MatchString(strings.TrimSpace(line))
+	}
+	if trailers {
+		return note + "\n" + strings.Join(lines, "\n")
+	}
+	return note + "\n\n" + strings.Join(lines, "\n")
+}
+
+// Tier returns the annotation tier; notes without a Tier trailer are deep.
+func (n annotationNote) Tier() string {
+	switch {
+	case strings.EqualFold(n.Trailers["tier"], tierQuick):
+		return tierQuick
+	case strings.EqualFold(n.Trailers["tier"], tierImported):
+		return tierImported
+	}
+	return tierDeep
+}
+
+// Risk returns the lower-cased Risk trailer, or "" when absent.
+func (n annotationNote) Risk() string {
+	return strings.ToLower(n.Trailers["risk"])
+}
+
+// Unverified returns the references of the Unverified trailer, which
+// annotate adds with a low Confidence trailer when the annotation names
+// files, functions, or commits that do not exist.
+func (n annotationNote) Unverified() []string {
+	var refs []string
+	for _, r := range strings.Split(n.Trailers["unverified"], ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			refs = append(refs, r)
+		}
+	}
+	return refs
+}
+
+// Labels returns the lower-cased labels of the Labels trailer.
+func (n annotationNote) Labels() []string {
+	var labels []string
+	for _, l := range strings.Split(n.Trailers["labels"], ",") {
+		if l = strings.ToLower(strings.TrimSpace(l)); l != "" {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
+
+// noteSummary returns the one-line summary of an "ai" note: the first
+// sentence of its first paragraph, which for imported notes is the title.
+func noteSummary(note string) string {
+	paragraph, _, _ := strings.Cut(parseNote(note).Text, "\n\n")
+	return cutText(firstSentence(paragraph), summaryLength)
+}
+
+// storeAnnotation stores note as the "ai" annotation of hash, and its
+// summary under summaryRef.
+func storeAnnotation(ctx context.Context, hash, note string) error {
+	if err := git.AddNote(ctx, hash, "ai", note); err != nil {
+		return err
+	}
+	if err := git.AddNote(ctx, hash, summaryRef, noteSummary(note)); err != nil {
+		return fmt.Errorf("failed to add summary: %w", err)
+	}
+	return nil
+}
+
+// firstSentence returns the first sentence of text on a single line.
+func firstSentence(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	for i := 0; i < len(text)-1; i++ {
+		if strings.ContainsRune(".!?", rune(text[i])) && text[i+1] == ' ' {
+			return text[:i+1]
+		}
+	}
+	return text
+}
+
+// cutText cuts s to at most n characters, at a word boundary when there is
+// one in the second half, marking the cut.
+func cutText(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	s = string(runes[:n])
+	if i := strings.LastIndex(s, " "); i > len(s)/2 {
+		s = s[:i]
+	}
+	return strings.TrimRight(s, " ,;:") + "..."
+}
+
+// riskRank returns the position of level in riskLevels, or -1.
+func riskRank(level string) int {
+	for i, l := range riskLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-git/internal/git"
+)
+
+func TestParseAnchor(t *testing.T) {
+	tests := []struct {
+		value string
+		want  noteAnchor
+		ok    bool
+	}{
+		{
+			value: "internal/auth/session.go:12-20 | Sessions expire after a day.",
+			want:  noteAnchor{Path: "internal/auth/session.go", Ranges: []git.LineRange{{Start: 12, End: 20}}, Statement: "Sessions expire after a day."},
+			ok:    true,
+		},
+		{
+			value: "main.go:3,7-9, 15 | Several hunks.",
+			want:  noteAnchor{Path: "main.go", Ranges: []git.LineRange{{Start: 3, End: 3}, {Start: 7, End: 9}, {Start: 15, End: 15}}, Statement: "Several hunks."},
+			ok:    true,
+		},
+		{
+			// Only the last colon separates the path from the ranges.
+			value: "docs/a:b.md:4-5 | A path with a colon.",
+			want:  noteAnchor{Path: "docs/a:b.md", Ranges: []git.LineRange{{Start: 4, End: 5}}, Statement: "A path with a colon."},
+			ok:    true,
+		},
+		{
+			// The statement may itself contain the separator.
+			value: "main.go:1 | Pipes | stay.",
+			want:  noteAnchor{Path: "main.go", Ranges: []git.LineRange{{Start: 1, End: 1}}, Statement: "Pipes | stay."},
+			ok:    true,
+		},
+		{value: "main.go:1-2 no separator"},
+		{value: "main.go:1-2 | "},
+		{value: "main.go | No ranges."},
+		{value: ":1-2 | No path."},
+		{value: "main.go: | Empty ranges."},
+		{value: "main.go:0-2 | Lines start at 1."},
+		{value: "main.go:9-3 | Backwards."},
+		{value: "main.go:3- | Open range."},
+		{value: "main.go:-3 | Negative."},
+		{value: "main.go:3,,4 | Empty part."},
+		{value: "main.go:a-b | Not numbers."},
+		{value: "main.go:1-2,x | One bad part spoils the anchor."},
+	}
+	for _, tt := range tests {
+		got, ok := parseAnchor(tt.value)
+		if ok != tt.ok {
+			t.Errorf("parseAnchor(%q) ok = %v, want %v", tt.value, ok, tt.ok)
+			continue
+		}
+		if ok && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseAnchor(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseNoteAnchors(t *testing.T) {
+	note := `Adds session expiry. Expired sessions are swept hourly.
+
+Risk: medium
+Labels: feature, security
+Anchor: session.go:10-14 | Adds session expiry.
+anchor: sweep.go:bad | Malformed anchors are skipped.
+Anchor: sweep.go:3-8 | Expired sessions are swept hourly.`
+
+	n := parseNote(note)
+	if n.Text != "Adds session expiry. Expired sessions are swept hourly." {
+		t.Errorf("Text = %q", n.Text)
+	}
+	if n.Risk() != "medium" || !reflect.DeepEqual(n.Labels(), []string{"feature", "security"}) {
+		t.Errorf("Risk, Labels = %q, %q", n.Risk(), n.Labels())
+	}
+	if len(n.Anchors) != 2 || n.Anchors[0].Path != "session.go" || n.Anchors[1].Path != "sweep.go" {
+		t.Fatalf("Anchors = %+v, want the two well-formed ones", n.Anchors)
+	}
+	if _, ok := n.Trailers[anchorTrailer]; ok {
+		t.Errorf("anchors leaked into Trailers: %v", n.Trailers)
+	}
+	if !n.Anchors[1].Covers("sweep.go", 8) || n.Anchors[1].Covers("sweep.go", 9) || n.Anchors[1].Covers("session.go", 5) {
+		t.Errorf("Covers gives wrong answers for %+v", n.Anchors[1])
+	}
+}
+
+func TestPruneAnchors(t *testing.T) {
+	const text = "Adds expiry.\n\nSweeps hourly."
+	keepSession := func(a noteAnchor) bool { return a.Path == "session.go" }
+	tests := []struct {
+		name string
+		note string
+		keep func(noteAnchor) bool
+		want string
+	}{
+		{
+			name: "keeps other trailers and kept anchors in order",
+			note: text + "\n\nRisk: low\nAnchor: session.go:1-2 | Adds expiry.\nLabels: feature\nAnchor: sweep.go:3 | Sweeps hourly.",
+			keep: keepSession,
+			want: text + "\n\nRisk: low\nAnchor: session.go:1-2 | Adds expiry.\nLabels: feature",
+		},
+		{
+			name: "drops malformed anchors even when everything is kept",
+			note: text + "\n\nRisk: low\nAnchor: session.go | No ranges.\nAnchor: session.go:2 | Adds expiry.",
+			keep: func(noteAnchor) bool { return true },
+			want: text + "\n\nRisk: low\nAnchor: session.go:2 | Adds expiry.",
+		},
+		{
+			name: "drops the trailer paragraph once it is empty",
+			note: text + "\n\nAnchor: sweep.go:3 | Sweeps hourly.",
+			keep: keepSession,
+			want: text,
+		},
+		{
+			name: "leaves notes without a second paragraph alone",
+			note: "  Adds expiry.  \n",
+			keep: func(noteAnchor) bool { return false },
+			want: "Adds expiry.",
+		},
+		{
+			name: "leaves notes without anchors alone",
+			note: text + "\n\nRisk: high\nLabels: bugfix",
+			keep: func(noteAnchor) bool { return false },
+			want: text + "\n\nRisk: high\nLabels: bugfix",
+		},
+		{
+			name: "matches the key case-insensitively",
+			note: text + "\n\nRisk: low\nANCHOR: sweep.go:3 | Sweeps hourly.",
+			keep: keepSession,
+			want: text + "\n\nRisk: low",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pruneAnchors(tt.note, tt.keep); got != tt.want {
+				t.Errorf("pruneAnchors =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAnchors(t *testing.T) {
+	r := newTestRepo(t)
+	r.commit("Add files", map[string]string{
+		"kept.go":    lines(20),
+		"deleted.go": lines(5),
+	})
+	// Lines 4-6 of kept.go are deleted and line 15 is changed, which after
+	// the commit is line 12; deleted.go is deleted outright.
+	after := strings.Replace(lines(20), "line 4\nline 5\nline 6\n", "", 1)
+	after = strings.Replace(after, "line 15\n", "changed 15\n", 1)
+	hash := r.commit("Trim kept.go", map[string]string{
+		"kept.go":    after,
+		"deleted.go": "",
+	})
+
+	annotation := strings.Join([]string{
+		"Drops three lines and rewrites one.",
+		"",
+		"Risk: low",
+		"Labels: refactor",
+		"Anchor: kept.go:12 | Rewrites line 15.",
+		"Anchor: kept.go:10-13 | Overlaps the rewritten line.",
+		"Anchor: kept.go:4-6 | Points at the deleted lines.",
+		"Anchor: kept.go:1-3 | Points at unchanged lines.",
+		"Anchor: deleted.go:1-5 | Points into a deleted file.",
+		"Anchor: missing.go:1 | Points at a file the commit does not touch.",
+	}, "\n")
+
+	got := checkAnchors(r.ctx, git.Commit{Hash: hash}, annotation)
+	want := strings.Join([]string{
+		"Drops three lines and rewrites one.",
+		"",
+		"Risk: low",
+		"Labels: refactor",
+		"Anchor: kept.go:12 | Rewrites line 15.",
+		"Anchor: kept.go:10-13 | Overlaps the rewritten line.",
+	}, "\n")
+	if got != want {
+		t.Errorf("checkAnchors =\n%s\nwant\n%s", got, want)
+	}
+}
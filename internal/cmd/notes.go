@@ -0,0 +1,246 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/config"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// defaultMirrorRefs are the refs pushed to mirrors that do not list their own.
+var defaultMirrorRefs = []string{"refs/notes/*"}
+
+// newNotesCmd creates the notes command group.
+func newNotesCmd(aiCfg *ai.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notes",
+		Short: "Manage the notes refs arc-git writes",
+		Long: `Manage the notes refs that hold arc-git's annotations and indexes, such as
+refs/notes/ai, refs/notes/ai-summary, and refs/notes/ai-symbols.`,
+	}
+
+	cmd.AddCommand(
+		newNotesImportCmd(aiCfg),
+		newNotesMigrateCmd(),
+		newNotesMirrorCmd(),
+		newNotesRefreshCmd(aiCfg),
+		newNotesShowCmd(),
+	)
+
+	return cmd
+}
+
+// newNotesMirrorCmd creates the notes mirror subcommand.
+func newNotesMirrorCmd() *cobra.Command {
+	var (
+		to         []string
+		refs       []string
+		force      bool
+		dryRun     bool
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "mirror",
+		Short: "Push notes refs to mirrors and forks",
+		Long: `Push the notes refs to mirrors and forks, so that annotations stay
+consistent wherever the repository is replicated.
+
+Without --to, every mirror in the repository config is pushed to:
+
+  mirrors:
+    - name: backup
+      url: git@mirror.example.com:team/repo.git
+      after_run: true        # also push whenever a command writes notes
+    - name: fork
+      url: upstream-fork     # a configured git remote works too
+      refs: [refs/notes/ai, refs/review/*]
+      force: true            # overwrite notes that diverged on the fork
+
+Mirrors push every notes ref unless they list their own refs. Pushes are
+fast-forward only unless force is set, so annotations added directly on a
+mirror are reported as rejected rather than lost.
+
+Mirrors with after_run are pushed to after annotate, rollup, and symbols
+index write notes; a failed push is logged and does not fail the run. For a
+schedule, run this command from cron or a CI job. The command exits non-zero
+when any push fails.`,
+		Example: `  # Push notes to every configured mirror
+  arc-git notes mirror
+
+  # Push to a one-off mirror
+  arc-git notes mirror --to git@mirror.example.com:team/repo.git
+
+  # Only the annotations, overwriting whatever the fork has
+  arc-git notes mirror --to fork --ref refs/notes/ai --force
+
+  # See what would be pushed
+  arc-git notes mirror --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+
+			mirrors := repoConfig(cmd.Context()).Mirrors
+			if len(to) > 0 {
+				mirrors = nil
+				for _, url := range to {
+					mirrors = append(mirrors, config.Mirror{Name: url, URL: url})
+				}
+			}
+			if len(mirrors) == 0 {
+				return errors.NewCLIError("no mirrors to push to").
+					WithHint("Pass --to <url>, or add a mirrors list to " + config.FileName)
+			}
+			for i := range mirrors {
+				if len(refs) > 0 {
+					mirrors[i].Refs = refs
+				}
+				mirrors[i].Force = mirrors[i].Force || force
+			}
+
+			cmd.SilenceUsage = true
+			return runNotesMirror(cmd.Context(), mirrors, dryRun, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&to, "to", nil, "Push to this URL or remote instead of the configured mirrors (repeatable)")
+	cmd.Flags().StringSliceVar(&refs, "ref", nil, "Ref or refspec pattern to push (repeatable; default: refs/notes/*)")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite refs that diverged on the mirror")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be pushed without pushing")
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// mirrorResult records the outcome of pushing to one mirror.
+type mirrorResult struct {
+	Name    string          `json:"name"`
+	URL     string          `json:"url"`
+	Status  string          `json:"status"`
+	Message string          `json:"message,omitempty"`
+	Refs    []git.PushedRef `json:"refs,omitempty"`
+	failure
+}
+
+// runNotesMirror implements the notes mirror workflow.
+func runNotesMirror(ctx context.Context, mirrors []config.Mirror, dryRun bool, out output.OutputOptions) error {
+	var results []mirrorResult
+	failed := 0
+	for _, m := range mirrors {
+		r := pushMirror(ctx, m, dryRun)
+		if r.Status == "failed" {
+			failed++
+		}
+		results = append(results, r)
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		if err := writeJSON(map[string]interface{}{
+			"dry_run": dryRun,
+			"failed":  failed,
+			"mirrors": results,
+		}); err != nil {
+			return err
+		}
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		for _, r := range results {
+			fmt.Printf("%s (%s): %s\n", r.Name, r.URL, r.Status)
+			if r.Message != "" {
+				fmt.Printf("  %s\n", r.Message)
+			}
+			if r.Hint != "" {
+				fmt.Printf("  Hint: %s\n", r.Hint)
+			}
+			for _, ref := range r.Refs {
+				if ref.Status != "up-to-date" {
+					fmt.Printf("  %-10s %s\n", ref.Status, ref.Ref)
+				}
+			}
+		}
+		if dryRun {
+			fmt.Println("\n(Dry run - nothing was pushed)")
+		}
+	}
+
+	if failed > 0 {
+		return errors.NewCLIError(fmt.Sprintf("%d of %d mirrors failed", failed, len(mirrors))).
+			WithHint("See the errors above; pushes to the other mirrors went through")
+	}
+	return nil
+}
+
+// pushMirror pushes the mirror's refs and summarizes the outcome.
+func pushMirror(ctx context.Context, m config.Mirror, dryRun bool) mirrorResult {
+	result := mirrorResult{Name: m.Name, URL: m.URL}
+
+	refs := m.Refs
+	if len(refs) == 0 {
+		refs = defaultMirrorRefs
+	}
+	var refspecs []string
+	for _, ref := range refs {
+		spec := ref
+		if !strings.Contains(spec, ":") {
+			spec = ref + ":" + ref
+		}
+		if m.Force && !strings.HasPrefix(spec, "+") {
+			spec = "+" + spec
+		}
+		refspecs = append(refspecs, spec)
+	}
+
+	pushed, err := git.Push(ctx, m.URL, refspecs, dryRun)
+	if err != nil {
+		result.Status, result.Message = "failed", err.Error()
+		result.failure = classifyFailure(err)
+		if strings.Contains(err.Error(), "non-fast-forward") || strings.Contains(err.Error(), "rejected") {
+			result.Hint = "Notes diverged on the mirror; fetch and merge them (git notes merge), or push with --force to overwrite"
+		}
+		return result
+	}
+	result.Refs = pushed
+
+	result.Status = "up-to-date"
+	for _, ref := range pushed {
+		switch {
+		case ref.Status == "up-to-date":
+		case dryRun:
+			result.Status = "would push"
+		default:
+			result.Status = "pushed"
+		}
+	}
+	return result
+}
+
+// mirrorAfterRun pushes to the mirrors configured with after_run, for
+// commands that have just written notes. Failures are only logged.
+func mirrorAfterRun(ctx context.Context) {
+	log := logging.L()
+	for _, m := range repoConfig(ctx).Mirrors {
+		if !m.AfterRun {
+			continue
+		}
+		// The run context may already be cancelled; the notes are written.
+		r := pushMirror(context.WithoutCancel(ctx), m, false)
+		if r.Status == "failed" {
+			log.Warn("Failed to push notes to mirror", "mirror", m.Name, "error", r.Message)
+			continue
+		}
+		log.Info("Pushed notes to mirror", "mirror", m.Name, "status", r.Status)
+	}
+}
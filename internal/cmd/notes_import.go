@@ -0,0 +1,322 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/forge"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	clierrors "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// importedTrailer marks notes written by notes import.
+const importedTrailer = "Tier: " + tierImported
+
+// maxImportedBody bounds the description kept in an imported note that is
+// not condensed.
+const maxImportedBody = 4000
+
+// htmlComment matches the HTML comments pull request templates leave in
+// descriptions.
+var htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// notesImportOptions holds the notes import flags.
+type notesImportOptions struct {
+	fromForge    bool
+	fromMessages bool
+	since        string
+	remote       string
+	condense     bool
+	minLength    int
+	force        bool
+	dryRun       bool
+}
+
+// newNotesImportCmd creates the notes import subcommand.
+func newNotesImportCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts       notesImportOptions
+		since      string
+		aiOpts     aiFlags
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Backfill annotations from pull request descriptions or commit messages",
+		Long: `Backfill the annotations of past commits from what their authors already
+wrote, instead of generating them from diffs.
+
+With --from-forge, every pull request merged in the window (GitHub) is
+imported onto the commit its merge created: the merge or squash commit, or
+the last commit of a rebase merge. With --from-messages, the bodies of the
+commits in the window are imported onto the commits themselves.
+
+Notes are written as the title and description (with template comments
+removed), followed by "Tier: imported" and a Source trailer naming the pull
+request or commit message. With --condense, the AI condenses each
+description into an annotation paragraph first; it uses a small model and
+no diffs, so it stays cheap.
+
+Descriptions shorter than --min-length are skipped as carrying too little,
+and commits that already have an annotation are left alone unless --force
+is given. annotate treats imported notes like deep annotations and does not
+replace them.`,
+		Example: `  # Backfill a year of history from merged pull requests
+  arc-git notes import --from-forge --since 1y
+
+  # Condense the descriptions with the AI, previewing first
+  arc-git notes import --from-forge --since 1y --condense --dry-run
+
+  # Use commit message bodies instead
+  arc-git notes import --from-messages --since 6m`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if opts.fromForge == opts.fromMessages {
+				return clierrors.NewCLIError("pass exactly one of --from-forge and --from-messages").
+					WithHint("--from-forge reads merged pull requests; --from-messages reads commit message bodies")
+			}
+			opts.since = gitSince(since)
+
+			return runNotesImport(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, opts, outputOpts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.fromForge, "from-forge", false, "Import merged pull request titles and descriptions")
+	cmd.Flags().BoolVar(&opts.fromMessages, "from-messages", false, "Import commit message bodies")
+	cmd.Flags().StringVar(&since, "since", "1y", "Only import changes more recent than this (1y, 6m, 2w, 30d, or a git date)")
+	cmd.Flags().StringVar(&opts.remote, "remote", "origin", "Remote whose forge is queried with --from-forge")
+	cmd.Flags().BoolVar(&opts.condense, "condense", false, "Condense each description into an annotation with the AI")
+	cmd.Flags().IntVar(&opts.minLength, "min-length", 80, "Skip descriptions shorter than this many characters")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Replace existing annotations")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show the notes without adding them")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// importCandidate is a description to import onto a commit.
+type importCandidate struct {
+	hash   string
+	kind   string
+	title  string
+	body   string
+	source string
+}
+
+// importResult records the outcome for one commit.
+type importResult struct {
+	Commit     string `json:"commit"`
+	Source     string `json:"source"`
+	Status     string `json:"status"`
+	Message    string `json:"message,omitempty"`
+	Annotation string `json:"annotation,omitempty"`
+	failure
+}
+
+// runNotesImport implements the notes import workflow.
+func runNotesImport(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, opts notesImportOptions, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	if planFor(ctx) != nil && !opts.condense {
+		// Nothing to send, and plans never write.
+		return nil
+	}
+
+	var (
+		candidates []importCandidate
+		err        error
+	)
+	if opts.fromForge {
+		candidates, err = forgeCandidates(ctx, opts)
+	} else {
+		candidates, err = messageCandidates(ctx, opts)
+	}
+	if err != nil {
+		return err
+	}
+	log.Info("Found descriptions to import", "count", len(candidates))
+
+	var service *ai.Service
+	model := aiOpts.modelFor(ctx, prompt.NotesImportModel)
+	if opts.condense && len(candidates) > 0 {
+		if service, err = newAIService(ctx, cfg); err != nil {
+			return err
+		}
+		if err := repoConfig(ctx).CheckAI("", model); err != nil {
+			return err
+		}
+	}
+
+	var results []importResult
+	written := false
+	for _, c := range candidates {
+		result := importOne(ctx, service, model, c, opts)
+		written = written || result.Status == "imported"
+		results = append(results, result)
+	}
+	if planFor(ctx) != nil {
+		return nil
+	}
+	if written {
+		mirrorAfterRun(ctx)
+	}
+
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.Status]++
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"since":   opts.since,
+			"dry_run": opts.dryRun,
+			"counts":  counts,
+			"results": results,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		for _, r := range results {
+			if r.Status == "skipped" {
+				continue
+			}
+			fmt.Printf("%s <- %s: %s", r.Commit, r.Source, r.Status)
+			if r.Message != "" {
+				fmt.Printf(" (%s)", r.Message)
+			}
+			fmt.Println()
+			if r.Status == "preview" {
+				fmt.Printf("\n%s\n\n", r.Annotation)
+			}
+		}
+		fmt.Printf("\n%d imported, %d previewed, %d skipped, %d failed\n", counts["imported"], counts["preview"], counts["skipped"], counts["failed"])
+		if opts.dryRun {
+			fmt.Println("\n(Dry run - no notes were added)")
+		}
+	}
+
+	return nil
+}
+
+// forgeCandidates lists the pull requests merged in the window.
+func forgeCandidates(ctx context.Context, opts notesImportOptions) ([]importCandidate, error) {
+	url, err := git.RemoteURL(ctx, opts.remote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve remote %q: %w", opts.remote, err)
+	}
+	provider, err := forge.Detect(url)
+	if err != nil {
+		return nil, clierrors.NewCLIError(fmt.Sprintf("cannot read pull requests: %v", err)).
+			WithHint("Use --from-messages to import commit message bodies instead")
+	}
+	cutoff, err := git.ParseDate(ctx, opts.since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read --since: %w", err)
+	}
+
+	logging.L().Info("Listing merged pull requests", "forge", provider.Name(), "since", opts.since)
+	prs, err := provider.MergedPullRequests(ctx, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	candidates := make([]importCandidate, 0, len(prs))
+	for _, pr := range prs {
+		candidates = append(candidates, importCandidate{
+			hash:   pr.MergeCommit,
+			kind:   "pull request",
+			title:  fmt.Sprintf("%s (#%d)", pr.Title, pr.Number),
+			body:   pr.Body,
+			source: pr.URL,
+		})
+	}
+	return candidates, nil
+}
+
+// messageCandidates lists the commits in the window that have a message
+// body.
+func messageCandidates(ctx context.Context, opts notesImportOptions) ([]importCandidate, error) {
+	changes, err := git.LogChanges(ctx, "--no-merges", "--since", opts.since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	var candidates []importCandidate
+	for _, c := range changes {
+		if c.Body == "" {
+			continue
+		}
+		candidates = append(candidates, importCandidate{
+			hash:   c.Hash,
+			kind:   "commit message",
+			title:  c.Message,
+			body:   c.Body,
+			source: "commit message",
+		})
+	}
+	return candidates, nil
+}
+
+// importOne writes the note for one candidate.
+func importOne(ctx context.Context, service *ai.Service, model string, c importCandidate, opts notesImportOptions) importResult {
+	result := importResult{Commit: shortHash(c.hash), Source: c.source}
+
+	body := strings.TrimSpace(htmlComment.ReplaceAllString(c.body, ""))
+	switch {
+	case len(body) < opts.minLength:
+		result.Status, result.Message = "skipped", "description too short"
+		return result
+	case !git.Exists(ctx, c.hash):
+		result.Status, result.Message = "skipped", "commit not fetched"
+		return result
+	case !opts.force && git.HasNote(ctx, c.hash, "ai"):
+		result.Status, result.Message = "skipped", "already annotated (use --force to replace)"
+		return result
+	}
+
+	text := c.title + "\n\n" + truncate(body, maxImportedBody)
+	if opts.condense {
+		systemPrompt, userPrompt := prompt.NotesImport(c.kind, c.title, body)
+		condensed, err := runPrompt(withPlanSubject(ctx, result.Commit), service, systemPrompt, userPrompt, model)
+		if errors.Is(err, ErrPlanned) {
+			result.Status = "planned"
+			return result
+		}
+		if err != nil {
+			logging.L().Warn("Failed to condense description", "commit", result.Commit, "error", err)
+			result.Status, result.Message = "failed", fmt.Sprintf("failed to condense description: %v", err)
+			result.failure = classifyFailure(err)
+			return result
+		}
+		text = strings.TrimSpace(condensed)
+	}
+	note := text + "\n\n" + importedTrailer + "\nSource: " + c.source
+	result.Annotation = note
+
+	if opts.dryRun {
+		result.Status = "preview"
+		return result
+	}
+	if err := storeAnnotation(context.WithoutCancel(ctx), c.hash, note); err != nil {
+		result.Status, result.Message, result.Annotation = "failed", fmt.Sprintf("failed to add note: %v", err), ""
+		result.failure = classifyFailure(err)
+		return result
+	}
+	result.Status = "imported"
+	return result
+}
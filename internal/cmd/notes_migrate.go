@@ -0,0 +1,159 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// maxMigratePreview bounds the summaries printed by a dry run.
+const maxMigratePreview = 20
+
+// newNotesMigrateCmd creates the notes migrate subcommand.
+func newNotesMigrateCmd() *cobra.Command {
+	var (
+		force      bool
+		dryRun     bool
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Write the one-line summaries of annotations stored without one",
+		Long: `Split annotations stored as a single note into the summary and detail
+tiers: write the one-line summary of every annotation under refs/notes/ai
+that has none under refs/notes/ai-summary. The full annotation is left as it
+is.
+
+Commands that write annotations store both tiers, so this is only needed for
+notes written by earlier versions, or by other tools. The summary is the
+first sentence of the annotation, or the title of an imported one, cut to
+100 characters. No AI requests are made. --force rewrites every summary.`,
+		Example: `  # See what would be written
+  arc-git notes migrate --dry-run
+
+  # Write the missing summaries, then browse them
+  arc-git notes migrate
+  git log --notes=ai-summary --oneline`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+
+			return runNotesMigrate(cmd.Context(), force, dryRun, outputOpts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Rewrite existing summaries too")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the summaries without writing them")
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// migratedNote is the summary written for one annotation.
+type migratedNote struct {
+	Commit  string `json:"commit"`
+	Summary string `json:"summary"`
+}
+
+// runNotesMigrate implements the notes migrate workflow.
+func runNotesMigrate(ctx context.Context, force, dryRun bool, out output.OutputOptions) error {
+	log := logging.L()
+
+	noted, err := git.NotedCommits(ctx, "ai")
+	if err != nil {
+		return errors.NewCLIError("failed to list annotations").
+			WithHint(err.Error())
+	}
+	summarized, err := git.NotedCommits(ctx, summaryRef)
+	if err != nil {
+		return errors.NewCLIError("failed to list summaries").
+			WithHint(err.Error())
+	}
+
+	var hashes []string
+	for hash := range noted {
+		if force || !summarized[hash] {
+			hashes = append(hashes, hash)
+		}
+	}
+	sort.Strings(hashes)
+	log.Info("Found annotations to summarize", "annotations", len(noted), "missing", len(hashes))
+
+	var (
+		written []migratedNote
+		failed  int
+	)
+	for _, hash := range hashes {
+		if ctx.Err() != nil {
+			break
+		}
+		raw, err := git.ShowNote(ctx, hash, "ai")
+		if err != nil {
+			log.Warn("Failed to read annotation", "commit", shortHash(hash), "error", err)
+			failed++
+			continue
+		}
+		m := migratedNote{Commit: shortHash(hash), Summary: noteSummary(raw)}
+		if !dryRun {
+			if err := git.AddNote(context.WithoutCancel(ctx), hash, summaryRef, m.Summary); err != nil {
+				log.Warn("Failed to add summary", "commit", m.Commit, "error", err)
+				failed++
+				continue
+			}
+		}
+		written = append(written, m)
+	}
+	if len(written) > 0 && !dryRun {
+		mirrorAfterRun(ctx)
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		if err := writeJSON(map[string]interface{}{
+			"annotations": len(noted),
+			"summarized":  len(written),
+			"failed":      failed,
+			"dry_run":     dryRun,
+			"summaries":   written,
+		}); err != nil {
+			return err
+		}
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		if len(hashes) == 0 {
+			fmt.Printf("All %d annotations have summaries.\n", len(noted))
+			return nil
+		}
+		if dryRun {
+			for i, m := range written {
+				if i == maxMigratePreview {
+					fmt.Printf("... and %d more\n", len(written)-i)
+					break
+				}
+				fmt.Printf("%s %s\n", m.Commit, m.Summary)
+			}
+			fmt.Printf("\n%d summaries to write, %d failed\n", len(written), failed)
+			fmt.Println("\n(Dry run - no notes were added)")
+			return nil
+		}
+		fmt.Printf("Wrote %d summaries, %d failed\n", len(written), failed)
+		fmt.Println("\nBrowse them with: git log --notes=ai-summary --oneline")
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d summaries could not be written", failed, len(hashes))
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	clierrors "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// maxRefreshCommits bounds the later commits described to the model when
+// refreshing one annotation.
+const maxRefreshCommits = 15
+
+// notesRefreshOptions holds the notes refresh flags.
+type notesRefreshOptions struct {
+	stalePaths bool
+	since      string
+	limit      int
+	dryRun     bool
+}
+
+// newNotesRefreshCmd creates the notes refresh subcommand.
+func newNotesRefreshCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts       notesRefreshOptions
+		since      string
+		aiOpts     aiFlags
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "refresh",
+		Short: "Regenerate annotations whose code has since been rewritten",
+		Long: `Regenerate the annotations of commits whose code later commits rewrote, so
+that they no longer read as a description of the current code.
+
+With --stale-paths, every annotated commit is checked against HEAD: when
+later commits changed or removed at least the freshness.rewritten share of
+the lines its files had (half by default), or its annotation is older than
+freshness.ttl and any of those lines changed, the annotation is stale. Moved
+and deleted files count as rewritten. log and why mark such annotations as
+historical context only.
+
+Each stale annotation is rewritten by the AI from the annotation itself and
+the later commits to its files, with their annotations; no diffs are sent. The
+refreshed note keeps its trailers and gains a Refreshed trailer naming HEAD,
+from which its freshness is measured from then on.`,
+		Example: `  # Preview what would be refreshed
+  arc-git notes refresh --stale-paths --dry-run
+
+  # Refresh annotations of the last six months, at most 20 of them
+  arc-git notes refresh --stale-paths --since 6m --limit 20`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if !opts.stalePaths {
+				return clierrors.NewCLIError("nothing to refresh").
+					WithHint("Pass --stale-paths to refresh annotations whose code has been rewritten")
+			}
+			opts.since = gitSince(since)
+
+			return runNotesRefresh(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, opts, outputOpts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.stalePaths, "stale-paths", false, "Refresh annotations of commits whose code was rewritten since")
+	cmd.Flags().StringVar(&since, "since", "", "Only check commits more recent than this (1y, 6m, 2w, 30d, or a git date)")
+	cmd.Flags().IntVarP(&opts.limit, "limit", "n", 0, "Maximum annotations to refresh (0 = no limit)")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show the refreshed annotations without storing them")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// staleNote is an annotation that notes refresh regenerates.
+type staleNote struct {
+	commit    git.NotedCommit
+	note      annotationNote
+	freshness freshness
+}
+
+// refreshResult records the outcome for one commit.
+type refreshResult struct {
+	Commit     string    `json:"commit"`
+	Subject    string    `json:"subject"`
+	Status     string    `json:"status"`
+	Reason     string    `json:"reason,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	Freshness  freshness `json:"freshness"`
+	Annotation string    `json:"annotation,omitempty"`
+	failure
+}
+
+// runNotesRefresh implements the notes refresh workflow.
+func runNotesRefresh(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, opts notesRefreshOptions, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	head, err := git.Run(ctx, "rev-parse", "HEAD")
+	if err != nil {
+		return clierrors.NewCLIError("failed to resolve HEAD").
+			WithHint(err.Error())
+	}
+	head = strings.TrimSpace(head)
+
+	stale, checked, err := staleNotes(ctx, opts)
+	if err != nil {
+		return err
+	}
+	log.Info("Found stale annotations", "checked", checked, "stale", len(stale))
+	if opts.limit > 0 && len(stale) > opts.limit {
+		stale = stale[:opts.limit]
+	}
+
+	var service *ai.Service
+	model := aiOpts.modelFor(ctx, prompt.NotesRefreshModel)
+	if len(stale) > 0 {
+		if service, err = newAIService(ctx, cfg); err != nil {
+			return err
+		}
+		if err := repoConfig(ctx).CheckAI("", model); err != nil {
+			return err
+		}
+	}
+
+	var results []refreshResult
+	written := false
+	for _, s := range stale {
+		if ctx.Err() != nil {
+			break
+		}
+		result := refreshOne(ctx, service, model, s, head, opts)
+		written = written || result.Status == "refreshed"
+		results = append(results, result)
+	}
+	if planFor(ctx) != nil {
+		return nil
+	}
+	if written {
+		mirrorAfterRun(ctx)
+	}
+
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.Status]++
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"checked": checked,
+			"dry_run": opts.dryRun,
+			"counts":  counts,
+			"results": results,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		if len(results) == 0 {
+			fmt.Printf("None of the %d annotations checked is stale.\n", checked)
+			return nil
+		}
+		for _, r := range results {
+			fmt.Printf("%s %s: %s (%s)\n", r.Commit, r.Subject, r.Status, r.Reason)
+			if r.Message != "" {
+				fmt.Printf("    %s\n", r.Message)
+			}
+			if r.Status == "preview" {
+				fmt.Printf("\n%s\n\n", r.Annotation)
+			}
+		}
+		fmt.Printf("\n%d refreshed, %d previewed, %d failed of %d stale (%d checked)\n", counts["refreshed"], counts["preview"], counts["failed"], len(results), checked)
+		if opts.dryRun {
+			fmt.Println("\n(Dry run - no notes were changed)")
+		}
+	}
+
+	return nil
+}
+
+// staleNotes returns the annotations of the commits in the window that are
+// stale under the freshness rules, newest first, and how many were checked.
+func staleNotes(ctx context.Context, opts notesRefreshOptions) ([]staleNote, int, error) {
+	args := []string{"--no-merges"}
+	if opts.since != "" {
+		args = append(args, "--since", opts.since)
+	}
+	commits, err := git.LogNotes(ctx, "ai", args...)
+	if err != nil {
+		return nil, 0, clierrors.NewCLIError("failed to read history").
+			WithHint(err.Error())
+	}
+
+	rules := repoConfig(ctx).Freshness
+	var (
+		stale   []staleNote
+		checked int
+	)
+	for _, c := range commits {
+		if ctx.Err() != nil {
+			break
+		}
+		if c.Note == "" {
+			continue
+		}
+		checked++
+		note := parseNote(c.Note)
+		f, err := annotationFreshness(ctx, c.Hash, note, rules)
+		if err != nil {
+			logging.L().Warn("Failed to measure freshness", "commit", c.Short(), "error", err)
+			continue
+		}
+		if f.Historical {
+			stale = append(stale, staleNote{commit: c, note: note, freshness: f})
+		}
+	}
+	return stale, checked, nil
+}
+
+// refreshOne regenerates and, unless opts.dryRun, stores one annotation.
+func refreshOne(ctx context.Context, service *ai.Service, model string, s staleNote, head string, opts notesRefreshOptions) refreshResult {
+	c := s.commit
+	result := refreshResult{Commit: c.Short(), Subject: c.Message, Reason: s.freshness.Reason, Freshness: s.freshness}
+
+	described, err := refreshContext(ctx, s)
+	if err != nil {
+		result.Status, result.Message = "failed", fmt.Sprintf("failed to read later commits: %v", err)
+		result.failure = classifyFailure(err)
+		return result
+	}
+	systemPrompt, userPrompt := prompt.NotesRefresh(s.note.Text, described)
+	text, err := runPrompt(withPlanSubject(ctx, result.Commit), service, systemPrompt, userPrompt, model)
+	if err == nil && text == "" {
+		err = fmt.Errorf("empty reply")
+	}
+	if errors.Is(err, ErrPlanned) {
+		result.Status = "planned"
+		return result
+	}
+	if err != nil {
+		logging.L().Warn("Failed to refresh annotation", "commit", result.Commit, "error", err)
+		result.Status, result.Message = "failed", fmt.Sprintf("failed to refresh annotation: %v", err)
+		result.failure = classifyFailure(err)
+		return result
+	}
+	note := refreshNote(c.Note, text, head)
+	result.Annotation = note
+
+	if opts.dryRun {
+		result.Status = "preview"
+		return result
+	}
+	if err := storeAnnotation(context.WithoutCancel(ctx), c.Hash, note); err != nil {
+		result.Status, result.Message, result.Annotation = "failed", fmt.Sprintf("failed to add note: %v", err), ""
+		result.failure = classifyFailure(err)
+		return result
+	}
+	result.Status = "refreshed"
+	return result
+}
+
+// refreshContext describes a stale annotation's commit and the later
+// commits to its files for the refresh prompt.
+func refreshContext(ctx context.Context, s staleNote) (string, error) {
+	f := s.freshness
+	var b strings.Builder
+	fmt.Fprintf(&b, "Commit: %s %s (%s)\n", s.commit.Short(), s.commit.Message, s.commit.Date)
+	fmt.Fprintf(&b, "Rewritten since: %.0f%% of the lines of its files\n", f.Rewritten*100)
+	fmt.Fprintf(&b, "Files changed since: %s\n", strings.Join(f.Files, ", "))
+
+	args := []string{"--no-merges", fmt.Sprintf("-n%d", maxRefreshCommits), s.commit.Hash + "..HEAD", "--"}
+	later, err := git.LogNotes(ctx, "ai", append(args, f.Files...)...)
+	if err != nil {
+		return "", err
+	}
+	b.WriteString("\nLater commits to those files, newest first:\n")
+	for _, l := range later {
+		fmt.Fprintf(&b, "- %s %s (%s)\n", l.Short(), l.Message, l.Date)
+		if l.Note != "" {
+			fmt.Fprintf(&b, "  Annotation: %s\n", truncate(strings.Join(strings.Fields(parseNote(l.Note).Text), " "), 600))
+		}
+	}
+	return b.String(), nil
+}
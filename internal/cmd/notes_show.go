@@ -0,0 +1,130 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// newNotesShowCmd creates the notes show subcommand.
+func newNotesShowCmd() *cobra.Command {
+	var (
+		summary    bool
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "show [<rev>]",
+		Short: "Show a commit's annotation with its one-line summary",
+		Long: `Show the annotation of a commit (HEAD by default): its one-line summary,
+then the full annotation with its trailers.
+
+Annotations are stored in two tiers: the full text under refs/notes/ai, and a
+one-line summary under refs/notes/ai-summary, for views such as
+
+  git log --notes=ai-summary --oneline
+
+Annotations stored before summaries existed have none until "arc-git notes
+migrate" writes them; until then the summary shown is derived on the fly.
+With a freshness block in the repository config, annotations whose code has
+since been rewritten are marked as historical context only.`,
+		Example: `  # The annotation of the last commit
+  arc-git notes show
+
+  # Just the summary line of a release's commit
+  arc-git notes show v2.4.0 --summary`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			rev := "HEAD"
+			if len(args) == 1 {
+				rev = args[0]
+			}
+
+			return runNotesShow(cmd.Context(), rev, summary, outputOpts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&summary, "summary", false, "Print only the one-line summary")
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// shownNote is a commit's annotation as notes show prints it.
+type shownNote struct {
+	Commit        string       `json:"commit"`
+	Date          string       `json:"date"`
+	Author        string       `json:"author"`
+	Message       string       `json:"message"`
+	Summary       string       `json:"summary"`
+	SummaryStored bool         `json:"summary_stored"`
+	Annotation    string       `json:"annotation"`
+	Tier          string       `json:"tier"`
+	Risk          string       `json:"risk,omitempty"`
+	Labels        []string     `json:"labels,omitempty"`
+	Unverified    []string     `json:"unverified,omitempty"`
+	Anchors       []noteAnchor `json:"anchors,omitempty"`
+	Freshness     *freshness   `json:"freshness,omitempty"`
+
+	raw string
+}
+
+// runNotesShow implements the notes show workflow.
+func runNotesShow(ctx context.Context, rev string, summaryOnly bool, out output.OutputOptions) error {
+	commits, err := git.Log(ctx, "--no-walk", "--date=short", rev)
+	if err != nil || len(commits) == 0 {
+		return errors.NewCLIError(fmt.Sprintf("unknown revision %q", rev)).
+			WithHint(fmt.Sprint(err))
+	}
+	c := commits[0]
+	raw, err := git.ShowNote(ctx, c.Hash, "ai")
+	if err != nil {
+		return errors.NewCLIError(fmt.Sprintf("commit %s has no annotation", c.Short())).
+			WithHint(fmt.Sprintf("Annotate it with: arc-git annotate --from %s^ --to %s", c.Short(), c.Short()))
+	}
+
+	note := parseNote(raw)
+	shown := shownNote{
+		Commit: c.Short(), Date: c.Date, Author: c.Author, Message: c.Message,
+		Annotation: note.Text, Tier: note.Tier(), Risk: note.Risk(), Labels: note.Labels(),
+		Unverified: note.Unverified(), Anchors: note.Anchors,
+		Freshness: freshnessFor(ctx, c.Hash, note),
+		raw:       raw,
+	}
+	if s, err := git.ShowNote(ctx, c.Hash, summaryRef); err == nil && s != "" {
+		shown.Summary, shown.SummaryStored = s, true
+	} else {
+		shown.Summary = noteSummary(raw)
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(shown)
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	case summaryOnly:
+		fmt.Println(shown.Summary)
+	default:
+		fmt.Printf("%s %s %s  %s\n", shown.Commit, shown.Date, shown.Message, shown.Author)
+		fmt.Printf("Summary: %s\n", shown.Summary)
+		if !shown.SummaryStored {
+			fmt.Println("(derived; store it with: arc-git notes migrate)")
+		}
+		if f := shown.Freshness; f != nil && f.Historical {
+			fmt.Printf("\n%s.\n", historicalNotice(f))
+		}
+		fmt.Printf("\n%s\n", shown.raw)
+	}
+
+	return nil
+}
@@ -0,0 +1,588 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/config"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	clierrors "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+const (
+	// maxOrgFindings caps the high-risk commits listed for the organization.
+	maxOrgFindings = 15
+	// maxRepoFindings caps the high-risk commits listed per repository.
+	maxRepoFindings = 5
+	// maxOrgThemes caps the labels listed as cross-repo themes.
+	maxOrgThemes = 12
+	// lowCoverage is the annotation coverage, in percent, below which a
+	// repository's risk picture is flagged as incomplete.
+	lowCoverage = 50
+)
+
+// quarterPattern matches quarters such as Q2, 2026-Q2, 2026Q2, and Q2-2026.
+var quarterPattern = regexp.MustCompile(`(?i)^(?:(\d{4})[- ]?)?q([1-4])(?:[- ](\d{4}))?$`)
+
+// newOrgReportCmd creates the org-report subcommand.
+func newOrgReportCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts       orgReportOptions
+		aiOpts     aiFlags
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "org-report",
+		Short: "Roll the repositories of a workspace up into a quarterly organization report",
+		Long: `Roll the repositories listed in a workspace file up into one quarterly
+report for engineering leadership reviews.
+
+For every repository the report gives the quarter's commits, authors, and
+changed lines, the share of commits with annotations, the risk levels and
+labels of deep annotations, the riskiest commits, reverts, the most changed
+areas, and unstable areas (reverts, fix-up chains, and rapid follow-ups, as
+in arc-git instability). The organization totals add up the repositories,
+and the labels across them become the cross-repo themes. The AI then writes
+the themes, risks, and recommendations from these figures; use
+--no-narrative for the figures alone.
+
+The workspace file lists the repositories:
+
+  name: Acme Engineering
+  repos:
+    - path: ../api          # relative to the workspace file
+      team: platform
+    - name: storefront
+      path: ~/src/web
+      rev: origin/main      # default HEAD
+
+--quarter takes Q1 to Q4, optionally with a year (2026-Q2 or Q2-2026);
+without a year it is the latest such quarter that has begun. The default is
+the last complete quarter. Repositories that cannot be read are reported
+and skipped. The redaction rules of the repository config apply to the
+narrative prompt and the report.`,
+		Example: `  # Last quarter's report, printed as Markdown
+  arc-git org-report --workspace ws.yaml
+
+  # Q2 of this year, written to a file for the review deck
+  arc-git org-report --workspace ws.yaml --quarter Q2 --out q2-engineering.md
+
+  # Figures only, as JSON for a dashboard
+  arc-git org-report --workspace ws.yaml --quarter 2026-Q1 --no-narrative --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if opts.workspace == "" {
+				return clierrors.NewCLIError("--workspace is required").
+					WithHint("Pass a workspace file listing the repositories, e.g. arc-git org-report --workspace ws.yaml")
+			}
+			start, end, label, err := parseQuarter(opts.quarter, time.Now())
+			if err != nil {
+				return clierrors.NewCLIError(err.Error()).
+					WithHint("Use Q1 to Q4, optionally with a year, e.g. --quarter Q2 or --quarter 2026-Q2")
+			}
+			opts.start, opts.end, opts.label = start, end, label
+
+			return runOrgReport(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, opts, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.workspace, "workspace", "", "Workspace file listing the repositories")
+	cmd.Flags().StringVar(&opts.quarter, "quarter", "", "Quarter to report on, e.g. Q2 or 2026-Q2 (default: the last complete quarter)")
+	cmd.Flags().StringVar(&opts.outFile, "out", "", "Write the report to this file instead of printing it")
+	cmd.Flags().IntVar(&opts.depth, "depth", 2, "Directory depth used to group files into areas")
+	cmd.Flags().BoolVar(&opts.noNarrative, "no-narrative", false, "Skip the AI themes, risks, and recommendations")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// orgReportOptions holds the org-report flags.
+type orgReportOptions struct {
+	workspace   string
+	quarter     string
+	outFile     string
+	depth       int
+	noNarrative bool
+
+	// start and end bound the quarter, end exclusive; label names it.
+	start, end time.Time
+	label      string
+}
+
+// parseQuarter returns the bounds and name of a quarter given as Q1 to Q4
+// with an optional year. Without a year the quarter is the latest such one
+// that has begun by now; an empty quarter is the last complete one.
+func parseQuarter(s string, now time.Time) (start, end time.Time, label string, err error) {
+	year, q := now.Year(), (int(now.Month())-1)/3+1
+	if s == "" {
+		if q--; q == 0 {
+			year, q = year-1, 4
+		}
+	} else {
+		m := quarterPattern.FindStringSubmatch(strings.TrimSpace(s))
+		if m == nil || (m[1] != "" && m[3] != "") {
+			return start, end, "", fmt.Errorf("invalid quarter %q", s)
+		}
+		explicit := m[1] + m[3]
+		want, _ := strconv.Atoi(m[2])
+		switch {
+		case explicit != "":
+			year, _ = strconv.Atoi(explicit)
+		case want > q:
+			year--
+		}
+		q = want
+	}
+	start = time.Date(year, time.Month(3*(q-1)+1), 1, 0, 0, 0, 0, now.Location())
+	return start, start.AddDate(0, 3, 0), fmt.Sprintf("%d-Q%d", year, q), nil
+}
+
+// orgFinding is a high-risk annotated commit.
+type orgFinding struct {
+	Repo    string `json:"repo"`
+	Hash    string `json:"hash"`
+	Subject string `json:"subject"`
+	Gist    string `json:"gist,omitempty"`
+	Lines   int    `json:"lines"`
+}
+
+// orgRepoReport is one repository's figures for the quarter.
+type orgRepoReport struct {
+	Name      string         `json:"name"`
+	Team      string         `json:"team,omitempty"`
+	Path      string         `json:"path"`
+	Commits   int            `json:"commits"`
+	Authors   int            `json:"authors"`
+	Added     int            `json:"added"`
+	Deleted   int            `json:"deleted"`
+	Annotated int            `json:"annotated"`
+	Deep      int            `json:"deep"`
+	Coverage  float64        `json:"coverage"`
+	Risk      map[string]int `json:"risk"`
+	Labels    map[string]int `json:"labels"`
+	Reverts   int            `json:"reverts"`
+	Hotspots  string         `json:"hotspots,omitempty"`
+	Unstable  []string       `json:"unstable,omitempty"`
+	HighRisk  []orgFinding   `json:"high_risk,omitempty"`
+	Error     string         `json:"error,omitempty"`
+
+	authors map[string]bool
+}
+
+// orgTheme is a label across the repositories.
+type orgTheme struct {
+	Label   string   `json:"label"`
+	Commits int      `json:"commits"`
+	Repos   []string `json:"repos"`
+}
+
+// orgTotals is the organization's figures for the quarter.
+type orgTotals struct {
+	Repos     int            `json:"repos"`
+	Commits   int            `json:"commits"`
+	Authors   int            `json:"authors"`
+	Added     int            `json:"added"`
+	Deleted   int            `json:"deleted"`
+	Annotated int            `json:"annotated"`
+	Coverage  float64        `json:"coverage"`
+	Risk      map[string]int `json:"risk"`
+	Reverts   int            `json:"reverts"`
+}
+
+// runOrgReport implements the org-report workflow.
+func runOrgReport(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, opts orgReportOptions, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	ws, err := config.LoadWorkspace(opts.workspace)
+	if err != nil {
+		return clierrors.NewCLIError(err.Error()).
+			WithHint("A workspace file has a repos list of entries with a path; see arc-git org-report --help")
+	}
+	name := ws.Name
+	if name == "" {
+		name = "Organization"
+	}
+
+	var repos []orgRepoReport
+	for _, r := range ws.Repos {
+		log.Info("Reading repository", "repo", r.Name, "quarter", opts.label)
+		report, err := orgRepoFigures(git.WithDir(ctx, r.Path), r, opts)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			log.Warn("Skipping unreadable repository", "repo", r.Name, "path", r.Path, "error", err)
+			report.Error = err.Error()
+		}
+		repos = append(repos, report)
+	}
+	totals, themes, findings := orgAggregate(repos)
+	if totals.Commits == 0 {
+		log.Warn("No commits in the quarter in any repository", "quarter", opts.label)
+	}
+
+	narrative := ""
+	if !opts.noNarrative && totals.Commits > 0 {
+		service, err := newAIService(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		model := aiOpts.modelFor(ctx, prompt.OrgReportModel)
+		if err := repoConfig(ctx).CheckAI("", model); err != nil {
+			return err
+		}
+		systemPrompt, userPrompt := prompt.OrgReport(name, opts.label, "")
+		fitted := fitPrompt(ctx, model,
+			prompt.Section{Name: prompt.SectionSystem, Text: systemPrompt + userPrompt, Required: true},
+			prompt.Section{Name: prompt.SectionRepo, Text: orgSummary(repos, totals, themes, findings), Trim: prompt.TrimLines},
+		)
+		systemPrompt, userPrompt = prompt.OrgReport(name, opts.label, fitted[1])
+
+		log.Info("Writing narrative", "repos", len(repos))
+		narrative, err = runPrompt(withPlanSubject(ctx, opts.label), service, systemPrompt, userPrompt, model)
+		if errors.Is(err, ErrPlanned) {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			log.Warn("Failed to write the narrative, reporting the figures only", "error", err)
+			narrative = ""
+		}
+	}
+
+	document := repoConfig(ctx).RedactText(orgDocument(name, opts, repos, totals, themes, findings, narrative))
+	if opts.outFile != "" {
+		if err := os.WriteFile(opts.outFile, []byte(document), 0o644); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"organization": name,
+			"quarter":      opts.label,
+			"from":         opts.start.Format("2006-01-02"),
+			"until":        opts.end.Format("2006-01-02"),
+			"totals":       totals,
+			"themes":       themes,
+			"high_risk":    findings,
+			"repos":        repos,
+			"narrative":    repoConfig(ctx).RedactText(narrative),
+			"document":     document,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		if opts.outFile == "" {
+			fmt.Print(document)
+			return nil
+		}
+		fmt.Printf("Wrote the %s report for %s to %s (%d repositories, %d commits)\n", opts.label, name, opts.outFile, totals.Repos, totals.Commits)
+	}
+	return nil
+}
+
+// orgRepoFigures reads one repository's figures for the quarter; ctx runs
+// git in the repository. Authors are counted as people under the
+// repository's own identity rules.
+func orgRepoFigures(ctx context.Context, r config.WorkspaceRepo, opts orgReportOptions) (orgRepoReport, error) {
+	report := orgRepoReport{
+		Name:    r.Name,
+		Team:    r.Team,
+		Path:    r.Path,
+		Risk:    make(map[string]int),
+		Labels:  make(map[string]int),
+		authors: make(map[string]bool),
+	}
+	if _, err := git.Run(ctx, "rev-parse", "--git-dir"); err != nil {
+		return report, fmt.Errorf("not a git repository")
+	}
+	tip, err := revParse(ctx, r.Rev)
+	if err != nil {
+		return report, fmt.Errorf("unknown revision %q", r.Rev)
+	}
+	repoCfg, err := loadRepoConfig(ctx, "")
+	if err != nil {
+		logging.L().Warn("Counting authors without identity rules", "repo", r.Name, "error", err)
+		repoCfg = &config.Config{}
+	}
+	ctx = withRepoConfig(ctx, repoCfg)
+	window := []string{"--no-merges", "--since", opts.start.Format(time.RFC3339), "--until", opts.end.Format(time.RFC3339), tip}
+
+	// Oldest first, for detectInstability.
+	changes, err := git.LogChanges(ctx, append([]string{"--reverse"}, window...)...)
+	if err != nil {
+		return report, err
+	}
+	noted, err := git.LogNotes(ctx, "ai", window...)
+	if err != nil {
+		return report, err
+	}
+	stats, err := git.LogNumStat(ctx, window...)
+	if err != nil {
+		return report, err
+	}
+
+	areas := make(map[string]int)
+	lines := make(map[string]int, len(stats))
+	for _, c := range changes {
+		report.Commits++
+		_, email := canonicalIdentity(ctx, c.Name, c.Email)
+		report.authors[email] = true
+		if isRevert(c) {
+			report.Reverts++
+		}
+		for _, a := range areasOf(c.Files, opts.depth) {
+			areas[a]++
+		}
+		for _, s := range stats[c.Hash] {
+			report.Added += s.Added
+			report.Deleted += s.Deleted
+			lines[c.Hash] += s.Added + s.Deleted
+		}
+	}
+	report.Authors = len(report.authors)
+	report.Hotspots = topAreas(areas, 5)
+	for _, a := range rankAreas(detectInstability(changes, opts.depth, 24*time.Hour), 3) {
+		report.Unstable = append(report.Unstable, fmt.Sprintf("%s (score %d)", a.Path, a.Score))
+	}
+
+	for _, c := range noted {
+		if c.Note == "" {
+			continue
+		}
+		report.Annotated++
+		if isQuickNote(c.Note) {
+			continue
+		}
+		report.Deep++
+		note := parseNote(c.Note)
+		if risk := note.Risk(); riskRank(risk) >= 0 {
+			report.Risk[risk]++
+			if risk == "high" {
+				report.HighRisk = append(report.HighRisk, orgFinding{Repo: r.Name, Hash: c.Short(), Subject: c.Message, Gist: noteGist(c.Note), Lines: lines[c.Hash]})
+			}
+		}
+		for _, l := range note.Labels() {
+			report.Labels[l]++
+		}
+	}
+	sort.SliceStable(report.HighRisk, func(i, j int) bool { return report.HighRisk[i].Lines > report.HighRisk[j].Lines })
+	if report.Commits > 0 {
+		report.Coverage = float64(report.Annotated*1000/report.Commits) / 10
+	}
+	return report, nil
+}
+
+// orgAggregate adds up the repositories and collects the cross-repo themes
+// and the largest high-risk commits. Per-repository finding lists are
+// capped afterwards.
+func orgAggregate(repos []orgRepoReport) (orgTotals, []orgTheme, []orgFinding) {
+	totals := orgTotals{Risk: make(map[string]int)}
+	authors := make(map[string]bool)
+	themes := make(map[string]*orgTheme)
+	var findings []orgFinding
+	for i := range repos {
+		r := &repos[i]
+		if r.Error != "" {
+			continue
+		}
+		totals.Repos++
+		totals.Commits += r.Commits
+		totals.Added += r.Added
+		totals.Deleted += r.Deleted
+		totals.Annotated += r.Annotated
+		totals.Reverts += r.Reverts
+		for a := range r.authors {
+			authors[a] = true
+		}
+		for level, n := range r.Risk {
+			totals.Risk[level] += n
+		}
+		for label, n := range r.Labels {
+			t, ok := themes[label]
+			if !ok {
+				t = &orgTheme{Label: label}
+				themes[label] = t
+			}
+			t.Commits += n
+			t.Repos = append(t.Repos, r.Name)
+		}
+		findings = append(findings, r.HighRisk...)
+		if len(r.HighRisk) > maxRepoFindings {
+			r.HighRisk = r.HighRisk[:maxRepoFindings]
+		}
+	}
+	totals.Authors = len(authors)
+	if totals.Commits > 0 {
+		totals.Coverage = float64(totals.Annotated*1000/totals.Commits) / 10
+	}
+
+	ranked := make([]orgTheme, 0, len(themes))
+	for _, t := range themes {
+		ranked = append(ranked, *t)
+	}
+	// Themes spanning more repositories come first.
+	sort.Slice(ranked, func(i, j int) bool {
+		if len(ranked[i].Repos) != len(ranked[j].Repos) {
+			return len(ranked[i].Repos) > len(ranked[j].Repos)
+		}
+		if ranked[i].Commits != ranked[j].Commits {
+			return ranked[i].Commits > ranked[j].Commits
+		}
+		return ranked[i].Label < ranked[j].Label
+	})
+	if len(ranked) > maxOrgThemes {
+		ranked = ranked[:maxOrgThemes]
+	}
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Lines > findings[j].Lines })
+	if len(findings) > maxOrgFindings {
+		findings = findings[:maxOrgFindings]
+	}
+	return totals, ranked, findings
+}
+
+// orgSummary renders the figures for the narrative prompt.
+func orgSummary(repos []orgRepoReport, totals orgTotals, themes []orgTheme, findings []orgFinding) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Totals: %d repositories, %d commits by %d authors, +%d/-%d lines, %.1f%% annotated, risk %s, %d reverts\n",
+		totals.Repos, totals.Commits, totals.Authors, totals.Added, totals.Deleted, totals.Coverage, riskCounts(totals.Risk), totals.Reverts)
+
+	b.WriteString("\nThemes (annotation labels):\n")
+	for _, t := range themes {
+		fmt.Fprintf(&b, "- %s: %d commits in %s\n", t.Label, t.Commits, strings.Join(t.Repos, ", "))
+	}
+
+	b.WriteString("\nHighest-risk commits:\n")
+	for _, f := range findings {
+		fmt.Fprintf(&b, "- %s %s (%d lines): %s\n", f.Repo, f.Hash, f.Lines, f.Subject)
+		if f.Gist != "" {
+			fmt.Fprintf(&b, "  %s\n", f.Gist)
+		}
+	}
+
+	b.WriteString("\nRepositories:\n")
+	for _, r := range repos {
+		if r.Error != "" {
+			fmt.Fprintf(&b, "- %s: not read (%s)\n", r.Name, r.Error)
+			continue
+		}
+		team := ""
+		if r.Team != "" {
+			team = " (team " + r.Team + ")"
+		}
+		fmt.Fprintf(&b, "- %s%s: %d commits by %d authors, +%d/-%d lines, %.1f%% annotated, risk %s, %d reverts\n",
+			r.Name, team, r.Commits, r.Authors, r.Added, r.Deleted, r.Coverage, riskCounts(r.Risk), r.Reverts)
+		if r.Hotspots != "" {
+			fmt.Fprintf(&b, "  Most changed: %s\n", r.Hotspots)
+		}
+		if len(r.Unstable) > 0 {
+			fmt.Fprintf(&b, "  Unstable: %s\n", strings.Join(r.Unstable, ", "))
+		}
+	}
+	return b.String()
+}
+
+// riskCounts renders risk level counts, highest first.
+func riskCounts(risk map[string]int) string {
+	var parts []string
+	for i := len(riskLevels) - 1; i >= 0; i-- {
+		parts = append(parts, fmt.Sprintf("%d %s", risk[riskLevels[i]], riskLevels[i]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// orgDocument renders the report as Markdown.
+func orgDocument(name string, opts orgReportOptions, repos []orgRepoReport, totals orgTotals, themes []orgTheme, findings []orgFinding, narrative string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s: engineering report for %s\n\n", name, opts.label)
+	unread := ""
+	if n := len(repos) - totals.Repos; n > 0 {
+		unread = fmt.Sprintf(" (%d more could not be read)", n)
+	}
+	fmt.Fprintf(&b, "Compiled by arc-git org-report on %s from %d repositories%s, covering commits from %s up to %s. Risk levels and labels come from AI annotations of the commits.\n\n",
+		time.Now().Format("2006-01-02"), totals.Repos, unread, opts.start.Format("2006-01-02"), opts.end.Format("2006-01-02"))
+
+	b.WriteString("## Summary\n\n")
+	fmt.Fprintf(&b, "- %d commits by %d authors across %d repositories\n", totals.Commits, totals.Authors, totals.Repos)
+	fmt.Fprintf(&b, "- +%d/-%d lines changed\n", totals.Added, totals.Deleted)
+	fmt.Fprintf(&b, "- %.1f%% of commits annotated; risk: %s\n", totals.Coverage, riskCounts(totals.Risk))
+	fmt.Fprintf(&b, "- %d reverts\n", totals.Reverts)
+
+	if narrative != "" {
+		b.WriteString("\n## Assessment\n\n" + strings.TrimSpace(narrative) + "\n")
+	}
+
+	if len(themes) > 0 {
+		b.WriteString("\n## Cross-repo themes\n\n| Label | Commits | Repositories |\n|---|---:|---|\n")
+		for _, t := range themes {
+			fmt.Fprintf(&b, "| %s | %d | %s |\n", t.Label, t.Commits, strings.Join(t.Repos, ", "))
+		}
+	}
+
+	b.WriteString("\n## Repositories\n\n| Repository | Team | Commits | Authors | Lines | Annotated | High risk | Reverts |\n|---|---|---:|---:|---:|---:|---:|---:|\n")
+	for _, r := range repos {
+		if r.Error != "" {
+			fmt.Fprintf(&b, "| %s | %s | not read: %s | | | | | |\n", r.Name, r.Team, r.Error)
+			continue
+		}
+		coverage := fmt.Sprintf("%.1f%%", r.Coverage)
+		if r.Commits > 0 && r.Coverage < lowCoverage {
+			coverage += " (low)"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %d | %d | +%d/-%d | %s | %d | %d |\n", r.Name, r.Team, r.Commits, r.Authors, r.Added, r.Deleted, coverage, r.Risk["high"], r.Reverts)
+	}
+
+	if len(findings) > 0 {
+		b.WriteString("\n## Highest-risk changes\n\n")
+		for _, f := range findings {
+			fmt.Fprintf(&b, "- **%s** `%s` %s (%d lines)", f.Repo, f.Hash, f.Subject, f.Lines)
+			if f.Gist != "" {
+				b.WriteString(": " + f.Gist)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n## Repository details\n")
+	for _, r := range repos {
+		if r.Error != "" || r.Commits == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", r.Name)
+		if r.Hotspots != "" {
+			fmt.Fprintf(&b, "- Most changed areas: %s\n", r.Hotspots)
+		}
+		if len(r.Unstable) > 0 {
+			fmt.Fprintf(&b, "- Unstable areas: %s\n", strings.Join(r.Unstable, ", "))
+		}
+		if len(r.Labels) > 0 {
+			fmt.Fprintf(&b, "- Labels: %s\n", topAreas(r.Labels, 6))
+		}
+		fmt.Fprintf(&b, "- Risk: %s\n", riskCounts(r.Risk))
+	}
+	return b.String()
+}
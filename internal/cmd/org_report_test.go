@@ -0,0 +1,32 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourorg/arc-git/internal/config"
+)
+
+func TestOrgRepoFiguresIdentities(t *testing.T) {
+	r := newTestRepo(t)
+	r.commit("Add config", map[string]string{
+		config.FileName: "identities:\n  - name: Alice\n    email: alice@example.com\n    match: [alice@*]\n",
+	})
+	for _, email := range []string{"alice@old.example", "ALICE@new.example", "bob@example.com"} {
+		t.Setenv("GIT_AUTHOR_EMAIL", email)
+		r.commit("Commit by "+email, map[string]string{"f.txt": email + "\n"})
+	}
+
+	opts := orgReportOptions{depth: 1, start: time.Now().Add(-time.Hour), end: time.Now().Add(time.Hour)}
+	report, err := orgRepoFigures(r.ctx, config.WorkspaceRepo{Name: "repo", Path: r.dir, Rev: "HEAD"}, opts)
+	if err != nil {
+		t.Fatalf("orgRepoFigures: %v", err)
+	}
+	// test@example.com, Alice under both emails, and Bob.
+	if report.Commits != 4 || report.Authors != 3 {
+		t.Errorf("orgRepoFigures = %d commits by %d authors, want 4 by 3", report.Commits, report.Authors)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// isStructured reports whether out requests quiet or machine-readable output.
+func isStructured(out output.OutputOptions) bool {
+	return out.Is(output.OutputQuiet) || out.Is(output.OutputJSON) || out.Is(output.OutputYAML)
+}
+
+// progressFor hides progress logging when out is quiet or machine-readable.
+func progressFor(out output.OutputOptions) {
+	if isStructured(out) {
+		logging.SuppressProgress()
+	}
+}
+
+// writeJSON encodes v as indented JSON on stdout.
+func writeJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return errors.NewCLIError(fmt.Sprintf("failed to encode JSON: %v", err)).
+			WithHint("Try --output table instead")
+	}
+	return nil
+}
+
+// errString returns err's message, or "" for nil.
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
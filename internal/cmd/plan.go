@@ -0,0 +1,189 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// ErrPlanned is returned by runPrompt under --plan instead of sending the
+// request. Commands that make a single request stop there; commands that
+// loop record every request and write nothing.
+var ErrPlanned = errors.New("AI request not sent (--plan)")
+
+// planKey carries the run's plan through the command context.
+type planKey struct{}
+
+// planSubjectKey carries what the next AI request is about, such as a commit.
+type planSubjectKey struct{}
+
+// runPlan collects the AI requests a --plan run would have sent.
+type runPlan struct {
+	mu       sync.Mutex
+	provider string
+	// configError is why the provider configuration would be rejected.
+	configError string
+	overBudget  bool
+	requests    []plannedRequest
+}
+
+// plannedRequest is an AI request that was not sent.
+type plannedRequest struct {
+	Subject      string `json:"subject"`
+	Model        string `json:"model"`
+	InputTokens  int    `json:"input_tokens_estimated"`
+	OutputTokens int    `json:"output_tokens_estimated"`
+}
+
+// withPlan returns ctx carrying p.
+func withPlan(ctx context.Context, p *runPlan) context.Context {
+	return context.WithValue(ctx, planKey{}, p)
+}
+
+// planFor returns the run's plan, or nil when not running under --plan.
+func planFor(ctx context.Context) *runPlan {
+	p, _ := ctx.Value(planKey{}).(*runPlan)
+	return p
+}
+
+// withPlanSubject returns ctx naming what AI requests made with it are
+// about, for the plan listing.
+func withPlanSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, planSubjectKey{}, subject)
+}
+
+// record adds a request to the plan.
+func (p *runPlan) record(ctx context.Context, model string, inputTokens int) {
+	subject, _ := ctx.Value(planSubjectKey{}).(string)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, plannedRequest{
+		Subject:      subject,
+		Model:        model,
+		InputTokens:  inputTokens,
+		OutputTokens: prompt.ReplyTokens,
+	})
+}
+
+// planRuns wraps the RunE of c and its subcommands so that under --plan they
+// run without sending AI requests and print the plan instead of their own
+// output. Commands that never call a provider are not run at all.
+func planRuns(c *cobra.Command) {
+	for _, sub := range c.Commands() {
+		planRuns(sub)
+	}
+	run := c.RunE
+	if run == nil {
+		return
+	}
+	c.RunE = func(cmd *cobra.Command, args []string) error {
+		p := planFor(cmd.Context())
+		if p == nil {
+			return run(cmd, args)
+		}
+		if cmd.Flags().Lookup("provider") != nil {
+			if err := runSilenced(func() error { return run(cmd, args) }); err != nil && !errors.Is(err, ErrPlanned) {
+				return err
+			}
+		}
+		format := ""
+		if f := cmd.Flags().Lookup("output"); f != nil {
+			format = f.Value.String()
+		}
+		return p.write(cmd.CommandPath(), format)
+	}
+}
+
+// runSilenced runs fn with stdout discarded.
+func runSilenced(fn func() error) error {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return fn()
+	}
+	defer devNull.Close()
+
+	stdout := os.Stdout
+	os.Stdout = devNull
+	defer func() { os.Stdout = stdout }()
+	return fn()
+}
+
+// write prints the plan for command in the given --output format.
+func (p *runPlan) write(command, format string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var input, reply int
+	cost, priced := 0.0, len(p.requests) > 0
+	for _, r := range p.requests {
+		input += r.InputTokens
+		reply += r.OutputTokens
+		c, ok := prompt.EstimateCost(r.Model, r.InputTokens, r.OutputTokens)
+		cost += c
+		priced = priced && ok
+	}
+
+	switch format {
+	case string(output.OutputJSON):
+		result := map[string]interface{}{
+			"command":                 command,
+			"provider":                p.provider,
+			"requests":                p.requests,
+			"input_tokens_estimated":  input,
+			"output_tokens_estimated": reply,
+			"over_budget":             p.overBudget,
+		}
+		if priced {
+			result["cost_usd_estimated"] = cost
+		}
+		if p.configError != "" {
+			result["config_error"] = p.configError
+		}
+		return writeJSON(result)
+	case string(output.OutputQuiet):
+		// Quiet mode: suppress output
+		return nil
+	}
+
+	fmt.Printf("\n=== Plan: %s ===\n", command)
+	if len(p.requests) == 0 {
+		fmt.Println("No AI requests would be made.")
+		return nil
+	}
+	if p.provider != "" {
+		fmt.Printf("Provider: %s\n", p.provider)
+	}
+	if p.configError != "" {
+		fmt.Printf("Warning: %s\n", p.configError)
+	}
+	fmt.Println()
+	for _, r := range p.requests {
+		subject := r.Subject
+		if subject == "" {
+			subject = strings.TrimPrefix(command, "arc-git ")
+		}
+		fmt.Printf("  %-20s %-30s ~%d in, ~%d out\n", subject, r.Model, r.InputTokens, r.OutputTokens)
+	}
+	fmt.Printf("\nRequests: %d\n", len(p.requests))
+	fmt.Printf("Estimated tokens: ~%d input, ~%d output\n", input, reply)
+	if priced {
+		fmt.Printf("Estimated cost: ~$%.2f\n", cost)
+	} else {
+		fmt.Println("Estimated cost: unknown (no price for some models)")
+	}
+	if p.overBudget {
+		fmt.Println("\nThe run would stop early at budget.max_tokens; later requests are not listed.")
+	}
+	fmt.Println("\n(Plan only - no AI requests were sent and nothing was written)")
+	return nil
+}
@@ -0,0 +1,317 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/config"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-git/internal/providers"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// newProvidersCmd creates the providers command group.
+func newProvidersCmd(aiCfg *ai.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "providers",
+		Short: "Inspect the AI providers arc-git can use",
+	}
+
+	cmd.AddCommand(newProvidersStatusCmd(aiCfg))
+
+	return cmd
+}
+
+// newProvidersStatusCmd creates the providers status subcommand.
+func newProvidersStatusCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		filter     string
+		aiOpts     aiFlags
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Check provider reachability and the models they serve",
+		Long: `Check that each configured AI provider is reachable and accepts its API key,
+list the models it serves, and flag configured models it no longer serves.
+
+The active provider (from --provider, the repository config, or the arc
+config) is always checked. Other providers are checked when their API key is
+set in the environment (` + providerEnvKeys() + `).
+
+Models are listed with their context size and price per million tokens where
+the provider exposes them; OpenRouter does, the Anthropic API does not.
+Use --filter to narrow long lists.
+
+The configured models are every command's built-in default, the models
+section of the repository config, and --model. They are checked against the
+active provider, matching aliases to dated ids, and the command exits
+non-zero when any of them is missing, so that a provider-side deprecation
+shows up here rather than halfway through a long run.
+
+No prompts are sent.`,
+		Example: `  # Check the active provider and the configured models
+  arc-git providers status
+
+  # Only list Claude models
+  arc-git providers status --filter claude
+
+  # Check OpenRouter with an explicit key
+  arc-git providers status --provider openrouter --api-key "$KEY"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+			return runProvidersStatus(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, filter, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&filter, "filter", "", "Only list models whose id contains this")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// providerStatus is the health of one provider.
+type providerStatus struct {
+	Name    string `json:"name"`
+	Active  bool   `json:"active"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	failure
+	ModelCount int               `json:"model_count"`
+	Models     []providers.Model `json:"models,omitempty"`
+
+	catalog []providers.Model
+}
+
+// configuredModel is a model some part of the configuration selects.
+type configuredModel struct {
+	ID        string   `json:"id"`
+	Sources   []string `json:"sources"`
+	Available bool     `json:"available"`
+	// Served is the provider's id for the model, when it differs.
+	Served        string `json:"served_as,omitempty"`
+	ContextTokens int    `json:"context_tokens,omitempty"`
+}
+
+// runProvidersStatus implements the providers status workflow.
+func runProvidersStatus(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, filter string, out output.OutputOptions) error {
+	if planFor(ctx) != nil {
+		// Nothing here sends prompts.
+		return nil
+	}
+
+	active := providers.Canonical(cfg.Provider)
+	names := []string{active}
+	for _, name := range providers.Names {
+		if key, _ := providers.EnvKey(name); key != "" && name != active {
+			names = append(names, name)
+		}
+	}
+
+	var statuses []providerStatus
+	for _, name := range names {
+		key := ""
+		if name == active {
+			key = cfg.APIKey
+		}
+		if key == "" {
+			key, _ = providers.EnvKey(name)
+		}
+		statuses = append(statuses, checkProviderStatus(ctx, name, key, name == active, filter))
+	}
+
+	// Configured models are checked against the active provider only, since
+	// that is where prompts go.
+	models := configuredModels(ctx, cfg, aiOpts)
+	missing := 0
+	if statuses[0].Status == checkOK {
+		for i := range models {
+			if m, ok := providers.Find(statuses[0].catalog, models[i].ID); ok {
+				models[i].Available, models[i].ContextTokens = true, m.ContextTokens
+				if m.ID != models[i].ID {
+					models[i].Served = m.ID
+				}
+				continue
+			}
+			missing++
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		if err := writeJSON(map[string]interface{}{
+			"providers":         statuses,
+			"configured_models": models,
+			"missing_models":    missing,
+		}); err != nil {
+			return err
+		}
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		printProviderStatuses(statuses, models)
+	}
+
+	switch {
+	case statuses[0].Status != checkOK:
+		return errors.NewCLIError(fmt.Sprintf("provider %s is not usable", active)).
+			WithHint(statuses[0].Hint)
+	case missing > 0:
+		return errors.NewCLIError(fmt.Sprintf("%d configured models are not served by %s", missing, active)).
+			WithHint("Update the models section of " + config.FileName + ", or pass --model with a served model")
+	}
+	return nil
+}
+
+// checkProviderStatus checks one provider and lists its models.
+func checkProviderStatus(ctx context.Context, name, key string, active bool, filter string) providerStatus {
+	status := providerStatus{Name: name, Active: active, Status: checkFail}
+	if name == "" {
+		status.Message = "no provider configured"
+		status.Hint = "Set provider in " + config.FileName + " or pass --provider"
+		return status
+	}
+	if err := repoConfig(ctx).CheckAI(name, ""); err != nil {
+		status.Message, status.failure = err.Error(), classifyFailure(err)
+		return status
+	}
+	catalog, err := providers.New(name, key)
+	if err != nil {
+		status.Message, status.Hint = err.Error(), "Pass --provider "+strings.Join(providers.Names, " or --provider ")
+		return status
+	}
+	if key == "" {
+		_, variable := providers.EnvKey(name)
+		status.Message = "no API key"
+		status.Hint = "Pass --api-key or set " + variable
+		return status
+	}
+
+	if err := catalog.Check(ctx); err != nil {
+		status.Message, status.failure = err.Error(), classifyFailure(err)
+		return status
+	}
+	models, err := catalog.Models(ctx)
+	if err != nil {
+		status.Message, status.failure = err.Error(), classifyFailure(err)
+		return status
+	}
+
+	status.Status, status.ModelCount, status.catalog = checkOK, len(models), models
+	for _, m := range models {
+		if filter == "" || strings.Contains(strings.ToLower(m.ID), strings.ToLower(filter)) {
+			status.Models = append(status.Models, m)
+		}
+	}
+	sort.Slice(status.Models, func(i, j int) bool { return status.Models[i].ID < status.Models[j].ID })
+	return status
+}
+
+// configuredModels collects the distinct models the configuration selects,
+// with where each is selected.
+func configuredModels(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags) []configuredModel {
+	var models []configuredModel
+	add := func(id, source string) {
+		if id == "" {
+			return
+		}
+		for i := range models {
+			if models[i].ID == id {
+				if !slices.Contains(models[i].Sources, source) {
+					models[i].Sources = append(models[i].Sources, source)
+				}
+				return
+			}
+		}
+		models = append(models, configuredModel{ID: id, Sources: []string{source}})
+	}
+
+	add(aiOpts.model, "--model")
+	commands := make([]string, 0, len(repoConfig(ctx).Models))
+	for command := range repoConfig(ctx).Models {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+	for _, command := range commands {
+		add(repoConfig(ctx).Models[command], "models."+command)
+	}
+	if aiOpts.model == "" {
+		add(cfg.DefaultModel, "default model")
+	}
+	for _, id := range prompt.DefaultModels {
+		add(id, "built-in default")
+	}
+	return models
+}
+
+// printProviderStatuses prints provider statuses and configured models as
+// text.
+func printProviderStatuses(statuses []providerStatus, models []configuredModel) {
+	for _, s := range statuses {
+		name := s.Name
+		if s.Active {
+			name += " (active)"
+		}
+		if s.Status != checkOK {
+			fmt.Printf("%s: %s - %s\n", name, s.Status, s.Message)
+			if s.Hint != "" {
+				fmt.Printf("  Hint: %s\n", s.Hint)
+			}
+			fmt.Println()
+			continue
+		}
+		fmt.Printf("%s: %s, %d models (context, $ per million input / output tokens)\n", name, s.Status, s.ModelCount)
+		for _, m := range s.Models {
+			window, price := "-", "-"
+			if m.ContextTokens > 0 {
+				window = fmt.Sprintf("%dk", m.ContextTokens/1000)
+			}
+			if m.InputPrice > 0 || m.OutputPrice > 0 {
+				price = fmt.Sprintf("$%.2f / $%.2f", m.InputPrice, m.OutputPrice)
+			}
+			fmt.Printf("  %-45s %8s  %s\n", m.ID, window, price)
+		}
+		fmt.Println()
+	}
+
+	if len(statuses) == 0 || statuses[0].Status != checkOK {
+		return
+	}
+	fmt.Printf("Configured models (%s):\n", statuses[0].Name)
+	for _, m := range models {
+		state := "ok"
+		if !m.Available {
+			state = "missing"
+		}
+		id := m.ID
+		if m.Served != "" {
+			id += " (as " + m.Served + ")"
+		}
+		fmt.Printf("  %-8s %-45s %s\n", state, id, strings.Join(m.Sources, ", "))
+	}
+}
+
+// providerEnvKeys lists the API key environment variables for help text.
+func providerEnvKeys() string {
+	var vars []string
+	for _, name := range providers.Names {
+		_, variable := providers.EnvKey(name)
+		vars = append(vars, variable)
+	}
+	return strings.Join(vars, ", ")
+}
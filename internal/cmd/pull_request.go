@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/forge"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	clierrors "github.com/yourorg/arc-sdk/errors"
+)
+
+// prFlags holds the --pr flag of commands that work on a range of commits,
+// which takes the range from a pull request on the forge instead of --from
+// and --to.
+type prFlags struct {
+	number int
+	remote string
+	cmd    *cobra.Command
+}
+
+// register adds the --pr and --remote flags to cmd.
+func (f *prFlags) register(cmd *cobra.Command) {
+	f.cmd = cmd
+	cmd.Flags().IntVar(&f.number, "pr", 0, "Work on the commits of this pull request, from its base to its head (instead of --from and --to)")
+	cmd.Flags().StringVar(&f.remote, "remote", "origin", "Remote of the forge --pr is looked up on and fetched from")
+}
+
+// set reports whether --pr was given.
+func (f *prFlags) set() bool {
+	return f.number > 0
+}
+
+// check rejects --pr together with the given range flags.
+func (f *prFlags) check(flags ...string) error {
+	if !f.set() || f.cmd == nil {
+		return nil
+	}
+	for _, name := range flags {
+		if f.cmd.Flags().Changed(name) {
+			return clierrors.NewCLIError(fmt.Sprintf("--pr cannot be combined with --%s", name)).
+				WithHint("The pull request's base and head give the range; drop --" + name)
+		}
+	}
+	return nil
+}
+
+// resolve looks the pull request up on the forge behind the remote, fetches
+// its head and base, and returns the base and head commits, so that
+// base..head are exactly the pull request's commits.
+func (f *prFlags) resolve(ctx context.Context) (base, head string, err error) {
+	url, err := git.RemoteURL(ctx, f.remote)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve remote %q: %w", f.remote, err)
+	}
+	provider, err := forge.Detect(url)
+	if err != nil {
+		return "", "", clierrors.NewCLIError(fmt.Sprintf("cannot look up pull request %d: %v", f.number, err)).
+			WithHint("Pass --from and --to instead of --pr")
+	}
+	pr, err := provider.PullRequest(ctx, f.number)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to look up pull request %d: %w", f.number, err)
+	}
+	logging.L().Info("Resolved pull request", "pr", pr.Number, "base", pr.BaseRef, "head", pr.HeadRef, "commit", shortHash(pr.HeadCommit))
+
+	refs := []string{fmt.Sprintf("refs/pull/%d/head", f.number)}
+	if !git.Exists(ctx, pr.BaseCommit) {
+		refs = append(refs, "refs/heads/"+pr.BaseRef)
+	}
+	if !git.Exists(ctx, pr.HeadCommit) || len(refs) > 1 {
+		if _, err := git.Run(ctx, append([]string{"fetch", "--quiet", f.remote}, refs...)...); err != nil {
+			return "", "", clierrors.NewCLIError(fmt.Sprintf("cannot fetch pull request %d: %v", f.number, err)).
+				WithHint("Pull request heads are fetched from GitHub's refs/pull/N/head; check the remote and its credentials")
+		}
+	}
+	for _, c := range []string{pr.BaseCommit, pr.HeadCommit} {
+		if !git.Exists(ctx, c) {
+			return "", "", clierrors.NewCLIError(fmt.Sprintf("commit %s of pull request %d is not available after fetching", shortHash(c), f.number)).
+				WithHint("The pull request may have been force-pushed meanwhile; run the command again")
+		}
+	}
+	return pr.BaseCommit, pr.HeadCommit, nil
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// rangeDiffPair matches a patch pairing line of git range-diff output, e.g.
+// "1:  abc1234 ! 1:  def5678 subject". Unmatched sides are shown as "-".
+var rangeDiffPair = regexp.MustCompile(`^\s*(?:\d+|-):\s+[0-9a-f-]+ ([=!<>]) (?:\d+|-):\s+[0-9a-f-]+ (.*)$`)
+
+// newRangeDiffExplainCmd creates the range-diff-explain subcommand.
+func newRangeDiffExplainCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		base       string
+		newBase    string
+		aiOpts     aiFlags
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "range-diff-explain <old> <new>",
+		Short: "Explain what changed between two versions of a patch series",
+		Long: `Explain in prose what changed between two versions of the same patch series.
+
+git range-diff is run under the hood to pair up the patches of both versions.
+The AI then explains how modified patches changed and which feedback they
+probably address, which patches were added or dropped, and how many are
+unchanged and can be skipped on re-review.
+
+Both series are assumed to start at the merge base of <old> and <new>. When
+the new version was rebased onto a newer upstream, pass --base for the old
+version's upstream and --new-base for the new one's, so upstream commits do
+not show up as added patches.`,
+		Example: `  # Explain v2 of a series relative to v1
+  arc-git range-diff-explain my-series-v1 my-series-v2
+
+  # The new version was rebased from an older main onto origin/main
+  arc-git range-diff-explain my-series-v1 my-series-v2 --base v1-base --new-base origin/main
+
+  # Emit structured JSON
+  arc-git range-diff-explain my-series-v1 my-series-v2 --output json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+
+			return runRangeDiffExplain(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, args[0], args[1], base, newBase, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&base, "base", "", "Upstream the old series is based on (default: merge base of both)")
+	cmd.Flags().StringVar(&newBase, "new-base", "", "Upstream the new series is based on (default: --base)")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// rangeDiffStats counts patch pairings by kind.
+type rangeDiffStats struct {
+	Unchanged int      `json:"unchanged"`
+	Modified  []string `json:"modified"`
+	Added     []string `json:"added"`
+	Dropped   []string `json:"dropped"`
+}
+
+// runRangeDiffExplain implements the range-diff-explain workflow.
+func runRangeDiffExplain(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, oldRev, newRev, base, newBase string, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	if base == "" {
+		mb, err := git.Run(ctx, "merge-base", oldRev, newRev)
+		if err != nil {
+			return errors.NewCLIError(fmt.Sprintf("%s and %s share no history", oldRev, newRev)).
+				WithHint("Pass --base (and --new-base) explicitly")
+		}
+		base = strings.TrimSpace(mb)
+	}
+	if newBase == "" {
+		newBase = base
+	}
+
+	log.Info("Running range-diff", "old", oldRev, "new", newRev)
+	rangeDiff, err := git.RangeDiff(ctx, base+".."+oldRev, newBase+".."+newRev)
+	if err != nil {
+		return fmt.Errorf("failed to compare series: %w", err)
+	}
+	if strings.TrimSpace(rangeDiff) == "" {
+		return errors.NewCLIError("both series are empty").
+			WithHint("Check the --base/--new-base revisions")
+	}
+	stats := parseRangeDiff(rangeDiff)
+
+	var explanation string
+	if len(stats.Modified)+len(stats.Added)+len(stats.Dropped) == 0 {
+		explanation = fmt.Sprintf("All %d patches are unchanged between %s and %s.", stats.Unchanged, oldRev, newRev)
+	} else {
+		service, err := newAIService(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		guarded, err := guardRangeDiff(ctx, base+".."+oldRev, newBase+".."+newRev, rangeDiff)
+		if err != nil {
+			return err
+		}
+		log.Info("Generating explanation", "modified", len(stats.Modified), "added", len(stats.Added), "dropped", len(stats.Dropped))
+		systemPrompt, userPrompt := prompt.RangeDiffExplain(oldRev, newRev, truncate(guarded, maxRangeDiff))
+		explanation, err = runPrompt(ctx, service, systemPrompt, userPrompt, aiOpts.modelFor(ctx, prompt.RangeDiffExplainModel))
+		if err != nil {
+			return fmt.Errorf("failed to generate explanation: %w", err)
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"old":         oldRev,
+			"new":         newRev,
+			"stats":       stats,
+			"explanation": explanation,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		fmt.Printf("=== %s -> %s: %d unchanged, %d modified, %d added, %d dropped ===\n\n",
+			oldRev, newRev, stats.Unchanged, len(stats.Modified), len(stats.Added), len(stats.Dropped))
+		fmt.Println(explanation)
+	}
+
+	return nil
+}
+
+// parseRangeDiff counts the patch pairings in range-diff output.
+func parseRangeDiff(rangeDiff string) rangeDiffStats {
+	var stats rangeDiffStats
+	for _, line := range strings.Split(rangeDiff, "\n") {
+		m := rangeDiffPair.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		switch m[1] {
+		case "=":
+			stats.Unchanged++
+		case "!":
+			stats.Modified = append(stats.Modified, m[2])
+		case ">":
+			stats.Added = append(stats.Added, m[2])
+		case "<":
+			stats.Dropped = append(stats.Dropped, m[2])
+		}
+	}
+	return stats
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/forge"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/ledger"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// newReleaseCmd creates the release command group.
+func newReleaseCmd(aiCfg *ai.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "release",
+		Short: "Generate and publish releases",
+		Long: `Generate release notes and publish releases on the forge.
+
+See also arc-git release-check for a pre-release checklist.`,
+	}
+
+	cmd.AddCommand(newReleasePublishCmd(aiCfg))
+
+	return cmd
+}
+
+// releaseOptions holds the flags of release publish.
+type releaseOptions struct {
+	tag        string
+	from       string
+	changelog  string
+	remote     string
+	draft      bool
+	prerelease bool
+	dryRun     bool
+}
+
+// newReleasePublishCmd creates the release publish subcommand.
+func newReleasePublishCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts       releaseOptions
+		aiOpts     aiFlags
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "publish",
+		Short: "Generate release notes for a tag and publish the forge release",
+		Long: `Generate release notes for a tag and publish them as a forge release.
+
+The notes are written by the AI from the commits between the previous tag
+and --tag, using existing AI annotations as extra context. If the changelog
+file has a section for the tag, it is attached to the release body. The
+published release is recorded in the arc-git ledger.
+
+The tag must already exist locally and on the remote. This makes the command
+suitable for a CI job triggered by tag pushes; GitHub requires GITHUB_TOKEN
+or GH_TOKEN.`,
+		Example: `  # Publish v2.4.0 from a tag-push CI job
+  arc-git release publish --tag v2.4.0
+
+  # Preview the release body without publishing
+  arc-git release publish --tag v2.4.0 --dry-run
+
+  # Publish as a draft pre-release, comparing against an explicit tag
+  arc-git release publish --tag v2.4.0-rc.1 --from v2.3.0 --draft --prerelease`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if opts.tag == "" {
+				return errors.NewCLIError("--tag is required").
+					WithHint("Pass the tag to publish, e.g. --tag v2.4.0")
+			}
+
+			return runReleasePublish(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, opts, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.tag, "tag", "", "Tag to publish (e.g., v2.4.0)")
+	cmd.Flags().StringVar(&opts.from, "from", "", "Previous release (default: the tag before --tag)")
+	cmd.Flags().StringVar(&opts.changelog, "changelog", "CHANGELOG.md", "Changelog file to take the tag's section from")
+	cmd.Flags().StringVar(&opts.remote, "remote", "origin", "Remote used to detect the forge provider")
+	cmd.Flags().BoolVar(&opts.draft, "draft", false, "Create the release as a draft")
+	cmd.Flags().BoolVar(&opts.prerelease, "prerelease", false, "Mark the release as a pre-release")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the release body without publishing")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// runReleasePublish implements the release publish workflow.
+func runReleasePublish(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, opts releaseOptions, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	if _, err := git.Run(ctx, "rev-parse", "--verify", "--quiet", "refs/tags/"+opts.tag); err != nil {
+		return errors.NewCLIError(fmt.Sprintf("tag %s does not exist", opts.tag)).
+			WithHint("Create and push the tag first, or fetch tags with git fetch --tags")
+	}
+	// Forges create a missing tag at the default branch's head rather than
+	// refuse, so an unpushed tag would publish the wrong commit.
+	if !opts.dryRun {
+		if err := checkRemoteTag(ctx, opts.remote, opts.tag); err != nil {
+			return err
+		}
+	}
+
+	from := opts.from
+	if from == "" {
+		prev, err := git.Run(ctx, "describe", "--tags", "--abbrev=0", opts.tag+"^")
+		if err != nil {
+			return errors.NewCLIError(fmt.Sprintf("no tag before %s", opts.tag)).
+				WithHint("Pass --from with the previous release")
+		}
+		from = strings.TrimSpace(prev)
+	}
+
+	rangeSpec := fmt.Sprintf("%s..%s", from, opts.tag)
+	commits, err := git.Log(ctx, "--no-merges", "--reverse", rangeSpec)
+	if err != nil {
+		return fmt.Errorf("failed to get commits: %w", err)
+	}
+	if len(commits) == 0 {
+		return errors.NewCLIError(fmt.Sprintf("no commits in %s", rangeSpec)).
+			WithHint("Check that --from is an ancestor of --tag")
+	}
+
+	var list strings.Builder
+	for _, c := range commits {
+		fmt.Fprintf(&list, "- %s %s\n", c.Short(), c.Message)
+		if note, err := git.ShowNote(ctx, c.Hash, "ai"); err == nil {
+			fmt.Fprintf(&list, "  Annotation: %s\n", note)
+		}
+	}
+
+	service, err := newAIService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Generating release notes", "tag", opts.tag, "from", from, "commits", len(commits))
+	systemPrompt, userPrompt := prompt.ReleaseNotes(opts.tag, from, list.String())
+	notes, err := runPrompt(ctx, service, systemPrompt, userPrompt, aiOpts.modelFor(ctx, prompt.ReleaseNotesModel))
+	if err != nil {
+		return fmt.Errorf("failed to generate release notes: %w", err)
+	}
+
+	body := notes
+	section := changelogSection(opts.changelog, opts.tag)
+	if section != "" {
+		log.Info("Attaching changelog section", "file", opts.changelog)
+		body += "\n\n## Changelog\n\n" + section
+	}
+
+	var url string
+	if !opts.dryRun {
+		remoteURL, err := git.RemoteURL(ctx, opts.remote)
+		if err != nil {
+			return fmt.Errorf("failed to resolve remote %q: %w", opts.remote, err)
+		}
+		provider, err := forge.Detect(remoteURL)
+		if err != nil {
+			return errors.NewCLIError(fmt.Sprintf("cannot publish release: %v", err)).
+				WithHint("Run with --dry-run to print the release body instead")
+		}
+
+		log.Info("Publishing release", "forge", provider.Name(), "tag", opts.tag)
+		url, err = provider.CreateRelease(ctx, forge.Release{
+			Tag:        opts.tag,
+			Name:       opts.tag,
+			Body:       body,
+			Draft:      opts.draft,
+			Prerelease: opts.prerelease,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to publish release: %w", err)
+		}
+
+		recordLedger(ctx, ledger.Entry{
+			Action: "release-publish",
+			Target: opts.tag,
+			URL:    url,
+			Detail: map[string]string{
+				"forge":   provider.Name(),
+				"from":    from,
+				"commits": fmt.Sprint(len(commits)),
+				"draft":   fmt.Sprint(opts.draft),
+			},
+		})
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"tag":       opts.tag,
+			"from":      from,
+			"commits":   len(commits),
+			"body":      body,
+			"changelog": section != "",
+			"url":       url,
+			"dry_run":   opts.dryRun,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		if opts.dryRun {
+			fmt.Printf("%s\n\n(Dry run - release not published)\n", body)
+		} else {
+			fmt.Printf("Published %s: %s\n", opts.tag, url)
+		}
+	}
+
+	return nil
+}
+
+// checkRemoteTag fails unless remote has tag, pointing where the local one
+// does.
+func checkRemoteTag(ctx context.Context, remote, tag string) error {
+	remoteTag, err := git.RemoteTag(ctx, remote, tag)
+	if err != nil {
+		return fmt.Errorf("failed to read tags of remote %q: %w", remote, err)
+	}
+	if remoteTag == "" {
+		return errors.NewCLIError(fmt.Sprintf("tag %s has not been pushed to %s", tag, remote)).
+			WithHint(fmt.Sprintf("Push it first: git push %s %s", remote, tag))
+	}
+	local, err := git.Run(ctx, "rev-parse", "refs/tags/"+tag)
+	if err != nil {
+		return fmt.Errorf("failed to read tag %s: %w", tag, err)
+	}
+	if strings.TrimSpace(local) != remoteTag {
+		return errors.NewCLIError(fmt.Sprintf("tag %s on %s differs from the local one", tag, remote)).
+			WithHint(fmt.Sprintf("Fetch the remote's tag with git fetch %s tag %s, or push the local one with --force", remote, tag))
+	}
+	return nil
+}
+
+// changelogSection returns the body of the changelog section whose heading
+// mentions tag, with or without its leading "v", or "" if there is none.
+func changelogSection(path, tag string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	version := strings.TrimPrefix(tag, "v")
+	var (
+		section []string
+		level   int
+	)
+	for _, line := range strings.Split(string(data), "\n") {
+		depth := len(line) - len(strings.TrimLeft(line, "#"))
+		if depth > 0 && strings.HasPrefix(line[depth:], " ") {
+			if level > 0 && depth <= level {
+				break
+			}
+			if level == 0 && (headingMentions(line, tag) || headingMentions(line, version)) {
+				level = depth
+				continue
+			}
+		}
+		if level > 0 {
+			section = append(section, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(section, "\n"))
+}
+
+// headingMentions reports whether a heading names version as a whole word,
+// so that "1.2" does not match "1.2.3".
+func headingMentions(heading, version string) bool {
+	for i := strings.Index(heading, version); i >= 0; {
+		end := i + len(version)
+		before := i == 0 || !isVersionChar(heading[i-1])
+		after := end == len(heading) || !isVersionChar(heading[end])
+		if before && after {
+			return true
+		}
+		next := strings.Index(heading[i+1:], version)
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return false
+}
+
+// isVersionChar reports whether c can be part of a version string.
+func isVersionChar(c byte) bool {
+	return c == '.' || c == '-' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z')
+}
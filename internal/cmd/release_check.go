@@ -0,0 +1,400 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/forge"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+var (
+	// migrationPattern matches schema migration files.
+	migrationPattern = regexp.MustCompile(`(^|/)(migrations?|migrate|schema)/|\.sql$`)
+	// flagPatterns capture configuration knobs introduced on added lines:
+	// CLI flags, environment variables, and config keys.
+	flagPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\.(?:String|Bool|Int|Int64|Uint|Float64|Duration|StringSlice|StringArray)(?:Var)?P?\((?:&[\w.]+, )?"([a-z0-9][a-z0-9-]*)"`),
+		regexp.MustCompile(`\bflag\.\w+\((?:&[\w.]+, )?"([^"]+)"`),
+		regexp.MustCompile(`(?:os\.(?:Getenv|LookupEnv)\(|process\.env\.|os\.environ(?:\.get)?\[?\(?)"?'?([A-Z][A-Z0-9_]{2,})`),
+	}
+	// exportedRemoval matches removed exported Go declarations.
+	exportedRemoval = regexp.MustCompile(`^-\s*(?:func (?:\([^)]*\) )?|type |var |const )([A-Z]\w*)`)
+	// breakingSubject matches conventional-commit breaking markers.
+	breakingSubject = regexp.MustCompile(`^\w+(\([^)]*\))?!:`)
+	// sensitivePattern matches paths where untested changes are risky.
+	sensitivePattern = regexp.MustCompile(`(?i)(auth|security|crypto|payment|billing|migrat|permission|session|token)`)
+)
+
+// newReleaseCheckCmd creates the release-check subcommand.
+func newReleaseCheckCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		from        string
+		to          string
+		riskyLines  int
+		noNarrative bool
+		aiOpts      aiFlags
+		outputOpts  output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "release-check",
+		Short: "Generate a pre-release checklist for a commit range",
+		Long: `Generate a pre-release checklist derived from the commits in a range.
+
+The checklist covers:
+- Pending migrations: schema or migration files added or changed
+- Config flags introduced: new CLI flags and environment variables
+- Breaking changes: conventional-commit "!" markers, BREAKING CHANGE footers,
+  and removed exported Go declarations
+- Docs likely needing updates: new flags and breaking changes with no
+  matching documentation change in the range
+- Untested risky commits: large or sensitive changes that touch no tests
+
+Every item links to the commits it was derived from. The AI then reviews the
+checklist and the commit list and writes a readiness assessment; use
+--no-narrative to print only the checklist.`,
+		Example: `  # Checklist for everything since the last release
+  arc-git release-check --from v2.3.0 --to HEAD
+
+  # Treat changes over 100 lines without tests as risky
+  arc-git release-check --from v2.3.0 --risky-lines 100
+
+  # Checklist only, as JSON, for CI gating
+  arc-git release-check --from v2.3.0 --no-narrative --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if from == "" {
+				return errors.NewCLIError("--from is required").
+					WithHint("Pass the previous release tag, e.g. --from v2.3.0")
+			}
+
+			return runReleaseCheck(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, from, to, riskyLines, noNarrative, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Previous release (e.g., v2.3.0)")
+	cmd.Flags().StringVar(&to, "to", "HEAD", "End of the release range")
+	cmd.Flags().IntVar(&riskyLines, "risky-lines", 200, "Changed lines without tests that make a commit risky")
+	cmd.Flags().BoolVar(&noNarrative, "no-narrative", false, "Skip the AI readiness assessment")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// checkItem is a single checklist entry with the commits it derives from.
+type checkItem struct {
+	Title  string   `json:"title"`
+	Hashes []string `json:"hashes"`
+	Links  []string `json:"links,omitempty"`
+}
+
+// releaseChecklist groups checklist items by section.
+type releaseChecklist struct {
+	Migrations      []checkItem `json:"migrations"`
+	ConfigFlags     []checkItem `json:"config_flags"`
+	BreakingChanges []checkItem `json:"breaking_changes"`
+	Docs            []checkItem `json:"docs"`
+	RiskyCommits    []checkItem `json:"risky_commits"`
+}
+
+// sections returns the checklist sections in display order.
+func (c *releaseChecklist) sections() []struct {
+	Name  string
+	Items []checkItem
+} {
+	return []struct {
+		Name  string
+		Items []checkItem
+	}{
+		{"Pending migrations", c.Migrations},
+		{"Config flags introduced", c.ConfigFlags},
+		{"Breaking changes", c.BreakingChanges},
+		{"Docs likely needing updates", c.Docs},
+		{"Untested risky commits", c.RiskyCommits},
+	}
+}
+
+// runReleaseCheck implements the release-check workflow.
+func runReleaseCheck(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, from, to string, riskyLines int, noNarrative bool, out output.OutputOptions) error {
+	rangeSpec := fmt.Sprintf("%s..%s", from, to)
+	commits, err := git.Log(ctx, "--no-merges", "--reverse", rangeSpec)
+	if err != nil {
+		return fmt.Errorf("failed to get commits: %w", err)
+	}
+	if len(commits) == 0 {
+		return errors.NewCLIError(fmt.Sprintf("no commits in %s", rangeSpec)).
+			WithHint("Check that --from names an ancestor of --to")
+	}
+
+	progressFor(out)
+	log := logging.L()
+
+	log.Info("Building release checklist", "range", rangeSpec, "commits", len(commits))
+	checklist, err := buildReleaseChecklist(ctx, commits, riskyLines)
+	if err != nil {
+		return err
+	}
+	linkChecklist(ctx, checklist)
+
+	var narrative string
+	if !noNarrative {
+		service, err := newAIService(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		log.Info("Generating readiness assessment")
+		var list strings.Builder
+		for _, c := range commits {
+			fmt.Fprintf(&list, "%s %s (%s)\n", c.Short(), c.Message, c.Author)
+		}
+		systemPrompt, userPrompt := prompt.ReleaseCheck(from, to, renderChecklist(checklist, false), list.String())
+		narrative, err = runPrompt(ctx, service, systemPrompt, userPrompt, aiOpts.modelFor(ctx, prompt.ReleaseCheckModel))
+		if err != nil {
+			return fmt.Errorf("failed to generate assessment: %w", err)
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"from":       from,
+			"to":         to,
+			"commits":    len(commits),
+			"checklist":  checklist,
+			"assessment": narrative,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		fmt.Printf("# Release checklist: %s (%d commits)\n\n", rangeSpec, len(commits))
+		fmt.Print(renderChecklist(checklist, true))
+		if narrative != "" {
+			fmt.Printf("\n## Assessment\n\n%s\n", narrative)
+		}
+	}
+
+	return nil
+}
+
+// buildReleaseChecklist derives checklist items from the commits' diffs.
+func buildReleaseChecklist(ctx context.Context, commits []git.Commit, riskyLines int) (*releaseChecklist, error) {
+	checklist := &releaseChecklist{}
+	migrations := make(map[string][]string)
+	flags := make(map[string][]string)
+	var docsDiff strings.Builder
+
+	for _, c := range commits {
+		stats, err := git.NumStat(ctx, c.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", c.Short(), err)
+		}
+		diff, err := git.Diff(ctx, c.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", c.Short(), err)
+		}
+
+		var (
+			codeLines   int
+			touchesTest bool
+			sensitive   bool
+		)
+		for _, s := range stats {
+			if migrationPattern.MatchString(s.Path) {
+				migrations[s.Path] = append(migrations[s.Path], c.Short())
+			}
+			switch {
+			case isTestFile(s.Path):
+				touchesTest = true
+			case isDocFile(s.Path):
+			default:
+				codeLines += s.Added + s.Deleted
+				if sensitivePattern.MatchString(s.Path) {
+					sensitive = true
+				}
+			}
+		}
+		if !touchesTest && codeLines > 0 && (codeLines >= riskyLines || sensitive) {
+			reason := fmt.Sprintf("%d changed lines", codeLines)
+			if sensitive {
+				reason += ", sensitive paths"
+			}
+			checklist.RiskyCommits = append(checklist.RiskyCommits, checkItem{
+				Title:  fmt.Sprintf("%s — %s, no tests", c.Message, reason),
+				Hashes: []string{c.Short()},
+			})
+		}
+
+		breaking := breakingSubject.MatchString(c.Message)
+		if body, err := git.Body(ctx, c.Hash); err == nil && strings.Contains(body, "BREAKING CHANGE") {
+			breaking = true
+		}
+		if breaking {
+			checklist.BreakingChanges = append(checklist.BreakingChanges, checkItem{
+				Title:  c.Message,
+				Hashes: []string{c.Short()},
+			})
+		}
+
+		file := ""
+		for _, line := range strings.Split(diff, "\n") {
+			if strings.HasPrefix(line, "+++ ") {
+				file = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+				continue
+			}
+			if isDocFile(file) {
+				docsDiff.WriteString(line + "\n")
+				continue
+			}
+			if isTestFile(file) {
+				continue
+			}
+			if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
+				for _, p := range flagPatterns {
+					for _, m := range p.FindAllStringSubmatch(line, -1) {
+						flags[m[1]] = appendUnique(flags[m[1]], c.Short())
+					}
+				}
+			}
+			if strings.HasSuffix(file, ".go") {
+				if m := exportedRemoval.FindStringSubmatch(line); m != nil && !strings.Contains(diff, "+"+strings.TrimPrefix(line, "-")) {
+					checklist.BreakingChanges = append(checklist.BreakingChanges, checkItem{
+						Title:  fmt.Sprintf("Exported %s removed or changed in %s", m[1], file),
+						Hashes: []string{c.Short()},
+					})
+				}
+			}
+		}
+	}
+
+	for _, path := range sortedKeys(migrations) {
+		checklist.Migrations = append(checklist.Migrations, checkItem{
+			Title:  fmt.Sprintf("Run and verify migration %s", path),
+			Hashes: migrations[path],
+		})
+	}
+
+	docs := docsDiff.String()
+	for _, name := range sortedKeys(flags) {
+		checklist.ConfigFlags = append(checklist.ConfigFlags, checkItem{
+			Title:  fmt.Sprintf("Review and document new setting %s", name),
+			Hashes: flags[name],
+		})
+		if !strings.Contains(docs, name) {
+			checklist.Docs = append(checklist.Docs, checkItem{
+				Title:  fmt.Sprintf("%s is new but not mentioned in any docs change", name),
+				Hashes: flags[name],
+			})
+		}
+	}
+	if docs == "" {
+		// No documentation changed at all: every breaking change needs
+		// upgrade notes.
+		for _, b := range checklist.BreakingChanges {
+			checklist.Docs = append(checklist.Docs, checkItem{
+				Title:  fmt.Sprintf("Upgrade notes for: %s", b.Title),
+				Hashes: b.Hashes,
+			})
+		}
+	}
+
+	return checklist, nil
+}
+
+// linkChecklist attaches web links to each item when the origin remote is
+// hosted on a known forge.
+func linkChecklist(ctx context.Context, c *releaseChecklist) {
+	url, err := git.RemoteURL(ctx, "origin")
+	if err != nil {
+		return
+	}
+	repo, err := forge.ParseRemote(url)
+	if err != nil {
+		return
+	}
+	for _, items := range [][]checkItem{c.Migrations, c.ConfigFlags, c.BreakingChanges, c.Docs, c.RiskyCommits} {
+		for i := range items {
+			for _, h := range items[i].Hashes {
+				items[i].Links = append(items[i].Links, repo.CommitURL(h))
+			}
+		}
+	}
+}
+
+// renderChecklist formats the checklist as Markdown. With links, hashes are
+// rendered as links to the forge when available.
+func renderChecklist(c *releaseChecklist, links bool) string {
+	var b strings.Builder
+	for _, section := range c.sections() {
+		fmt.Fprintf(&b, "## %s\n\n", section.Name)
+		if len(section.Items) == 0 {
+			b.WriteString("- none detected\n\n")
+			continue
+		}
+		for _, item := range section.Items {
+			refs := make([]string, len(item.Hashes))
+			for i, h := range item.Hashes {
+				refs[i] = h
+				if links && i < len(item.Links) {
+					refs[i] = fmt.Sprintf("[%s](%s)", h, item.Links[i])
+				}
+			}
+			fmt.Fprintf(&b, "- [ ] %s (%s)\n", item.Title, strings.Join(refs, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// isTestFile reports whether path looks like a test file.
+func isTestFile(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, "_test.go") ||
+		strings.Contains(lower, "/test/") || strings.Contains(lower, "/tests/") ||
+		strings.HasPrefix(lower, "test/") || strings.HasPrefix(lower, "tests/") ||
+		strings.Contains(lower, ".test.") || strings.Contains(lower, ".spec.") ||
+		strings.Contains(lower, "/test_") || strings.HasPrefix(lower, "test_")
+}
+
+// isDocFile reports whether path looks like documentation.
+func isDocFile(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, ".md") || strings.HasSuffix(lower, ".rst") ||
+		strings.HasSuffix(lower, ".adoc") || strings.HasPrefix(lower, "docs/") ||
+		strings.Contains(lower, "/docs/")
+}
+
+// appendUnique appends s to list unless it is already present.
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckRemoteTag(t *testing.T) {
+	r := newTestRepo(t)
+	remote := t.TempDir()
+	r.git("init", "-q", "--bare", remote)
+	r.git("remote", "add", "origin", remote)
+	r.commit("First", map[string]string{"a.txt": "a\n"})
+	r.git("tag", "-a", "-m", "v1", "v1.0.0")
+
+	if err := checkRemoteTag(r.ctx, "origin", "v1.0.0"); err == nil || !strings.Contains(err.Error(), "has not been pushed") {
+		t.Errorf("checkRemoteTag of an unpushed tag: error = %v, want it refused", err)
+	}
+
+	r.git("push", "-q", "origin", "v1.0.0")
+	if err := checkRemoteTag(r.ctx, "origin", "v1.0.0"); err != nil {
+		t.Errorf("checkRemoteTag of a pushed tag: %v", err)
+	}
+
+	r.commit("Second", map[string]string{"a.txt": "b\n"})
+	r.git("tag", "-f", "-a", "-m", "v1 again", "v1.0.0")
+	if err := checkRemoteTag(r.ctx, "origin", "v1.0.0"); err == nil || !strings.Contains(err.Error(), "differs") {
+		t.Errorf("checkRemoteTag of a moved tag: error = %v, want it refused", err)
+	}
+}
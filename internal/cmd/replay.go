@@ -0,0 +1,48 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// replayKey carries the replayed replies of a run.
+type replayKey struct{}
+
+// replayProvider answers AI requests from recorded replies instead of a
+// provider, so that a run needs neither network nor API key. Replies are
+// keyed by the subject of the commit a prompt is about.
+type replayProvider struct {
+	replies map[string]string
+}
+
+// withReplay returns ctx in which AI requests are answered from replies.
+func withReplay(ctx context.Context, replies map[string]string) context.Context {
+	return context.WithValue(ctx, replayKey{}, &replayProvider{replies: replies})
+}
+
+// replayFor returns the replay provider of the run, or nil when requests go
+// to the configured provider.
+func replayFor(ctx context.Context) *replayProvider {
+	p, _ := ctx.Value(replayKey{}).(*replayProvider)
+	return p
+}
+
+// reply returns the recorded reply to a user prompt, found by the commit
+// subject on its "Message:" line.
+func (p *replayProvider) reply(user string) (string, error) {
+	for _, line := range strings.Split(user, "\n") {
+		subject, ok := strings.CutPrefix(line, "Message: ")
+		if !ok {
+			continue
+		}
+		if text, ok := p.replies[strings.TrimSpace(subject)]; ok {
+			return text, nil
+		}
+		return "", fmt.Errorf("no recorded reply for %q", subject)
+	}
+	return "", fmt.Errorf("no recorded reply for a prompt without a commit message")
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/yourorg/arc-git/internal/config"
+	"github.com/yourorg/arc-git/internal/git"
+)
+
+// ErrBudgetExceeded is returned by runPrompt when a request would push the
+// run past the configured budget.max_tokens.
+var ErrBudgetExceeded = errors.New("token budget exhausted")
+
+// repoConfigKey carries the loaded .arc-git.yaml through the command context.
+type repoConfigKey struct{}
+
+// tokenBudgetKey carries the run's token budget through the command context.
+type tokenBudgetKey struct{}
+
+// tokenBudget tracks the estimated prompt tokens spent by a run.
+type tokenBudget struct {
+	limit int64
+	used  atomic.Int64
+}
+
+// loadRepoConfig loads path, or .arc-git.yaml at the work tree root when path
+// is empty. Outside a repository an empty config is returned.
+func loadRepoConfig(ctx context.Context, path string) (*config.Config, error) {
+	if path == "" {
+		top, err := git.Run(ctx, "rev-parse", "--show-toplevel")
+		if err != nil {
+			return &config.Config{}, nil
+		}
+		path = filepath.Join(strings.TrimSpace(top), config.FileName)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository config: %w", err)
+	}
+	return cfg, nil
+}
+
+// withRepoConfig returns ctx carrying cfg and a fresh token budget for the run.
+func withRepoConfig(ctx context.Context, cfg *config.Config) context.Context {
+	ctx = context.WithValue(ctx, repoConfigKey{}, cfg)
+	return context.WithValue(ctx, tokenBudgetKey{}, &tokenBudget{limit: int64(cfg.Budget.MaxTokens)})
+}
+
+// repoConfig returns the repository config for the run; never nil.
+func repoConfig(ctx context.Context) *config.Config {
+	if cfg, ok := ctx.Value(repoConfigKey{}).(*config.Config); ok {
+		return cfg
+	}
+	return &config.Config{}
+}
+
+// spendTokens charges n estimated tokens to the run's budget, failing
+// without charging when that would exceed budget.max_tokens.
+func spendTokens(ctx context.Context, n int) error {
+	b, ok := ctx.Value(tokenBudgetKey{}).(*tokenBudget)
+	if !ok || b.limit == 0 {
+		return nil
+	}
+	for {
+		used := b.used.Load()
+		if used+int64(n) > b.limit {
+			return fmt.Errorf("%w: %d of %d estimated tokens used", ErrBudgetExceeded, used, b.limit)
+		}
+		if b.used.CompareAndSwap(used, used+int64(n)) {
+			return nil
+		}
+	}
+}
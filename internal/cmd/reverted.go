@@ -0,0 +1,382 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// maxRelandCandidates caps the later commits whose patch IDs are compared
+// against a reverted commit.
+const maxRelandCandidates = 50
+
+var (
+	// revertSubject extracts the original subject from git's default revert
+	// subject.
+	revertSubject = regexp.MustCompile(`^Revert "(.*)"$`)
+	// reapplySubject extracts the original subject from git's subject for a
+	// revert of a revert.
+	reapplySubject = regexp.MustCompile(`^Reapply "(.*)"$`)
+	// issueRefPattern finds issue references in commit messages.
+	issueRefPattern = regexp.MustCompile(`(?:#|GH-|issues/)(\d+)\b`)
+	// sinceShorthand matches compact ages such as 1y, 6m, 2w, or 30d.
+	sinceShorthand = regexp.MustCompile(`^(\d+)([ymwd])$`)
+)
+
+// newRevertedCmd creates the reverted subcommand.
+func newRevertedCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		since       string
+		limit       int
+		noNarrative bool
+		aiOpts      aiFlags
+		outputOpts  output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "reverted",
+		Short: "Find reverted work and explain what was attempted and why it was backed out",
+		Long: `Find reverted work and explain what was attempted and why it was backed out.
+
+Every revert in the window is paired with the commit it reverted, using the
+"This reverts commit" line or the "Revert \"...\"" subject. For each pair the
+command checks whether the work landed again later: as a revert of the
+revert, as a commit with the same subject, or as a commit with the same
+patch.
+
+The AI then summarizes what the original change attempted and why it was
+backed out, using the commit messages, existing annotations, and referenced
+issues. Use --no-narrative to list the pairs without calling the AI.`,
+		Example: `  # Reverted work over the last year
+  arc-git reverted --since 1y
+
+  # Only the pairs and re-landing status, as JSON
+  arc-git reverted --since 6m --no-narrative --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+
+			return runReverted(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, gitSince(since), limit, noNarrative, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "1y", "Only consider reverts more recent than this (1y, 6m, 2w, 30d, or a git date)")
+	cmd.Flags().IntVar(&limit, "limit", 20, "Summarize at most this many of the most recent reverts")
+	cmd.Flags().BoolVar(&noNarrative, "no-narrative", false, "Skip the AI summaries")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// revertedCommit is one side of a revert pair, as reported in JSON output.
+type revertedCommit struct {
+	Hash    string `json:"hash"`
+	Message string `json:"message"`
+	Date    string `json:"date"`
+}
+
+// revertPair is a reverted commit, its revert, and whether it landed again.
+type revertPair struct {
+	Original   revertedCommit  `json:"original"`
+	Revert     revertedCommit  `json:"revert"`
+	Issues     []string        `json:"issues,omitempty"`
+	Relanded   bool            `json:"relanded"`
+	RelandedAs *revertedCommit `json:"relanded_as,omitempty"`
+	RelandedBy string          `json:"relanded_by,omitempty"`
+	Attempted  string          `json:"attempted,omitempty"`
+	Reason     string          `json:"reason,omitempty"`
+
+	original git.Change
+	revert   git.Change
+}
+
+// runReverted implements the reverted workflow.
+func runReverted(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, since string, limit int, noNarrative bool, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	log.Info("Scanning history", "since", since)
+	changes, err := git.LogChanges(ctx, "--no-merges", "--reverse", "--since", since)
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+
+	pairs := pairReverts(ctx, changes)
+	log.Info("Found reverts", "count", len(pairs))
+	position := make(map[string]int, len(changes))
+	for i, c := range changes {
+		position[c.Hash] = i
+	}
+	for i := range pairs {
+		findReland(ctx, &pairs[i], changes[position[pairs[i].revert.Hash]+1:])
+	}
+
+	// Most recent first.
+	sort.SliceStable(pairs, func(i, j int) bool { return position[pairs[i].revert.Hash] > position[pairs[j].revert.Hash] })
+	if limit > 0 && len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+
+	if !noNarrative && len(pairs) > 0 {
+		service, err := newAIService(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		model := aiOpts.modelFor(ctx, prompt.RevertedModel)
+		if err := repoConfig(ctx).CheckAI("", model); err != nil {
+			return err
+		}
+		for i := range pairs {
+			p := &pairs[i]
+			log.Info("Summarizing revert", "original", p.Original.Hash, "revert", p.Revert.Hash)
+			systemPrompt, userPrompt := prompt.Reverted(describeRevertPair(ctx, p))
+			text, err := runPrompt(withPlanSubject(ctx, p.Revert.Hash), service, systemPrompt, userPrompt, model)
+			if errors.Is(err, ErrPlanned) {
+				continue
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return err
+				}
+				log.Warn("Failed to summarize revert", "revert", p.Revert.Hash, "error", err)
+				continue
+			}
+			p.Attempted, p.Reason = parseRevertSummary(text)
+		}
+		if planFor(ctx) != nil {
+			return nil
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"since": since,
+			"pairs": pairs,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		if len(pairs) == 0 {
+			fmt.Println("No reverts found.")
+			return nil
+		}
+		for _, p := range pairs {
+			fmt.Printf("%s %s (%s)\n", p.Original.Hash, p.Original.Message, p.Original.Date)
+			fmt.Printf("  Reverted by %s on %s", p.Revert.Hash, p.Revert.Date)
+			if p.RelandedAs != nil {
+				fmt.Printf("; relanded as %s (%s)", p.RelandedAs.Hash, p.RelandedBy)
+			} else {
+				fmt.Print("; never relanded")
+			}
+			fmt.Println()
+			if len(p.Issues) > 0 {
+				fmt.Printf("  Issues:    %s\n", strings.Join(p.Issues, ", "))
+			}
+			if p.Attempted != "" {
+				fmt.Printf("  Attempted: %s\n", p.Attempted)
+			}
+			if p.Reason != "" {
+				fmt.Printf("  Reason:    %s\n", p.Reason)
+			}
+			fmt.Println()
+		}
+	}
+
+	return nil
+}
+
+// isRevert reports whether c reverts another commit.
+func isRevert(c git.Change) bool {
+	return strings.HasPrefix(c.Message, "Revert ") || revertPattern.MatchString(c.Body)
+}
+
+// pairReverts matches each revert in changes (oldest first) with the commit
+// it reverted. Reverts of reverts are left to findReland.
+func pairReverts(ctx context.Context, changes []git.Change) []revertPair {
+	byHash := make(map[string]git.Change, len(changes))
+	bySubject := make(map[string]git.Change, len(changes))
+	for _, c := range changes {
+		byHash[c.Hash] = c
+		bySubject[c.Message] = c
+	}
+
+	var pairs []revertPair
+	for _, c := range changes {
+		if !isRevert(c) {
+			continue
+		}
+
+		var (
+			original git.Change
+			found    bool
+		)
+		if m := revertPattern.FindStringSubmatch(c.Body); m != nil {
+			if original, found = byHash[m[1]]; !found {
+				// Reverted commit predates the window, or the hash is abbreviated.
+				if older, err := git.LogChanges(ctx, "-n1", m[1]); err == nil && len(older) == 1 {
+					original, found = older[0], true
+				}
+			}
+		} else if m := revertSubject.FindStringSubmatch(c.Message); m != nil {
+			original, found = bySubject[m[1]]
+		}
+		if !found || isRevert(original) {
+			continue
+		}
+
+		pairs = append(pairs, revertPair{
+			Original: revertedCommit{Hash: original.Short(), Message: original.Message, Date: original.Date},
+			Revert:   revertedCommit{Hash: c.Short(), Message: c.Message, Date: c.Date},
+			Issues:   issueRefs(original.Message, original.Body, c.Message, c.Body),
+			original: original,
+			revert:   c,
+		})
+	}
+	return pairs
+}
+
+// findReland looks through the commits after a revert for the reverted work
+// landing again: as a revert of the revert, a commit with the original
+// subject, or a commit with the same patch.
+func findReland(ctx context.Context, p *revertPair, later []git.Change) {
+	mark := func(c git.Change, how string) {
+		p.Relanded = true
+		p.RelandedAs = &revertedCommit{Hash: c.Short(), Message: c.Message, Date: c.Date}
+		p.RelandedBy = how
+	}
+
+	for _, c := range later {
+		if m := revertPattern.FindStringSubmatch(c.Body); m != nil && strings.HasPrefix(p.revert.Hash, m[1]) {
+			mark(c, "revert of the revert")
+			return
+		}
+		if m := reapplySubject.FindStringSubmatch(c.Message); m != nil && m[1] == p.original.Message {
+			mark(c, "reapplied")
+			return
+		}
+		if c.Message == p.original.Message && !isRevert(c) {
+			mark(c, "same subject")
+			return
+		}
+	}
+
+	id, err := git.PatchID(ctx, p.original.Hash+"^", p.original.Hash)
+	if err != nil || id == "" {
+		return
+	}
+	touched := make(map[string]bool, len(p.original.Files))
+	for _, f := range p.original.Files {
+		touched[f] = true
+	}
+	checked := 0
+	for _, c := range later {
+		if checked >= maxRelandCandidates {
+			return
+		}
+		overlaps := false
+		for _, f := range c.Files {
+			if touched[f] {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps || isRevert(c) {
+			continue
+		}
+		checked++
+		if cid, err := git.PatchID(ctx, c.Hash+"^", c.Hash); err == nil && cid == id {
+			mark(c, "same patch")
+			return
+		}
+	}
+}
+
+// describeRevertPair renders a revert pair for the reverted prompt.
+func describeRevertPair(ctx context.Context, p *revertPair) string {
+	var b strings.Builder
+	describe := func(label string, c git.Change) {
+		fmt.Fprintf(&b, "--- %s %s by %s on %s\n", label, c.Short(), c.Author, c.Date)
+		fmt.Fprintf(&b, "Message: %s\n", c.Message)
+		if c.Body != "" {
+			fmt.Fprintf(&b, "%s\n", c.Body)
+		}
+		if len(c.Files) > 0 {
+			fmt.Fprintf(&b, "Files: %s\n", strings.Join(c.Files, ", "))
+		}
+		if note, err := git.ShowNote(ctx, c.Hash, "ai"); err == nil {
+			fmt.Fprintf(&b, "Annotation: %s\n", note)
+		}
+		b.WriteString("\n")
+	}
+
+	describe("Original", p.original)
+	describe("Revert", p.revert)
+	if len(p.Issues) > 0 {
+		fmt.Fprintf(&b, "Referenced issues: %s\n", strings.Join(p.Issues, ", "))
+	}
+	if p.RelandedAs != nil {
+		fmt.Fprintf(&b, "Relanded later as %s %q (%s)\n", p.RelandedAs.Hash, p.RelandedAs.Message, p.RelandedBy)
+	} else {
+		b.WriteString("Not relanded since.\n")
+	}
+	return b.String()
+}
+
+// parseRevertSummary splits the AI reply into its Attempted and Reason lines.
+func parseRevertSummary(text string) (attempted, reason string) {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if v, ok := strings.CutPrefix(line, "Attempted:"); ok {
+			attempted = strings.TrimSpace(v)
+		} else if v, ok := strings.CutPrefix(line, "Reason:"); ok {
+			reason = strings.TrimSpace(v)
+		}
+	}
+	if attempted == "" && reason == "" {
+		attempted = strings.TrimSpace(text)
+	}
+	return attempted, reason
+}
+
+// issueRefs returns the distinct issue references in texts, as "#N".
+func issueRefs(texts ...string) []string {
+	var refs []string
+	for _, t := range texts {
+		for _, m := range issueRefPattern.FindAllStringSubmatch(t, -1) {
+			refs = appendUnique(refs, "#"+m[1])
+		}
+	}
+	return refs
+}
+
+// gitSince expands compact ages such as 1y, 6m, 2w, or 30d into git's
+// approxidate form; anything else is passed through unchanged.
+func gitSince(s string) string {
+	m := sinceShorthand.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return s
+	}
+	n, _ := strconv.Atoi(m[1])
+	unit := map[string]string{"y": "year", "m": "month", "w": "week", "d": "day"}[m[2]]
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s ago", n, unit)
+}
@@ -0,0 +1,406 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	clierrors "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// rollupOptions holds the flags of rollup.
+type rollupOptions struct {
+	pr     int
+	branch string
+	commit string
+	into   string
+	remote string
+	detect bool
+	limit  int
+	dryRun bool
+	force  bool
+}
+
+// newRollupCmd creates the rollup subcommand.
+func newRollupCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts       rollupOptions
+		aiOpts     aiFlags
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "rollup",
+		Short: "Fold a squash-merged branch's annotations into the squash commit",
+		Long: `Fold the annotations of a squash-merged branch into one note on the squash
+commit.
+
+When a branch is squash-merged, the annotations written on its individual
+commits stay attached to commits that are no longer part of the main
+history. rollup collects those annotations and has the AI synthesize them
+into one annotation on the squash commit. The note records which commits
+it was rolled up from.
+
+The branch can be given as:
+  --pr N       the pull request head, fetched from --remote (GitHub); the
+               squash commit is the one whose subject ends in "(#N)"
+  --branch B   a local branch
+  --detect     every local branch that was squash-merged into --into
+
+Unless --commit names it, the squash commit is found by matching the
+branch's combined diff against the patch IDs of recent commits on --into.`,
+		Example: `  # Roll up the annotations of pull request 512
+  arc-git rollup --pr 512
+
+  # Roll up a local branch into an explicit squash commit
+  arc-git rollup --branch feature/cache --commit 3f2a9c1
+
+  # Find every squash-merged local branch and preview the rollups
+  arc-git rollup --detect --into main --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			sources := 0
+			for _, set := range []bool{opts.pr > 0, opts.branch != "", opts.detect} {
+				if set {
+					sources++
+				}
+			}
+			if sources != 1 {
+				return clierrors.NewCLIError("exactly one of --pr, --branch, or --detect is required").
+					WithHint("e.g. arc-git rollup --pr 512")
+			}
+			if opts.detect && opts.commit != "" {
+				return clierrors.NewCLIError("--commit cannot be combined with --detect").
+					WithHint("Use --branch with --commit to pair a branch with its squash commit")
+			}
+
+			return runRollup(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, opts, outputOpts)
+		},
+	}
+
+	cmd.Flags().IntVar(&opts.pr, "pr", 0, "Pull request whose branch was squash-merged")
+	cmd.Flags().StringVar(&opts.branch, "branch", "", "Local branch that was squash-merged")
+	cmd.Flags().StringVar(&opts.commit, "commit", "", "Squash commit (default: detected)")
+	cmd.Flags().StringVar(&opts.into, "into", "HEAD", "Branch the squash commits landed on")
+	cmd.Flags().StringVar(&opts.remote, "remote", "origin", "Remote to fetch pull request heads from")
+	cmd.Flags().BoolVar(&opts.detect, "detect", false, "Roll up every squash-merged local branch")
+	cmd.Flags().IntVar(&opts.limit, "limit", 200, "Recent commits on --into to search for squash commits")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Preview rollups without saving")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Replace an existing annotation on the squash commit")
+	aiOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// rollupPair is a branch tip and the commit that squash-merged it.
+type rollupPair struct {
+	source string
+	tip    string
+	squash string
+}
+
+// rollupResult records the outcome for one squash commit.
+type rollupResult struct {
+	Squash     string `json:"squash"`
+	Source     string `json:"source"`
+	Commits    int    `json:"commits"`
+	Annotated  int    `json:"annotated"`
+	Status     string `json:"status"`
+	Message    string `json:"message,omitempty"`
+	Annotation string `json:"annotation,omitempty"`
+	failure
+}
+
+// runRollup implements the rollup workflow.
+func runRollup(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, opts rollupOptions, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	finder := &squashFinder{into: opts.into, limit: opts.limit}
+	pairs, err := rollupPairs(ctx, finder, opts)
+	if err != nil {
+		return err
+	}
+	if len(pairs) == 0 {
+		log.Info("No squash-merged branches found", "into", opts.into)
+		return nil
+	}
+
+	service, err := newAIService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	model := aiOpts.modelFor(ctx, prompt.RollupModel)
+	if err := repoConfig(ctx).CheckAI("", model); err != nil {
+		return err
+	}
+
+	var results []rollupResult
+	written := false
+	for _, p := range pairs {
+		log.Info("Rolling up", "source", p.source, "squash", shortHash(p.squash))
+		result := rollupOne(ctx, service, model, p, opts)
+		written = written || result.Status == "success"
+		results = append(results, result)
+	}
+	if planFor(ctx) != nil {
+		return nil
+	}
+	if written {
+		mirrorAfterRun(ctx)
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"into":    opts.into,
+			"dry_run": opts.dryRun,
+			"results": results,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		for _, r := range results {
+			fmt.Printf("%s <- %s (%d commits, %d annotated): %s", r.Squash, r.Source, r.Commits, r.Annotated, r.Status)
+			if r.Message != "" {
+				fmt.Printf(" (%s)", r.Message)
+			}
+			fmt.Println()
+			if r.Status == "preview" {
+				fmt.Printf("\n%s\n\n", r.Annotation)
+			}
+		}
+		if opts.dryRun {
+			fmt.Println("\n(Dry run - no notes were added)")
+		}
+	}
+
+	return nil
+}
+
+// rollupPairs resolves the branches and squash commits to roll up.
+func rollupPairs(ctx context.Context, finder *squashFinder, opts rollupOptions) ([]rollupPair, error) {
+	switch {
+	case opts.pr > 0:
+		source := fmt.Sprintf("#%d", opts.pr)
+		if _, err := git.Run(ctx, "fetch", "--quiet", opts.remote, fmt.Sprintf("refs/pull/%d/head", opts.pr)); err != nil {
+			return nil, clierrors.NewCLIError(fmt.Sprintf("cannot fetch pull request %d: %v", opts.pr, err)).
+				WithHint("Pull request heads are fetched from GitHub's refs/pull/N/head; use --branch for other forges")
+		}
+		tip, err := revParse(ctx, "FETCH_HEAD")
+		if err != nil {
+			return nil, err
+		}
+		squash := opts.commit
+		if squash == "" {
+			squash, err = prSquashCommit(ctx, opts.into, opts.pr)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if squash == "" {
+			if squash, err = finder.find(ctx, tip); err != nil {
+				return nil, err
+			}
+		}
+		if squash == "" {
+			return nil, clierrors.NewCLIError(fmt.Sprintf("no squash commit for %s found on %s", source, opts.into)).
+				WithHint("Pass the squash commit with --commit")
+		}
+		return []rollupPair{{source: source, tip: tip, squash: squash}}, nil
+
+	case opts.branch != "":
+		tip, err := revParse(ctx, opts.branch)
+		if err != nil {
+			return nil, clierrors.NewCLIError(fmt.Sprintf("unknown branch %q", opts.branch)).
+				WithHint("List local branches with git branch")
+		}
+		squash := opts.commit
+		if squash == "" {
+			if squash, err = finder.find(ctx, tip); err != nil {
+				return nil, err
+			}
+		}
+		if squash == "" {
+			return nil, clierrors.NewCLIError(fmt.Sprintf("no squash merge of %s found on %s", opts.branch, opts.into)).
+				WithHint("Pass the squash commit with --commit, or raise --limit")
+		}
+		return []rollupPair{{source: opts.branch, tip: tip, squash: squash}}, nil
+
+	default:
+		out, err := git.Run(ctx, "for-each-ref", "--format=%(refname:short)", "refs/heads")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list branches: %w", err)
+		}
+		into, _ := git.Run(ctx, "rev-parse", "--abbrev-ref", opts.into)
+		var pairs []rollupPair
+		for _, branch := range strings.Fields(out) {
+			if branch == strings.TrimSpace(into) {
+				continue
+			}
+			tip, err := revParse(ctx, branch)
+			if err != nil {
+				continue
+			}
+			squash, err := finder.find(ctx, tip)
+			if err != nil {
+				return nil, err
+			}
+			if squash != "" {
+				pairs = append(pairs, rollupPair{source: branch, tip: tip, squash: squash})
+			}
+		}
+		return pairs, nil
+	}
+}
+
+// rollupOne synthesizes and, unless opts.dryRun, stores the rollup note for
+// one squash commit.
+func rollupOne(ctx context.Context, service *ai.Service, model string, p rollupPair, opts rollupOptions) rollupResult {
+	log := logging.L()
+	result := rollupResult{Squash: shortHash(p.squash), Source: p.source}
+
+	commits, err := git.Log(ctx, "--no-merges", "--reverse", p.tip, "--not", p.squash+"^")
+	if err != nil {
+		result.Status, result.Message = "failed", fmt.Sprintf("failed to list branch commits: %v", err)
+		result.failure = classifyFailure(err)
+		return result
+	}
+	result.Commits = len(commits)
+
+	var (
+		list   strings.Builder
+		hashes []string
+	)
+	for _, c := range commits {
+		fmt.Fprintf(&list, "- %s %s\n", c.Short(), c.Message)
+		if note, err := git.ShowNote(ctx, c.Hash, "ai"); err == nil {
+			fmt.Fprintf(&list, "  Annotation: %s\n", note)
+			result.Annotated++
+		}
+		hashes = append(hashes, c.Short())
+	}
+	if result.Annotated == 0 {
+		result.Status, result.Message = "skipped", "no annotations on the branch"
+		return result
+	}
+	if !opts.force && git.HasNote(ctx, p.squash, "ai") {
+		result.Status, result.Message = "skipped", "already annotated (use --force to replace)"
+		return result
+	}
+
+	squash, err := git.Log(ctx, "-n1", p.squash)
+	if err == nil && len(squash) == 0 {
+		err = fmt.Errorf("no commit %s", p.squash)
+	}
+	if err != nil {
+		result.Status, result.Message = "failed", fmt.Sprintf("failed to read squash commit: %v", err)
+		result.failure = classifyFailure(err)
+		return result
+	}
+
+	systemPrompt, userPrompt := prompt.Rollup(squash[0].Short(), squash[0].Message, list.String())
+	annotation, err := runPrompt(withPlanSubject(ctx, result.Squash), service, systemPrompt, userPrompt, model)
+	if errors.Is(err, ErrPlanned) {
+		result.Status = "planned"
+		return result
+	}
+	if err != nil {
+		log.Warn("Failed to generate rollup", "squash", result.Squash, "error", err)
+		result.Status, result.Message = "failed", fmt.Sprintf("failed to generate rollup: %v", err)
+		result.failure = classifyFailure(err)
+		return result
+	}
+	annotation += fmt.Sprintf("\n\nRolled up from %s: %s", p.source, strings.Join(hashes, ", "))
+	result.Annotation = annotation
+
+	if opts.dryRun {
+		result.Status = "preview"
+		return result
+	}
+	if err := storeAnnotation(context.WithoutCancel(ctx), p.squash, annotation); err != nil {
+		result.Status, result.Message, result.Annotation = "failed", fmt.Sprintf("failed to add note: %v", err), ""
+		result.failure = classifyFailure(err)
+		return result
+	}
+	result.Status = "success"
+	return result
+}
+
+// prSquashCommit returns the first-parent commit on into whose subject ends
+// with GitHub's "(#N)" squash suffix, or "".
+func prSquashCommit(ctx context.Context, into string, pr int) (string, error) {
+	commits, err := git.Log(ctx, "--first-parent", "--fixed-strings", "--grep", fmt.Sprintf("(#%d)", pr), into)
+	if err != nil {
+		return "", fmt.Errorf("failed to search %s: %w", into, err)
+	}
+	suffix := fmt.Sprintf("(#%d)", pr)
+	for _, c := range commits {
+		if strings.HasSuffix(c.Message, suffix) {
+			return c.Hash, nil
+		}
+	}
+	return "", nil
+}
+
+// squashFinder matches branch tips to the commits on into that squash-merged
+// them, by patch ID. Patch IDs of the candidate commits are computed once.
+type squashFinder struct {
+	into  string
+	limit int
+	ids   map[string]string
+}
+
+// find returns the commit on into that introduced the same change as the
+// branch ending at tip, or "" if there is none.
+func (f *squashFinder) find(ctx context.Context, tip string) (string, error) {
+	base, err := git.MergeBase(ctx, f.into, tip)
+	if err != nil || base == tip {
+		// Unrelated, or already merged without squashing.
+		return "", nil
+	}
+	id, err := git.PatchID(ctx, base, tip)
+	if err != nil || id == "" {
+		return "", err
+	}
+
+	if f.ids == nil {
+		candidates, err := git.RevList(ctx, "--first-parent", "--no-merges", fmt.Sprintf("-n%d", f.limit), f.into)
+		if err != nil {
+			return "", fmt.Errorf("failed to list commits on %s: %w", f.into, err)
+		}
+		f.ids = make(map[string]string, len(candidates))
+		for _, c := range candidates {
+			cid, err := git.PatchID(ctx, c+"^", c)
+			if err != nil || cid == "" {
+				continue
+			}
+			if _, seen := f.ids[cid]; !seen {
+				f.ids[cid] = c
+			}
+		}
+	}
+	return f.ids[id], nil
+}
+
+// revParse resolves rev to a full commit hash.
+func revParse(ctx context.Context, rev string) (string, error) {
+	out, err := git.Run(ctx, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
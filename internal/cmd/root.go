@@ -4,12 +4,25 @@
 package cmd
 
 import (
+	"context"
+	"time"
+
 	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/logging"
 	"github.com/yourorg/arc-sdk/ai"
 )
 
 // NewRootCmd creates the root command for arc-git.
 func NewRootCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		logOpts         logging.Options
+		configPath      string
+		requestTimeout  time.Duration
+		runDeadline     time.Duration
+		plan            bool
+		allowThirdParty bool
+	)
+
 	root := &cobra.Command{
 		Use:   "arc-git",
 		Short: "Git integration with AI",
@@ -28,12 +41,90 @@ automated documentation of code changes.`,
   git log --show-notes=ai
 
   # Search only the AI-generated notes for key terms
-  git log --grep "refactor" --notes=ai`,
+  git log --grep "refactor" --notes=ai
+
+  # Summarize how an issue was resolved
+  arc-git close-summary --issue 842
+
+  # Trace git commands and AI request timings
+  arc-git annotate --since 5 --verbose
+
+  # Bound unattended CI runs
+  arc-git annotate --since 200 --request-timeout 90s --run-deadline 30m
+
+  # What would backfilling 500 commits send, and roughly what would it cost?
+  arc-git annotate --since 500 --plan`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := logging.Configure(logOpts); err != nil {
+				return err
+			}
+
+			repoCfg, err := loadRepoConfig(cmd.Context(), configPath)
+			if err != nil {
+				return err
+			}
+
+			ctx := withRepoConfig(cmd.Context(), repoCfg)
+			ctx = withRequestTimeout(ctx, requestTimeout)
+			ctx = withRunningCommand(ctx, cmd)
+			ctx = withThirdPartyAllowed(ctx, allowThirdParty)
+			if runDeadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, runDeadline)
+				runStateFor(ctx).setDeadline(ctx, cancel)
+			}
+			if plan {
+				ctx = withPlan(ctx, &runPlan{})
+			}
+			cmd.SetContext(ctx)
+			return nil
+		},
 	}
 
+	root.PersistentFlags().BoolVar(&logOpts.Verbose, "verbose", false, "Log git commands and request timings")
+	root.PersistentFlags().BoolVar(&logOpts.Debug, "debug", false, "Log everything, including request sizes and cache lookups")
+	root.PersistentFlags().StringVar(&logOpts.Format, "log-format", "text", "Log format (text, json)")
+	root.PersistentFlags().StringVar(&configPath, "config", "", "Repository config file (default: .arc-git.yaml at the work tree root)")
+	root.PersistentFlags().DurationVar(&requestTimeout, "request-timeout", 0, "Abort a single AI request after this long (e.g. 90s; 0 disables)")
+	root.PersistentFlags().DurationVar(&runDeadline, "run-deadline", 0, "Stop the whole run after this long, keeping completed work (e.g. 30m; 0 disables)")
+	root.PersistentFlags().BoolVar(&plan, "plan", false, "Print the AI requests, estimated tokens, and cost a command would need, without sending them or writing anything")
+	root.PersistentFlags().BoolVar(&allowThirdParty, "allow-third-party", false, "Send vendored and third-party code to the AI when the repository's third_party policy is opt-in")
+
 	root.AddCommand(
+		newAnomaliesCmd(aiCfg),
 		newAnnotateCmd(aiCfg),
+		newAPIDiffCmd(aiCfg),
+		newChangelogCmd(aiCfg),
+		newCheckMessagesCmd(aiCfg),
+		newCloseSummaryCmd(aiCfg),
+		newCoverLetterCmd(aiCfg),
+		newDescribeRepoCmd(aiCfg),
+		newDemoCmd(),
+		newDevtoolsCmd(),
+		newDoctorCmd(aiCfg),
+		newExportContextCmd(),
+		newFlagsCmd(aiCfg),
+		newHandoverCmd(aiCfg),
+		newHooksCmd(),
+		newHoverDataCmd(),
+		newIdentitiesCmd(),
+		newInitCmd(aiCfg),
+		newInstabilityCmd(aiCfg),
+		newLogCmd(),
+		newNotesCmd(aiCfg),
+		newOrgReportCmd(aiCfg),
+		newProvidersCmd(aiCfg),
+		newRangeDiffExplainCmd(aiCfg),
+		newReleaseCmd(aiCfg),
+		newReleaseCheckCmd(aiCfg),
+		newRevertedCmd(aiCfg),
+		newRollupCmd(aiCfg),
+		newStatusCmd(),
+		newSymbolsCmd(),
+		newTestsForCmd(aiCfg),
+		newWhyCmd(),
 	)
+	planRuns(root)
 
 	return root
 }
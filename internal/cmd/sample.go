@@ -0,0 +1,187 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+)
+
+// Sampling strategies for covering long histories within a budget.
+const (
+	sampleStratifiedByMonth = "stratified-by-month"
+	sampleTopChurn          = "top-churn"
+	sampleTaggedReleases    = "tagged-releases"
+)
+
+// sampleStrategies lists the valid --sample values.
+var sampleStrategies = []string{sampleStratifiedByMonth, sampleTopChurn, sampleTaggedReleases}
+
+// defaultSampleSize is used when neither --sample-size nor budget.max_commits
+// is set.
+const defaultSampleSize = 100
+
+// commitSample selects a representative subset of history instead of the
+// most recent commits.
+type commitSample struct {
+	strategy string
+	size     int
+}
+
+// sampleCandidate is a non-merge commit eligible for sampling.
+type sampleCandidate struct {
+	hash  string
+	time  time.Time
+	lines int
+	// tagged is set on commits a tag points at.
+	tagged bool
+	// closes is set on the last candidate of a release interval: a tagged
+	// commit, or the one before a tagged merge or excluded commit.
+	closes bool
+}
+
+// sampleCommits picks up to s.size commits from the history selected by
+// rangeArg, leaving out those in exclude, and returns them newest first.
+//
+//   - stratified-by-month takes the largest commits of every month in turn,
+//     so that each period of history is represented.
+//   - top-churn takes the commits with the most changed lines.
+//   - tagged-releases takes the tagged commits first, then the largest
+//     commits of every release interval in turn.
+func sampleCommits(ctx context.Context, s commitSample, rangeArg string, exclude map[string]bool) ([]git.Commit, error) {
+	candidates, err := sampleCandidates(ctx, rangeArg, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	// Buckets are sampled round-robin, largest commits first.
+	var buckets [][]sampleCandidate
+	switch s.strategy {
+	case sampleTopChurn:
+		buckets = [][]sampleCandidate{candidates}
+	case sampleStratifiedByMonth:
+		index := make(map[string]int)
+		for _, c := range candidates {
+			month := c.time.UTC().Format("2006-01")
+			i, ok := index[month]
+			if !ok {
+				i = len(buckets)
+				index[month] = i
+				buckets = append(buckets, nil)
+			}
+			buckets[i] = append(buckets[i], c)
+		}
+	case sampleTaggedReleases:
+		var current []sampleCandidate
+		for _, c := range candidates {
+			current = append(current, c)
+			if c.closes {
+				buckets = append(buckets, current)
+				current = nil
+			}
+		}
+		if len(current) > 0 {
+			buckets = append(buckets, current)
+		}
+	default:
+		return nil, fmt.Errorf("unknown sampling strategy %q", s.strategy)
+	}
+
+	for _, b := range buckets {
+		sort.SliceStable(b, func(i, j int) bool {
+			if b[i].tagged != b[j].tagged {
+				return b[i].tagged
+			}
+			return b[i].lines > b[j].lines
+		})
+	}
+
+	var picked []string
+	for round := 0; len(picked) < s.size; round++ {
+		progressed := false
+		for _, b := range buckets {
+			if round < len(b) && len(picked) < s.size {
+				picked = append(picked, b[round].hash)
+				progressed = true
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+	logging.L().Info("Sampled history", "strategy", s.strategy, "candidates", len(candidates), "buckets", len(buckets), "picked", len(picked))
+	if len(picked) == 0 {
+		return nil, nil
+	}
+
+	// --no-walk orders the picked commits by date, newest first.
+	return git.Log(ctx, append([]string{"--no-walk"}, picked...)...)
+}
+
+// sampleCandidates returns the non-merge commits of rangeArg, oldest first,
+// with their changed line counts and whether they are tagged.
+func sampleCandidates(ctx context.Context, rangeArg string, exclude map[string]bool) ([]sampleCandidate, error) {
+	// Merges and excluded commits are listed too, since a tag may point at
+	// one; they end a release interval but are never sampled themselves.
+	out, err := git.Run(ctx, "log", "--reverse", "--format=%x1e%H%x1f%at%x1f%P%x1f%D", rangeArg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	lines, err := git.ChangedLines(ctx, "--no-merges", rangeArg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure churn: %w", err)
+	}
+
+	var candidates []sampleCandidate
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.Split(strings.TrimSpace(record), "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		tagged := strings.Contains(fields[3], "tag: ")
+		if len(strings.Fields(fields[2])) > 1 || exclude[fields[0]] {
+			if tagged && len(candidates) > 0 {
+				candidates[len(candidates)-1].closes = true
+			}
+			continue
+		}
+		ts, _ := strconv.ParseInt(fields[1], 10, 64)
+		candidates = append(candidates, sampleCandidate{
+			hash:   fields[0],
+			time:   time.Unix(ts, 0),
+			lines:  lines[fields[0]],
+			tagged: tagged,
+			closes: tagged,
+		})
+	}
+	return candidates, nil
+}
+
+// quickNotedCommits returns the commits whose "ai" note is a quick-tier gist,
+// found by searching the notes tree for quickTrailer.
+func quickNotedCommits(ctx context.Context) (map[string]bool, error) {
+	quick := make(map[string]bool)
+	if _, err := git.Run(ctx, "rev-parse", "--verify", "--quiet", "refs/notes/ai"); err != nil {
+		return quick, nil
+	}
+	out, err := git.Grep(ctx, "^"+quickTrailer+"$", "refs/notes/ai")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		parts := strings.SplitN(strings.TrimPrefix(line, "refs/notes/ai:"), ":", 2)
+		if len(parts) == 2 {
+			// Note paths may be fanned out as ab/cdef...
+			quick[strings.ReplaceAll(parts[0], "/", "")] = true
+		}
+	}
+	return quick, nil
+}
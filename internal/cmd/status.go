@@ -0,0 +1,245 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/checkpoint"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// newStatusCmd creates the status subcommand.
+func newStatusCmd() *cobra.Command {
+	var (
+		branch     string
+		badge      string
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show annotation coverage for the current branch",
+		Long: `Show how much of a branch's history carries AI annotations.
+
+The report includes:
+- Coverage: annotated commits out of all non-merge commits on the branch
+- Oldest gap: the oldest commit that has no annotation yet
+- Last run: when arc-git annotate last finished, and whether it was cut short
+- Pending budget: commits still to annotate and their estimated prompt tokens
+
+With --badge shields-json, a shields.io endpoint badge is printed instead, so
+repositories can publish their annotation coverage:
+  https://img.shields.io/endpoint?url=<hosted badge json>`,
+		Example: `  # Coverage for the current branch
+  arc-git status
+
+  # Coverage for main
+  arc-git status --branch main
+
+  # Publish a coverage badge from CI
+  arc-git status --badge shields-json > coverage-badge.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			if badge != "" && badge != "shields-json" {
+				return errors.NewCLIError(fmt.Sprintf("unknown badge format %q", badge)).
+					WithHint("Supported formats: shields-json")
+			}
+
+			return runStatus(cmd.Context(), branch, badge, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&branch, "branch", "HEAD", "Branch or revision to report on")
+	cmd.Flags().StringVar(&badge, "badge", "", "Print a coverage badge instead (shields-json)")
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// coverageGap describes the oldest unannotated commit.
+type coverageGap struct {
+	Hash    string `json:"hash"`
+	Date    string `json:"date"`
+	Message string `json:"message"`
+}
+
+// lastRun summarizes the most recent annotate checkpoint.
+type lastRun struct {
+	FinishedAt  time.Time `json:"finished_at"`
+	Range       string    `json:"range"`
+	Annotated   int       `json:"annotated"`
+	Failed      int       `json:"failed"`
+	Interrupted bool      `json:"interrupted"`
+	Reason      string    `json:"reason,omitempty"`
+}
+
+// coverageStatus is the full status report.
+type coverageStatus struct {
+	Branch         string       `json:"branch"`
+	Total          int          `json:"total"`
+	Annotated      int          `json:"annotated"`
+	Coverage       float64      `json:"coverage"`
+	OldestGap      *coverageGap `json:"oldest_gap,omitempty"`
+	LastRun        *lastRun     `json:"last_run,omitempty"`
+	PendingCommits int          `json:"pending_commits"`
+	PendingTokens  int          `json:"pending_tokens_estimated"`
+	CarriedOver    int          `json:"carried_over,omitempty"`
+}
+
+// runStatus implements the status workflow.
+func runStatus(ctx context.Context, branch, badge string, out output.OutputOptions) error {
+	status, err := collectStatus(ctx, branch)
+	if err != nil {
+		return err
+	}
+
+	if badge == "shields-json" {
+		return writeJSON(shieldsBadge(status.Coverage))
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(status)
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		fmt.Printf("Branch:     %s\n", status.Branch)
+		fmt.Printf("Coverage:   %d of %d commits annotated (%.1f%%)\n", status.Annotated, status.Total, status.Coverage)
+		if status.OldestGap != nil {
+			fmt.Printf("Oldest gap: %s %s (%s)\n", status.OldestGap.Hash, status.OldestGap.Message, status.OldestGap.Date)
+		} else {
+			fmt.Println("Oldest gap: none")
+		}
+		if status.LastRun != nil {
+			state := "completed"
+			if status.LastRun.Interrupted {
+				state = "stopped early: " + status.LastRun.Reason
+			}
+			fmt.Printf("Last run:   %s (%s, %d annotated, %d failed, %s)\n",
+				status.LastRun.FinishedAt.Local().Format(time.RFC1123), status.LastRun.Range,
+				status.LastRun.Annotated, status.LastRun.Failed, state)
+		} else {
+			fmt.Println("Last run:   never")
+		}
+		fmt.Printf("Pending:    %d commits, ~%d prompt tokens", status.PendingCommits, status.PendingTokens)
+		if status.CarriedOver > 0 {
+			fmt.Printf(" (%d left over from the last run)", status.CarriedOver)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// branchName returns the branch rev names, resolving HEAD to the checked-out
+// branch. A detached HEAD is labeled with its commit, and other revisions
+// that name no branch, such as tags and hashes, are returned as given.
+func branchName(ctx context.Context, rev string) string {
+	if name, err := git.Run(ctx, "rev-parse", "--abbrev-ref", rev); err == nil {
+		if name = strings.TrimSpace(name); name != "" && name != "HEAD" {
+			return name
+		}
+	}
+	if rev != "HEAD" {
+		return rev
+	}
+	if hash, err := git.Run(ctx, "rev-parse", "--short", "HEAD"); err == nil {
+		return "detached HEAD at " + strings.TrimSpace(hash)
+	}
+	return "detached HEAD"
+}
+
+// collectStatus computes coverage for branch.
+func collectStatus(ctx context.Context, branch string) (*coverageStatus, error) {
+	commits, err := git.RevList(ctx, "--no-merges", branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits on %s: %w", branch, err)
+	}
+	noted, err := git.NotedCommits(ctx, "ai")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notes: %w", err)
+	}
+
+	status := &coverageStatus{Branch: branchName(ctx, branch), Total: len(commits)}
+	var gaps []string
+	for _, hash := range commits {
+		if noted[hash] {
+			status.Annotated++
+		} else {
+			gaps = append(gaps, hash)
+		}
+	}
+	if status.Total > 0 {
+		status.Coverage = math.Round(float64(status.Annotated)/float64(status.Total)*1000) / 10
+	}
+
+	if len(gaps) > 0 {
+		// rev-list is newest first, so the last gap is the oldest.
+		oldest := gaps[len(gaps)-1]
+		if log, err := git.Log(ctx, "-n1", oldest); err == nil && len(log) == 1 {
+			status.OldestGap = &coverageGap{Hash: log[0].Short(), Date: log[0].Date, Message: log[0].Message}
+		}
+
+		lines, err := git.ChangedLines(ctx, "--no-merges", branch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to size pending commits: %w", err)
+		}
+		for _, hash := range gaps {
+			status.PendingTokens += prompt.EstimateDiffTokens(lines[hash])
+		}
+	}
+	status.PendingCommits = len(gaps)
+
+	if gitDir, err := git.Dir(ctx); err == nil {
+		cp, err := checkpoint.Load(checkpoint.Path(gitDir, "annotate"))
+		if err != nil {
+			return nil, err
+		}
+		if cp != nil {
+			status.LastRun = &lastRun{
+				FinishedAt:  cp.FinishedAt,
+				Range:       cp.Range,
+				Annotated:   cp.Annotated,
+				Failed:      cp.Failed,
+				Interrupted: cp.Interrupted,
+				Reason:      cp.Reason,
+			}
+			status.CarriedOver = len(cp.Pending)
+		}
+	}
+
+	return status, nil
+}
+
+// shieldsBadge renders coverage as a shields.io endpoint badge.
+func shieldsBadge(coverage float64) map[string]interface{} {
+	color := "red"
+	switch {
+	case coverage >= 90:
+		color = "brightgreen"
+	case coverage >= 75:
+		color = "green"
+	case coverage >= 50:
+		color = "yellow"
+	case coverage >= 25:
+		color = "orange"
+	}
+	return map[string]interface{}{
+		"schemaVersion": 1,
+		"label":         "AI annotations",
+		"message":       fmt.Sprintf("%.0f%%", coverage),
+		"color":         color,
+	}
+}
@@ -0,0 +1,359 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/symbols"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// symbolsRef is the notes ref holding the symbols each commit touched, next
+// to the "ai" annotations.
+const symbolsRef = "ai-symbols"
+
+// symbolsHeader is the first line of every symbols note; a note with only
+// the header records that the commit touched no known symbols.
+const symbolsHeader = "symbols v1"
+
+// maxSymbolFileSize skips parsing files larger than this, which are usually
+// generated.
+const maxSymbolFileSize = 1 << 20
+
+// newSymbolsCmd creates the symbols command group.
+func newSymbolsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "symbols",
+		Short: "Index and query the functions and types each commit touched",
+		Long: `Record which functions, methods, and types each commit changed, and query
+their history.
+
+Changed lines are mapped to their enclosing definitions by parsing the files
+with tree-sitter (Go, Python, JavaScript, TypeScript, Rust, and Java). The
+result is stored in the "` + symbolsRef + `" notes ref, next to the AI annotations,
+so it can be pushed and fetched the same way.`,
+	}
+
+	cmd.AddCommand(newSymbolsIndexCmd(), newSymbolsHistoryCmd())
+
+	return cmd
+}
+
+// newSymbolsIndexCmd creates the symbols index subcommand.
+func newSymbolsIndexCmd() *cobra.Command {
+	var (
+		since      int
+		from       string
+		to         string
+		force      bool
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "index",
+		Short: "Record the symbols touched by commits",
+		Long: `Record the functions, methods, and types touched by commits.
+
+Added lines are looked up in the file after the commit, and removed lines in
+the file before it, so deleted functions are recorded too. Names are
+qualified by their class, impl, or receiver type (e.g. "Session.Refresh").
+Commits that are already indexed are skipped unless --force is given.`,
+		Example: `  # Index the last 100 commits
+  arc-git symbols index --since 100
+
+  # Index a release range
+  arc-git symbols index --from v2.3.0 --to v2.4.0`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+
+			return runSymbolsIndex(cmd.Context(), since, from, to, force, outputOpts)
+		},
+	}
+
+	cmd.Flags().IntVar(&since, "since", 10, "Index last N commits")
+	cmd.Flags().StringVar(&from, "from", "", "Start commit (e.g., HEAD~20)")
+	cmd.Flags().StringVar(&to, "to", "HEAD", "End commit (default: HEAD)")
+	cmd.Flags().BoolVar(&force, "force", false, "Re-index commits that are already indexed")
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// symbolsResult records the outcome of indexing one commit.
+type symbolsResult struct {
+	Hash    string           `json:"hash"`
+	Status  string           `json:"status"`
+	Message string           `json:"message,omitempty"`
+	Symbols []symbols.Symbol `json:"symbols,omitempty"`
+	failure
+}
+
+// runSymbolsIndex implements the symbols index workflow.
+func runSymbolsIndex(ctx context.Context, since int, from, to string, force bool, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	commits, err := getCommits(ctx, since, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to get commits: %w", err)
+	}
+	indexed, err := git.NotedCommits(ctx, symbolsRef)
+	if err != nil {
+		return err
+	}
+
+	var results []symbolsResult
+	counts := make(map[string]int)
+	for i, c := range commits {
+		if ctx.Err() != nil {
+			break
+		}
+		result := symbolsResult{Hash: c.Short()}
+		if indexed[c.Hash] && !force {
+			result.Status = "skipped"
+		} else {
+			result = indexCommitSymbols(ctx, c)
+			log.Info("Indexed commit", "commit", c.Short(), "status", result.Status, "symbols", len(result.Symbols), "progress", fmt.Sprintf("%d/%d", i+1, len(commits)))
+		}
+		counts[result.Status]++
+		results = append(results, result)
+	}
+	reason := stopReason(ctx)
+	if counts["indexed"] > 0 {
+		mirrorAfterRun(ctx)
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		if err := writeJSON(map[string]interface{}{
+			"total":       len(commits),
+			"indexed":     counts["indexed"],
+			"skipped":     counts["skipped"],
+			"failed":      counts["failed"],
+			"stop_reason": errString(reason),
+			"results":     results,
+		}); err != nil {
+			return err
+		}
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress summary
+	default:
+		fmt.Printf("Indexed: %d\nSkipped: %d\nFailed: %d\n", counts["indexed"], counts["skipped"], counts["failed"])
+		if counts["indexed"] > 0 {
+			fmt.Println("\nQuery with: arc-git symbols history <name>")
+		}
+	}
+
+	if reason != nil {
+		return fmt.Errorf("%w: %d of %d commits processed", reason, len(results), len(commits))
+	}
+	return nil
+}
+
+// indexCommitSymbols computes and stores the symbols note of a commit.
+func indexCommitSymbols(ctx context.Context, c git.Commit) symbolsResult {
+	result := symbolsResult{Hash: c.Short()}
+	syms, err := commitSymbols(ctx, c.Hash)
+	if err != nil {
+		result.Status, result.Message = "failed", err.Error()
+		result.failure = classifyFailure(err)
+		return result
+	}
+
+	lines := []string{symbolsHeader}
+	for _, s := range syms {
+		lines = append(lines, s.String())
+	}
+	// The run context may be cancelled; notes are written without it so that
+	// a finished commit is still recorded.
+	if err := git.AddNote(context.WithoutCancel(ctx), c.Hash, symbolsRef, strings.Join(lines, "\n")+"\n"); err != nil {
+		result.Status, result.Message = "failed", err.Error()
+		result.failure = classifyFailure(err)
+		return result
+	}
+	result.Status, result.Symbols = "indexed", syms
+	return result
+}
+
+// commitSymbols returns the symbols a non-merge commit touched.
+func commitSymbols(ctx context.Context, hash string) ([]symbols.Symbol, error) {
+	files, err := git.ChangedRanges(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var found []symbols.Symbol
+	add := func(rev, path string, ranges []git.LineRange) error {
+		if path == "" || len(ranges) == 0 || !symbols.Supported(path) {
+			return nil
+		}
+		src, err := git.FileAt(ctx, rev, path)
+		if err != nil {
+			return err
+		}
+		if len(src) > maxSymbolFileSize {
+			return nil
+		}
+		syms, err := symbols.Enclosing(ctx, path, src, ranges)
+		if err != nil {
+			return err
+		}
+		for _, s := range syms {
+			if !seen[s.String()] {
+				seen[s.String()] = true
+				found = append(found, s)
+			}
+		}
+		return nil
+	}
+
+	for _, f := range files {
+		if err := add(hash, f.Path, f.New); err != nil {
+			return nil, err
+		}
+		if err := add(hash+"^", f.OldPath, f.Old); err != nil {
+			return nil, err
+		}
+	}
+	return found, nil
+}
+
+// newSymbolsHistoryCmd creates the symbols history subcommand.
+func newSymbolsHistoryCmd() *cobra.Command {
+	var (
+		limit      int
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history <name>",
+		Short: "List the indexed commits that touched a symbol",
+		Long: `List the indexed commits that touched a function, method, or type, newest
+first, with the first line of each commit's AI annotation.
+
+name matches the last part of qualified names, so "Refresh" finds
+"Session.Refresh" and "Token.Refresh"; pass "Session.Refresh" to narrow it
+down. Only commits indexed with arc-git symbols index are searched.`,
+		Example: `  # Every change to RefreshToken
+  arc-git symbols history RefreshToken
+
+  # One method, as JSON
+  arc-git symbols history Session.Refresh --output json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+
+			return runSymbolsHistory(cmd.Context(), args[0], limit, outputOpts)
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 50, "Maximum commits to list")
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// symbolChange is a commit that touched a queried symbol.
+type symbolChange struct {
+	Hash       string           `json:"hash"`
+	Date       string           `json:"date"`
+	Author     string           `json:"author"`
+	Message    string           `json:"message"`
+	Symbols    []symbols.Symbol `json:"symbols"`
+	Annotation string           `json:"annotation,omitempty"`
+}
+
+// runSymbolsHistory implements the symbols history workflow.
+func runSymbolsHistory(ctx context.Context, name string, limit int, out output.OutputOptions) error {
+	if _, err := git.Run(ctx, "rev-parse", "--verify", "--quiet", "refs/notes/"+symbolsRef); err != nil {
+		return errors.NewCLIError("no commits have been indexed").
+			WithHint("Run arc-git symbols index first, e.g. arc-git symbols index --since 500")
+	}
+
+	pattern := `^[a-z]+ ([^ ]*\.)?` + regexp.QuoteMeta(name) + ` `
+	matches, err := git.Grep(ctx, pattern, "refs/notes/"+symbolsRef)
+	if err != nil {
+		return fmt.Errorf("failed to search the symbols index: %w", err)
+	}
+
+	touched := make(map[string][]symbols.Symbol)
+	var hashes []string
+	for _, line := range strings.Split(strings.TrimSpace(matches), "\n") {
+		parts := strings.SplitN(strings.TrimPrefix(line, "refs/notes/"+symbolsRef+":"), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		fields := strings.SplitN(parts[2], " ", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		// Note paths may be fanned out as ab/cdef...
+		hash := strings.ReplaceAll(parts[0], "/", "")
+		if _, ok := touched[hash]; !ok {
+			hashes = append(hashes, hash)
+		}
+		touched[hash] = append(touched[hash], symbols.Symbol{Kind: fields[0], Name: fields[1], Path: fields[2]})
+	}
+
+	var changes []symbolChange
+	if len(hashes) > 0 {
+		commits, err := git.Log(ctx, append([]string{"--no-walk"}, hashes...)...)
+		if err != nil {
+			return fmt.Errorf("failed to read commits: %w", err)
+		}
+		for _, c := range commits {
+			if len(changes) == limit {
+				break
+			}
+			change := symbolChange{Hash: c.Short(), Date: c.Date, Author: c.Author, Message: c.Message, Symbols: touched[c.Hash]}
+			if note, err := git.ShowNote(ctx, c.Hash, "ai"); err == nil {
+				change.Annotation, _, _ = strings.Cut(note, "\n")
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"name":    name,
+			"commits": len(hashes),
+			"changes": changes,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		if len(changes) == 0 {
+			fmt.Printf("No indexed commits touch %s.\n", name)
+			return nil
+		}
+		for _, c := range changes {
+			fmt.Printf("%s %s %s\n", c.Hash, c.Date, c.Message)
+			for _, s := range c.Symbols {
+				fmt.Printf("    %s %s (%s)\n", s.Kind, s.Name, s.Path)
+			}
+			if c.Annotation != "" {
+				fmt.Printf("    %s\n", cutText(strings.Join(strings.Fields(c.Annotation), " "), 120))
+			}
+		}
+		if len(hashes) > len(changes) {
+			fmt.Printf("\n(%d more; raise --limit to see them)\n", len(hashes)-len(changes))
+		}
+	}
+
+	return nil
+}
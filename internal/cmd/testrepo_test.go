@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-git/internal/git"
+)
+
+// testRepo is a scratch git repository for tests.
+type testRepo struct {
+	t   *testing.T
+	dir string
+	ctx context.Context
+}
+
+// newTestRepo creates an empty repository in a temporary directory, with a
+// context that runs git in it.
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "Test")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "test@example.com")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	dir := t.TempDir()
+	r := &testRepo{t: t, dir: dir, ctx: git.WithDir(context.Background(), dir)}
+	r.git("init", "-q")
+	return r
+}
+
+// git runs git in the repository and returns its trimmed output.
+func (r *testRepo) git(args ...string) string {
+	r.t.Helper()
+	out, err := git.Run(r.ctx, args...)
+	if err != nil {
+		r.t.Fatalf("git %s: %v", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(out)
+}
+
+// commit writes files, keyed by path, removes those given as "", and
+// commits everything; it returns the new commit's hash.
+func (r *testRepo) commit(message string, files map[string]string) string {
+	r.t.Helper()
+	for name, data := range files {
+		path := filepath.Join(r.dir, name)
+		if data == "" {
+			if err := os.Remove(path); err != nil {
+				r.t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			r.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			r.t.Fatal(err)
+		}
+	}
+	r.git("add", "-A")
+	r.git("commit", "-q", "--allow-empty", "-m", message)
+	return r.git("rev-parse", "HEAD")
+}
+
+// lines returns the numbered lines "line 1" to "line n", one per line.
+func lines(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	return b.String()
+}
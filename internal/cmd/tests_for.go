@@ -0,0 +1,523 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/diffview"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	clierrors "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// Where a test selected by tests-for came from.
+const (
+	testSourceChanged    = "changed"
+	testSourceCoverage   = "coverage"
+	testSourceConvention = "convention"
+	testSourcePackage    = "package"
+	testSourceReference  = "reference"
+	testSourceAI         = "ai"
+)
+
+// How sure tests-for is that a test exercises the change, most sure first.
+var testConfidences = []string{"high", "medium", "low"}
+
+const (
+	// maxReferencedSymbols caps the changed symbols looked up in test files.
+	maxReferencedSymbols = 20
+	// maxReferencingTests is the number of test files naming a symbol above
+	// which each of them is only a weak candidate.
+	maxReferencingTests = 5
+	// minReferencedName is the shortest symbol name looked up in test files;
+	// shorter names match too much.
+	minReferencedName = 4
+	// maxTestsForDiff caps the diff shown to the AI, in bytes.
+	maxTestsForDiff = 60000
+)
+
+var (
+	// testDirSegments are directory names that hold tests or sources without
+	// being part of what they test, so that src/main/java/x and
+	// src/test/java/x mirror each other.
+	testDirSegments = map[string]bool{"test": true, "tests": true, "spec": true, "specs": true, "__tests__": true, "src": true, "lib": true, "main": true}
+	// commonSymbols are names too generic to find the tests of a change by.
+	commonSymbols = map[string]bool{"init": true, "main": true, "String": true, "Error": true, "New": true, "Close": true, "Run": true, "Get": true, "Set": true}
+	// testDecisionPattern parses a reply line of the test selection prompt.
+	testDecisionPattern = regexp.MustCompile(`(?i)^(run|skip)\s+([^\s:]+)(?:\s*[-:|—]+\s*(.*))?$`)
+)
+
+// newTestsForCmd creates the tests-for subcommand.
+func newTestsForCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts       testsForOptions
+		aiOpts     aiFlags
+		diffOpts   diffFlags
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tests-for <rev>",
+		Short: "List the tests that exercise the code a commit changed",
+		Long: `List the tests that exercise the code a commit changed, for targeted test
+selection when the commit is backported, reverted, or re-applied.
+
+Tests are found by:
+- Coverage: with --coverage, the tests whose coverage includes a changed line
+- Changed tests: test files the commit itself changed
+- Naming conventions: foo_test.go, test_foo.py, foo.test.ts, FooTest.java,
+  foo_spec.rb, and the same names under mirrored test directories
+- Packages: the other Go test files of a changed package
+- References: test files that name a function, method, or type the commit
+  changed
+
+The AI then reviews the candidates against the diff and the test files of
+the tree, adding tests the heuristics missed, such as tests of callers, and
+dropping weak candidates that clearly do not exercise the change. Tests from
+coverage, changed tests, and naming conventions are never dropped. Use
+--no-ai to skip the review, and --diff-format to set how the diff is shown
+to the AI.
+
+--coverage reads a per-test coverage map: a JSON object mapping test names
+to covered files, as "path" or "path:start-end", or a directory with one Go
+coverprofile or LCOV file per test, named after the test. Tests from the
+map are listed by name rather than by file.
+
+Test files are looked up in the tree of the commit, or in --on for a
+backport to another branch. Use --list to print only the tests, one per
+line, for CI.`,
+		Example: `  # Tests to run for the last commit
+  arc-git tests-for HEAD
+
+  # Tests on the release branch for a commit being backported to it
+  arc-git tests-for 4f2a9c1 --on release/2.4 --list
+
+  # From per-test coverage, with no AI review
+  arc-git tests-for HEAD --coverage coverage/by-test/ --no-ai --output json
+
+  # Run only the selected Go test files' packages
+  go test $(arc-git tests-for HEAD --list | xargs -n1 dirname | sort -u | sed 's|^|./|')`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			opts.rev = args[0]
+			var err error
+			if opts.diffFormat, err = diffOpts.resolve(cmd.Context()); err != nil {
+				return err
+			}
+
+			return runTestsFor(cmd.Context(), aiOpts.apply(cmd.Context(), aiCfg), &aiOpts, opts, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.on, "on", "", "Look tests up in this revision's tree instead of the commit's (e.g. the backport target)")
+	cmd.Flags().StringVar(&opts.coverage, "coverage", "", "Per-test coverage map: a JSON file, or a directory of per-test Go coverprofile or LCOV files")
+	cmd.Flags().BoolVar(&opts.noAI, "no-ai", false, "Skip the AI review of the candidates")
+	cmd.Flags().BoolVar(&opts.list, "list", false, "Print only the selected tests, one per line")
+	aiOpts.register(cmd)
+	diffOpts.register(cmd)
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// testsForOptions holds the tests-for flags.
+type testsForOptions struct {
+	rev      string
+	on       string
+	coverage string
+	noAI     bool
+	list     bool
+	// diffFormat is how the diff is shown to the AI.
+	diffFormat string
+}
+
+// selectedTest is a test that exercises a commit's changes: a test file, or
+// a test named by the coverage map.
+type selectedTest struct {
+	ID         string   `json:"id"`
+	Kind       string   `json:"kind"` // "file" or "test"
+	Confidence string   `json:"confidence"`
+	Sources    []string `json:"sources"`
+	Reasons    []string `json:"reasons"`
+}
+
+// droppable reports whether the AI review may drop the test: only tests
+// found by package or reference alone are weak enough.
+func (t selectedTest) droppable() bool {
+	for _, s := range t.Sources {
+		if s != testSourcePackage && s != testSourceReference {
+			return false
+		}
+	}
+	return true
+}
+
+// testSelection accumulates the tests found for a commit.
+type testSelection struct {
+	tests map[string]*selectedTest
+}
+
+// add records that test id exercises the change, keeping the highest
+// confidence it was found with.
+func (s *testSelection) add(id, kind, source, confidence, reason string) {
+	t, ok := s.tests[id]
+	if !ok {
+		t = &selectedTest{ID: id, Kind: kind, Confidence: confidence}
+		s.tests[id] = t
+	}
+	if slices.Index(testConfidences, confidence) < slices.Index(testConfidences, t.Confidence) {
+		t.Confidence = confidence
+	}
+	if !slices.Contains(t.Sources, source) {
+		t.Sources = append(t.Sources, source)
+	}
+	if !slices.Contains(t.Reasons, reason) {
+		t.Reasons = append(t.Reasons, reason)
+	}
+}
+
+// sorted returns the tests, most confident first.
+func (s *testSelection) sorted() []selectedTest {
+	tests := make([]selectedTest, 0, len(s.tests))
+	for _, t := range s.tests {
+		tests = append(tests, *t)
+	}
+	sort.Slice(tests, func(i, j int) bool {
+		ci, cj := slices.Index(testConfidences, tests[i].Confidence), slices.Index(testConfidences, tests[j].Confidence)
+		if ci != cj {
+			return ci < cj
+		}
+		return tests[i].ID < tests[j].ID
+	})
+	return tests
+}
+
+// runTestsFor implements the tests-for workflow.
+func runTestsFor(ctx context.Context, cfg *ai.Config, aiOpts *aiFlags, opts testsForOptions, out output.OutputOptions) error {
+	progressFor(out)
+	log := logging.L()
+
+	hash, err := revParse(ctx, opts.rev)
+	if err != nil {
+		return clierrors.NewCLIError(fmt.Sprintf("unknown revision %q", opts.rev)).
+			WithHint("Pass a commit, branch, or tag, e.g. arc-git tests-for HEAD")
+	}
+	if parents, err := git.Run(ctx, "rev-list", "--parents", "-n1", hash); err == nil && len(strings.Fields(parents)) > 2 {
+		return clierrors.NewCLIError(fmt.Sprintf("%s is a merge commit", shortHash(hash))).
+			WithHint("Pass the commits the merge brought in, one at a time")
+	}
+	target := hash
+	if opts.on != "" {
+		if target, err = revParse(ctx, opts.on); err != nil {
+			return clierrors.NewCLIError(fmt.Sprintf("unknown revision %q", opts.on)).
+				WithHint("Pass the branch or commit the tests will run on with --on")
+		}
+	}
+
+	changes, err := git.ChangedRanges(ctx, hash)
+	if err != nil {
+		return fmt.Errorf("failed to read the changes of %s: %w", shortHash(hash), err)
+	}
+	var testFiles []string
+	if tree, err := git.Run(ctx, "ls-tree", "-r", "-z", "--full-tree", "--name-only", target); err == nil {
+		for _, f := range strings.Split(strings.TrimSuffix(tree, "\x00"), "\x00") {
+			if isTestFile(f) {
+				testFiles = append(testFiles, f)
+			}
+		}
+	} else {
+		return fmt.Errorf("failed to list the files of %s: %w", shortHash(target), err)
+	}
+	log.Info("Finding tests", "commit", shortHash(hash), "files", len(changes), "tests", len(testFiles))
+
+	selection := &testSelection{tests: make(map[string]*selectedTest)}
+	if opts.coverage != "" {
+		coverage, err := loadCoverageMap(opts.coverage)
+		if err != nil {
+			return clierrors.NewCLIError(fmt.Sprintf("failed to read coverage map %s: %v", opts.coverage, err)).
+				WithHint("Pass a JSON object of test names to covered files, or a directory of per-test coverprofile or LCOV files")
+		}
+		selectCovered(selection, coverage, changes)
+	}
+	selectByPath(selection, changes, testFiles)
+	selectByReference(ctx, selection, hash, target, testFiles)
+
+	var skipped []selectedTest
+	if !opts.noAI && len(changes) > 0 {
+		service, err := newAIService(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		model := aiOpts.modelFor(ctx, prompt.TestsForModel)
+		if err := repoConfig(ctx).CheckAI("", model); err != nil {
+			return err
+		}
+		skipped, err = reviewTests(withPlanSubject(ctx, shortHash(hash)), service, model, opts.diffFormat, hash, selection, testFiles)
+		if errors.Is(err, ErrPlanned) {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			log.Warn("Failed to review the tests, listing the heuristic candidates", "commit", shortHash(hash), "error", err)
+		}
+	}
+	tests := selection.sorted()
+	files := make([]string, 0, len(changes))
+	for _, c := range changes {
+		files = append(files, testedPath(c))
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(map[string]interface{}{
+			"commit":  shortHash(hash),
+			"target":  shortHash(target),
+			"files":   files,
+			"tests":   tests,
+			"skipped": skipped,
+		})
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	case opts.list:
+		for _, t := range tests {
+			fmt.Println(t.ID)
+		}
+	default:
+		fmt.Printf("Tests for %s (%d files changed", shortHash(hash), len(files))
+		if target != hash {
+			fmt.Printf(", tests on %s", shortHash(target))
+		}
+		fmt.Println("):")
+		if len(tests) == 0 {
+			fmt.Println("  No tests found.")
+		}
+		for _, t := range tests {
+			fmt.Printf("  %-6s  %s\n", t.Confidence, t.ID)
+			fmt.Printf("          %s\n", strings.Join(t.Reasons, "; "))
+		}
+		if len(skipped) > 0 {
+			fmt.Println("\nSkipped:")
+			for _, t := range skipped {
+				fmt.Printf("  %s: %s\n", t.ID, strings.Join(t.Reasons, "; "))
+			}
+		}
+	}
+
+	return nil
+}
+
+// testedPath returns the path of a changed file, or its old path if the
+// commit deleted it.
+func testedPath(c git.FileChange) string {
+	if c.Path != "" {
+		return c.Path
+	}
+	return c.OldPath
+}
+
+// selectCovered adds the tests of coverage whose covered lines overlap the
+// changes. Changes are matched against both sides of each hunk, since the
+// map may have been recorded before or after the commit.
+func selectCovered(selection *testSelection, coverage map[string][]coveredFile, changes []git.FileChange) {
+	for test, covered := range coverage {
+		for _, c := range changes {
+			for _, p := range []string{c.Path, c.OldPath} {
+				if p == "" {
+					continue
+				}
+				for _, f := range covered {
+					if f.matches(p) && f.overlaps(c) {
+						selection.add(test, "test", testSourceCoverage, "high", "covers "+p)
+					}
+				}
+			}
+		}
+	}
+}
+
+// selectByPath adds the test files the commit changed, the test files named
+// after its changed source files, and the other test files of changed Go
+// packages.
+func selectByPath(selection *testSelection, changes []git.FileChange, testFiles []string) {
+	for _, c := range changes {
+		p := testedPath(c)
+		if isTestFile(p) {
+			if slices.Contains(testFiles, p) {
+				selection.add(p, "file", testSourceChanged, "high", "changed by the commit")
+			}
+			continue
+		}
+		stem := strings.ToLower(strings.TrimSuffix(path.Base(p), path.Ext(p)))
+		dir := path.Dir(p)
+		for _, t := range testFiles {
+			tdir := path.Dir(t)
+			if testStem(t) == stem {
+				switch near := testDirKey(tdir) == testDirKey(dir); {
+				case tdir == dir || near:
+					selection.add(t, "file", testSourceConvention, "high", "tests "+p)
+				case path.Ext(p) != ".go" && segmentSuffix(testDirKey(tdir), testDirKey(dir)):
+					// Go tests live next to their code; elsewhere tests/
+					// directories need not mirror the sources fully.
+					selection.add(t, "file", testSourceConvention, "medium", "named after "+p)
+				}
+			} else if path.Ext(p) == ".go" && strings.HasSuffix(t, "_test.go") && tdir == dir {
+				selection.add(t, "file", testSourcePackage, "low", "in the package of "+p)
+			}
+		}
+	}
+}
+
+// selectByReference adds the test files that name a symbol the commit
+// changed.
+func selectByReference(ctx context.Context, selection *testSelection, hash, target string, testFiles []string) {
+	syms, err := commitSymbols(ctx, hash)
+	if err != nil {
+		logging.L().Warn("Failed to find the changed symbols, not searching tests for them", "commit", shortHash(hash), "error", err)
+		return
+	}
+	seen := make(map[string]bool)
+	for _, s := range syms {
+		if isTestFile(s.Path) || len(seen) == maxReferencedSymbols {
+			continue
+		}
+		name := s.Name[strings.LastIndex(s.Name, ".")+1:]
+		if len(name) < minReferencedName || commonSymbols[name] || seen[name] {
+			continue
+		}
+		seen[name] = true
+		files, err := git.FilesWithWord(ctx, target, name)
+		if err != nil {
+			logging.L().Warn("Failed to search tests for a symbol", "symbol", name, "error", err)
+			continue
+		}
+		files = slices.DeleteFunc(files, func(f string) bool { return !slices.Contains(testFiles, f) })
+		// A name many test files use is a weak signal for each of them.
+		confidence := "medium"
+		if len(files) > maxReferencingTests {
+			confidence = "low"
+		}
+		for _, f := range files {
+			selection.add(f, "file", testSourceReference, confidence, "references "+s.Name)
+		}
+	}
+}
+
+// testStem returns the lower-cased name of what a test file tests, with
+// its extension and test markers removed: foo for foo_test.go,
+// test_foo.py, foo.test.ts, FooTest.java, and foo_spec.rb.
+func testStem(file string) string {
+	base := strings.ToLower(path.Base(file))
+	base = strings.TrimSuffix(base, path.Ext(base))
+	for _, marker := range []string{".test", ".spec", "_test", "_spec", "-test", "-spec", "tests", "test"} {
+		if strings.HasSuffix(base, marker) && len(base) > len(marker) {
+			return strings.TrimSuffix(base, marker)
+		}
+	}
+	return strings.TrimPrefix(base, "test_")
+}
+
+// testDirKey returns dir without the segments that only separate tests from
+// sources.
+func testDirKey(dir string) string {
+	var kept []string
+	for _, s := range strings.Split(dir, "/") {
+		if s != "." && !testDirSegments[strings.ToLower(s)] {
+			kept = append(kept, s)
+		}
+	}
+	return strings.Join(kept, "/")
+}
+
+// segmentSuffix reports whether one of a and b ends with the other, by whole
+// path segments.
+func segmentSuffix(a, b string) bool {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	return b == "" || a == b || strings.HasSuffix(a, "/"+b)
+}
+
+// reviewTests asks the AI which tests the heuristics missed and which of
+// their weak candidates do not exercise the change, and applies the answer
+// to selection. It returns the dropped candidates.
+func reviewTests(ctx context.Context, service *ai.Service, model, diffFormat, hash string, selection *testSelection, testFiles []string) ([]selectedTest, error) {
+	message, err := git.Body(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit message: %w", err)
+	}
+	diff, err := diffview.Commit(ctx, diffFormat, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read diff: %w", err)
+	}
+	diff = guardDiff(ctx, hash, diff)
+
+	var candidates strings.Builder
+	for _, t := range selection.sorted() {
+		fmt.Fprintf(&candidates, "- %s (%s): %s\n", t.ID, t.Confidence, strings.Join(t.Reasons, "; "))
+	}
+	if candidates.Len() == 0 {
+		candidates.WriteString("(none)\n")
+	}
+
+	systemPrompt, userPrompt := prompt.TestsFor(shortHash(hash), "", "", candidates.String(), "")
+	fitted := fitPrompt(ctx, model,
+		prompt.Section{Name: prompt.SectionSystem, Text: systemPrompt + userPrompt, Required: true},
+		prompt.Section{Name: prompt.SectionMetadata, Text: message},
+		prompt.Section{Name: prompt.SectionDiff, Text: prompt.TrimDiff(diff, maxTestsForDiff), Trim: prompt.TrimDiff},
+		prompt.Section{Name: prompt.SectionRepo, Text: strings.Join(testFiles, "\n"), Trim: prompt.TrimLines},
+	)
+	systemPrompt, userPrompt = prompt.TestsFor(shortHash(hash), fitted[1], fitted[2], candidates.String(), fitted[3])
+
+	logging.L().Info("Reviewing tests", "commit", shortHash(hash), "candidates", len(selection.tests))
+	reply, err := runPrompt(ctx, service, systemPrompt, userPrompt, model)
+	if err != nil {
+		return nil, err
+	}
+
+	var skipped []selectedTest
+	for _, line := range strings.Split(reply, "\n") {
+		line = strings.Trim(strings.TrimSpace(line), "-*` ")
+		m := testDecisionPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		id, reason := strings.Trim(m[2], "`"), strings.TrimSpace(m[3])
+		if reason == "" {
+			reason = "chosen by the AI review"
+		}
+		switch strings.ToLower(m[1]) {
+		case "run":
+			if !slices.Contains(testFiles, id) {
+				logging.L().Warn("Ignoring a test the AI review named that does not exist", "test", id)
+				continue
+			}
+			if _, ok := selection.tests[id]; !ok {
+				selection.add(id, "file", testSourceAI, "medium", reason)
+			}
+		case "skip":
+			if t, ok := selection.tests[id]; ok && t.droppable() {
+				delete(selection.tests, id)
+				skipped = append(skipped, selectedTest{ID: t.ID, Kind: t.Kind, Confidence: t.Confidence, Sources: t.Sources, Reasons: []string{reason}})
+			}
+		}
+	}
+	return skipped, nil
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-git/internal/logging"
+)
+
+// thirdPartyKey carries --allow-third-party.
+type thirdPartyKey struct{}
+
+// withThirdPartyAllowed records whether the run passed --allow-third-party.
+func withThirdPartyAllowed(ctx context.Context, allowed bool) context.Context {
+	return context.WithValue(ctx, thirdPartyKey{}, allowed)
+}
+
+// thirdPartyAllowed reports whether third-party code may be sent to the AI:
+// the policy has no third_party rule, or the rule is opt-in and the run
+// passed --allow-third-party.
+func thirdPartyAllowed(ctx context.Context) bool {
+	tp := repoConfig(ctx).Policy.ThirdParty
+	if !tp.Enabled() {
+		return true
+	}
+	allowed, _ := ctx.Value(thirdPartyKey{}).(bool)
+	return tp.Mode == "opt-in" && allowed
+}
+
+// diffFile is one file's part of a patch.
+type diffFile struct {
+	oldPath, newPath string
+	text             string
+}
+
+// splitDiff splits a patch into the text before its first file and the
+// parts of its files, which each start with git's "diff --git" line.
+func splitDiff(diff string) (string, []diffFile) {
+	var (
+		files []diffFile
+		start int
+	)
+	preamble := diff
+	for pos := 0; pos < len(diff); {
+		end := len(diff)
+		if i := strings.IndexByte(diff[pos:], '\n'); i >= 0 {
+			end = pos + i + 1
+		}
+		if header, ok := strings.CutPrefix(diff[pos:end], "diff --git "); ok {
+			if len(files) == 0 {
+				preamble = diff[:pos]
+			} else {
+				files[len(files)-1].text = diff[start:pos]
+			}
+			oldPath, newPath := diffHeaderPaths(strings.TrimSuffix(header, "\n"))
+			files = append(files, diffFile{oldPath: oldPath, newPath: newPath})
+			start = pos
+		}
+		pos = end
+	}
+	if len(files) > 0 {
+		files[len(files)-1].text = diff[start:]
+	}
+	return preamble, files
+}
+
+// diffHeaderPaths returns the paths of a "diff --git" line after its
+// prefix, unquoting git's C-style quoting.
+func diffHeaderPaths(header string) (string, string) {
+	sep := " b/"
+	if strings.HasSuffix(header, `"`) {
+		sep = ` "b/`
+	}
+	i := strings.LastIndex(header, sep)
+	if i < 0 {
+		return header, header
+	}
+	unquote := func(p string) string {
+		if strings.HasPrefix(p, `"`) {
+			if s, err := strconv.Unquote(p); err == nil {
+				p = s
+			}
+		}
+		return p[min(2, len(p)):]
+	}
+	return unquote(header[:i]), unquote(strings.TrimPrefix(header[i:], " "))
+}
+
+// thirdPartyReason returns why the policy treats a file changed at rev as
+// third-party code, or "". License headers are read from the file at rev,
+// or from text, such as the file's part of a patch, when rev lacks it.
+func thirdPartyReason(ctx context.Context, rev, file, text string) string {
+	tp := repoConfig(ctx).Policy.ThirdParty
+	if reason := tp.Vendored(file); reason != "" {
+		return reason
+	}
+	if rev != "" {
+		if src, err := git.FileAt(ctx, rev, file); err == nil {
+			return tp.Licensed(string(src))
+		}
+	}
+	// Start at the first hunk, so that the header lines of the patch do not
+	// count towards the top of the file.
+	if i := strings.Index(text, "\n@@"); i >= 0 {
+		text = text[i+1:]
+	}
+	return tp.Licensed(text)
+}
+
+// thirdPartyIn returns the files of diff, a patch of rev, that the policy
+// treats as third-party code, with the reason for each.
+func thirdPartyIn(ctx context.Context, rev, diff string) map[string]string {
+	if !repoConfig(ctx).Policy.ThirdParty.Enabled() {
+		return nil
+	}
+	_, files := splitDiff(diff)
+	found := make(map[string]string)
+	for _, f := range files {
+		reason := thirdPartyReason(ctx, rev, f.newPath, f.text)
+		if reason == "" && f.oldPath != f.newPath {
+			reason = thirdPartyReason(ctx, rev, f.oldPath, f.text)
+		}
+		if reason != "" {
+			found[f.newPath] = reason
+		}
+	}
+	return found
+}
+
+// guardDiff returns diff, a patch of rev about to be shown to the AI, with
+// the files the third_party policy keeps out of prompts reduced to their
+// "diff --git" line and a note, so that the model still knows they changed.
+func guardDiff(ctx context.Context, rev, diff string) string {
+	if thirdPartyAllowed(ctx) {
+		return diff
+	}
+	found := thirdPartyIn(ctx, rev, diff)
+	if len(found) == 0 {
+		return diff
+	}
+	logging.L().Info("Leaving third-party code out of the prompt", "files", describeThirdParty(found))
+
+	preamble, files := splitDiff(diff)
+	var b strings.Builder
+	b.WriteString(preamble)
+	for _, f := range files {
+		reason, ok := found[f.newPath]
+		if !ok {
+			b.WriteString(f.text)
+			continue
+		}
+		header, _, _ := strings.Cut(f.text, "\n")
+		fmt.Fprintf(&b, "%s\n(third-party code, %s: left out by policy)\n", header, reason)
+	}
+	return b.String()
+}
+
+// guardRangeDiff returns rangeDiff, the range-diff of oldRange and newRange
+// about to be shown to the AI, redone without the files the third_party
+// policy keeps out of prompts in any commit of either range, and with a note
+// naming them. Commits that cannot be checked are an error, not a pass.
+func guardRangeDiff(ctx context.Context, oldRange, newRange, rangeDiff string) (string, error) {
+	if thirdPartyAllowed(ctx) {
+		return rangeDiff, nil
+	}
+	found := make(map[string]string)
+	for _, r := range []string{oldRange, newRange} {
+		commits, err := git.Log(ctx, "--no-merges", r)
+		if err != nil {
+			return "", fmt.Errorf("failed to check %s for third-party code: %w", r, err)
+		}
+		for _, c := range commits {
+			patch, err := git.Diff(ctx, c.Hash)
+			if err != nil {
+				return "", fmt.Errorf("failed to check %s for third-party code: %w", c.Short(), err)
+			}
+			maps.Copy(found, thirdPartyIn(ctx, c.Hash, patch))
+		}
+	}
+	if len(found) == 0 {
+		return rangeDiff, nil
+	}
+	logging.L().Info("Leaving third-party code out of the prompt", "files", describeThirdParty(found))
+
+	pathspecs := make([]string, 0, len(found))
+	for f := range found {
+		pathspecs = append(pathspecs, ":(exclude,top,literal)"+f)
+	}
+	sort.Strings(pathspecs)
+	filtered, err := git.RangeDiff(ctx, oldRange, newRange, pathspecs...)
+	if err != nil {
+		return "", fmt.Errorf("failed to compare series without third-party code: %w", err)
+	}
+	return filtered + fmt.Sprintf("\n(third-party code left out by policy: %s)\n", describeThirdParty(found)), nil
+}
+
+// describeThirdParty lists found files and their reasons, for messages.
+func describeThirdParty(found map[string]string) string {
+	files := make([]string, 0, len(found))
+	for f := range found {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	more := 0
+	if len(files) > 3 {
+		files, more = files[:2], len(files)-2
+	}
+	for i, f := range files {
+		files[i] = fmt.Sprintf("%s (%s)", f, found[f])
+	}
+	if more > 0 {
+		files = append(files, fmt.Sprintf("%d more", more))
+	}
+	return strings.Join(files, ", ")
+}
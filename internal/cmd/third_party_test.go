@@ -0,0 +1,111 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-git/internal/config"
+)
+
+func TestDiffHeaderPaths(t *testing.T) {
+	tests := []struct {
+		header   string
+		old, new string
+	}{
+		{header: "a/main.go b/main.go", old: "main.go", new: "main.go"},
+		{header: "a/old/name.go b/new/name.go", old: "old/name.go", new: "new/name.go"},
+		{header: "a/my file.go b/my file.go", old: "my file.go", new: "my file.go"},
+		{header: `"a/caf\303\251.go" "b/caf\303\251.go"`, old: "café.go", new: "café.go"},
+		{header: `"a/tab\tname.go" "b/tab\tname.go"`, old: "tab\tname.go", new: "tab\tname.go"},
+		// Only the side that needs it is quoted in a rename.
+		{header: `a/plain.go "b/vendor/caf\303\251.go"`, old: "plain.go", new: "vendor/café.go"},
+		{header: `"a/vendor/caf\303\251.go" b/plain.go`, old: "vendor/café.go", new: "plain.go"},
+		{header: "garbled", old: "garbled", new: "garbled"},
+	}
+	for _, tt := range tests {
+		old, new := diffHeaderPaths(tt.header)
+		if old != tt.old || new != tt.new {
+			t.Errorf("diffHeaderPaths(%q) = %q, %q; want %q, %q", tt.header, old, new, tt.old, tt.new)
+		}
+	}
+}
+
+func TestSplitDiff(t *testing.T) {
+	first := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n+b\n"
+	// A line that merely mentions the header inside a hunk does not start
+	// a file.
+	second := "diff --git a/vendor/x.go b/vendor/y.go\nsimilarity index 90%\n@@ -1 +1 @@\n- diff --git a/fake b/fake\n+x"
+	tests := []struct {
+		name     string
+		diff     string
+		preamble string
+		files    []diffFile
+	}{
+		{name: "empty"},
+		{name: "no files", diff: "commit abc\n\n    Message\n", preamble: "commit abc\n\n    Message\n"},
+		{
+			name:     "files after a preamble",
+			diff:     "commit abc\n\n" + first + second,
+			preamble: "commit abc\n\n",
+			files: []diffFile{
+				{oldPath: "a.go", newPath: "a.go", text: first},
+				{oldPath: "vendor/x.go", newPath: "vendor/y.go", text: second},
+			},
+		},
+		{name: "no preamble", diff: first, files: []diffFile{{oldPath: "a.go", newPath: "a.go", text: first}}},
+	}
+	for _, tt := range tests {
+		preamble, files := splitDiff(tt.diff)
+		if preamble != tt.preamble || !reflect.DeepEqual(files, tt.files) {
+			t.Errorf("%s: splitDiff = %q, %+v; want %q, %+v", tt.name, preamble, files, tt.preamble, tt.files)
+		}
+	}
+}
+
+func TestGuardRangeDiff(t *testing.T) {
+	r := newTestRepo(t)
+	base := r.commit("Base", nil)
+	r.commit("Add code", map[string]string{
+		"vendor/lib/lib.go": lines(50),
+		"main.go":           lines(50),
+	})
+	v1 := r.git("rev-parse", "HEAD")
+	r.git("reset", "-q", "--hard", base)
+	r.commit("Add code", map[string]string{
+		"vendor/lib/lib.go": lines(50) + "vendored secret\n",
+		"main.go":           lines(50) + "own change\n",
+	})
+	oldRange, newRange := base+".."+v1, base+"..HEAD"
+	rangeDiff := r.git("range-diff", "--no-color", oldRange, newRange)
+	if !strings.Contains(rangeDiff, "vendored secret") {
+		t.Fatalf("range-diff lacks the vendored change:\n%s", rangeDiff)
+	}
+
+	if got, err := guardRangeDiff(r.ctx, oldRange, newRange, rangeDiff); err != nil || got != rangeDiff {
+		t.Errorf("guardRangeDiff without a policy = %q, %v; want the range-diff unchanged", got, err)
+	}
+
+	ctx := withRepoConfig(r.ctx, &config.Config{Policy: config.Policy{ThirdParty: config.ThirdParty{Mode: "exclude"}}})
+	got, err := guardRangeDiff(ctx, oldRange, newRange, rangeDiff)
+	if err != nil {
+		t.Fatalf("guardRangeDiff: %v", err)
+	}
+	if strings.Contains(got, "vendored secret") || strings.Contains(got, "## vendor/lib/lib.go") {
+		t.Errorf("guardRangeDiff kept the vendored file:\n%s", got)
+	}
+	if !strings.Contains(got, "own change") {
+		t.Errorf("guardRangeDiff dropped the repository's own change:\n%s", got)
+	}
+	if !strings.Contains(got, "(third-party code left out by policy: vendor/lib/lib.go (vendored under vendor/))") {
+		t.Errorf("guardRangeDiff does not name what it left out:\n%s", got)
+	}
+
+	allowed := withThirdPartyAllowed(withRepoConfig(r.ctx, &config.Config{Policy: config.Policy{ThirdParty: config.ThirdParty{Mode: "opt-in"}}}), true)
+	if got, err := guardRangeDiff(allowed, oldRange, newRange, rangeDiff); err != nil || got != rangeDiff {
+		t.Errorf("guardRangeDiff with --allow-third-party = %q, %v; want the range-diff unchanged", got, err)
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"time"
+)
+
+// requestTimeoutKey carries the --request-timeout value through the command
+// context to runPrompt.
+type requestTimeoutKey struct{}
+
+// withRequestTimeout returns ctx annotated with the per-request AI timeout.
+func withRequestTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, requestTimeoutKey{}, d)
+}
+
+// requestTimeout returns the per-request AI timeout, or zero for none.
+func requestTimeout(ctx context.Context) time.Duration {
+	d, _ := ctx.Value(requestTimeoutKey{}).(time.Duration)
+	return d
+}
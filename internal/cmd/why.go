@@ -0,0 +1,179 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-git/internal/git"
+	"github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// newWhyCmd creates the why subcommand.
+func newWhyCmd() *cobra.Command {
+	var (
+		rev        string
+		outputOpts output.OutputOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "why <file> <line>",
+		Short: "Explain a line from the annotation of the commit that last changed it",
+		Long: `Find the commit that last changed a line through git blame and print the
+part of its AI annotation that is about that line.
+
+Annotations written with "arc-git annotate --anchors" tie each statement to
+the hunks it describes, so only the statements covering the line are shown.
+Without a matching anchor the whole annotation is printed.
+
+The line is looked up in the work tree, or at --rev. Lines that are not
+committed yet, and commits without an annotation, are reported as such, and
+with a freshness block in the repository config so are annotations whose code
+has since been rewritten.`,
+		Example: `  # Why does line 42 look like this?
+  arc-git why internal/auth/session.go 42
+
+  # The same line as of the last release
+  arc-git why internal/auth/session.go 42 --rev v2.4.0
+
+  # For editor integrations
+  arc-git why internal/auth/session.go 42 --output json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputOpts.Resolve(); err != nil {
+				return err
+			}
+			line, err := strconv.Atoi(args[1])
+			if err != nil || line <= 0 {
+				return errors.NewCLIError(fmt.Sprintf("invalid line number %q", args[1])).
+					WithHint("Give a 1-based line number, e.g. arc-git why main.go 42")
+			}
+
+			return runWhy(cmd.Context(), args[0], line, rev, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVar(&rev, "rev", "", "Look the line up at this revision instead of the work tree")
+	outputOpts.AddOutputFlags(cmd, output.OutputTable)
+
+	return cmd
+}
+
+// whyResult is the explanation of one line.
+type whyResult struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Commit  string `json:"commit,omitempty"`
+	Date    string `json:"date,omitempty"`
+	Author  string `json:"author,omitempty"`
+	Message string `json:"message,omitempty"`
+	// OriginPath and OriginLine locate the line in the commit that last
+	// changed it, which is what anchors refer to.
+	OriginPath string       `json:"origin_path,omitempty"`
+	OriginLine int          `json:"origin_line,omitempty"`
+	Committed  bool         `json:"committed"`
+	Annotated  bool         `json:"annotated"`
+	Anchors    []noteAnchor `json:"anchors,omitempty"`
+	Annotation string       `json:"annotation,omitempty"`
+	Risk       string       `json:"risk,omitempty"`
+	Labels     []string     `json:"labels,omitempty"`
+	Freshness  *freshness   `json:"freshness,omitempty"`
+}
+
+// runWhy implements the why workflow.
+func runWhy(ctx context.Context, file string, line int, rev string, out output.OutputOptions) error {
+	blame, err := git.Blame(ctx, rev, file, line)
+	if err != nil {
+		return errors.NewCLIError(fmt.Sprintf("failed to blame %s:%d", file, line)).
+			WithHint(err.Error())
+	}
+
+	result := whyResult{File: file, Line: line, Committed: blame.Committed()}
+	if result.Committed {
+		result.OriginPath, result.OriginLine = blame.Path, blame.Line
+		commits, err := git.Log(ctx, "--no-walk", "--date=short", blame.Hash)
+		if err != nil || len(commits) == 0 {
+			return errors.NewCLIError(fmt.Sprintf("failed to read commit %s", shortHash(blame.Hash))).
+				WithHint(fmt.Sprint(err))
+		}
+		c := commits[0]
+		result.Commit, result.Date, result.Author, result.Message = c.Short(), c.Date, c.Author, c.Message
+
+		if raw, err := git.ShowNote(ctx, c.Hash, "ai"); err == nil {
+			note := parseNote(raw)
+			result.Annotated = true
+			result.Risk, result.Labels = note.Risk(), note.Labels()
+			result.Freshness = freshnessFor(ctx, c.Hash, note)
+			for _, a := range note.Anchors {
+				if a.Covers(blame.Path, blame.Line) {
+					result.Anchors = append(result.Anchors, a)
+				}
+			}
+			if len(result.Anchors) == 0 {
+				result.Annotation = note.Text
+			}
+		}
+	}
+
+	switch {
+	case out.Is(output.OutputJSON):
+		return writeJSON(result)
+	case out.Is(output.OutputQuiet):
+		// Quiet mode: suppress output
+	default:
+		printWhy(result)
+	}
+
+	return nil
+}
+
+// printWhy prints a why result as text.
+func printWhy(r whyResult) {
+	fmt.Printf("%s:%d\n", r.File, r.Line)
+	if !r.Committed {
+		fmt.Println("Not committed yet.")
+		return
+	}
+	fmt.Printf("%s %s %s  %s\n", r.Commit, r.Date, r.Message, r.Author)
+	if r.OriginPath != r.File || r.OriginLine != r.Line {
+		fmt.Printf("(line %d of %s in that commit)\n", r.OriginLine, r.OriginPath)
+	}
+	fmt.Println()
+
+	if !r.Annotated {
+		fmt.Println("The commit has no annotation.")
+		fmt.Printf("Annotate it with: arc-git annotate --from %s^ --to %s --anchors\n", r.Commit, r.Commit)
+		return
+	}
+	if r.Freshness != nil && r.Freshness.Historical {
+		fmt.Printf("%s.\n\n", historicalNotice(r.Freshness))
+	}
+
+	switch {
+	case len(r.Anchors) > 0:
+		for _, a := range r.Anchors {
+			fmt.Printf("  %s\n", a.Statement)
+		}
+	default:
+		fmt.Println("No anchor covers this line; the full annotation follows.")
+		fmt.Println()
+		fmt.Printf("  %s\n", strings.Join(strings.Fields(r.Annotation), " "))
+	}
+
+	var tags []string
+	if r.Risk != "" {
+		tags = append(tags, "risk: "+r.Risk)
+	}
+	if len(r.Labels) > 0 {
+		tags = append(tags, "labels: "+strings.Join(r.Labels, ", "))
+	}
+	if len(tags) > 0 {
+		fmt.Printf("\n[%s]\n", strings.Join(tags, "; "))
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// BranchTiers lists the annotation tiers a branch rule can choose; "none"
+// leaves the commits unannotated.
+var BranchTiers = []string{"deep", "quick", "none"}
+
+// BranchRule sets how annotate treats the commits reachable from the branches
+// it matches, such as deep annotations with a premium model for main and
+// release branches, and quick gists or none for feature branches.
+type BranchRule struct {
+	Name string `yaml:"name"`
+	// Match lists branch name globs, e.g. "main" or "release/*", where "*"
+	// does not cross "/". Remote-tracking branches match without their
+	// remote, so "main" covers origin/main. A rule without patterns matches
+	// every commit.
+	Match []string `yaml:"match,omitempty"`
+	// Tier is one of BranchTiers.
+	Tier string `yaml:"tier"`
+	// Model replaces the tier's configured model.
+	Model string `yaml:"model,omitempty"`
+	// RequireStructured requires deep annotations to carry valid Risk and
+	// Labels trailers, like the policy rule of the same name.
+	RequireStructured bool `yaml:"require_structured,omitempty"`
+
+	// Source is the file or URL the rule was defined in.
+	Source string `yaml:"-"`
+}
+
+// compile validates the rule.
+func (r *BranchRule) compile(source string) error {
+	if r.Name == "" {
+		return fmt.Errorf("branch rule has no name")
+	}
+	if !slices.Contains(BranchTiers, r.Tier) {
+		return fmt.Errorf("branch rule %q: unknown tier %q (use %s)", r.Name, r.Tier, strings.Join(BranchTiers, ", "))
+	}
+	for _, p := range r.Match {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("branch rule %q: invalid pattern %q", r.Name, p)
+		}
+	}
+	r.Source = source
+	return nil
+}
+
+// matches reports whether the rule covers a commit reachable from branches.
+func (r BranchRule) matches(branches []string) bool {
+	if len(r.Match) == 0 {
+		return true
+	}
+	for _, p := range r.Match {
+		for _, b := range branches {
+			if ok, _ := path.Match(p, b); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Violation returns a violation of one of the rule's requirements.
+func (r BranchRule) Violation(rule, detail string) *PolicyViolation {
+	return &PolicyViolation{Rule: "branches[" + r.Name + "]." + rule, Detail: detail, Source: r.Source}
+}
+
+// BranchRule returns the first branch rule that covers a commit reachable
+// from branches, or nil. Rules are tried in order, so protected branches
+// are listed before the rules for everything else.
+func (c *Config) BranchRule(branches []string) *BranchRule {
+	for i := range c.Branches {
+		if c.Branches[i].matches(branches) {
+			return &c.Branches[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,584 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package config loads the per-repository .arc-git.yaml file, and the
+// workspace files that list the repositories of organization reports.
+//
+// A repository config may extend a shared base config, given as a path or an
+// HTTPS URL, so that organizations can manage model choices, redaction rules,
+// and budgets centrally while repositories override only what they need:
+//
+//	extends:
+//	  source: https://config.example.com/arc-git/base.yaml
+//	  sha256: 3b1f...   # optional pin
+//	  ttl: 24h          # optional cache lifetime
+//	models:
+//	  annotate: claude-haiku-4-5
+//
+// Bases may themselves extend other bases. Values in the extending file win:
+// scalars replace, maps merge by key, redaction rules, mirrors,
+// post-processing steps, and branch rules merge by name, and identity rules
+// merge by canonical email. A policy block replaces the base's as a whole,
+// unless the base marks its policy enforced: then the base policy and the
+// redaction rules it requires cannot be changed by extending files.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-git/internal/providers"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the repository config file, relative to the work tree root.
+const FileName = ".arc-git.yaml"
+
+// maxExtendsDepth bounds extends chains.
+const maxExtendsDepth = 8
+
+// Config is the merged repository configuration.
+type Config struct {
+	// Extends names the base config this file builds on.
+	Extends *Extends `yaml:"extends,omitempty"`
+	// Provider overrides the AI provider.
+	Provider string `yaml:"provider,omitempty"`
+	// Models maps command names (or "default") to model identifiers.
+	Models map[string]string `yaml:"models,omitempty"`
+	// DiffFormats maps command names (or "default") to the format diffs are
+	// shown to the model in; see package diffview.
+	DiffFormats map[string]string `yaml:"diff_formats,omitempty"`
+	// Redact lists patterns scrubbed from prompts before they are sent.
+	Redact []RedactRule `yaml:"redact,omitempty"`
+	// Budget limits the work a single run may do.
+	Budget Budget `yaml:"budget,omitempty"`
+	// Context sizes prompts to the model's context window.
+	Context Context `yaml:"context,omitempty"`
+	// Identities merges author identities beyond what .mailmap does.
+	Identities []IdentityRule `yaml:"identities,omitempty"`
+	// Policy restricts the providers and models runs may use.
+	Policy Policy `yaml:"policy,omitempty"`
+	// Mirrors lists the remotes that annotation refs are replicated to.
+	Mirrors []Mirror `yaml:"mirrors,omitempty"`
+	// PostProcessing is the chain generated text goes through before it is
+	// written anywhere.
+	PostProcessing []PostStep `yaml:"postprocess,omitempty"`
+	// Branches sets the annotation tier and model by the branches a commit
+	// is reachable from.
+	Branches []BranchRule `yaml:"branches,omitempty"`
+	// Freshness marks annotations of rewritten code as historical.
+	Freshness Freshness `yaml:"freshness,omitempty"`
+
+	// Sources lists the files and URLs the config was assembled from, base
+	// first.
+	Sources []string `yaml:"-"`
+}
+
+// Extends references a base config.
+type Extends struct {
+	// Source is a file path (relative to the extending file) or HTTPS URL.
+	Source string `yaml:"source"`
+	// SHA256 pins the expected content hash of a remote base.
+	SHA256 string `yaml:"sha256,omitempty"`
+	// TTL is how long a fetched remote base is reused before refetching.
+	TTL Duration `yaml:"ttl,omitempty"`
+}
+
+// UnmarshalYAML accepts either a bare source string or a mapping.
+func (e *Extends) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		e.Source = node.Value
+		return nil
+	}
+	type plain Extends
+	return node.Decode((*plain)(e))
+}
+
+// RedactRule replaces matches of Pattern with Replacement.
+type RedactRule struct {
+	Name        string `yaml:"name"`
+	Pattern     string `yaml:"pattern"`
+	Replacement string `yaml:"replacement,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// IdentityRule maps every author whose email (or name) matches one of Match
+// to a single canonical identity. Patterns are case-insensitive globs, e.g.
+// "alice@*" or "*@old-corp.example"; patterns without "@" match the name.
+type IdentityRule struct {
+	Name  string   `yaml:"name"`
+	Email string   `yaml:"email"`
+	Match []string `yaml:"match"`
+}
+
+// matches reports whether the rule covers the author.
+func (r IdentityRule) matches(name, email string) bool {
+	for _, p := range r.Match {
+		p = strings.ToLower(p)
+		subject := strings.ToLower(email)
+		if !strings.Contains(p, "@") {
+			subject = strings.ToLower(name)
+		}
+		if ok, _ := path.Match(p, subject); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Mirror is a fork or mirror that notes refs are pushed to by
+// "arc-git notes mirror".
+type Mirror struct {
+	Name string `yaml:"name"`
+	// URL is a remote URL or the name of a configured git remote.
+	URL string `yaml:"url"`
+	// Refs lists the refs or refspec patterns to push; the default is every
+	// notes ref.
+	Refs []string `yaml:"refs,omitempty"`
+	// Force overwrites refs that diverged on the mirror.
+	Force bool `yaml:"force,omitempty"`
+	// AfterRun pushes to the mirror whenever a command has written notes.
+	AfterRun bool `yaml:"after_run,omitempty"`
+}
+
+// Budget limits the work of a single run. Zero means unlimited.
+type Budget struct {
+	// MaxCommits caps the commits a single annotate run processes.
+	MaxCommits int `yaml:"max_commits,omitempty"`
+	// MaxTokens caps the estimated prompt tokens a single run may send.
+	MaxTokens int `yaml:"max_tokens,omitempty"`
+}
+
+// Context shapes how prompts that would not fit the model's context window
+// are trimmed. Zero values use the defaults.
+type Context struct {
+	// MaxTokens caps prompts below the model's context window.
+	MaxTokens int `yaml:"max_tokens,omitempty"`
+	// Reserve is the part of the window kept free for the reply.
+	Reserve int `yaml:"reserve,omitempty"`
+	// Weights sets the relative share of each prompt section (system, repo,
+	// metadata, diff, enrichments) when trimming.
+	Weights map[string]float64 `yaml:"weights,omitempty"`
+}
+
+// ModelTiers lists the model tiers a policy can cap, cheapest first. A
+// model's tier is the family its name contains.
+var ModelTiers = []string{"haiku", "sonnet", "opus"}
+
+// Policy restricts what runs may do, for organizations that need guarantees
+// before rolling arc-git out.
+type Policy struct {
+	// Enforced stops configs extending this one from changing the policy.
+	Enforced bool `yaml:"enforced,omitempty"`
+	// MaxModelTier is the most capable tier of ModelTiers runs may use.
+	// Models of no known tier are refused.
+	MaxModelTier string `yaml:"max_model_tier,omitempty"`
+	// RequiredRedactions names redaction rules that must be configured.
+	RequiredRedactions []string `yaml:"required_redactions,omitempty"`
+	// ForbiddenProviders lists AI providers runs may not send prompts to.
+	ForbiddenProviders []string `yaml:"forbidden_providers,omitempty"`
+	// RequireStructured requires deep annotations to carry valid Risk and
+	// Labels trailers; annotations without them are not stored.
+	RequireStructured bool `yaml:"require_structured,omitempty"`
+	// ThirdParty keeps vendored third-party code out of prompts.
+	ThirdParty ThirdParty `yaml:"third_party,omitempty"`
+
+	// Source is the file or URL the policy was defined in.
+	Source string `yaml:"-"`
+}
+
+// isZero reports whether the policy block is absent.
+func (p Policy) isZero() bool {
+	return !p.Enforced && p.MaxModelTier == "" && len(p.RequiredRedactions) == 0 &&
+		len(p.ForbiddenProviders) == 0 && !p.RequireStructured && !p.ThirdParty.Enabled()
+}
+
+// requiresRedaction reports whether name is a required redaction rule.
+func (p Policy) requiresRedaction(name string) bool {
+	for _, r := range p.RequiredRedactions {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyViolation reports the policy rule that blocked a run.
+type PolicyViolation struct {
+	// Rule is the policy key, such as "max_model_tier".
+	Rule   string
+	Detail string
+	Source string
+}
+
+// Error names the rule and where the policy came from.
+func (v *PolicyViolation) Error() string {
+	return fmt.Sprintf("blocked by policy rule %s (from %s): %s", v.Rule, v.Source, v.Detail)
+}
+
+// Violation returns a violation of rule for the policy.
+func (p Policy) Violation(rule, detail string) *PolicyViolation {
+	return &PolicyViolation{Rule: rule, Detail: detail, Source: p.Source}
+}
+
+// CheckAI returns the policy violation, if any, of sending prompts to model
+// through provider. Providers are compared by their canonical names, so
+// forbidding anthropic forbids claude too. Empty arguments are not checked.
+func (c *Config) CheckAI(provider, model string) error {
+	p := c.Policy
+	for _, name := range p.RequiredRedactions {
+		found := false
+		for _, r := range c.Redact {
+			found = found || r.Name == name
+		}
+		if !found {
+			return p.Violation("required_redactions", fmt.Sprintf("redaction rule %q is not configured", name))
+		}
+	}
+	if provider != "" {
+		for _, f := range p.ForbiddenProviders {
+			if providers.Canonical(f) == providers.Canonical(provider) {
+				return p.Violation("forbidden_providers", fmt.Sprintf("provider %q is forbidden", provider))
+			}
+		}
+	}
+	if model != "" && p.MaxModelTier != "" {
+		tier := modelTier(model)
+		switch {
+		case tier < 0:
+			return p.Violation("max_model_tier", fmt.Sprintf("model %q is of no known tier (%s)", model, strings.Join(ModelTiers, ", ")))
+		case tier > modelTier(p.MaxModelTier):
+			return p.Violation("max_model_tier", fmt.Sprintf("model %q is above the %s tier", model, p.MaxModelTier))
+		}
+	}
+	return nil
+}
+
+// modelTier returns the index in ModelTiers of the family model belongs to,
+// or -1.
+func modelTier(model string) int {
+	model = strings.ToLower(model)
+	for i, t := range ModelTiers {
+		if strings.Contains(model, t) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Duration is a time.Duration that unmarshals from strings like "24h".
+type Duration time.Duration
+
+// UnmarshalYAML parses a Go duration string.
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	v, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", node.Value, err)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Load reads path and resolves its extends chain. A missing file yields an
+// empty config.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return resolve(data, abs, nil)
+}
+
+// Model returns the model configured for command, falling back to the
+// "default" entry and then to def.
+func (c *Config) Model(command, def string) string {
+	if m := c.Models[command]; m != "" {
+		return m
+	}
+	if m := c.Models["default"]; m != "" {
+		return m
+	}
+	return def
+}
+
+// DiffFormat returns the diff format configured for command, falling back
+// to the "default" entry, or "" when neither is set.
+func (c *Config) DiffFormat(command string) string {
+	if f := c.DiffFormats[command]; f != "" {
+		return f
+	}
+	return c.DiffFormats["default"]
+}
+
+// RedactText applies every redaction rule to s.
+func (c *Config) RedactText(s string) string {
+	for _, r := range c.Redact {
+		if r.re == nil {
+			continue
+		}
+		replacement := r.Replacement
+		if replacement == "" {
+			replacement = "[REDACTED:" + r.Name + "]"
+		}
+		s = r.re.ReplaceAllString(s, replacement)
+	}
+	return s
+}
+
+// Identity returns the canonical identity for an author under the identity
+// rules, and whether a rule matched. The first matching rule wins.
+func (c *Config) Identity(name, email string) (string, string, bool) {
+	for _, r := range c.Identities {
+		if r.matches(name, email) {
+			return r.Name, r.Email, true
+		}
+	}
+	return name, email, false
+}
+
+// resolve parses data from source and merges it over its base, if any.
+// seen tracks the chain to detect cycles.
+func resolve(data []byte, source string, seen []string) (*Config, error) {
+	for _, s := range seen {
+		if s == source {
+			return nil, fmt.Errorf("config extends cycle: %s", strings.Join(append(seen, source), " -> "))
+		}
+	}
+	if len(seen) >= maxExtendsDepth {
+		return nil, fmt.Errorf("config extends chain deeper than %d at %s", maxExtendsDepth, source)
+	}
+	seen = append(seen, source)
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", source, err)
+	}
+	if err := cfg.compile(source); err != nil {
+		return nil, err
+	}
+	cfg.Sources = []string{source}
+	if !cfg.Policy.isZero() {
+		cfg.Policy.Source = source
+	}
+
+	if cfg.Extends == nil || cfg.Extends.Source == "" {
+		return &cfg, nil
+	}
+
+	baseSource, baseData, err := fetchBase(source, *cfg.Extends)
+	if err != nil {
+		return nil, fmt.Errorf("%s: extends: %w", source, err)
+	}
+	base, err := resolve(baseData, baseSource, seen)
+	if err != nil {
+		return nil, err
+	}
+	return merge(base, &cfg), nil
+}
+
+// compile validates redaction and identity patterns, context weights,
+// post-processing steps, branch rules, freshness, and the policy.
+func (c *Config) compile(source string) error {
+	for i := range c.Redact {
+		r := &c.Redact[i]
+		if r.Name == "" {
+			return fmt.Errorf("%s: redact rule %d has no name", source, i+1)
+		}
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return fmt.Errorf("%s: redact rule %q: %w", source, r.Name, err)
+		}
+		r.re = re
+	}
+	if t := c.Policy.MaxModelTier; t != "" && modelTier(t) < 0 {
+		return fmt.Errorf("%s: policy: unknown max_model_tier %q (use %s)", source, t, strings.Join(ModelTiers, ", "))
+	}
+	if err := c.Policy.ThirdParty.compile(); err != nil {
+		return fmt.Errorf("%s: policy: %w", source, err)
+	}
+	if err := c.Freshness.compile(); err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+	for name, w := range c.Context.Weights {
+		if w < 0 {
+			return fmt.Errorf("%s: context: weight of %q is negative", source, name)
+		}
+	}
+	for i := range c.PostProcessing {
+		if err := c.PostProcessing[i].compile(source); err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+	}
+	for i := range c.Branches {
+		if err := c.Branches[i].compile(source); err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+	}
+	for i, m := range c.Mirrors {
+		if m.Name == "" || m.URL == "" {
+			return fmt.Errorf("%s: mirror %d needs a name and a url", source, i+1)
+		}
+	}
+	for i, r := range c.Identities {
+		if r.Email == "" {
+			return fmt.Errorf("%s: identity rule %d has no email", source, i+1)
+		}
+		for _, p := range r.Match {
+			if _, err := path.Match(p, ""); err != nil {
+				return fmt.Errorf("%s: identity rule %q: invalid pattern %q", source, r.Email, p)
+			}
+		}
+	}
+	return nil
+}
+
+// merge returns base overridden by over.
+func merge(base, over *Config) *Config {
+	out := *base
+	out.Extends = over.Extends
+	out.Sources = append(append([]string{}, base.Sources...), over.Sources...)
+
+	if over.Provider != "" {
+		out.Provider = over.Provider
+	}
+
+	out.Models = make(map[string]string, len(base.Models)+len(over.Models))
+	for k, v := range base.Models {
+		out.Models[k] = v
+	}
+	for k, v := range over.Models {
+		out.Models[k] = v
+	}
+	out.DiffFormats = make(map[string]string, len(base.DiffFormats)+len(over.DiffFormats))
+	for k, v := range base.DiffFormats {
+		out.DiffFormats[k] = v
+	}
+	for k, v := range over.DiffFormats {
+		out.DiffFormats[k] = v
+	}
+
+	// An enforced policy, and the redaction rules it requires, come from the
+	// base alone.
+	enforced := base.Policy.Enforced
+	if !enforced && !over.Policy.isZero() {
+		out.Policy = over.Policy
+	}
+
+	out.Redact = append([]RedactRule{}, base.Redact...)
+	for _, r := range over.Redact {
+		if enforced && base.Policy.requiresRedaction(r.Name) {
+			continue
+		}
+		replaced := false
+		for i := range out.Redact {
+			if out.Redact[i].Name == r.Name {
+				out.Redact[i] = r
+				replaced = true
+			}
+		}
+		if !replaced {
+			out.Redact = append(out.Redact, r)
+		}
+	}
+
+	// Identity rules merge by canonical email, and the extending file's rules
+	// are tried first.
+	out.Identities = append([]IdentityRule{}, over.Identities...)
+	for _, r := range base.Identities {
+		overridden := false
+		for _, o := range over.Identities {
+			if strings.EqualFold(o.Email, r.Email) {
+				overridden = true
+			}
+		}
+		if !overridden {
+			out.Identities = append(out.Identities, r)
+		}
+	}
+
+	out.Mirrors = append([]Mirror{}, base.Mirrors...)
+	for _, m := range over.Mirrors {
+		replaced := false
+		for i := range out.Mirrors {
+			if out.Mirrors[i].Name == m.Name {
+				out.Mirrors[i] = m
+				replaced = true
+			}
+		}
+		if !replaced {
+			out.Mirrors = append(out.Mirrors, m)
+		}
+	}
+
+	out.PostProcessing = append([]PostStep{}, base.PostProcessing...)
+	for _, s := range over.PostProcessing {
+		replaced := false
+		for i := range out.PostProcessing {
+			if out.PostProcessing[i].Name == s.Name {
+				out.PostProcessing[i] = s
+				replaced = true
+			}
+		}
+		if !replaced {
+			out.PostProcessing = append(out.PostProcessing, s)
+		}
+	}
+
+	out.Branches = append([]BranchRule{}, base.Branches...)
+	for _, r := range over.Branches {
+		replaced := false
+		for i := range out.Branches {
+			if out.Branches[i].Name == r.Name {
+				out.Branches[i] = r
+				replaced = true
+			}
+		}
+		if !replaced {
+			out.Branches = append(out.Branches, r)
+		}
+	}
+
+	if over.Budget.MaxCommits != 0 {
+		out.Budget.MaxCommits = over.Budget.MaxCommits
+	}
+	if over.Budget.MaxTokens != 0 {
+		out.Budget.MaxTokens = over.Budget.MaxTokens
+	}
+
+	if over.Freshness.Rewritten != 0 {
+		out.Freshness.Rewritten = over.Freshness.Rewritten
+	}
+	if over.Freshness.TTL != 0 {
+		out.Freshness.TTL = over.Freshness.TTL
+	}
+
+	if over.Context.MaxTokens != 0 {
+		out.Context.MaxTokens = over.Context.MaxTokens
+	}
+	if over.Context.Reserve != 0 {
+		out.Context.Reserve = over.Context.Reserve
+	}
+	out.Context.Weights = make(map[string]float64, len(base.Context.Weights)+len(over.Context.Weights))
+	for k, v := range base.Context.Weights {
+		out.Context.Weights[k] = v
+	}
+	for k, v := range over.Context.Weights {
+		out.Context.Weights[k] = v
+	}
+
+	return &out
+}
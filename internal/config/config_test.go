@@ -0,0 +1,282 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes files, keyed by name, into a new temporary directory and
+// returns it.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Provider != "" || len(cfg.Sources) != 0 {
+		t.Errorf("Load of a missing file = %+v, want an empty config", cfg)
+	}
+}
+
+func TestLoadExtendsChain(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"shared/base.yaml": `
+provider: anthropic
+models:
+  default: base-default
+  annotate: base-annotate
+redact:
+  - name: token
+    pattern: tok_[a-z]+
+  - name: email
+    pattern: '[a-z]+@example\.com'
+mirrors:
+  - name: backup
+    url: git@backup.example.com:repo.git
+`,
+		"shared/team.yaml": `
+extends: base.yaml
+models:
+  annotate: team-annotate
+redact:
+  - name: email
+    pattern: '[a-z]+@team\.example\.com'
+`,
+		FileName: `
+extends:
+  source: shared/team.yaml
+provider: openai
+models:
+  why: repo-why
+`,
+	})
+
+	cfg, err := Load(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	want := []string{
+		filepath.Join(dir, "shared/base.yaml"),
+		filepath.Join(dir, "shared/team.yaml"),
+		filepath.Join(dir, FileName),
+	}
+	if !slices.Equal(cfg.Sources, want) {
+		t.Errorf("Sources = %q, want base first: %q", cfg.Sources, want)
+	}
+	if cfg.Provider != "openai" {
+		t.Errorf("Provider = %q, want the extending file's openai", cfg.Provider)
+	}
+	for command, want := range map[string]string{
+		"annotate": "team-annotate",
+		"why":      "repo-why",
+		"log":      "base-default",
+	} {
+		if got := cfg.Model(command, "fallback"); got != want {
+			t.Errorf("Model(%q) = %q, want %q", command, got, want)
+		}
+	}
+
+	var names []string
+	for _, r := range cfg.Redact {
+		names = append(names, r.Name)
+	}
+	if !slices.Equal(names, []string{"token", "email"}) {
+		t.Errorf("redact rules = %q, want token and email merged by name", names)
+	}
+	if got := cfg.RedactText("tok_abc a@team.example.com b@example.com"); got != "[REDACTED:token] [REDACTED:email] b@example.com" {
+		t.Errorf("RedactText = %q, want the team's email rule to replace the base's", got)
+	}
+	if len(cfg.Mirrors) != 1 || cfg.Mirrors[0].Name != "backup" {
+		t.Errorf("Mirrors = %+v, want the base's mirror", cfg.Mirrors)
+	}
+}
+
+func TestLoadExtendsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{
+				FileName: "extends: a.yaml\n",
+				"a.yaml": "extends: b.yaml\n",
+				"b.yaml": "extends: a.yaml\n",
+			},
+			want: "config extends cycle",
+		},
+		{
+			name: "self",
+			files: map[string]string{
+				FileName: "extends: " + FileName + "\n",
+			},
+			want: "config extends cycle",
+		},
+		{
+			name: "too deep",
+			files: map[string]string{
+				FileName: "extends: 1.yaml\n",
+				"1.yaml": "extends: 2.yaml\n",
+				"2.yaml": "extends: 3.yaml\n",
+				"3.yaml": "extends: 4.yaml\n",
+				"4.yaml": "extends: 5.yaml\n",
+				"5.yaml": "extends: 6.yaml\n",
+				"6.yaml": "extends: 7.yaml\n",
+				"7.yaml": "extends: 8.yaml\n",
+				"8.yaml": "provider: anthropic\n",
+			},
+			want: "deeper than 8",
+		},
+		{
+			name: "missing base",
+			files: map[string]string{
+				FileName: "extends: missing.yaml\n",
+			},
+			want: "extends:",
+		},
+		{
+			name: "pin mismatch",
+			files: map[string]string{
+				FileName:    "extends:\n  source: base.yaml\n  sha256: " + strings.Repeat("0", 64) + "\n",
+				"base.yaml": "provider: anthropic\n",
+			},
+			want: "pinned " + strings.Repeat("0", 64),
+		},
+		{
+			name: "plain http",
+			files: map[string]string{
+				FileName: "extends: http://config.example.com/base.yaml\n",
+			},
+			want: "only https:// URLs are supported",
+		},
+		{
+			name: "invalid base",
+			files: map[string]string{
+				FileName:    "extends: base.yaml\n",
+				"base.yaml": "redact:\n  - pattern: x\n",
+			},
+			want: "has no name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, tt.files)
+			_, err := Load(filepath.Join(dir, FileName))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Load error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadExtendsPin(t *testing.T) {
+	base := "provider: anthropic\n"
+	dir := writeFiles(t, map[string]string{
+		"base.yaml": base,
+		FileName:    "extends:\n  source: base.yaml\n  sha256: " + strings.ToUpper(digest([]byte(base))) + "\n",
+	})
+	cfg, err := Load(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatalf("Load with a matching pin: %v", err)
+	}
+	if cfg.Provider != "anthropic" {
+		t.Errorf("Provider = %q, want the pinned base's anthropic", cfg.Provider)
+	}
+}
+
+func TestLoadEnforcedPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		enforced bool
+		wantTier string
+		wantRule string
+	}{
+		{name: "enforced", enforced: true, wantTier: "haiku", wantRule: `[a-z]+@corp\.example`},
+		{name: "not enforced", enforced: false, wantTier: "opus", wantRule: "changed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enforced := "false"
+			if tt.enforced {
+				enforced = "true"
+			}
+			dir := writeFiles(t, map[string]string{
+				"base.yaml": `
+policy:
+  enforced: ` + enforced + `
+  max_model_tier: haiku
+  required_redactions: [email]
+redact:
+  - name: email
+    pattern: '[a-z]+@corp\.example'
+`,
+				FileName: `
+extends: base.yaml
+policy:
+  max_model_tier: opus
+redact:
+  - name: email
+    pattern: changed
+`,
+			})
+			cfg, err := Load(filepath.Join(dir, FileName))
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.Policy.MaxModelTier != tt.wantTier {
+				t.Errorf("MaxModelTier = %q, want %q", cfg.Policy.MaxModelTier, tt.wantTier)
+			}
+			if len(cfg.Redact) != 1 || cfg.Redact[0].Pattern != tt.wantRule {
+				t.Errorf("redact rules = %+v, want one with pattern %q", cfg.Redact, tt.wantRule)
+			}
+			if tt.enforced && cfg.Policy.Source != filepath.Join(dir, "base.yaml") {
+				t.Errorf("Policy.Source = %q, want the base that enforces it", cfg.Policy.Source)
+			}
+		})
+	}
+}
+
+func TestCheckAIForbiddenProviders(t *testing.T) {
+	cfg := &Config{Policy: Policy{ForbiddenProviders: []string{"Anthropic"}}}
+	for provider, forbidden := range map[string]bool{
+		"anthropic":  true,
+		"claude":     true,
+		" Claude ":   true,
+		"openai":     false,
+		"":           false,
+		"anthropic2": false,
+	} {
+		err := cfg.CheckAI(provider, "")
+		if (err != nil) != forbidden {
+			t.Errorf("CheckAI(%q) = %v, want forbidden %v", provider, err, forbidden)
+		}
+	}
+
+	// Aliases in the policy forbid the canonical name too.
+	cfg.Policy.ForbiddenProviders = []string{"claude"}
+	if err := cfg.CheckAI("anthropic", ""); err == nil {
+		t.Errorf("CheckAI(anthropic) with claude forbidden = nil, want a violation")
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-git/internal/logging"
+)
+
+// defaultTTL is how long an unpinned remote base is reused from the cache.
+const defaultTTL = time.Hour
+
+// maxRemoteSize bounds the size of a remote base config.
+const maxRemoteSize = 1 << 20
+
+// httpClient fetches remote bases.
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// fetchBase returns the location and contents of the base named by ext,
+// resolved relative to from.
+func fetchBase(from string, ext Extends) (string, []byte, error) {
+	source, err := resolveSource(from, ext.Source)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var data []byte
+	if isURL(source) {
+		data, err = fetchRemote(source, ext)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	if ext.SHA256 != "" {
+		if got := digest(data); !strings.EqualFold(got, ext.SHA256) {
+			return "", nil, fmt.Errorf("%s: sha256 is %s, pinned %s", source, got, ext.SHA256)
+		}
+	}
+	return source, data, nil
+}
+
+// resolveSource resolves ref against the file or URL that referenced it.
+// Remote configs may only extend other remote configs.
+func resolveSource(from, ref string) (string, error) {
+	if strings.HasPrefix(ref, "http://") {
+		return "", fmt.Errorf("%s: only https:// URLs are supported", ref)
+	}
+	if isURL(from) {
+		base, err := url.Parse(from)
+		if err != nil {
+			return "", err
+		}
+		u, err := base.Parse(ref)
+		if err != nil {
+			return "", err
+		}
+		if u.Scheme != "https" {
+			return "", fmt.Errorf("%s: remote configs can only extend https:// URLs", ref)
+		}
+		return u.String(), nil
+	}
+	if isURL(ref) {
+		return ref, nil
+	}
+	if strings.HasPrefix(ref, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, ref[2:]), nil
+		}
+	}
+	if filepath.IsAbs(ref) {
+		return ref, nil
+	}
+	return filepath.Join(filepath.Dir(from), ref), nil
+}
+
+// fetchRemote returns the contents of a remote base, using the on-disk cache
+// when it is fresh (or, for pinned bases, when it matches the pin). A stale
+// cache entry is used when the fetch fails.
+func fetchRemote(source string, ext Extends) ([]byte, error) {
+	cachePath := cacheFile(source)
+	cached, info := readCache(cachePath)
+
+	if cached != nil {
+		ttl := time.Duration(ext.TTL)
+		if ttl == 0 {
+			ttl = defaultTTL
+		}
+		switch {
+		case ext.SHA256 != "" && strings.EqualFold(digest(cached), ext.SHA256):
+			logging.L().Debug("Config cache hit", "source", source, "pinned", true)
+			return cached, nil
+		case ext.SHA256 == "" && time.Since(info.ModTime()) < ttl:
+			logging.L().Debug("Config cache hit", "source", source, "age", time.Since(info.ModTime()).Round(time.Second).String())
+			return cached, nil
+		}
+	}
+
+	data, err := download(source)
+	if err != nil {
+		if cached != nil {
+			logging.L().Warn("Using cached config after fetch failure", "source", source, "error", err)
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	if cachePath != "" {
+		if err := writeCache(cachePath, data); err != nil {
+			logging.L().Debug("Failed to cache config", "source", source, "error", err)
+		}
+	}
+	return data, nil
+}
+
+// download fetches source over HTTPS.
+func download(source string) ([]byte, error) {
+	resp, err := httpClient.Get(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", source, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	if len(data) > maxRemoteSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", source, maxRemoteSize)
+	}
+	return data, nil
+}
+
+// cacheFile returns where source is cached, or "" without a cache directory.
+func cacheFile(source string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "arc-git", "config", digest([]byte(source))+".yaml")
+}
+
+// readCache returns the cached contents at path, or nil.
+func readCache(path string) ([]byte, os.FileInfo) {
+	if path == "" {
+		return nil, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil
+	}
+	return data, info
+}
+
+// writeCache atomically stores data at path.
+func writeCache(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// isURL reports whether s is an https:// URL.
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "https://")
+}
+
+// digest returns the hex sha256 of data.
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// remoteConfigs serves files, keyed by path, over HTTPS and counts requests.
+// The package's HTTP client and cache directory are pointed at the server
+// and a temporary directory for the test.
+func remoteConfigs(t *testing.T, files map[string]string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		data, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(data))
+	}))
+	t.Cleanup(server.Close)
+
+	client := httpClient
+	httpClient = server.Client()
+	t.Cleanup(func() { httpClient = client })
+
+	cache := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cache)
+	t.Setenv("HOME", cache)
+	return server, &hits
+}
+
+func TestResolveSource(t *testing.T) {
+	tests := []struct {
+		from, ref string
+		want      string
+		wantErr   string
+	}{
+		{from: "/repo/.arc-git.yaml", ref: "shared/base.yaml", want: "/repo/shared/base.yaml"},
+		{from: "/repo/.arc-git.yaml", ref: "/etc/arc-git.yaml", want: "/etc/arc-git.yaml"},
+		{from: "/repo/.arc-git.yaml", ref: "https://cfg.example.com/base.yaml", want: "https://cfg.example.com/base.yaml"},
+		{from: "/repo/.arc-git.yaml", ref: "http://cfg.example.com/base.yaml", wantErr: "only https://"},
+		{from: "https://cfg.example.com/team/a.yaml", ref: "b.yaml", want: "https://cfg.example.com/team/b.yaml"},
+		{from: "https://cfg.example.com/team/a.yaml", ref: "../org.yaml", want: "https://cfg.example.com/org.yaml"},
+		// Remote configs cannot reach into the local disk.
+		{from: "https://cfg.example.com/team/a.yaml", ref: "/etc/passwd", want: "https://cfg.example.com/etc/passwd"},
+		{from: "https://cfg.example.com/team/a.yaml", ref: "file:///etc/passwd", wantErr: "can only extend https://"},
+	}
+	for _, tt := range tests {
+		got, err := resolveSource(tt.from, tt.ref)
+		switch {
+		case tt.wantErr != "":
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("resolveSource(%q, %q) error = %v, want %q", tt.from, tt.ref, err, tt.wantErr)
+			}
+		case err != nil:
+			t.Errorf("resolveSource(%q, %q): %v", tt.from, tt.ref, err)
+		case got != tt.want:
+			t.Errorf("resolveSource(%q, %q) = %q, want %q", tt.from, tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestLoadRemoteChain(t *testing.T) {
+	server, _ := remoteConfigs(t, map[string]string{
+		"/org.yaml":       "provider: anthropic\nmodels:\n  default: org-default\n",
+		"/team/base.yaml": "extends: ../org.yaml\nmodels:\n  annotate: team-annotate\n",
+	})
+	dir := writeFiles(t, map[string]string{
+		FileName: "extends: " + server.URL + "/team/base.yaml\n",
+	})
+
+	cfg, err := Load(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Provider != "anthropic" || cfg.Model("annotate", "") != "team-annotate" || cfg.Model("why", "") != "org-default" {
+		t.Errorf("Load = provider %q, models %v; want the merged remote chain", cfg.Provider, cfg.Models)
+	}
+	if len(cfg.Sources) != 3 || cfg.Sources[0] != server.URL+"/org.yaml" {
+		t.Errorf("Sources = %q, want the org base first", cfg.Sources)
+	}
+}
+
+func TestFetchRemotePin(t *testing.T) {
+	files := map[string]string{"/base.yaml": "provider: anthropic\n"}
+	server, hits := remoteConfigs(t, files)
+	source := server.URL + "/base.yaml"
+	pin := digest([]byte(files["/base.yaml"]))
+
+	if _, _, err := fetchBase("/repo/.arc-git.yaml", Extends{Source: source, SHA256: strings.Repeat("0", 64)}); err == nil || !strings.Contains(err.Error(), "pinned") {
+		t.Fatalf("fetchBase with a wrong pin: error = %v, want a pin mismatch", err)
+	}
+
+	// A matching pin is served from the cache however old it is, even
+	// when the server has changed the file since.
+	if _, _, err := fetchBase("/repo/.arc-git.yaml", Extends{Source: source, SHA256: pin}); err != nil {
+		t.Fatalf("fetchBase with a matching pin: %v", err)
+	}
+	before := hits.Load()
+	files["/base.yaml"] = "provider: openai\n"
+	ageCache(t, source, 48*time.Hour)
+	_, data, err := fetchBase("/repo/.arc-git.yaml", Extends{Source: source, SHA256: pin})
+	if err != nil {
+		t.Fatalf("fetchBase from the cache: %v", err)
+	}
+	if string(data) != "provider: anthropic\n" || hits.Load() != before {
+		t.Errorf("fetchBase = %q after %d requests, want the pinned content from the cache", data, hits.Load()-before)
+	}
+
+	// The changed file no longer matches the pin once the cache is gone.
+	if err := os.Remove(cacheFile(source)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := fetchBase("/repo/.arc-git.yaml", Extends{Source: source, SHA256: pin}); err == nil || !strings.Contains(err.Error(), "pinned") {
+		t.Errorf("fetchBase of changed content: error = %v, want a pin mismatch", err)
+	}
+}
+
+func TestFetchRemoteTTL(t *testing.T) {
+	files := map[string]string{"/base.yaml": "provider: anthropic\n"}
+	server, hits := remoteConfigs(t, files)
+	source := server.URL + "/base.yaml"
+	ext := Extends{Source: source, TTL: Duration(time.Hour)}
+
+	if _, err := fetchRemote(source, ext); err != nil {
+		t.Fatalf("fetchRemote: %v", err)
+	}
+	if _, err := fetchRemote(source, ext); err != nil {
+		t.Fatalf("fetchRemote within the TTL: %v", err)
+	}
+	if hits.Load() != 1 {
+		t.Errorf("requests = %d, want 1: a fresh cache entry is reused", hits.Load())
+	}
+
+	files["/base.yaml"] = "provider: openai\n"
+	ageCache(t, source, 2*time.Hour)
+	data, err := fetchRemote(source, ext)
+	if err != nil {
+		t.Fatalf("fetchRemote after the TTL: %v", err)
+	}
+	if string(data) != "provider: openai\n" || hits.Load() != 2 {
+		t.Errorf("fetchRemote = %q after %d requests, want the refetched content", data, hits.Load())
+	}
+
+	// A stale entry stands in when the server fails.
+	delete(files, "/base.yaml")
+	ageCache(t, source, 2*time.Hour)
+	data, err = fetchRemote(source, ext)
+	if err != nil || string(data) != "provider: openai\n" {
+		t.Errorf("fetchRemote with the server failing = %q, %v; want the stale cache", data, err)
+	}
+
+	if _, err := fetchRemote(server.URL+"/missing.yaml", ext); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("fetchRemote of a missing file without a cache: error = %v, want 404", err)
+	}
+}
+
+// ageCache backdates the cache entry of source by age.
+func ageCache(t *testing.T, source string, age time.Duration) {
+	t.Helper()
+	old := time.Now().Add(-age)
+	if err := os.Chtimes(cacheFile(source), old, old); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultRewritten is the share of rewritten code that makes an annotation
+// historical when the freshness block does not set one.
+const DefaultRewritten = 0.5
+
+// Freshness marks annotations as historical context once the code they
+// describe has been rewritten, for fast-moving code where a commit's
+// explanation soon stops describing what is in the tree:
+//
+//	freshness:
+//	  rewritten: 0.6   # share of the code the commit touched
+//	  ttl: 4320h       # 180 days
+//
+// Without the block, log and why show annotations as they are.
+type Freshness struct {
+	// Rewritten is the share, from 0 to 1, of the lines of the files a
+	// commit touched that later commits must have changed or removed for its
+	// annotation to be historical. Zero uses DefaultRewritten.
+	Rewritten float64 `yaml:"rewritten,omitempty"`
+	// TTL is the age after which an annotation is historical as soon as any
+	// line of those files changed.
+	TTL Duration `yaml:"ttl,omitempty"`
+}
+
+// compile validates the block.
+func (f Freshness) compile() error {
+	if f.Rewritten < 0 || f.Rewritten > 1 {
+		return fmt.Errorf("freshness: rewritten must be between 0 and 1, not %g", f.Rewritten)
+	}
+	if f.TTL < 0 {
+		return fmt.Errorf("freshness: ttl is negative")
+	}
+	return nil
+}
+
+// Enabled reports whether the block is set.
+func (f Freshness) Enabled() bool {
+	return f.Rewritten != 0 || f.TTL != 0
+}
+
+// Threshold returns the share of rewritten code that makes an annotation
+// historical.
+func (f Freshness) Threshold() float64 {
+	if f.Rewritten == 0 {
+		return DefaultRewritten
+	}
+	return f.Rewritten
+}
+
+// Expired reports whether an annotation written at written is past the TTL.
+func (f Freshness) Expired(written, now time.Time) bool {
+	return f.TTL > 0 && now.Sub(written) > time.Duration(f.TTL)
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PostStep is one step of the post-processing chain that generated text goes
+// through before it is written to notes, forges, or reports. Each step does
+// exactly one of: replace matches of Pattern, normalize Glossary terms, Mask
+// words, or cut the text to MaxChars.
+type PostStep struct {
+	Name string `yaml:"name"`
+	// Commands limits the step to these commands, named as in models (e.g.
+	// "annotate" or "release-publish"); empty applies it to every command.
+	Commands []string `yaml:"commands,omitempty"`
+
+	// Pattern and Replacement rewrite text, e.g. links to an old host.
+	Pattern     string `yaml:"pattern,omitempty"`
+	Replacement string `yaml:"replacement,omitempty"`
+	// Glossary maps terms to their preferred spelling; GlossaryFile, a YAML
+	// map given as a path relative to the config file or an HTTPS URL, adds
+	// more. Terms match whole words, case-insensitively.
+	Glossary     map[string]string `yaml:"glossary,omitempty"`
+	GlossaryFile string            `yaml:"glossary_file,omitempty"`
+	// Mask lists words replaced by asterisks, matched like glossary terms.
+	Mask []string `yaml:"mask,omitempty"`
+	// MaxChars cuts longer text at the last sentence end that fits.
+	MaxChars int `yaml:"max_chars,omitempty"`
+
+	re    *regexp.Regexp
+	terms []glossaryTerm
+}
+
+// glossaryTerm is a compiled glossary entry.
+type glossaryTerm struct {
+	re        *regexp.Regexp
+	preferred string
+}
+
+// compile validates the step and prepares its patterns. source is the config
+// file or URL the step was defined in.
+func (s *PostStep) compile(source string) error {
+	if s.Name == "" {
+		return fmt.Errorf("postprocess step has no name")
+	}
+	kinds := 0
+	for _, set := range []bool{s.Pattern != "", len(s.Glossary) > 0 || s.GlossaryFile != "", len(s.Mask) > 0, s.MaxChars != 0} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return fmt.Errorf("postprocess step %q needs exactly one of pattern, glossary, mask, or max_chars", s.Name)
+	}
+
+	switch {
+	case s.Pattern != "":
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("postprocess step %q: %w", s.Name, err)
+		}
+		s.re = re
+	case s.MaxChars < 0:
+		return fmt.Errorf("postprocess step %q: max_chars is negative", s.Name)
+	case len(s.Mask) > 0:
+		words := make([]string, 0, len(s.Mask))
+		for _, w := range s.Mask {
+			words = append(words, regexp.QuoteMeta(w))
+		}
+		s.re = regexp.MustCompile(`(?i)\b(?:` + strings.Join(words, "|") + `)\b`)
+	case len(s.Glossary) > 0 || s.GlossaryFile != "":
+		terms := make(map[string]string, len(s.Glossary))
+		if s.GlossaryFile != "" {
+			file, err := loadGlossary(source, s.GlossaryFile)
+			if err != nil {
+				return fmt.Errorf("postprocess step %q: %w", s.Name, err)
+			}
+			for k, v := range file {
+				terms[k] = v
+			}
+		}
+		for k, v := range s.Glossary {
+			terms[k] = v
+		}
+		s.terms = compileGlossary(terms)
+	}
+	return nil
+}
+
+// loadGlossary reads a YAML map of terms from ref, resolved against source.
+func loadGlossary(source, ref string) (map[string]string, error) {
+	target, err := resolveSource(source, ref)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	if isURL(target) {
+		data, err = fetchRemote(target, Extends{})
+	} else {
+		data, err = os.ReadFile(target)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var terms map[string]string
+	if err := yaml.Unmarshal(data, &terms); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", target, err)
+	}
+	return terms, nil
+}
+
+// compileGlossary compiles terms, longest first so that "pull request" is
+// normalized before "pull".
+func compileGlossary(terms map[string]string) []glossaryTerm {
+	keys := make([]string, 0, len(terms))
+	for k := range terms {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	compiled := make([]glossaryTerm, 0, len(keys))
+	for _, k := range keys {
+		compiled = append(compiled, glossaryTerm{
+			re:        regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(k) + `\b`),
+			preferred: terms[k],
+		})
+	}
+	return compiled
+}
+
+// applies reports whether the step runs for command.
+func (s *PostStep) applies(command string) bool {
+	return len(s.Commands) == 0 || slices.Contains(s.Commands, command)
+}
+
+// apply runs the step on text.
+func (s *PostStep) apply(text string) string {
+	switch {
+	case s.Pattern != "":
+		return s.re.ReplaceAllString(text, s.Replacement)
+	case len(s.Mask) > 0:
+		return s.re.ReplaceAllStringFunc(text, func(w string) string { return strings.Repeat("*", len(w)) })
+	case s.MaxChars > 0:
+		return cutText(text, s.MaxChars)
+	default:
+		for _, t := range s.terms {
+			text = t.re.ReplaceAllLiteralString(text, t.preferred)
+		}
+		return text
+	}
+}
+
+// cutText shortens text to at most maxChars, ending at the last sentence end
+// that fits, or at the last word boundary with an ellipsis.
+func cutText(text string, maxChars int) string {
+	if len(text) <= maxChars {
+		return text
+	}
+	head := text[:maxChars]
+	if i := strings.LastIndexAny(head, ".!?\n"); i >= maxChars/2 {
+		return strings.TrimSpace(head[:i+1])
+	}
+	if i := strings.LastIndex(head[:maxChars-len("…")], " "); i > 0 {
+		return head[:i] + "…"
+	}
+	return strings.ToValidUTF8(head[:maxChars-len("…")], "") + "…"
+}
+
+// PostProcess runs the post-processing steps that apply to command on text,
+// in order.
+func (c *Config) PostProcess(command, text string) string {
+	for i := range c.PostProcessing {
+		if s := &c.PostProcessing[i]; s.applies(command) {
+			text = s.apply(text)
+		}
+	}
+	return text
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ThirdPartyModes lists the modes of the third_party policy rule: "exclude"
+// always leaves third-party code out of prompts, and "opt-in" does unless a
+// run passes --allow-third-party.
+var ThirdPartyModes = []string{"exclude", "opt-in"}
+
+// VendorDirs are directory names that hold third-party code wherever they
+// appear in a path.
+var VendorDirs = []string{"vendor", "third_party", "third-party", "thirdparty", "node_modules", "bower_components", "Pods", "Carthage"}
+
+// ThirdParty is the policy rule that keeps vendored third-party code, whose
+// license may forbid sending it to external services, out of AI prompts.
+// A file is third-party code when it is under one of VendorDirs or Paths,
+// or when its license header names a license or copyright holder other than
+// the repository's own.
+type ThirdParty struct {
+	// Mode is one of ThirdPartyModes.
+	Mode string `yaml:"mode"`
+	// Paths lists further globs of third-party files, where "*" does not
+	// cross "/" and a trailing "/**" covers a whole directory.
+	Paths []string `yaml:"paths,omitempty"`
+	// Licenses lists the SPDX identifiers of the repository's own code.
+	// Files whose SPDX-License-Identifier names any other are third-party.
+	Licenses []string `yaml:"licenses,omitempty"`
+	// Owners lists the repository's copyright holders. Files with a
+	// Copyright line that names none of them are third-party.
+	Owners []string `yaml:"owners,omitempty"`
+}
+
+var (
+	spdxPattern      = regexp.MustCompile(`SPDX-License-Identifier:\s*([^\n]+)`)
+	copyrightPattern = regexp.MustCompile(`(?i)\bcopyright\s+(?:\(c\)|©|\d{4})[^\n]*`)
+	// spdxOperators are the keywords of SPDX license expressions.
+	spdxOperators = []string{"AND", "OR", "WITH"}
+)
+
+// headerLines is how much of the top of a file a license header is looked
+// for in.
+const headerLines = 30
+
+// compile validates the rule.
+func (t ThirdParty) compile() error {
+	if t.Mode == "" {
+		if len(t.Paths) > 0 || len(t.Licenses) > 0 || len(t.Owners) > 0 {
+			return fmt.Errorf("third_party has no mode (use %s)", strings.Join(ThirdPartyModes, ", "))
+		}
+		return nil
+	}
+	if !slices.Contains(ThirdPartyModes, t.Mode) {
+		return fmt.Errorf("third_party: unknown mode %q (use %s)", t.Mode, strings.Join(ThirdPartyModes, ", "))
+	}
+	for _, p := range t.Paths {
+		if _, err := path.Match(strings.TrimSuffix(p, "/**"), ""); err != nil {
+			return fmt.Errorf("third_party: invalid pattern %q", p)
+		}
+	}
+	return nil
+}
+
+// Enabled reports whether the rule is set.
+func (t ThirdParty) Enabled() bool {
+	return t.Mode != ""
+}
+
+// Vendored returns why file, a path relative to the work tree root, is
+// third-party code by its location, or "".
+func (t ThirdParty) Vendored(file string) string {
+	parts := strings.Split(file, "/")
+	for _, dir := range parts[:len(parts)-1] {
+		if slices.Contains(VendorDirs, dir) {
+			return "vendored under " + dir + "/"
+		}
+	}
+	for _, p := range t.Paths {
+		if dir, ok := strings.CutSuffix(p, "/**"); ok {
+			for i := 1; i < len(parts); i++ {
+				if ok, _ := path.Match(dir, strings.Join(parts[:i], "/")); ok {
+					return "matches " + p
+				}
+			}
+			continue
+		}
+		if ok, _ := path.Match(p, file); ok {
+			return "matches " + p
+		}
+	}
+	return ""
+}
+
+// Licensed returns why the top of a file, given as header, marks it as
+// third-party code, or "".
+func (t ThirdParty) Licensed(header string) string {
+	lines := strings.SplitN(header, "\n", headerLines+1)
+	header = strings.Join(lines[:min(len(lines), headerLines)], "\n")
+
+	if len(t.Licenses) > 0 {
+		for _, m := range spdxPattern.FindAllStringSubmatch(header, -1) {
+			for _, id := range spdxIdentifiers(m[1]) {
+				if !slices.ContainsFunc(t.Licenses, func(l string) bool { return strings.EqualFold(l, id) }) {
+					return "licensed " + id
+				}
+			}
+		}
+	}
+	if len(t.Owners) > 0 {
+		for _, line := range copyrightPattern.FindAllString(header, -1) {
+			owned := slices.ContainsFunc(t.Owners, func(o string) bool {
+				return strings.Contains(strings.ToLower(line), strings.ToLower(o))
+			})
+			if !owned {
+				return "copyright of another holder (" + strings.TrimSpace(line) + ")"
+			}
+		}
+	}
+	return ""
+}
+
+// spdxIdentifiers returns the license identifiers of an SPDX expression,
+// without its operators and license exceptions.
+func spdxIdentifiers(expr string) []string {
+	// Expressions end where the comment does.
+	if i := strings.IndexAny(expr, "*;'\"<>"); i >= 0 {
+		expr = expr[:i]
+	}
+	expr = strings.TrimRight(expr, " \t\r-")
+	expr = strings.NewReplacer("(", " ", ")", " ").Replace(expr)
+
+	var ids []string
+	fields := strings.Fields(expr)
+	for i := 0; i < len(fields); i++ {
+		switch f := fields[i]; {
+		case strings.EqualFold(f, "WITH"):
+			i++
+		case slices.Contains(spdxOperators, strings.ToUpper(f)):
+		default:
+			ids = append(ids, f)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestThirdPartyVendored(t *testing.T) {
+	tp := ThirdParty{Mode: "exclude", Paths: []string{"libs/acme-sdk/**", "assets/*.min.js", "gen/*/**"}}
+	tests := []struct {
+		file string
+		want string
+	}{
+		{file: "vendor/github.com/pkg/errors/errors.go", want: "vendored under vendor/"},
+		{file: "web/node_modules/left-pad/index.js", want: "vendored under node_modules/"},
+		{file: "ios/Pods/Alamofire/Session.swift", want: "vendored under Pods/"},
+		{file: "libs/acme-sdk/client.go", want: "matches libs/acme-sdk/**"},
+		{file: "libs/acme-sdk/deep/er/file.go", want: "matches libs/acme-sdk/**"},
+		{file: "assets/jquery.min.js", want: "matches assets/*.min.js"},
+		{file: "gen/proto/api.pb.go", want: "matches gen/*/**"},
+		// A file named like a vendor directory is not in one.
+		{file: "vendor"},
+		{file: "cmd/vendor.go"},
+		{file: "vendored/lib.go"},
+		{file: "libs/acme-sdk-extra/client.go"},
+		{file: "assets/sub/jquery.min.js"},
+		{file: "main.go"},
+	}
+	for _, tt := range tests {
+		if got := tp.Vendored(tt.file); got != tt.want {
+			t.Errorf("Vendored(%q) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestSPDXIdentifiers(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{expr: "MIT", want: []string{"MIT"}},
+		{expr: "Apache-2.0 OR MIT", want: []string{"Apache-2.0", "MIT"}},
+		{expr: "(GPL-2.0-only WITH Classpath-exception-2.0) and BSD-3-Clause", want: []string{"GPL-2.0-only", "BSD-3-Clause"}},
+		{expr: "GPL-3.0-or-later */", want: []string{"GPL-3.0-or-later"}},
+		{expr: `MPL-2.0" -->`, want: []string{"MPL-2.0"}},
+		{expr: "BSD-2-Clause --", want: []string{"BSD-2-Clause"}},
+		{expr: "ISC;", want: []string{"ISC"}},
+		{expr: "   ", want: nil},
+	}
+	for _, tt := range tests {
+		if got := spdxIdentifiers(tt.expr); !slices.Equal(got, tt.want) {
+			t.Errorf("spdxIdentifiers(%q) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestThirdPartyLicensed(t *testing.T) {
+	tp := ThirdParty{Mode: "exclude", Licenses: []string{"MIT"}, Owners: []string{"Arc Engineering"}}
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "own", header: "// Copyright (c) 2025 Arc Engineering\n// SPDX-License-Identifier: mit\n"},
+		{name: "other license", header: "// SPDX-License-Identifier: MIT OR GPL-2.0-only\n", want: "licensed GPL-2.0-only"},
+		{name: "other holder", header: "/* Copyright 2019 Acme Corp. */\n", want: "copyright of another holder (Copyright 2019 Acme Corp. */)"},
+		{name: "no header", header: "package main\n"},
+		{name: "below the header", header: strings.Repeat("// line\n", headerLines) + "// SPDX-License-Identifier: GPL-2.0-only\n"},
+	}
+	for _, tt := range tests {
+		if got := tp.Licensed(tt.header); got != tt.want {
+			t.Errorf("%s: Licensed = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Workspace lists the repositories that organization-level commands read,
+// as given in a workspace file:
+//
+//	name: Acme Engineering
+//	repos:
+//	  - path: ../api          # relative to the workspace file
+//	    team: platform
+//	  - name: storefront
+//	    path: ~/src/web
+//	    rev: origin/main      # default HEAD
+type Workspace struct {
+	Name  string          `yaml:"name,omitempty"`
+	Repos []WorkspaceRepo `yaml:"repos"`
+
+	// Source is the workspace file.
+	Source string `yaml:"-"`
+}
+
+// WorkspaceRepo is one repository of a workspace.
+type WorkspaceRepo struct {
+	// Name defaults to the last element of Path.
+	Name string `yaml:"name,omitempty"`
+	// Path is the repository's work tree, absolute once loaded.
+	Path string `yaml:"path"`
+	Team string `yaml:"team,omitempty"`
+	// Rev is the branch or commit whose history is read; the default is
+	// the repository's HEAD.
+	Rev string `yaml:"rev,omitempty"`
+}
+
+// LoadWorkspace reads a workspace file, resolving repository paths against
+// its directory.
+func LoadWorkspace(file string) (*Workspace, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var ws Workspace
+	if err := yaml.Unmarshal(data, &ws); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	if len(ws.Repos) == 0 {
+		return nil, fmt.Errorf("%s lists no repos", file)
+	}
+	seen := make(map[string]bool, len(ws.Repos))
+	for i := range ws.Repos {
+		r := &ws.Repos[i]
+		if r.Path == "" {
+			return nil, fmt.Errorf("%s: repo %d has no path", file, i+1)
+		}
+		switch {
+		case strings.HasPrefix(r.Path, "~/"):
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, err
+			}
+			r.Path = filepath.Join(home, r.Path[2:])
+		case !filepath.IsAbs(r.Path):
+			r.Path = filepath.Join(filepath.Dir(abs), r.Path)
+		}
+		if r.Name == "" {
+			r.Name = filepath.Base(r.Path)
+		}
+		if r.Rev == "" {
+			r.Rev = "HEAD"
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("%s: two repos are named %q; set name on one of them", file, r.Name)
+		}
+		seen[r.Name] = true
+	}
+	ws.Source = abs
+	return &ws, nil
+}
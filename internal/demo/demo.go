@@ -0,0 +1,469 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package demo holds the sample repository arc-git demo tours: a small Go
+// rate limiter with a hand-written history, and the annotations a model gave
+// its commits, which the tour replays so that it needs neither network nor
+// API key. Authors and dates are fixed, so every build has the same hashes.
+package demo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/yourorg/arc-git/internal/git"
+)
+
+// author is a commit identity.
+type author struct {
+	name  string
+	email string
+}
+
+var (
+	dana = author{"Dana Whitfield", "dana@example.com"}
+	sam  = author{"Sam Okafor", "sam@example.com"}
+)
+
+// commit is one commit of the sample history.
+type commit struct {
+	author  author
+	subject string
+	body    string
+	// files holds the whole contents of the files the commit adds or changes.
+	files map[string]string
+	// annotation is the recorded reply to the commit's annotation prompt.
+	annotation string
+}
+
+// startTime is the date of the first commit, 2024-03-04 09:00 UTC; the
+// others follow a day and a half apart.
+const (
+	startTime = 1709542800
+	interval  = 36 * 3600
+)
+
+// Build creates the sample repository at dir, which must not exist or be
+// empty, and returns its HEAD commit.
+func Build(ctx context.Context, dir string) (string, error) {
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return "", fmt.Errorf("%s is not empty", dir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	// git init -b needs git 2.28; point HEAD at main by hand instead. The
+	// notes the tour writes need a committer, which a fresh machine may not
+	// have configured.
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"symbolic-ref", "HEAD", "refs/heads/main"},
+		{"config", "user.name", "arc-git demo"},
+		{"config", "user.email", "demo@example.com"},
+	} {
+		if _, err := git.RunIn(ctx, dir, "", args...); err != nil {
+			return "", err
+		}
+	}
+	if _, err := git.RunIn(ctx, dir, stream(), "fast-import", "--quiet"); err != nil {
+		return "", err
+	}
+	if _, err := git.RunIn(ctx, dir, "", "reset", "-q", "--hard"); err != nil {
+		return "", err
+	}
+	head, err := git.RunIn(ctx, dir, "", "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(head), nil
+}
+
+// stream returns the fast-import stream of the history.
+func stream() string {
+	var b strings.Builder
+	data := func(s string) {
+		fmt.Fprintf(&b, "data %d\n%s\n", len(s), s)
+	}
+	for i, c := range history {
+		when := startTime + i*interval
+		fmt.Fprintf(&b, "commit refs/heads/main\nmark :%d\n", i+1)
+		fmt.Fprintf(&b, "author %s <%s> %d +0000\n", c.author.name, c.author.email, when)
+		fmt.Fprintf(&b, "committer %s <%s> %d +0000\n", c.author.name, c.author.email, when)
+		message := c.subject + "\n"
+		if c.body != "" {
+			message += "\n" + c.body + "\n"
+		}
+		data(message)
+		if i > 0 {
+			fmt.Fprintf(&b, "from :%d\n", i)
+		}
+		paths := make([]string, 0, len(c.files))
+		for p := range c.files {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			fmt.Fprintf(&b, "M 100644 inline %s\n", p)
+			data(c.files[p])
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Commits returns the number of commits of the sample history.
+func Commits() int {
+	return len(history)
+}
+
+// Replies returns the recorded annotation of each commit, keyed by its
+// subject.
+func Replies() map[string]string {
+	replies := make(map[string]string, len(history))
+	for _, c := range history {
+		replies[c.subject] = c.annotation
+	}
+	return replies
+}
+
+// Spotlight returns the file and line the tour asks arc-git why about: the
+// lock taken in Bucket.Allow, which the race fix added.
+func Spotlight() (string, int) {
+	const file, want = "bucket.go", "\tb.mu.Lock()"
+	var src string
+	for _, c := range history {
+		if s, ok := c.files[file]; ok {
+			src = s
+		}
+	}
+	for i, line := range strings.Split(src, "\n") {
+		if line == want {
+			return file, i + 1
+		}
+	}
+	return file, 1
+}
+
+// history is the sample history, oldest first.
+var history = []commit{
+	{
+		author:  dana,
+		subject: "Add token bucket rate limiter",
+		files: map[string]string{
+			"go.mod": "module example.com/ratelimit\n\ngo 1.22\n",
+			"README.md": `# ratelimit
+
+Token bucket rate limiting for Go services.
+`,
+			"bucket.go": `// Package ratelimit limits how often clients may act, with token buckets.
+package ratelimit
+
+import "time"
+
+// Bucket is a token bucket: it holds up to Burst tokens and gains Rate
+// tokens per second. Each allowed request takes one.
+type Bucket struct {
+	Rate  float64
+	Burst float64
+
+	tokens float64
+	last   time.Time
+}
+
+// NewBucket returns a full bucket.
+func NewBucket(rate, burst float64) *Bucket {
+	return &Bucket{Rate: rate, Burst: burst, tokens: burst, last: time.Now()}
+}
+
+// Allow reports whether a request may proceed now, taking a token if so.
+func (b *Bucket) Allow() bool {
+	b.refill(time.Now())
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// refill adds the tokens earned since the last call.
+func (b *Bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	b.tokens = min(b.Burst, b.tokens+elapsed*b.Rate)
+	b.last = now
+}
+`,
+		},
+		annotation: "Introduces the package's core primitive, a token bucket in bucket.go that refills lazily: `Bucket.Allow` credits the tokens earned since the previous call before spending one, so no background timer is needed. Buckets start full, which lets a new caller burst immediately. The bucket has no locking, so it is only safe for use from one goroutine at a time.\n\nRisk: low\nLabels: feature",
+	},
+	{
+		author:  sam,
+		subject: "Limit each client separately",
+		body:    "Services need one budget per API key rather than a global one.",
+		files: map[string]string{
+			"limiter.go": `package ratelimit
+
+import "sync"
+
+// Limiter keeps a bucket per client, created on first use.
+type Limiter struct {
+	rate, burst float64
+
+	mu      sync.Mutex
+	buckets map[string]*Bucket
+}
+
+// NewLimiter returns a limiter giving each client rate requests per second,
+// in bursts of up to burst.
+func NewLimiter(rate, burst float64) *Limiter {
+	return &Limiter{rate: rate, burst: burst, buckets: make(map[string]*Bucket)}
+}
+
+// Allow reports whether client may make a request now.
+func (l *Limiter) Allow(client string) bool {
+	l.mu.Lock()
+	b, ok := l.buckets[client]
+	if !ok {
+		b = NewBucket(l.rate, l.burst)
+		l.buckets[client] = b
+	}
+	l.mu.Unlock()
+	return b.Allow()
+}
+`,
+		},
+		annotation: "Adds `Limiter` in limiter.go, which hands every client its own bucket, created on first use, so that one noisy API key cannot exhaust the budget of the others. The map is guarded by a mutex, but the lock is released before `Bucket.Allow` runs, so concurrent requests from the same client reach the unsynchronized bucket at once. Memory grows with the number of distinct clients, as buckets are never removed.\n\nRisk: medium\nLabels: feature",
+	},
+	{
+		author:  dana,
+		subject: "Fix data race in bucket refill",
+		body:    "Limiter.Allow calls Bucket.Allow after releasing its own lock, so two\nrequests from one client could both refill and spend the same tokens.\nFound with go test -race under load.",
+		files: map[string]string{
+			"bucket.go": `// Package ratelimit limits how often clients may act, with token buckets.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Bucket is a token bucket: it holds up to Burst tokens and gains Rate
+// tokens per second. Each allowed request takes one. Buckets are safe for
+// concurrent use.
+type Bucket struct {
+	Rate  float64
+	Burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewBucket returns a full bucket.
+func NewBucket(rate, burst float64) *Bucket {
+	return &Bucket{Rate: rate, Burst: burst, tokens: burst, last: time.Now()}
+}
+
+// Allow reports whether a request may proceed now, taking a token if so.
+func (b *Bucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(time.Now())
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// refill adds the tokens earned since the last call; b.mu must be held.
+func (b *Bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	b.tokens = min(b.Burst, b.tokens+elapsed*b.Rate)
+	b.last = now
+}
+`,
+		},
+		annotation: "Fixes a data race between concurrent goroutines serving the same client: `Bucket.Allow` now holds a per-bucket mutex across the refill and the spend, where before two requests could read the same token count and both be let through, letting clients exceed their limit under load. The lock lives in the bucket rather than in `Limiter`, so requests from different clients still proceed in parallel. Callers of refill must now hold the lock, which the doc comment records.\n\nRisk: high\nLabels: bugfix, concurrency",
+	},
+	{
+		author:  sam,
+		subject: "Count rejected requests",
+		files: map[string]string{
+			"limiter.go": `package ratelimit
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Limiter keeps a bucket per client, created on first use.
+type Limiter struct {
+	rate, burst float64
+
+	mu       sync.Mutex
+	buckets  map[string]*Bucket
+	rejected atomic.Uint64
+}
+
+// NewLimiter returns a limiter giving each client rate requests per second,
+// in bursts of up to burst.
+func NewLimiter(rate, burst float64) *Limiter {
+	return &Limiter{rate: rate, burst: burst, buckets: make(map[string]*Bucket)}
+}
+
+// Allow reports whether client may make a request now.
+func (l *Limiter) Allow(client string) bool {
+	l.mu.Lock()
+	b, ok := l.buckets[client]
+	if !ok {
+		b = NewBucket(l.rate, l.burst)
+		l.buckets[client] = b
+	}
+	l.mu.Unlock()
+	if !b.Allow() {
+		l.rejected.Add(1)
+		return false
+	}
+	return true
+}
+
+// Rejected returns how many requests Allow has turned down.
+func (l *Limiter) Rejected() uint64 {
+	return l.rejected.Load()
+}
+`,
+		},
+		annotation: "Adds a rejection counter to `Limiter`, exposed through `Limiter.Rejected`, so that services can export how often clients hit their limit. The counter is an atomic rather than a field under the limiter's mutex, which keeps the hot path of `Limiter.Allow` from taking the lock a second time.\n\nRisk: low\nLabels: feature, observability",
+	},
+	{
+		author:  dana,
+		subject: "Evict idle clients",
+		body:    "Long-running services accumulated a bucket for every client they had\never seen.",
+		files: map[string]string{
+			"limiter.go": `package ratelimit
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Limiter keeps a bucket per client, created on first use.
+type Limiter struct {
+	rate, burst float64
+
+	mu       sync.Mutex
+	buckets  map[string]*Bucket
+	seen     map[string]time.Time
+	rejected atomic.Uint64
+}
+
+// NewLimiter returns a limiter giving each client rate requests per second,
+// in bursts of up to burst.
+func NewLimiter(rate, burst float64) *Limiter {
+	return &Limiter{
+		rate:    rate,
+		burst:   burst,
+		buckets: make(map[string]*Bucket),
+		seen:    make(map[string]time.Time),
+	}
+}
+
+// Allow reports whether client may make a request now.
+func (l *Limiter) Allow(client string) bool {
+	l.mu.Lock()
+	b, ok := l.buckets[client]
+	if !ok {
+		b = NewBucket(l.rate, l.burst)
+		l.buckets[client] = b
+	}
+	l.seen[client] = time.Now()
+	l.mu.Unlock()
+	if !b.Allow() {
+		l.rejected.Add(1)
+		return false
+	}
+	return true
+}
+
+// Rejected returns how many requests Allow has turned down.
+func (l *Limiter) Rejected() uint64 {
+	return l.rejected.Load()
+}
+
+// Evict forgets the clients that made no request for idle, so that the
+// buckets of one-off clients do not pile up, and returns how many it forgot.
+// Run it periodically.
+func (l *Limiter) Evict(idle time.Duration) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	cutoff := time.Now().Add(-idle)
+	evicted := 0
+	for client, last := range l.seen {
+		if last.Before(cutoff) {
+			delete(l.buckets, client)
+			delete(l.seen, client)
+			evicted++
+		}
+	}
+	return evicted
+}
+`,
+		},
+		annotation: "Fixes the unbounded growth of the client map by recording when each client was last seen and adding `Limiter.Evict`, which drops the buckets of clients idle for longer than a given duration. Eviction is left to the caller, typically a background goroutine on a ticker, so nothing is reclaimed unless a service wires it up. An evicted client that returns gets a fresh, full bucket, so an idle period shorter than the time to refill a bucket would let clients reset their limit.\n\nRisk: medium\nLabels: bugfix, performance",
+	},
+	{
+		author:  sam,
+		subject: "Validate rate limit config",
+		files: map[string]string{
+			"README.md": `# ratelimit
+
+Token bucket rate limiting for Go services.
+
+    cfg := ratelimit.Config{Rate: 5, Burst: 10}
+    limiter, err := cfg.NewLimiter()
+    if err != nil {
+        log.Fatal(err)
+    }
+    if !limiter.Allow(apiKey) {
+        http.Error(w, "slow down", http.StatusTooManyRequests)
+    }
+`,
+			"config.go": `package ratelimit
+
+import "fmt"
+
+// Config is the rate limit configuration of a service.
+type Config struct {
+	// Rate is the number of requests per second each client may make.
+	Rate float64 ` + "`yaml:\"rate\"`" + `
+	// Burst is the number of requests a client may make at once.
+	Burst float64 ` + "`yaml:\"burst\"`" + `
+}
+
+// Validate rejects settings that would let no request through.
+func (c Config) Validate() error {
+	if c.Rate <= 0 {
+		return fmt.Errorf("rate must be positive, got %v", c.Rate)
+	}
+	if c.Burst < 1 {
+		return fmt.Errorf("burst must be at least 1, got %v", c.Burst)
+	}
+	return nil
+}
+
+// NewLimiter returns the limiter c describes.
+func (c Config) NewLimiter() (*Limiter, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return NewLimiter(c.Rate, c.Burst), nil
+}
+`,
+		},
+		annotation: "Adds `Config` in config.go as the way services set up a limiter, with `Config.Validate` rejecting a non-positive rate or a burst below one: a bucket that can never hold a whole token would silently turn down every request. The README now shows the intended setup through `Config.NewLimiter`.\n\nRisk: low\nLabels: feature, docs",
+	},
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package diffview renders patches for prompts in formats other than git's
+// unified diff: word-level diffs, side-by-side before and after blocks with
+// the whole enclosing function as context, and syntax-aware diffs from
+// difftastic. Unified diffs repeat every changed line's surroundings and
+// split small edits into whole removed and added lines, which is often
+// neither the cheapest nor the clearest form for a model.
+//
+// Every format keeps git's "diff --git" line at the start of each file, so
+// that prompt.TrimDiff can share the space among files, and all but unified
+// diffs start with a line telling the model how to read them.
+package diffview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourorg/arc-git/internal/git"
+)
+
+// Diff formats.
+const (
+	// Unified is git's unified diff.
+	Unified = "unified"
+	// Word marks changed words inline as [-removed-]{+added+}.
+	Word = "word"
+	// SideBySide shows each change as a before block next to an after block,
+	// within the whole function it is in.
+	SideBySide = "side-by-side"
+	// Semantic is difftastic's syntax-aware diff, which ignores formatting
+	// and shows moved code as such.
+	Semantic = "semantic"
+)
+
+// Formats lists the diff formats.
+var Formats = []string{Unified, Word, SideBySide, Semantic}
+
+// legends explain the formats a model may not know to read.
+var legends = map[string]string{
+	Word:       "Word diff: [-text-] was removed and {+text+} added within the line.",
+	SideBySide: `Side-by-side diff: changed lines show the old text left of " | " and the new text right of it, within the whole function they are in.`,
+	Semantic:   "Syntax-aware diff: each line starts with its old and new line numbers; formatting-only changes are left out.",
+}
+
+// difftastic is difftastic's executable.
+const difftastic = "difft"
+
+// ErrNoDifftastic is returned for the semantic format when difftastic is not
+// installed.
+var ErrNoDifftastic = errors.New("difftastic (difft) is not installed")
+
+// Available reports whether format can be rendered on this machine.
+func Available(format string) error {
+	if format != Semantic {
+		return nil
+	}
+	if _, err := exec.LookPath(difftastic); err != nil {
+		return ErrNoDifftastic
+	}
+	return nil
+}
+
+// Commit renders the patch of a non-merge commit in format.
+func Commit(ctx context.Context, format, hash string) (string, error) {
+	if format == Unified || format == "" {
+		return git.Diff(ctx, hash)
+	}
+	var (
+		diff string
+		err  error
+	)
+	if format == Semantic {
+		var changed string
+		if changed, err = git.Run(ctx, "diff-tree", "-r", "-z", "-M", "--root", "--no-commit-id", "--name-status", hash); err == nil {
+			diff, err = semantic(ctx, hash+"^", hash, changed)
+		}
+	} else {
+		diff, err = render(ctx, format, []string{"show", "--format=", "--no-color", "--no-ext-diff"}, hash)
+	}
+	if err != nil {
+		return "", err
+	}
+	return withLegend(format, diff), nil
+}
+
+// Between renders the diff from revision old to revision new in format.
+func Between(ctx context.Context, format, old, new string) (string, error) {
+	var (
+		diff string
+		err  error
+	)
+	if format == Semantic {
+		var changed string
+		if changed, err = git.Run(ctx, "diff", "-z", "-M", "--name-status", old, new); err == nil {
+			diff, err = semantic(ctx, old, new, changed)
+		}
+	} else {
+		diff, err = render(ctx, format, []string{"diff", "--no-color", "--no-ext-diff"}, old, new)
+	}
+	if err != nil {
+		return "", err
+	}
+	return withLegend(format, diff), nil
+}
+
+// withLegend prefixes a non-empty diff with the legend of format, if it has
+// one.
+func withLegend(format, diff string) string {
+	if diff == "" || legends[format] == "" {
+		return diff
+	}
+	return legends[format] + "\n\n" + diff
+}
+
+// render runs git base with the options of a git-rendered format before
+// revs.
+func render(ctx context.Context, format string, base []string, revs ...string) (string, error) {
+	var opts []string
+	switch format {
+	case Unified, "":
+	case Word:
+		opts = []string{"--word-diff=plain"}
+	case SideBySide:
+		opts = []string{"--function-context"}
+	default:
+		return "", fmt.Errorf("unknown diff format %q (use %s)", format, strings.Join(Formats, ", "))
+	}
+	out, err := git.Run(ctx, append(append(base, opts...), revs...)...)
+	if err != nil || format != SideBySide {
+		return out, err
+	}
+	return sideBySide(out), nil
+}
+
+// sideBySide rewrites a unified diff so that each run of removed and added
+// lines becomes rows of old and new text separated by " | "; pure removals
+// and additions stay as they are. Context lines are printed once, indented;
+// file and hunk headers are kept.
+func sideBySide(diff string) string {
+	var (
+		b          strings.Builder
+		old, added []string
+		inHunk     bool
+	)
+	flush := func() {
+		width := 0
+		for _, l := range old {
+			width = max(width, len(l))
+		}
+		for i := 0; i < max(len(old), len(added)); i++ {
+			left, right := "", ""
+			if i < len(old) {
+				left = "- " + old[i]
+			}
+			if i < len(added) {
+				right = "+ " + added[i]
+			}
+			if len(old) == 0 || right == "" {
+				b.WriteString(left + right + "\n")
+				continue
+			}
+			fmt.Fprintf(&b, "%-*s | %s\n", width+2, left, right)
+		}
+		old, added = old[:0], added[:0]
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			inHunk = false
+			b.WriteString(line + "\n")
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			inHunk = true
+			b.WriteString(line + "\n")
+		case !inHunk:
+			b.WriteString(line + "\n")
+		case strings.HasPrefix(line, "-"):
+			old = append(old, line[1:])
+		case strings.HasPrefix(line, "+"):
+			added = append(added, line[1:])
+		default:
+			flush()
+			b.WriteString("  " + strings.TrimPrefix(line, " ") + "\n")
+		}
+	}
+	flush()
+	return b.String()
+}
+
+// semantic runs difftastic on each file of changed, the NUL-separated name
+// status output of git diff, comparing its content at old and new. Files
+// difftastic cannot parse get its line-based diff.
+func semantic(ctx context.Context, old, new, changed string) (string, error) {
+	if err := Available(Semantic); err != nil {
+		return "", err
+	}
+	dir, err := os.MkdirTemp("", "arc-git-difft-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	var b strings.Builder
+	fields := strings.Split(strings.TrimSuffix(changed, "\x00"), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		status, oldPath, newPath := fields[i], fields[i+1], fields[i+1]
+		if strings.HasPrefix(status, "R") || strings.HasPrefix(status, "C") {
+			if i+2 >= len(fields) {
+				break
+			}
+			newPath = fields[i+2]
+			i++
+		}
+		// Missing sides, as for added and deleted files, are empty.
+		before, _ := git.FileAt(ctx, old, oldPath)
+		after, _ := git.FileAt(ctx, new, newPath)
+
+		// difftastic picks the language by file name.
+		beforeFile := filepath.Join(dir, "a", filepath.Base(oldPath))
+		afterFile := filepath.Join(dir, "b", filepath.Base(newPath))
+		for _, f := range []struct {
+			name string
+			data []byte
+		}{{beforeFile, before}, {afterFile, after}} {
+			if err := os.MkdirAll(filepath.Dir(f.name), 0o755); err != nil {
+				return "", err
+			}
+			if err := os.WriteFile(f.name, f.data, 0o644); err != nil {
+				return "", err
+			}
+		}
+
+		cmd := exec.CommandContext(ctx, difftastic, "--color=never", "--display=inline", "--syntax-highlight=off", beforeFile, afterFile)
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("difftastic failed on %s: %w", newPath, err)
+		}
+		fmt.Fprintf(&b, "diff --git a/%s b/%s\n", oldPath, newPath)
+		// difftastic's first line names the compared temporary files.
+		_, body, _ := strings.Cut(string(out), "\n")
+		b.WriteString(strings.TrimLeft(body, "\n"))
+		if body != "" && !strings.HasSuffix(body, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return b.String(), nil
+}
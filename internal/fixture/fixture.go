@@ -0,0 +1,690 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package fixture builds synthetic git repositories for integration tests and
+// bug reproductions. Repositories are generated from a seed, so that the same
+// options always yield the same history, hashes included.
+package fixture
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/yourorg/arc-git/internal/git"
+)
+
+// Profiles lists the kinds of history that can be generated: clean is a
+// linear history of small, conventionally described changes; messy adds
+// merges, renames, binary files, unicode paths and messages, huge diffs,
+// duplicate identities, and sloppy messages.
+var Profiles = []string{"clean", "messy"}
+
+// Options describes the repository to build.
+type Options struct {
+	// Commits is the number of commits, merges and branch commits included.
+	Commits int
+	Profile string
+	Seed    int64
+	// Notes annotates about a third of the commits under the "ai" notes ref.
+	Notes bool
+}
+
+// Summary describes a built repository.
+type Summary struct {
+	Dir          string `json:"dir"`
+	Profile      string `json:"profile"`
+	Seed         int64  `json:"seed"`
+	Commits      int    `json:"commits"`
+	Merges       int    `json:"merges"`
+	Renames      int    `json:"renames"`
+	Deletes      int    `json:"deletes"`
+	Binary       int    `json:"binary_changes"`
+	HugeDiffs    int    `json:"huge_diffs"`
+	UnicodePaths int    `json:"unicode_paths"`
+	Tags         int    `json:"tags"`
+	Notes        int    `json:"notes"`
+	Head         string `json:"head"`
+}
+
+// Build creates the repository at dir, which must not exist or be empty.
+func Build(ctx context.Context, dir string, opts Options) (Summary, error) {
+	p, ok := profiles[opts.Profile]
+	if !ok {
+		return Summary{}, fmt.Errorf("unknown profile %q (profiles: %s)", opts.Profile, strings.Join(Profiles, ", "))
+	}
+	if opts.Commits <= 0 {
+		return Summary{}, fmt.Errorf("the number of commits must be positive")
+	}
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return Summary{}, fmt.Errorf("%s is not empty", dir)
+	}
+
+	g := &generator{
+		rng:  rand.New(rand.NewSource(opts.Seed)),
+		p:    p,
+		opts: opts,
+		when: startTime,
+		summary: Summary{
+			Dir:     dir,
+			Profile: opts.Profile,
+			Seed:    opts.Seed,
+		},
+	}
+	stream := g.generate()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Summary{}, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	// git init -b needs git 2.28; point HEAD at main by hand instead.
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"symbolic-ref", "HEAD", "refs/heads/main"},
+	} {
+		if _, err := git.RunIn(ctx, dir, "", args...); err != nil {
+			return Summary{}, err
+		}
+	}
+	if _, err := git.RunIn(ctx, dir, stream, "fast-import", "--quiet"); err != nil {
+		return Summary{}, err
+	}
+	if _, err := git.RunIn(ctx, dir, "", "reset", "-q", "--hard"); err != nil {
+		return Summary{}, err
+	}
+	head, err := git.RunIn(ctx, dir, "", "rev-parse", "HEAD")
+	if err != nil {
+		return Summary{}, err
+	}
+	g.summary.Head = strings.TrimSpace(head)
+	return g.summary, nil
+}
+
+// startTime is the date of the first commit, 2024-01-01 UTC.
+const startTime = 1704067200
+
+// author is a commit identity.
+type author struct {
+	name  string
+	email string
+	// zone is the UTC offset the author commits from.
+	zone string
+}
+
+// profile weighs the changes a history is made of: each regular commit is
+// one kind of change, picked with these probabilities, or else edits to
+// existing files. merges is the probability of a topic branch being merged
+// instead.
+type profile struct {
+	authors []author
+	// dirs are where new text files are created.
+	dirs     []string
+	adds     float64
+	deletes  float64
+	renames  float64
+	binary   float64
+	huge     float64
+	merges   float64
+	sloppy   float64
+	unicode  bool
+	backdate bool
+}
+
+var profiles = map[string]profile{
+	"clean": {
+		authors: []author{
+			{"Alice Example", "alice@example.com", "+0000"},
+			{"Bob Example", "bob@example.com", "-0500"},
+			{"Carol Example", "carol@example.com", "+0100"},
+		},
+		dirs: []string{"cmd", "internal/core", "internal/store", "docs"},
+		adds: 0.15,
+	},
+	"messy": {
+		authors: []author{
+			{"Alice Example", "alice@example.com", "+0000"},
+			{"alice", "Alice@Example.COM", "-0700"},
+			{"Zoë Ångström", "zoe@example.com", "+0100"},
+			{"李雷", "lilei@example.cn", "+0800"},
+			{"Ravi Kumar", "ravi@users.noreply.example.com", "+0530"},
+			{"dependabot[bot]", "49699333+dependabot[bot]@users.noreply.github.com", "+0000"},
+		},
+		dirs:     []string{"cmd", "internal/core", "internal/store", "docs", "src/ünïcode", "docs/日本語", "assets/with space"},
+		adds:     0.15,
+		deletes:  0.05,
+		renames:  0.08,
+		binary:   0.06,
+		huge:     0.03,
+		merges:   0.08,
+		sloppy:   0.4,
+		unicode:  true,
+		backdate: true,
+	},
+}
+
+// file is the content of a path in a tree.
+type file struct {
+	mode  string
+	lines []string
+	// data is the content of binary files, which have no lines.
+	data []byte
+	mark int
+}
+
+// tree maps paths to their content.
+type tree map[string]*file
+
+// clone returns a copy of t sharing the file contents.
+func (t tree) clone() tree {
+	c := make(tree, len(t))
+	for k, v := range t {
+		c[k] = v
+	}
+	return c
+}
+
+// paths returns the paths of t in order, for deterministic choices.
+func (t tree) paths(filter func(*file) bool) []string {
+	var paths []string
+	for p, f := range t {
+		if filter == nil || filter(f) {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// isText reports whether f is a regular text file.
+func isText(f *file) bool { return f.data == nil && f.mode != "120000" }
+
+// generator writes a git fast-import stream.
+type generator struct {
+	rng     *rand.Rand
+	p       profile
+	opts    Options
+	b       strings.Builder
+	mark    int
+	when    int64
+	made    int
+	seq     int
+	main    tree
+	tip     int
+	marks   []int
+	summary Summary
+}
+
+// generate returns the fast-import stream of the whole history.
+func (g *generator) generate() string {
+	g.main = tree{}
+	g.commit("refs/heads/main", g.main, 0, 0, func(t tree, c *change) {
+		c.subject, c.body = "Initial commit", ""
+		g.add(t, c, "README.md", g.prose(8))
+		for _, d := range g.p.dirs[:2] {
+			g.addText(t, c, d)
+		}
+	})
+
+	for g.made < g.opts.Commits {
+		left := g.opts.Commits - g.made
+		if left >= 4 && g.rng.Float64() < g.p.merges {
+			g.branch(min(left-2, 2+g.rng.Intn(3)))
+			continue
+		}
+		g.commit("refs/heads/main", g.main, g.tip, 0, g.change)
+		if g.made/50 > g.summary.Tags {
+			g.tag()
+		}
+	}
+
+	if g.opts.Notes {
+		g.notes()
+	}
+	return g.b.String()
+}
+
+// change is a commit being generated.
+type change struct {
+	subject  string
+	body     string
+	commands []string
+}
+
+// commit writes a commit on ref whose tree starts as t and is changed by
+// fill. from and merge are the marks of its parents, 0 for none.
+func (g *generator) commit(ref string, t tree, from, merge int, fill func(tree, *change)) int {
+	c := &change{}
+	fill(t, c)
+	if c.subject == "" {
+		c.subject, c.body = g.message(c)
+	}
+
+	g.mark++
+	mark := g.mark
+	a := g.p.authors[g.rng.Intn(len(g.p.authors))]
+	g.when += int64(600 + g.rng.Intn(36*3600))
+	authored := g.when
+	if g.p.backdate && g.rng.Float64() < 0.1 {
+		// Rebased or cherry-picked: authored days before it was committed.
+		authored -= int64(86400 * (1 + g.rng.Intn(20)))
+	}
+	message := c.subject
+	if c.body != "" {
+		message += "\n\n" + c.body
+	}
+
+	fmt.Fprintf(&g.b, "commit %s\nmark :%d\n", ref, mark)
+	fmt.Fprintf(&g.b, "author %s <%s> %d %s\n", a.name, a.email, authored, a.zone)
+	fmt.Fprintf(&g.b, "committer %s <%s> %d %s\n", a.name, a.email, g.when, a.zone)
+	g.data(message + "\n")
+	if from != 0 {
+		fmt.Fprintf(&g.b, "from :%d\n", from)
+	}
+	if merge != 0 {
+		fmt.Fprintf(&g.b, "merge :%d\n", merge)
+	}
+	for _, cmd := range c.commands {
+		g.b.WriteString(cmd + "\n")
+	}
+	g.b.WriteString("\n")
+
+	g.made++
+	g.summary.Commits++
+	g.marks = append(g.marks, mark)
+	if ref == "refs/heads/main" {
+		g.tip = mark
+	}
+	return mark
+}
+
+// branch writes a topic branch of n commits off main, a commit on main, and
+// the merge of the branch.
+func (g *generator) branch(n int) {
+	g.seq++
+	name := fmt.Sprintf("topic-%d", g.seq)
+	ref := "refs/heads/" + name
+	dir := fmt.Sprintf("features/%s", name)
+	topic := g.main.clone()
+
+	tip := 0
+	for i := 0; i < n; i++ {
+		from := tip
+		if i == 0 {
+			from = g.tip
+		}
+		tip = g.commit(ref, topic, from, 0, func(t tree, c *change) {
+			if paths := t.paths(nil); i > 0 && len(paths) > 0 && g.rng.Intn(2) == 0 {
+				for _, p := range paths {
+					if strings.HasPrefix(p, dir+"/") && isText(t[p]) {
+						g.edit(t, c, p)
+						return
+					}
+				}
+			}
+			g.addText(t, c, dir)
+		})
+	}
+	// Main moves on meanwhile, so that the merge is a real one.
+	g.commit("refs/heads/main", g.main, g.tip, 0, func(t tree, c *change) { g.editAny(t, c) })
+
+	g.commit("refs/heads/main", g.main, g.tip, tip, func(t tree, c *change) {
+		c.subject = fmt.Sprintf("Merge branch '%s'", name)
+		c.commands = append(c.commands, "deleteall")
+		for _, p := range topic.paths(nil) {
+			if strings.HasPrefix(p, dir+"/") {
+				t[p] = topic[p]
+			}
+		}
+		for _, p := range t.paths(nil) {
+			c.commands = append(c.commands, fmt.Sprintf("M %s :%d %s", t[p].mode, t[p].mark, quote(p)))
+		}
+	})
+	g.summary.Merges++
+}
+
+// change fills a regular commit with one kind of change.
+func (g *generator) change(t tree, c *change) {
+	p := g.p
+	kinds := []struct {
+		weight float64
+		apply  func()
+	}{
+		{p.adds, func() { g.addText(t, c, p.dirs[g.rng.Intn(len(p.dirs))]) }},
+		{p.deletes, func() { g.remove(t, c) }},
+		{p.renames, func() { g.rename(t, c) }},
+		{p.binary, func() { g.addBinary(t, c) }},
+		{p.huge, func() { g.addHuge(t, c) }},
+	}
+	r := g.rng.Float64()
+	for _, k := range kinds {
+		if r < k.weight {
+			k.apply()
+			return
+		}
+		r -= k.weight
+	}
+	g.editAny(t, c)
+}
+
+// blob writes the content of f as a blob and records its mark.
+func (g *generator) blob(f *file) {
+	g.mark++
+	f.mark = g.mark
+	fmt.Fprintf(&g.b, "blob\nmark :%d\n", f.mark)
+	if f.data != nil {
+		g.data(string(f.data))
+	} else if f.mode == "120000" {
+		g.data(f.lines[0])
+	} else {
+		g.data(strings.Join(f.lines, "\n") + "\n")
+	}
+}
+
+// data writes a data command.
+func (g *generator) data(s string) {
+	fmt.Fprintf(&g.b, "data %d\n%s\n", len(s), s)
+}
+
+// put stores f at path in t and in the commit.
+func (g *generator) put(t tree, c *change, path string, f *file) {
+	g.blob(f)
+	t[path] = f
+	c.commands = append(c.commands, fmt.Sprintf("M %s :%d %s", f.mode, f.mark, quote(path)))
+}
+
+// add creates a text file.
+func (g *generator) add(t tree, c *change, path string, lines []string) {
+	g.put(t, c, path, &file{mode: "100644", lines: lines})
+	if !isASCII(path) {
+		g.summary.UnicodePaths++
+	}
+}
+
+// addText creates a source or document file in dir.
+func (g *generator) addText(t tree, c *change, dir string) {
+	g.seq++
+	var path string
+	var lines []string
+	switch {
+	case strings.HasPrefix(dir, "docs"):
+		path, lines = fmt.Sprintf("%s/guide-%d.md", dir, g.seq), g.prose(5+g.rng.Intn(30))
+	case strings.HasPrefix(dir, "assets"):
+		path, lines = fmt.Sprintf("%s/notes %d.txt", dir, g.seq), g.prose(3+g.rng.Intn(10))
+	case g.p.unicode && g.rng.Intn(8) == 0:
+		path, lines = fmt.Sprintf("scripts/run-%d.sh", g.seq), []string{"#!/bin/sh", "set -eu", fmt.Sprintf("exec ./bin/tool --job %d \"$@\"", g.seq)}
+		g.put(t, c, path, &file{mode: "100755", lines: lines})
+		return
+	default:
+		path, lines = fmt.Sprintf("%s/file%d.go", dir, g.seq), g.code(10+g.rng.Intn(60))
+	}
+	g.add(t, c, path, lines)
+	if g.p.unicode && g.rng.Intn(30) == 0 {
+		link := fmt.Sprintf("%s/link-%d", dir, g.seq)
+		g.put(t, c, link, &file{mode: "120000", lines: []string{path[strings.LastIndex(path, "/")+1:]}})
+	}
+}
+
+// addBinary creates or replaces a binary file.
+func (g *generator) addBinary(t tree, c *change) {
+	paths := t.paths(func(f *file) bool { return f.data != nil })
+	path := ""
+	if len(paths) > 0 && g.rng.Intn(2) == 0 {
+		path = paths[g.rng.Intn(len(paths))]
+	} else {
+		g.seq++
+		path = fmt.Sprintf("assets/image-%d.png", g.seq)
+	}
+	data := make([]byte, 256+g.rng.Intn(4096))
+	g.rng.Read(data)
+	copy(data, "\x89PNG\r\n\x1a\n\x00")
+	g.put(t, c, path, &file{mode: "100644", data: data})
+	g.summary.Binary++
+}
+
+// addHuge creates or rewrites a generated file of thousands of lines.
+func (g *generator) addHuge(t tree, c *change) {
+	g.seq++
+	path := fmt.Sprintf("data/generated-%d.txt", g.seq%3)
+	lines := make([]string, 2000+g.rng.Intn(6000))
+	for i := range lines {
+		lines[i] = fmt.Sprintf("%06d,%08x,%s", i, g.rng.Uint32(), g.word())
+	}
+	g.put(t, c, path, &file{mode: "100644", lines: lines})
+	g.summary.HugeDiffs++
+}
+
+// editAny edits one to three text files, or adds one when there are none.
+func (g *generator) editAny(t tree, c *change) {
+	paths := t.paths(isText)
+	if len(paths) == 0 {
+		g.addText(t, c, g.p.dirs[0])
+		return
+	}
+	for n := 1 + g.rng.Intn(3); n > 0; n-- {
+		g.edit(t, c, paths[g.rng.Intn(len(paths))])
+	}
+}
+
+// edit changes a few lines of the text file at path.
+func (g *generator) edit(t tree, c *change, path string) {
+	old := t[path]
+	lines := append([]string(nil), old.lines...)
+	for n := 1 + g.rng.Intn(4); n > 0; n-- {
+		i := g.rng.Intn(len(lines) + 1)
+		switch g.rng.Intn(3) {
+		case 0:
+			lines = append(lines[:i], append([]string{g.line(path)}, lines[i:]...)...)
+		case 1:
+			if i < len(lines) {
+				lines[i] = g.line(path)
+			}
+		default:
+			if i < len(lines) && len(lines) > 1 {
+				lines = append(lines[:i], lines[i+1:]...)
+			}
+		}
+	}
+	g.put(t, c, path, &file{mode: old.mode, lines: lines})
+}
+
+// remove deletes a file, keeping a few around.
+func (g *generator) remove(t tree, c *change) {
+	paths := t.paths(nil)
+	if len(paths) <= 4 {
+		g.editAny(t, c)
+		return
+	}
+	path := paths[g.rng.Intn(len(paths))]
+	if path == "README.md" {
+		g.editAny(t, c)
+		return
+	}
+	delete(t, path)
+	c.commands = append(c.commands, "D "+quote(path))
+	g.summary.Deletes++
+}
+
+// rename moves a text file to another directory, sometimes editing it too.
+func (g *generator) rename(t tree, c *change) {
+	paths := t.paths(func(f *file) bool { return isText(f) && f.mode == "100644" })
+	if len(paths) == 0 {
+		g.editAny(t, c)
+		return
+	}
+	from := paths[g.rng.Intn(len(paths))]
+	if from == "README.md" {
+		g.editAny(t, c)
+		return
+	}
+	dir := g.p.dirs[g.rng.Intn(len(g.p.dirs))]
+	to := dir + "/" + from[strings.LastIndex(from, "/")+1:]
+	if _, taken := t[to]; taken || to == from {
+		g.editAny(t, c)
+		return
+	}
+	t[to] = t[from]
+	delete(t, from)
+	c.commands = append(c.commands, fmt.Sprintf("R %s %s", quote(from), quote(to)))
+	if g.rng.Intn(2) == 0 {
+		g.edit(t, c, to)
+	}
+	if !isASCII(to) {
+		g.summary.UnicodePaths++
+	}
+	g.summary.Renames++
+	c.body = "Moved " + from + " to " + to + "."
+}
+
+// tag writes an annotated release tag on main.
+func (g *generator) tag() {
+	g.summary.Tags++
+	name := fmt.Sprintf("v0.%d.0", g.summary.Tags)
+	a := g.p.authors[0]
+	fmt.Fprintf(&g.b, "tag %s\nfrom :%d\ntagger %s <%s> %d %s\n", name, g.tip, a.name, a.email, g.when, a.zone)
+	g.data("Release " + name + "\n")
+}
+
+// notes annotates about a third of the commits in one notes commit.
+func (g *generator) notes() {
+	a := g.p.authors[0]
+	var commands []string
+	for _, mark := range g.marks {
+		if g.rng.Intn(3) != 0 {
+			continue
+		}
+		var note string
+		if g.p.unicode && g.rng.Intn(2) == 0 {
+			note = "Adjusts " + g.word() + " handling.\n\nTier: quick"
+		} else {
+			risks := []string{"low", "medium", "high"}
+			labels := []string{"bugfix", "feature", "refactor", "docs", "test"}
+			note = strings.Join(g.prose(3), " ") + "\n\nRisk: " + risks[g.rng.Intn(len(risks))] + "\nLabels: " + labels[g.rng.Intn(len(labels))]
+		}
+		commands = append(commands, fmt.Sprintf("N inline :%d\ndata %d\n%s", mark, len(note), note))
+		g.summary.Notes++
+	}
+	if len(commands) == 0 {
+		return
+	}
+	fmt.Fprintf(&g.b, "commit refs/notes/ai\ncommitter %s <%s> %d %s\n", a.name, a.email, g.when, a.zone)
+	g.data("Notes added by arc-git devtools make-fixture\n")
+	for _, cmd := range commands {
+		g.b.WriteString(cmd + "\n")
+	}
+	g.b.WriteString("\n")
+}
+
+// message returns the subject and body of a commit, sometimes sloppy ones.
+func (g *generator) message(c *change) (subject, body string) {
+	types := []string{"feat", "fix", "refactor", "docs", "test", "chore", "perf"}
+	scope := g.word()
+	subject = fmt.Sprintf("%s(%s): %s %s %s", types[g.rng.Intn(len(types))], scope, verbs[g.rng.Intn(len(verbs))], g.word(), g.word())
+	if g.rng.Intn(3) == 0 {
+		body = wrap(strings.Join(g.prose(2+g.rng.Intn(4)), " "))
+	}
+	if c.body != "" {
+		body = strings.TrimSpace(c.body + "\n\n" + body)
+	}
+	if g.rng.Float64() >= g.p.sloppy {
+		return subject, body
+	}
+
+	switch g.rng.Intn(9) {
+	case 0:
+		return []string{"fix", "wip", "updates", "asdf", "."}[g.rng.Intn(5)], ""
+	case 1:
+		return "fixup! " + subject, ""
+	case 2:
+		return strings.Repeat(verbs[g.rng.Intn(len(verbs))]+" the "+g.word()+" and ", 8) + "more", body
+	case 3:
+		return "✨ Ajout de la fonctionnalité « " + g.word() + " »", "Größere Änderung, siehe Ticket.\n\n修正了一个问题。"
+	case 4:
+		return "Fix the " + g.word() + " bug.", body
+	case 5:
+		// No blank line between subject and body.
+		return subject + "\n" + strings.Join(g.prose(1), ""), ""
+	case 6:
+		return subject, strings.TrimSpace(body + "\n\nFixes #" + fmt.Sprint(1+g.rng.Intn(500)))
+	case 7:
+		return subject, strings.TrimSpace(body + "\n\nSigned-off-by: Alice Example <alice@example.com>\nCo-authored-by: Zoë Ångström <zoe@example.com>")
+	default:
+		return "Bump " + g.word() + " from 1." + fmt.Sprint(g.rng.Intn(9)) + ".0 to 1." + fmt.Sprint(9+g.rng.Intn(9)) + ".0", ""
+	}
+}
+
+var (
+	verbs = []string{"add", "fix", "handle", "remove", "rename", "simplify", "speed up", "document", "validate"}
+	words = []string{"cache", "parser", "session", "token", "config", "retry", "index", "buffer", "router", "schema", "queue", "limit", "report", "client", "worker"}
+	// unicodeWords spice up messy content.
+	unicodeWords = []string{"naïve", "Größe", "café", "日本語", "données", "✓", "emoji 🎉"}
+)
+
+// word returns a random word.
+func (g *generator) word() string {
+	if g.p.unicode && g.rng.Intn(10) == 0 {
+		return unicodeWords[g.rng.Intn(len(unicodeWords))]
+	}
+	return words[g.rng.Intn(len(words))]
+}
+
+// prose returns n sentences.
+func (g *generator) prose(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("The %s %s the %s when the %s is %s.", g.word(), []string{"updates", "reads", "checks", "drops", "wraps"}[g.rng.Intn(5)], g.word(), g.word(), []string{"full", "stale", "empty", "missing", "ready"}[g.rng.Intn(5)])
+	}
+	return lines
+}
+
+// code returns n lines of Go-like source.
+func (g *generator) code(n int) []string {
+	lines := []string{"package main", ""}
+	for len(lines) < n {
+		lines = append(lines, g.line(".go"))
+	}
+	return lines
+}
+
+// line returns a line fitting the file at path.
+func (g *generator) line(path string) string {
+	if strings.HasSuffix(path, ".go") {
+		return fmt.Sprintf("func %s%d() int { return %d } // %s", g.word(), g.rng.Intn(1000), g.rng.Intn(100), g.word())
+	}
+	return g.prose(1)[0]
+}
+
+// wrap breaks text into lines of at most 72 characters.
+func wrap(text string) string {
+	var b strings.Builder
+	width := 0
+	for _, w := range strings.Fields(text) {
+		if width > 0 && width+1+len(w) > 72 {
+			b.WriteString("\n")
+			width = 0
+		} else if width > 0 {
+			b.WriteString(" ")
+			width++
+		}
+		b.WriteString(w)
+		width += len(w)
+	}
+	return b.String()
+}
+
+// quote renders a path for fast-import, C-style, leaving UTF-8 as is.
+func quote(path string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+	return `"` + r.Replace(path) + `"`
+}
+
+// isASCII reports whether s is plain ASCII.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package forge talks to the code hosting service a repository lives on.
+package forge
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Provider is the set of forge operations arc-git relies on.
+type Provider interface {
+	// Name returns the provider identifier (e.g. "github").
+	Name() string
+	// CommentIssue posts a comment on an issue or pull request.
+	CommentIssue(ctx context.Context, number int, body string) error
+	// CreateRelease publishes a release for an existing tag and returns its
+	// web URL.
+	CreateRelease(ctx context.Context, r Release) (string, error)
+	// MergedPullRequests lists the pull requests merged since the given
+	// time, most recently updated first.
+	MergedPullRequests(ctx context.Context, since time.Time) ([]PullRequest, error)
+	// PullRequest returns an open or closed pull request with its base and
+	// head.
+	PullRequest(ctx context.Context, number int) (PullRequest, error)
+}
+
+// PullRequest is a pull request. MergeCommit and MergedAt are set once it
+// has been merged, and only PullRequest fills in its base and head.
+type PullRequest struct {
+	Number int
+	Title  string
+	Body   string
+	Author string
+	URL    string
+	// BaseRef is the branch the pull request targets, and BaseCommit its
+	// tip as the forge last compared the pull request against it.
+	BaseRef    string
+	BaseCommit string
+	// HeadRef is the pull request's branch, and HeadCommit its tip.
+	HeadRef    string
+	HeadCommit string
+	// MergeCommit is the commit the merge created on the base branch: the
+	// merge or squash commit, or the last rebased commit.
+	MergeCommit string
+	MergedAt    time.Time
+}
+
+// Release describes a release to publish on the forge.
+type Release struct {
+	Tag        string
+	Name       string
+	Body       string
+	Draft      bool
+	Prerelease bool
+}
+
+// Repo identifies a repository on a forge.
+type Repo struct {
+	Host  string
+	Owner string
+	Name  string
+}
+
+// remotePattern matches SSH and HTTPS remote URLs.
+var remotePattern = regexp.MustCompile(`^(?:[a-z+]+://)?(?:[^@/]+@)?([^:/]+)[:/]([^/]+)/([^/]+?)(?:\.git)?/?$`)
+
+// ParseRemote extracts the host, owner, and name from a git remote URL.
+func ParseRemote(url string) (Repo, error) {
+	m := remotePattern.FindStringSubmatch(strings.TrimSpace(url))
+	if m == nil {
+		return Repo{}, fmt.Errorf("unrecognized remote URL: %s", url)
+	}
+	return Repo{Host: m[1], Owner: m[2], Name: m[3]}, nil
+}
+
+// Detect returns a provider for the repository behind the given remote URL.
+func Detect(remoteURL string) (Provider, error) {
+	repo, err := ParseRemote(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case repo.Host == "github.com" || strings.Contains(repo.Host, "github"):
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			token = os.Getenv("GH_TOKEN")
+		}
+		if token == "" {
+			return nil, fmt.Errorf("no GitHub token found (set GITHUB_TOKEN or GH_TOKEN)")
+		}
+		return NewGitHub(repo, token), nil
+	default:
+		return nil, fmt.Errorf("unsupported forge host: %s", repo.Host)
+	}
+}
+
+// CommitURL returns the web URL of a commit in the repository.
+func (r Repo) CommitURL(hash string) string {
+	if strings.Contains(r.Host, "gitlab") {
+		return fmt.Sprintf("https://%s/%s/%s/-/commit/%s", r.Host, r.Owner, r.Name, hash)
+	}
+	return fmt.Sprintf("https://%s/%s/%s/commit/%s", r.Host, r.Owner, r.Name, hash)
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// GitHub implements Provider against the GitHub REST API.
+type GitHub struct {
+	repo    Repo
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+// NewGitHub creates a GitHub provider for the given repository.
+// GitHub Enterprise hosts are addressed through their /api/v3 endpoint.
+func NewGitHub(repo Repo, token string) *GitHub {
+	baseURL := "https://api.github.com"
+	if repo.Host != "github.com" {
+		baseURL = "https://" + repo.Host + "/api/v3"
+	}
+	return &GitHub{
+		repo:    repo,
+		token:   token,
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns "github".
+func (g *GitHub) Name() string { return "github" }
+
+// CommentIssue posts a comment on an issue or pull request.
+func (g *GitHub) CommentIssue(ctx context.Context, number int, body string) error {
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", g.repo.Owner, g.repo.Name, number)
+	return g.do(ctx, http.MethodPost, path, map[string]string{"body": body}, nil)
+}
+
+// CreateRelease publishes a release for an existing tag.
+func (g *GitHub) CreateRelease(ctx context.Context, r Release) (string, error) {
+	path := fmt.Sprintf("/repos/%s/%s/releases", g.repo.Owner, g.repo.Name)
+	in := map[string]interface{}{
+		"tag_name":   r.Tag,
+		"name":       r.Name,
+		"body":       r.Body,
+		"draft":      r.Draft,
+		"prerelease": r.Prerelease,
+	}
+	var out struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := g.do(ctx, http.MethodPost, path, in, &out); err != nil {
+		return "", err
+	}
+	return out.HTMLURL, nil
+}
+
+// MergedPullRequests pages through closed pull requests by last update,
+// newest first, until they predate since.
+func (g *GitHub) MergedPullRequests(ctx context.Context, since time.Time) ([]PullRequest, error) {
+	var prs []PullRequest
+	for page := 1; ; page++ {
+		query := url.Values{
+			"state":     {"closed"},
+			"sort":      {"updated"},
+			"direction": {"desc"},
+			"per_page":  {"100"},
+			"page":      {fmt.Sprint(page)},
+		}
+		path := fmt.Sprintf("/repos/%s/%s/pulls?%s", g.repo.Owner, g.repo.Name, query.Encode())
+		var out []struct {
+			Number         int        `json:"number"`
+			Title          string     `json:"title"`
+			Body           string     `json:"body"`
+			HTMLURL        string     `json:"html_url"`
+			MergeCommitSHA string     `json:"merge_commit_sha"`
+			MergedAt       *time.Time `json:"merged_at"`
+			UpdatedAt      time.Time  `json:"updated_at"`
+			User           struct {
+				Login string `json:"login"`
+			} `json:"user"`
+		}
+		if err := g.do(ctx, http.MethodGet, path, nil, &out); err != nil {
+			return nil, err
+		}
+		for _, pr := range out {
+			// A pull request is updated when merged, so once updates
+			// predate since, so do all later merges.
+			if pr.UpdatedAt.Before(since) {
+				return prs, nil
+			}
+			if pr.MergedAt == nil || pr.MergedAt.Before(since) || pr.MergeCommitSHA == "" {
+				continue
+			}
+			prs = append(prs, PullRequest{
+				Number:      pr.Number,
+				Title:       pr.Title,
+				Body:        pr.Body,
+				Author:      pr.User.Login,
+				URL:         pr.HTMLURL,
+				MergeCommit: pr.MergeCommitSHA,
+				MergedAt:    *pr.MergedAt,
+			})
+		}
+		if len(out) < 100 {
+			return prs, nil
+		}
+	}
+}
+
+// PullRequest fetches a single pull request.
+func (g *GitHub) PullRequest(ctx context.Context, number int) (PullRequest, error) {
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d", g.repo.Owner, g.repo.Name, number)
+	var out struct {
+		Number         int        `json:"number"`
+		Title          string     `json:"title"`
+		Body           string     `json:"body"`
+		HTMLURL        string     `json:"html_url"`
+		MergeCommitSHA string     `json:"merge_commit_sha"`
+		MergedAt       *time.Time `json:"merged_at"`
+		User           struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		Base struct {
+			Ref string `json:"ref"`
+			SHA string `json:"sha"`
+		} `json:"base"`
+		Head struct {
+			Ref string `json:"ref"`
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := g.do(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return PullRequest{}, err
+	}
+	pr := PullRequest{
+		Number:     out.Number,
+		Title:      out.Title,
+		Body:       out.Body,
+		Author:     out.User.Login,
+		URL:        out.HTMLURL,
+		BaseRef:    out.Base.Ref,
+		BaseCommit: out.Base.SHA,
+		HeadRef:    out.Head.Ref,
+		HeadCommit: out.Head.SHA,
+	}
+	// GitHub computes a test merge commit for open pull requests too.
+	if out.MergedAt != nil {
+		pr.MergeCommit = out.MergeCommitSHA
+		pr.MergedAt = *out.MergedAt
+	}
+	return pr, nil
+}
+
+// do performs an API request, encoding in as the JSON body and decoding the
+// response into out when non-nil.
+func (g *GitHub) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, g.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+g.token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("github request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("github %s %s: %s: %s", method, path, resp.Status, bytes.TrimSpace(msg))
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode github response: %w", err)
+		}
+	}
+	return nil
+}
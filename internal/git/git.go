@@ -0,0 +1,961 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package git wraps the git command line for the operations arc-git needs.
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-git/internal/logging"
+	"github.com/yourorg/arc-git/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Commit represents a git commit.
+type Commit struct {
+	Hash    string
+	Message string
+	Author  string
+	Date    string
+}
+
+// Short returns the abbreviated commit hash.
+func (c Commit) Short() string {
+	if len(c.Hash) < 7 {
+		return c.Hash
+	}
+	return c.Hash[:7]
+}
+
+// logFormat is the per-commit format parsed by Log. Author names and emails
+// are mapped through .mailmap.
+const logFormat = "--format=%H%n%aN <%aE>%n%ad%n%s"
+
+// Run executes git with the given arguments and returns its stdout.
+func Run(ctx context.Context, args ...string) (string, error) {
+	return run(ctx, nil, args...)
+}
+
+// RunInput is Run with input supplied on stdin.
+func RunInput(ctx context.Context, input string, args ...string) (string, error) {
+	return run(ctx, strings.NewReader(input), args...)
+}
+
+// RunIn is RunInput in the repository at dir rather than the working
+// directory; input may be empty.
+func RunIn(ctx context.Context, dir, input string, args ...string) (string, error) {
+	return runIn(ctx, dir, strings.NewReader(input), args...)
+}
+
+// dirKey is the context key of the repository set by WithDir.
+type dirKey struct{}
+
+// WithDir returns a context in which git runs in the repository at dir
+// instead of the working directory, for commands that read several
+// repositories.
+func WithDir(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, dirKey{}, dir)
+}
+
+// dirOf returns the repository set by WithDir, or "" for the working
+// directory.
+func dirOf(ctx context.Context) string {
+	dir, _ := ctx.Value(dirKey{}).(string)
+	return dir
+}
+
+// run executes git with optional stdin, in the repository set by WithDir.
+func run(ctx context.Context, stdin io.Reader, args ...string) (out string, err error) {
+	return runIn(ctx, dirOf(ctx), stdin, args...)
+}
+
+// runIn executes git in dir, or the working directory when dir is empty,
+// with optional stdin.
+func runIn(ctx context.Context, dir string, stdin io.Reader, args ...string) (out string, err error) {
+	ctx, span := telemetry.Start(ctx, "git "+args[0], attribute.String("git.args", strings.Join(args, " ")))
+	defer func() {
+		if err != nil {
+			telemetry.Failure(ctx, "git")
+		}
+		telemetry.End(span, err)
+	}()
+
+	start := time.Now()
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stdin = stdin
+	raw, err := cmd.Output()
+	logging.Timed(start, err, "git", "args", strings.Join(args, " "))
+	if err != nil {
+		gitErr := &Error{Command: args[0], Err: err}
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			gitErr.Stderr = strings.TrimSpace(string(exitErr.Stderr))
+		}
+		return "", gitErr
+	}
+	return string(raw), nil
+}
+
+// Error is a failed git invocation.
+type Error struct {
+	// Command is the git subcommand, such as "log".
+	Command string
+	Err     error
+	Stderr  string
+}
+
+// Error renders the failure with git's stderr, if any.
+func (e *Error) Error() string {
+	if e.Stderr != "" {
+		return fmt.Sprintf("git %s failed: %v: %s", e.Command, e.Err, e.Stderr)
+	}
+	return fmt.Sprintf("git %s failed: %v", e.Command, e.Err)
+}
+
+// Unwrap returns the underlying exec error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Exists reports whether rev names a commit in the repository.
+func Exists(ctx context.Context, rev string) bool {
+	_, err := Run(ctx, "cat-file", "-e", rev+"^{commit}")
+	return err == nil
+}
+
+// Log runs git log with the given extra arguments and parses the commits.
+func Log(ctx context.Context, args ...string) ([]Commit, error) {
+	out, err := Run(ctx, append([]string{"log", logFormat}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(out), nil
+}
+
+// parseLog parses output produced with logFormat.
+func parseLog(out string) []Commit {
+	var commits []Commit
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	for i := 0; i+3 < len(lines); i += 4 {
+		if lines[i] == "" {
+			break
+		}
+		commits = append(commits, Commit{
+			Hash:    lines[i],
+			Author:  lines[i+1],
+			Date:    lines[i+2],
+			Message: lines[i+3],
+		})
+	}
+	return commits
+}
+
+// Diff returns the patch introduced by a commit.
+func Diff(ctx context.Context, hash string) (string, error) {
+	return Run(ctx, "show", "--format=", hash)
+}
+
+// HunkHeaders returns the hunk headers of a commit's patch grouped under
+// their file paths, without the changed lines themselves.
+func HunkHeaders(ctx context.Context, hash string) (string, error) {
+	out, err := Run(ctx, "show", "--format=", "--unified=0", "--no-color", hash)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			if i := strings.LastIndex(line, " b/"); i >= 0 {
+				b.WriteString(line[i+3:] + "\n")
+			}
+		case strings.HasPrefix(line, "@@"):
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	return b.String(), nil
+}
+
+// Body returns the full commit message of a commit.
+func Body(ctx context.Context, hash string) (string, error) {
+	out, err := Run(ctx, "show", "-s", "--format=%B", hash)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// HasNote checks if a commit has a note under the given ref.
+func HasNote(ctx context.Context, hash, ref string) bool {
+	_, err := Run(ctx, "notes", "--ref", ref, "show", hash)
+	return err == nil
+}
+
+// ShowNote returns the note attached to a commit under the given ref.
+func ShowNote(ctx context.Context, hash, ref string) (string, error) {
+	out, err := Run(ctx, "notes", "--ref", ref, "show", hash)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// NotedCommit is a commit together with its note under some ref.
+type NotedCommit struct {
+	Commit
+	Note string
+}
+
+// LogNotes runs git log with the given extra arguments and returns each
+// commit with its note under ref, which is empty for commits without one.
+func LogNotes(ctx context.Context, ref string, args ...string) ([]NotedCommit, error) {
+	format := "--format=%x1e%H%x1f%aN <%aE>%x1f%ad%x1f%s%x1f%N"
+	out, err := Run(ctx, append([]string{"log", "--no-notes", "--notes=" + ref, format}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	var commits []NotedCommit
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.SplitN(record, "\x1f", 5)
+		if len(fields) != 5 {
+			continue
+		}
+		commits = append(commits, NotedCommit{
+			Commit: Commit{
+				Hash:    fields[0],
+				Author:  fields[1],
+				Date:    fields[2],
+				Message: fields[3],
+			},
+			Note: strings.TrimSpace(fields[4]),
+		})
+	}
+	return commits, nil
+}
+
+// AddNote adds a note to a commit under the given ref, replacing any
+// existing note.
+func AddNote(ctx context.Context, hash, ref, note string) (err error) {
+	ctx, span := telemetry.Start(ctx, "git notes add", attribute.String("git.notes_ref", ref))
+	defer func() {
+		if err != nil {
+			telemetry.Failure(ctx, "git")
+		}
+		telemetry.End(span, err)
+	}()
+
+	// Write note to temp file
+	tmpFile, err := os.CreateTemp("", "arc-git-note-*.txt")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.WriteString(note); err != nil {
+		return fmt.Errorf("failed to write note: %w", err)
+	}
+	tmpFile.Close()
+
+	start := time.Now()
+	cmd := exec.CommandContext(ctx, "git", "notes", "--ref", ref, "add", "-f", "-F", tmpFile.Name(), hash)
+	cmd.Dir = dirOf(ctx)
+	out, err := cmd.CombinedOutput()
+	logging.Timed(start, err, "git", "args", "notes --ref "+ref+" add -f "+hash)
+	if err != nil {
+		return fmt.Errorf("git notes failed: %w\nOutput: %s", err, out)
+	}
+
+	return nil
+}
+
+// RemoteURL returns the URL of the named remote.
+func RemoteURL(ctx context.Context, name string) (string, error) {
+	out, err := Run(ctx, "remote", "get-url", name)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// RemoteTag returns the object the tag points at on the named remote, or ""
+// when the remote has no such tag.
+func RemoteTag(ctx context.Context, remote, tag string) (string, error) {
+	out, err := Run(ctx, "ls-remote", "--tags", remote, "refs/tags/"+tag)
+	if err != nil {
+		return "", err
+	}
+	hash, _, _ := strings.Cut(strings.TrimSpace(out), "\t")
+	return hash, nil
+}
+
+// Change is a commit together with its full message, timestamp, and the
+// files it touched.
+type Change struct {
+	Commit
+	Name  string
+	Email string
+	Time  time.Time
+	Body  string
+	Files []string
+}
+
+// changeFormat delimits records with \x1e and fields with \x1f so that
+// bodies and file lists can be split unambiguously. Author names and emails
+// are mapped through .mailmap.
+const changeFormat = "--format=%x1e%H%x1f%at%x1f%aN%x1f%aE%x1f%ad%x1f%s%x1f%b%x1f"
+
+// LogChanges runs git log with the given extra arguments and returns each
+// commit with the files it touched.
+func LogChanges(ctx context.Context, args ...string) ([]Change, error) {
+	out, err := Run(ctx, append([]string{"log", changeFormat, "--name-only"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseChanges(out), nil
+}
+
+// parseChanges parses output produced with changeFormat and --name-only.
+func parseChanges(out string) []Change {
+	var changes []Change
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.Split(record, "\x1f")
+		if len(fields) < 8 {
+			continue
+		}
+		ts, _ := strconv.ParseInt(fields[1], 10, 64)
+		c := Change{
+			Commit: Commit{
+				Hash:    fields[0],
+				Author:  fmt.Sprintf("%s <%s>", fields[2], fields[3]),
+				Date:    fields[4],
+				Message: fields[5],
+			},
+			Name:  fields[2],
+			Email: fields[3],
+			Time:  time.Unix(ts, 0),
+			Body:  strings.TrimSpace(fields[6]),
+		}
+		for _, f := range strings.Split(fields[7], "\n") {
+			if f = strings.TrimSpace(f); f != "" {
+				c.Files = append(c.Files, f)
+			}
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// FileStat is the per-file line count of a commit.
+type FileStat struct {
+	Path    string
+	Added   int
+	Deleted int
+	Binary  bool
+}
+
+// NumStat returns the per-file added and deleted line counts of a commit.
+func NumStat(ctx context.Context, hash string) ([]FileStat, error) {
+	out, err := Run(ctx, "show", "--numstat", "--format=", hash)
+	if err != nil {
+		return nil, err
+	}
+	return parseNumStat(out), nil
+}
+
+// LogNumStat returns the per-file line counts of each commit selected by git
+// log args, by hash.
+func LogNumStat(ctx context.Context, args ...string) (map[string][]FileStat, error) {
+	out, err := Run(ctx, append([]string{"log", "--format=%x1e%H", "--numstat"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[string][]FileStat)
+	for _, record := range strings.Split(out, "\x1e") {
+		hash, rest, _ := strings.Cut(strings.TrimSpace(record), "\n")
+		if hash != "" {
+			stats[hash] = parseNumStat(rest)
+		}
+	}
+	return stats, nil
+}
+
+// DiffNumStat returns the per-file line counts of the changes from one
+// revision to another, limited to paths, which are taken literally and
+// relative to the top of the tree wherever the command runs, as git reports
+// them. Renames are not detected, so a
+// moved file counts as removed. An empty from compares against the empty
+// tree, which counts the lines of the files at to.
+func DiffNumStat(ctx context.Context, from, to string, paths ...string) ([]FileStat, error) {
+	if from == "" {
+		tree, err := RunInput(ctx, "", "hash-object", "-t", "tree", "--stdin")
+		if err != nil {
+			return nil, err
+		}
+		from = strings.TrimSpace(tree)
+	}
+	args := []string{"diff", "--numstat", "--no-renames", from, to, "--"}
+	for _, p := range paths {
+		args = append(args, ":(top,literal)"+p)
+	}
+	out, err := Run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseNumStat(out), nil
+}
+
+// TouchedFiles returns the files a commit added or modified, without the
+// ones it deleted. Renamed files are listed under their new path.
+func TouchedFiles(ctx context.Context, hash string) ([]string, error) {
+	out, err := Run(ctx, "show", "-z", "--format=", "--name-only", "--no-renames", "--diff-filter=d", hash)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, f := range strings.Split(out, "\x00") {
+		if f = strings.TrimPrefix(f, "\n"); f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// CommitTime returns the committer date of rev.
+func CommitTime(ctx context.Context, rev string) (time.Time, error) {
+	out, err := Run(ctx, "show", "-s", "--format=%ct", rev)
+	if err != nil {
+		return time.Time{}, err
+	}
+	ts, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unexpected commit date %q", strings.TrimSpace(out))
+	}
+	return time.Unix(ts, 0), nil
+}
+
+// parseNumStat parses --numstat lines.
+func parseNumStat(out string) []FileStat {
+	var stats []FileStat
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		s := FileStat{Path: fields[2]}
+		if fields[0] == "-" {
+			s.Binary = true
+		} else {
+			s.Added, _ = strconv.Atoi(fields[0])
+			s.Deleted, _ = strconv.Atoi(fields[1])
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// Dir returns the absolute path of the repository's git directory.
+func Dir(ctx context.Context) (string, error) {
+	out, err := Run(ctx, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// NotedCommits returns the set of commit hashes that have a note under ref.
+// A missing ref yields an empty set.
+func NotedCommits(ctx context.Context, ref string) (map[string]bool, error) {
+	noted := make(map[string]bool)
+	if _, err := Run(ctx, "rev-parse", "--verify", "--quiet", "refs/notes/"+ref); err != nil {
+		return noted, nil
+	}
+	out, err := Run(ctx, "notes", "--ref", ref, "list")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 {
+			noted[fields[1]] = true
+		}
+	}
+	return noted, nil
+}
+
+// RevList returns the commit hashes selected by git rev-list args, newest first.
+func RevList(ctx context.Context, args ...string) ([]string, error) {
+	out, err := Run(ctx, append([]string{"rev-list"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+// shortStatPattern captures insertion and deletion counts from --shortstat.
+var shortStatPattern = regexp.MustCompile(`(\d+) insertions?\(\+\)|(\d+) deletions?\(-\)`)
+
+// ChangedLines returns the number of inserted plus deleted lines for each
+// commit selected by git log args.
+func ChangedLines(ctx context.Context, args ...string) (map[string]int, error) {
+	out, err := Run(ctx, append([]string{"log", "--format=%x1e%H", "--shortstat"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	lines := make(map[string]int)
+	for _, record := range strings.Split(out, "\x1e") {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+		hash, stat, _ := strings.Cut(record, "\n")
+		total := 0
+		for _, m := range shortStatPattern.FindAllStringSubmatch(stat, -1) {
+			for _, n := range m[1:] {
+				if v, err := strconv.Atoi(n); err == nil {
+					total += v
+				}
+			}
+		}
+		lines[strings.TrimSpace(hash)] = total
+	}
+	return lines, nil
+}
+
+// RangeDiff compares two versions of a patch series, given as revision
+// ranges (e.g. "main..v1" and "main..v2"), without color. Pathspecs, when
+// given, limit the patches compared.
+func RangeDiff(ctx context.Context, oldRange, newRange string, pathspecs ...string) (string, error) {
+	args := []string{"range-diff", "--no-color", oldRange, newRange}
+	if len(pathspecs) > 0 {
+		args = append(append(args, "--"), pathspecs...)
+	}
+	return Run(ctx, args...)
+}
+
+// PatchID returns the stable patch ID of the diff between two revisions, or
+// "" when they have the same tree. Commits with equal patch IDs introduce the
+// same change, which identifies squash merges and cherry-picks.
+func PatchID(ctx context.Context, from, to string) (string, error) {
+	diff, err := Run(ctx, "diff", "--no-color", from, to)
+	if err != nil || diff == "" {
+		return "", err
+	}
+	out, err := RunInput(ctx, diff, "patch-id", "--stable")
+	if err != nil {
+		return "", err
+	}
+	id, _, _ := strings.Cut(strings.TrimSpace(out), " ")
+	return id, nil
+}
+
+// MergeBase returns the best common ancestor of two revisions.
+func MergeBase(ctx context.Context, a, b string) (string, error) {
+	out, err := Run(ctx, "merge-base", a, b)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// Grep runs git grep for an extended regular expression in the tree of rev
+// and returns the "rev:path:line:text" matches, with paths relative to the
+// top of the tree. The whole tree is searched, wherever the command runs. No
+// matches is not an error.
+func Grep(ctx context.Context, pattern, rev string) (string, error) {
+	out, err := Run(ctx, "grep", "-n", "-I", "-E", "--full-name", "-e", pattern, rev, "--", ":/")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return "", nil
+	}
+	return out, err
+}
+
+// ContainsWord reports whether a text file in the tree of rev contains word
+// as a whole word. The whole tree is searched, wherever the command runs.
+func ContainsWord(ctx context.Context, rev, word string) (bool, error) {
+	_, err := Run(ctx, "grep", "-q", "-I", "-w", "-F", "-e", word, rev, "--", ":/")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// FilesWithWord returns the paths of the text files in the tree of rev that
+// contain word as a whole word, relative to the top of the tree.
+func FilesWithWord(ctx context.Context, rev, word string) ([]string, error) {
+	out, err := Run(ctx, "grep", "-l", "-z", "-I", "-w", "-F", "--full-name", "-e", word, rev, "--", ":/")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, f := range strings.Split(strings.TrimSuffix(out, "\x00"), "\x00") {
+		files = append(files, strings.TrimPrefix(f, rev+":"))
+	}
+	return files, nil
+}
+
+// AddWorktree checks out rev, detached, into a new worktree at dir.
+func AddWorktree(ctx context.Context, dir, rev string) error {
+	_, err := Run(ctx, "worktree", "add", "--quiet", "--detach", dir, rev)
+	return err
+}
+
+// RemoveWorktree deletes the worktree at dir, discarding any changes in it.
+func RemoveWorktree(ctx context.Context, dir string) error {
+	_, err := Run(ctx, "worktree", "remove", "--force", dir)
+	return err
+}
+
+// Author is a commit author identity, as recorded in commits and as mapped
+// through .mailmap.
+type Author struct {
+	Name        string
+	Email       string
+	MappedName  string
+	MappedEmail string
+	Commits     int
+}
+
+// Authors returns the distinct recorded author identities of the commits
+// selected by git log args, most commits first.
+func Authors(ctx context.Context, args ...string) ([]Author, error) {
+	out, err := Run(ctx, append([]string{"log", "--format=%an%x1f%ae%x1f%aN%x1f%aE"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int)
+	var authors []Author
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		key := fields[0] + "\x1f" + fields[1]
+		i, ok := index[key]
+		if !ok {
+			i = len(authors)
+			index[key] = i
+			authors = append(authors, Author{Name: fields[0], Email: fields[1], MappedName: fields[2], MappedEmail: fields[3]})
+		}
+		authors[i].Commits++
+	}
+	sort.SliceStable(authors, func(i, j int) bool {
+		return authors[i].Commits > authors[j].Commits
+	})
+	return authors, nil
+}
+
+// LineRange is an inclusive, 1-based range of lines.
+type LineRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// FileChange is the changed line ranges of one file in a commit: Old ranges
+// were removed from OldPath and New ranges added to Path.
+type FileChange struct {
+	OldPath string
+	Path    string
+	Old     []LineRange
+	New     []LineRange
+}
+
+// hunkPattern captures the old and new ranges of a hunk header.
+var hunkPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ChangedRanges returns the line ranges each file's patch touches in a
+// non-merge commit, following renames.
+func ChangedRanges(ctx context.Context, hash string) ([]FileChange, error) {
+	out, err := Run(ctx, "show", "--format=", "--unified=0", "--no-color", "--no-ext-diff", "-M", hash)
+	if err != nil {
+		return nil, err
+	}
+	var files []FileChange
+	var cur *FileChange
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, FileChange{})
+			cur = &files[len(files)-1]
+		case cur == nil:
+		case strings.HasPrefix(line, "--- "):
+			cur.OldPath = diffPath(line[4:], "a/")
+		case strings.HasPrefix(line, "+++ "):
+			cur.Path = diffPath(line[4:], "b/")
+		case strings.HasPrefix(line, "@@"):
+			m := hunkPattern.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			if r, ok := hunkRange(m[1], m[2]); ok {
+				cur.Old = append(cur.Old, r)
+			}
+			if r, ok := hunkRange(m[3], m[4]); ok {
+				cur.New = append(cur.New, r)
+			}
+		}
+	}
+	return files, nil
+}
+
+// diffPath strips the a/ or b/ prefix from a patch header path; /dev/null
+// yields "".
+func diffPath(p, prefix string) string {
+	p = strings.TrimSuffix(p, "\t")
+	if p == "/dev/null" {
+		return ""
+	}
+	if unquoted, err := strconv.Unquote(p); err == nil {
+		p = unquoted
+	}
+	return strings.TrimPrefix(p, prefix)
+}
+
+// hunkRange converts a hunk's start and optional count into a line range;
+// empty ranges are dropped.
+func hunkRange(start, count string) (LineRange, bool) {
+	s, _ := strconv.Atoi(start)
+	n := 1
+	if count != "" {
+		n, _ = strconv.Atoi(count)
+	}
+	if n == 0 {
+		return LineRange{}, false
+	}
+	return LineRange{Start: s, End: s + n - 1}, true
+}
+
+// FileAt returns the content of path at rev.
+func FileAt(ctx context.Context, rev, path string) ([]byte, error) {
+	out, err := Run(ctx, "show", rev+":"+path)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(out), nil
+}
+
+// BlameLine is the origin of a line as found by blame: the commit that last
+// changed it, and the path and line number it had in that commit.
+type BlameLine struct {
+	Hash string
+	Path string
+	Line int
+	// Author, Time, and Summary describe the commit: its mailmapped author,
+	// author date, and subject.
+	Author  string
+	Time    time.Time
+	Summary string
+}
+
+// Committed reports whether the line comes from a commit rather than from
+// uncommitted changes in the work tree.
+func (b BlameLine) Committed() bool {
+	return strings.Trim(b.Hash, "0") != ""
+}
+
+// Blame finds the origin of line of path as of rev, or in the work tree when
+// rev is empty.
+func Blame(ctx context.Context, rev, path string, line int) (BlameLine, error) {
+	lines, err := blame(ctx, rev, path, "-L", fmt.Sprintf("%d,%d", line, line))
+	if err != nil {
+		return BlameLine{}, err
+	}
+	if len(lines) == 0 {
+		return BlameLine{}, fmt.Errorf("no blame for line %d of %s", line, path)
+	}
+	return lines[0], nil
+}
+
+// BlameFile finds the origin of every line of path as of rev, or in the work
+// tree when rev is empty, in one pass. The result is indexed by line number
+// minus one.
+func BlameFile(ctx context.Context, rev, path string) ([]BlameLine, error) {
+	return blame(ctx, rev, path)
+}
+
+// blame runs git blame --line-porcelain on path with extra args and parses
+// one BlameLine per line.
+func blame(ctx context.Context, rev, path string, extra ...string) ([]BlameLine, error) {
+	args := append([]string{"blame", "--line-porcelain"}, extra...)
+	if rev != "" {
+		args = append(args, rev)
+	}
+	out, err := Run(ctx, append(args, "--", path)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		lines  []BlameLine
+		header = true
+	)
+	for _, l := range strings.Split(out, "\n") {
+		if header {
+			fields := strings.Fields(l)
+			if len(fields) < 3 {
+				continue
+			}
+			b := BlameLine{Hash: fields[0], Path: path}
+			b.Line, _ = strconv.Atoi(fields[1])
+			lines = append(lines, b)
+			header = false
+			continue
+		}
+		b := &lines[len(lines)-1]
+		switch key, value, _ := strings.Cut(l, " "); key {
+		case "author":
+			b.Author = value
+		case "author-time":
+			if ts, err := strconv.ParseInt(value, 10, 64); err == nil {
+				b.Time = time.Unix(ts, 0)
+			}
+		case "summary":
+			b.Summary = value
+		case "filename":
+			b.Path = value
+		}
+		// The line's content, prefixed by a tab, ends its entry.
+		header = strings.HasPrefix(l, "\t")
+	}
+	if len(lines) == 0 && strings.TrimSpace(out) != "" {
+		return nil, fmt.Errorf("unexpected blame output %q", strings.TrimSpace(out))
+	}
+	return lines, nil
+}
+
+// PushedRef is a ref update reported by git push.
+type PushedRef struct {
+	Ref string `json:"ref"`
+	// Status is "new", "updated", "forced", "deleted", "up-to-date", or
+	// "rejected".
+	Status  string `json:"status"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// pushStatuses maps git push --porcelain flags to PushedRef statuses.
+var pushStatuses = map[string]string{
+	"*": "new",
+	" ": "updated",
+	"+": "forced",
+	"-": "deleted",
+	"=": "up-to-date",
+	"!": "rejected",
+}
+
+// Push pushes refspecs to remote, a remote name or URL, and returns the ref
+// updates it reported.
+func Push(ctx context.Context, remote string, refspecs []string, dryRun bool) ([]PushedRef, error) {
+	args := []string{"push", "--porcelain"}
+	if dryRun {
+		args = append(args, "--dry-run")
+	}
+	out, err := Run(ctx, append(append(args, remote), refspecs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []PushedRef
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) < 3 || len(parts[0]) != 1 {
+			continue
+		}
+		_, to, _ := strings.Cut(parts[1], ":")
+		refs = append(refs, PushedRef{Ref: to, Status: pushStatuses[parts[0]], Summary: parts[2]})
+	}
+	return refs, nil
+}
+
+// ParseDate resolves a git date such as "1 week ago" or "2025-01-31" to a
+// time, the way --since would.
+func ParseDate(ctx context.Context, date string) (time.Time, error) {
+	out, err := Run(ctx, "rev-parse", "--since="+date)
+	if err != nil {
+		return time.Time{}, err
+	}
+	ts, err := strconv.ParseInt(strings.TrimPrefix(strings.TrimSpace(out), "--max-age="), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unrecognized date %q", date)
+	}
+	return time.Unix(ts, 0), nil
+}
+
+// Refs returns the names of the refs under prefix (e.g. "refs/remotes").
+func Refs(ctx context.Context, prefix string) ([]string, error) {
+	out, err := Run(ctx, "for-each-ref", "--format=%(refname)", prefix)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+// BranchesContaining returns the names of the local and remote-tracking
+// branches that hash is reachable from. Remote-tracking branches are named
+// without their remote, so refs/remotes/origin/main is reported as "main".
+func BranchesContaining(ctx context.Context, hash string) ([]string, error) {
+	out, err := Run(ctx, "for-each-ref", "--contains", hash, "--format=%(refname)", "refs/heads", "refs/remotes")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	seen := make(map[string]bool)
+	for _, ref := range strings.Fields(out) {
+		name, ok := strings.CutPrefix(ref, "refs/heads/")
+		if !ok {
+			_, name, _ = strings.Cut(strings.TrimPrefix(ref, "refs/remotes/"), "/")
+		}
+		if name == "" || name == "HEAD" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// ReflogEntry is one recorded update of a ref. Old is empty for the oldest
+// recorded update.
+type ReflogEntry struct {
+	Old     string
+	New     string
+	Subject string
+	Time    time.Time
+}
+
+// Reflog returns the recorded updates of ref, newest first.
+func Reflog(ctx context.Context, ref string) ([]ReflogEntry, error) {
+	out, err := Run(ctx, "log", "-g", "--date=unix", "--format=%H%x1f%gd%x1f%gs", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+	var entries []ReflogEntry
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 3 {
+			continue
+		}
+		// The selector reads ref@{<unix time>} with --date=unix.
+		selector := strings.TrimSuffix(fields[1], "}")
+		ts, _ := strconv.ParseInt(selector[strings.LastIndex(selector, "{")+1:], 10, 64)
+		entries = append(entries, ReflogEntry{New: fields[0], Subject: fields[2], Time: time.Unix(ts, 0)})
+	}
+	for i := 0; i+1 < len(entries); i++ {
+		entries[i].Old = entries[i+1].New
+	}
+	return entries, nil
+}
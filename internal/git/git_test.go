@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// newSubdirRepo creates a repository with files, keyed by path, committed
+// in one commit, and returns a context running git in its top directory and
+// one running git in its sub directory.
+func newSubdirRepo(t *testing.T, files map[string]string) (top, sub context.Context) {
+	t.Helper()
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	dir := t.TempDir()
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	gitIn(t, dir, "init", "-q")
+	gitIn(t, dir, "add", "-A")
+	gitIn(t, dir, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "Add files")
+	return WithDir(context.Background(), dir), WithDir(context.Background(), filepath.Join(dir, "sub"))
+}
+
+// gitIn runs git in dir, failing the test on errors.
+func gitIn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+func TestGrepFromSubdirectory(t *testing.T) {
+	top, sub := newSubdirRepo(t, map[string]string{
+		"main.go":     "package main // Foo\n",
+		"sub/file.go": "package sub // Foo\n",
+		"other/x.go":  "package other\n",
+	})
+	want := "HEAD:main.go:1:package main // Foo\nHEAD:sub/file.go:1:package sub // Foo\n"
+	for name, ctx := range map[string]context.Context{"top": top, "sub": sub} {
+		got, err := Grep(ctx, "Foo", "HEAD")
+		if err != nil {
+			t.Fatalf("Grep from %s: %v", name, err)
+		}
+		if got != want {
+			t.Errorf("Grep from %s = %q, want %q", name, got, want)
+		}
+	}
+	if got, err := Grep(sub, "Missing", "HEAD"); got != "" || err != nil {
+		t.Errorf("Grep without matches = %q, %v; want no matches and no error", got, err)
+	}
+}
+
+func TestDiffNumStatFromSubdirectory(t *testing.T) {
+	top, sub := newSubdirRepo(t, map[string]string{
+		"sub/f.txt":  "one\ntwo\n",
+		"sub/*.txt":  "literal\n",
+		"other.txt":  "x\n",
+		"sub/g.txt":  "not asked for\n",
+		"sub/h i.go": "a\nb\nc\n",
+	})
+	want := []FileStat{
+		{Path: "sub/*.txt", Added: 1},
+		{Path: "sub/f.txt", Added: 2},
+		{Path: "sub/h i.go", Added: 3},
+	}
+	for name, ctx := range map[string]context.Context{"top": top, "sub": sub} {
+		got, err := DiffNumStat(ctx, "", "HEAD", "sub/f.txt", "sub/*.txt", "sub/h i.go")
+		if err != nil {
+			t.Fatalf("DiffNumStat from %s: %v", name, err)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("DiffNumStat from %s = %+v, want %+v", name, got, want)
+		}
+	}
+}
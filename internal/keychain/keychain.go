@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package keychain keeps API keys in the operating system's credential
+// store: the login keychain on macOS, through security(1), and the Secret
+// Service (GNOME Keyring, KWallet) elsewhere, through secret-tool(1). Keys
+// are stored under the service "arc-git" with the provider as the account,
+// and never passed on a command line, where other users could see them.
+package keychain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// service is the keychain service the keys are stored under.
+const service = "arc-git"
+
+// ErrNotFound is returned by Get when no key is stored for the account.
+var ErrNotFound = errors.New("no key in the keychain")
+
+// ErrUnavailable is returned when the machine has no supported credential
+// store.
+var ErrUnavailable = errors.New("no keychain available (macOS security or secret-tool from libsecret is required)")
+
+// tool returns the credential store's executable.
+func tool() (string, error) {
+	name := "secret-tool"
+	if runtime.GOOS == "darwin" {
+		name = "security"
+	}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", ErrUnavailable
+	}
+	return path, nil
+}
+
+// Available reports whether keys can be stored on this machine.
+func Available() error {
+	_, err := tool()
+	return err
+}
+
+// Get returns the key stored for account.
+func Get(account string) (string, error) {
+	path, err := tool()
+	if err != nil {
+		return "", err
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command(path, "find-generic-password", "-s", service, "-a", account, "-w")
+	} else {
+		cmd = exec.Command(path, "lookup", "service", service, "account", account)
+	}
+	out, err := cmd.Output()
+	key := strings.TrimSpace(string(out))
+	if err != nil || key == "" {
+		// Both tools exit non-zero when nothing matches.
+		return "", ErrNotFound
+	}
+	return key, nil
+}
+
+// Set stores key for account, replacing any stored key.
+func Set(account, key string) error {
+	path, err := tool()
+	if err != nil {
+		return err
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		// In interactive mode security reads the command from stdin.
+		if strings.ContainsAny(key, "\"\\\n") {
+			return fmt.Errorf("the key contains characters the keychain cannot take")
+		}
+		cmd = exec.Command(path, "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w \"%s\"\n", service, account, key))
+	} else {
+		cmd = exec.Command(path, "store", "--label", "arc-git "+account+" API key", "service", service, "account", account)
+		cmd.Stdin = strings.NewReader(key)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to store the key: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package logging provides the leveled, structured logger shared by all
+// arc-git commands.
+//
+// Progress messages are logged at Info, diagnostic detail such as executed
+// git commands and request timings at Verbose, and internals such as request
+// sizes and cache lookups at Debug. Logs are written to stderr so that structured
+// command output on stdout stays machine-readable.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LevelVerbose sits between Debug and Info and is enabled by --verbose.
+const LevelVerbose = slog.Level(-2)
+
+// Options configures the shared logger.
+type Options struct {
+	// Verbose enables LevelVerbose messages.
+	Verbose bool
+	// Debug enables all messages, including LevelVerbose.
+	Debug bool
+	// Format is "text" (default) or "json".
+	Format string
+	// Writer receives log output; defaults to os.Stderr.
+	Writer io.Writer
+}
+
+var (
+	level    = new(slog.LevelVar)
+	explicit bool
+	logger   = slog.New(newTextHandler(os.Stderr, level))
+)
+
+// Configure replaces the shared logger according to opts.
+func Configure(opts Options) error {
+	w := opts.Writer
+	if w == nil {
+		w = os.Stderr
+	}
+
+	switch {
+	case opts.Debug:
+		level.Set(slog.LevelDebug)
+	case opts.Verbose:
+		level.Set(LevelVerbose)
+	default:
+		level.Set(slog.LevelInfo)
+	}
+	explicit = opts.Debug || opts.Verbose
+
+	switch opts.Format {
+	case "", "text":
+		logger = slog.New(newTextHandler(w, level))
+	case "json":
+		logger = slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{
+			Level:       level,
+			ReplaceAttr: replaceLevel,
+		}))
+	default:
+		return fmt.Errorf("unknown log format %q (expected text or json)", opts.Format)
+	}
+	return nil
+}
+
+// L returns the shared logger.
+func L() *slog.Logger {
+	return logger
+}
+
+// SuppressProgress hides Info-level progress messages, typically because the
+// command is emitting structured or quiet output. It has no effect when
+// --verbose or --debug was requested explicitly.
+func SuppressProgress() {
+	if !explicit {
+		level.Set(slog.LevelWarn)
+	}
+}
+
+// Verbose logs a message at LevelVerbose.
+func Verbose(msg string, args ...any) {
+	logger.Log(context.Background(), LevelVerbose, msg, args...)
+}
+
+// Timed logs msg at LevelVerbose with the elapsed time since start and any
+// error, and returns err unchanged.
+func Timed(start time.Time, err error, msg string, args ...any) error {
+	args = append(args, "duration", time.Since(start).Round(time.Millisecond).String())
+	if err != nil {
+		args = append(args, "error", err)
+	}
+	Verbose(msg, args...)
+	return err
+}
+
+// levelName returns the display name of a level.
+func levelName(l slog.Level) string {
+	if l == LevelVerbose {
+		return "VERBOSE"
+	}
+	return l.String()
+}
+
+// replaceLevel renders LevelVerbose by name in JSON output.
+func replaceLevel(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.LevelKey && len(groups) == 0 {
+		if l, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(levelName(l))
+		}
+	}
+	return a
+}
+
+// textHandler renders records for humans: Info messages as plain
+// "message key=value" lines, other levels prefixed with their name.
+type textHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Leveler
+	attrs []slog.Attr
+	group string
+}
+
+func newTextHandler(w io.Writer, level slog.Leveler) *textHandler {
+	return &textHandler{mu: &sync.Mutex{}, w: w, level: level}
+}
+
+func (h *textHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= h.level.Level()
+}
+
+func (h *textHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	if r.Level != slog.LevelInfo {
+		b.WriteString(levelName(r.Level))
+		b.WriteString(": ")
+	}
+	b.WriteString(r.Message)
+	for _, a := range h.attrs {
+		writeAttr(&b, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(&b, h.group, a)
+		return true
+	})
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), qualify(h.group, attrs)...)
+	return &clone
+}
+
+func (h *textHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	if clone.group != "" {
+		clone.group += "." + name
+	} else {
+		clone.group = name
+	}
+	return &clone
+}
+
+// qualify prefixes attribute keys with the group name.
+func qualify(group string, attrs []slog.Attr) []slog.Attr {
+	if group == "" {
+		return attrs
+	}
+	out := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		out[i] = slog.Attr{Key: group + "." + a.Key, Value: a.Value}
+	}
+	return out
+}
+
+// writeAttr appends " key=value", quoting values that contain spaces.
+func writeAttr(b *strings.Builder, group string, a slog.Attr) {
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	key := a.Key
+	if group != "" {
+		key = group + "." + key
+	}
+	val := a.Value.Resolve().String()
+	if strings.ContainsAny(val, " \t\n\"=") {
+		val = fmt.Sprintf("%q", val)
+	}
+	fmt.Fprintf(b, " %s=%s", key, val)
+}
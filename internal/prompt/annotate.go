@@ -3,11 +3,23 @@
 
 package prompt
 
+import "strings"
+
 // AnnotateCommitModel is the default model for commit annotation.
 const AnnotateCommitModel = "claude-sonnet-4-5-20250929"
 
+// annotateAnchors asks for one Anchor trailer per statement of a deep
+// annotation.
+const annotateAnchors = `
+
+After the Risk and Labels lines, add one line per sentence of the paragraph in this form, naming the file and the line ranges of the hunks the sentence is about:
+Anchor: <path>:<start>-<end>[,<start>-<end>...] | <the sentence, verbatim>
+Line numbers are those of the file after the commit, taken from the "+" side of the hunk headers. Omit the line for sentences that are not about specific hunks.`
+
 // AnnotateCommit returns the system and user prompts for annotating a commit.
-func AnnotateCommit(hash, message, author, date, diff string) (system, user string) {
+// With anchors, the annotation also ties each statement to the hunks it is
+// about.
+func AnnotateCommit(hash, message, author, date, diff string, anchors bool) (system, user string) {
 	system = `You are an expert code archaeologist and technical documentation specialist. Your task is to analyze git commits and generate clear, informative annotations that explain the technical significance of the changes.
 
 Your annotations should:
@@ -19,7 +31,14 @@ Your annotations should:
 6. Use present tense and clear, professional language
 7. Focus on the "why" and "impact", not just the "what"
 
-Format your annotation as a single paragraph without bullet points or markdown.`
+Format your annotation as a single paragraph without bullet points or markdown.
+
+After the paragraph, add a blank line followed by exactly these two trailer lines:
+Risk: <low, medium, or high: how likely the change is to cause regressions or need follow-up>
+Labels: <one to three comma-separated lowercase labels, such as feature, bugfix, refactor, performance, security, docs, tests, build, dependencies>`
+	if anchors {
+		system += annotateAnchors
+	}
 
 	user = `Analyze this git commit and provide a technical annotation:
 
@@ -35,3 +54,35 @@ Provide a technical annotation that explains the significance of these changes:`
 
 	return system, user
 }
+
+// AnnotateCorrection returns an addition to the system prompt of an
+// annotation that is regenerated because the previous draft named files,
+// functions, or commits that do not exist.
+func AnnotateCorrection(unverified []string) string {
+	return `
+
+A previous draft of this annotation mentioned ` + strings.Join(unverified, ", ") + `, which appear neither in the changes nor in the repository. Do not mention them; only name files, functions, and commits that the changes show.`
+}
+
+// AnnotateQuickModel is the default model for quick-tier annotation, which
+// only needs to produce a one-line gist.
+const AnnotateQuickModel = "claude-haiku-4-5-20251001"
+
+// AnnotateQuick returns the system and user prompts for a quick-tier
+// annotation, built from file stats and hunk headers instead of the full diff.
+func AnnotateQuick(hash, message, stats, hunks string) (system, user string) {
+	system = `You are a code archaeologist writing one-line gists of git commits. You only see the commit message, per-file line counts, and hunk headers (which name the enclosing function or section), not the changed lines.
+
+Write a single sentence of at most 25 words that says what the commit changes and where. Do not speculate beyond what the message, file names, and hunk headers support. Output only the sentence.`
+
+	user = `Commit: ` + hash + `
+Message: ` + message + `
+
+Files (added/deleted lines):
+` + stats + `
+Hunk headers:
+` + hunks + `
+One-line gist:`
+
+	return system, user
+}
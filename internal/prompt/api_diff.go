@@ -0,0 +1,33 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// APIDiffModel is the default model for API migration guides.
+const APIDiffModel = "claude-sonnet-4-5-20250929"
+
+// APIDiff returns the system and user prompts for writing a migration guide
+// from one module version to another. changes is a pre-rendered list of the
+// exported API changes with the commits that introduced them.
+func APIDiff(module, from, to, changes string) (system, user string) {
+	system = `You are a Go library maintainer writing upgrade notes for the downstream consumers of a module. You are given every change to the module's exported API between two versions, each with the commit that most likely introduced it.
+
+Your migration guide should:
+1. Start with a one-paragraph summary of how disruptive the upgrade is
+2. Have a section per breaking change (or group of related changes) showing what to change in calling code, with short before/after Go snippets when helpful
+3. Use the commit messages to explain why an API changed when they say so
+4. Briefly list notable additions consumers may want to adopt
+5. Reference commits by their short hash
+
+Write in Markdown. Do not invent APIs or behavior that are not in the list.`
+
+	user = `Module: ` + module + `
+Upgrading from ` + from + ` to ` + to + `
+
+Exported API changes:
+
+` + changes + `
+Write the migration guide:`
+
+	return system, user
+}
@@ -0,0 +1,263 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Prompt section names, which budget weights are configured by.
+const (
+	SectionSystem      = "system"
+	SectionRepo        = "repo"
+	SectionMetadata    = "metadata"
+	SectionDiff        = "diff"
+	SectionEnrichments = "enrichments"
+)
+
+// DefaultWeights are the shares of the context window trimmable sections get
+// when a prompt does not fit, relative to each other. Sections weighted 0
+// only get what is left once the others fit.
+var DefaultWeights = map[string]float64{
+	SectionSystem:      1,
+	SectionRepo:        2,
+	SectionMetadata:    1,
+	SectionDiff:        4,
+	SectionEnrichments: 1,
+}
+
+// modelWindow is the context window of a model family in tokens.
+type modelWindow struct {
+	family string
+	tokens int
+}
+
+// modelWindows is matched in order against model names, like modelPrices.
+var modelWindows = []modelWindow{
+	{"claude", 200000},
+	{"gpt-4.1", 1000000},
+	{"gpt-4o", 128000},
+	{"gemini", 1000000},
+}
+
+// defaultWindow is assumed for models of no known family.
+const defaultWindow = 128000
+
+// ContextWindow returns the context window of model in tokens.
+func ContextWindow(model string) int {
+	model = strings.ToLower(model)
+	for _, w := range modelWindows {
+		if strings.Contains(model, w.family) {
+			return w.tokens
+		}
+	}
+	return defaultWindow
+}
+
+// Section is a part of a prompt competing for the context window.
+type Section struct {
+	Name string
+	Text string
+	// Required sections are sent whole; the others share what is left.
+	Required bool
+	// Trim shortens text to at most maxChars; nil cuts whole lines from the
+	// end with TrimLines.
+	Trim func(text string, maxChars int) string
+}
+
+// Cut records a section that was trimmed to fit the budget.
+type Cut struct {
+	Section string
+	From    int
+	To      int
+}
+
+// Budget divides a token budget among prompt sections.
+type Budget struct {
+	// Tokens is the prompt size to stay within.
+	Tokens int
+	// Weights overrides DefaultWeights by section name.
+	Weights map[string]float64
+}
+
+// weight returns the share weight of the named section.
+func (b Budget) weight(name string) float64 {
+	if w, ok := b.Weights[name]; ok {
+		return w
+	}
+	return DefaultWeights[name]
+}
+
+// Fit returns the section texts, in order, trimmed so that together they
+// stay within the budget. Required sections are kept whole and the rest of
+// the budget is divided among the other sections by weight: sections that
+// need less than their share keep all of it and pass the remainder on, and
+// the rest are trimmed to their share, so that the lowest-weighted sections
+// lose the most. With no budget everything is returned unchanged.
+func (b Budget) Fit(sections ...Section) ([]string, []Cut) {
+	texts := make([]string, len(sections))
+	need := make([]int, len(sections))
+	total := 0
+	for i, s := range sections {
+		texts[i] = s.Text
+		need[i] = EstimateTokens(s.Text)
+		total += need[i]
+	}
+	if b.Tokens <= 0 || total <= b.Tokens {
+		return texts, nil
+	}
+
+	avail := b.Tokens
+	var open []int
+	for i, s := range sections {
+		if s.Required {
+			avail -= need[i]
+		} else {
+			open = append(open, i)
+		}
+	}
+	weights := make([]float64, len(sections))
+	for _, i := range open {
+		weights[i] = b.weight(sections[i].Name)
+	}
+	alloc := waterFill(max(avail, 0), need, weights, open)
+
+	var cuts []Cut
+	for _, i := range open {
+		if alloc[i] >= need[i] {
+			continue
+		}
+		trim := sections[i].Trim
+		if trim == nil {
+			trim = TrimLines
+		}
+		texts[i] = trim(sections[i].Text, alloc[i]*charsPerToken)
+		cuts = append(cuts, Cut{Section: sections[i].Name, From: need[i], To: EstimateTokens(texts[i])})
+	}
+	return texts, cuts
+}
+
+// waterFill divides avail among the open indexes by weight, giving each at
+// most its need and passing what it does not use on to the others. Weight 0
+// indexes share whatever is left once the weighted ones are satisfied.
+func waterFill(avail int, need []int, weights []float64, open []int) []int {
+	alloc := make([]int, len(need))
+	for len(open) > 0 && avail > 0 {
+		sum := 0.0
+		for _, i := range open {
+			sum += weights[i]
+		}
+		share := func(i int) int {
+			if sum == 0 {
+				return avail / len(open)
+			}
+			return int(float64(avail) * weights[i] / sum)
+		}
+
+		var rest []int
+		used := 0
+		for _, i := range open {
+			if need[i] <= share(i) {
+				alloc[i] = need[i]
+				used += need[i]
+			} else {
+				rest = append(rest, i)
+			}
+		}
+		if len(rest) == len(open) {
+			// Nobody fits in their share: the weighted are trimmed to their
+			// share and the unweighted get what rounding leaves.
+			var unweighted []int
+			for _, i := range open {
+				if sum > 0 && weights[i] == 0 {
+					unweighted = append(unweighted, i)
+					continue
+				}
+				alloc[i] = share(i)
+				used += alloc[i]
+			}
+			rest = unweighted
+		}
+		avail -= used
+		open = rest
+	}
+	return alloc
+}
+
+// TrimLines keeps the leading whole lines of text that fit in maxChars
+// together with a note of how many were left out. The note counts towards
+// maxChars; when not even the note fits, nothing is kept.
+func TrimLines(text string, maxChars int) string {
+	if len(text) <= maxChars {
+		return text
+	}
+	lines := strings.SplitAfter(strings.TrimSuffix(text, "\n"), "\n")
+	// The note's count has at most as many digits as the number of lines,
+	// and the kept lines may need a final newline.
+	room := maxChars - len(omissionNote(len(lines))) - 1
+	if room < 0 {
+		return ""
+	}
+	size, kept := 0, 0
+	for _, line := range lines {
+		if size+len(line) > room {
+			break
+		}
+		size += len(line)
+		kept++
+	}
+	omitted := len(lines) - kept
+	head := strings.Join(lines[:kept], "")
+	if head != "" && !strings.HasSuffix(head, "\n") {
+		head += "\n"
+	}
+	return head + omissionNote(omitted)
+}
+
+// omissionNote notes that n lines were left out.
+func omissionNote(n int) string {
+	return fmt.Sprintf("[%d lines omitted to fit the context budget]\n", n)
+}
+
+// TrimDiff shortens a multi-file patch to at most maxChars by sharing the
+// space among its files, so that every file keeps its header and its first
+// hunks rather than later files being dropped.
+func TrimDiff(diff string, maxChars int) string {
+	if len(diff) <= maxChars {
+		return diff
+	}
+
+	var files []string
+	for rest := diff; rest != ""; {
+		i := strings.Index(rest[1:], "\ndiff --git ")
+		if i < 0 {
+			files = append(files, rest)
+			break
+		}
+		files = append(files, rest[:i+2])
+		rest = rest[i+2:]
+	}
+
+	need := make([]int, len(files))
+	weights := make([]float64, len(files))
+	open := make([]int, len(files))
+	headers := 0
+	for i, f := range files {
+		header, _, _ := strings.Cut(f, "\n")
+		headers += len(header) + 1
+		need[i] = len(f) - len(header) - 1
+		weights[i] = 1
+		open[i] = i
+	}
+	alloc := waterFill(max(maxChars-headers, 0), need, weights, open)
+
+	var b strings.Builder
+	for i, f := range files {
+		header, body, _ := strings.Cut(f, "\n")
+		b.WriteString(header + "\n")
+		b.WriteString(TrimLines(body, alloc[i]))
+	}
+	return b.String()
+}
@@ -0,0 +1,297 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// block returns n lines of 8 characters, 2 estimated tokens each.
+func block(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "line%03d\n", i)
+	}
+	return b.String()
+}
+
+// omitted is what TrimLines leaves of block(n) when k lines fit.
+func omitted(n, k int) string {
+	return block(k) + fmt.Sprintf("[%d lines omitted to fit the context budget]\n", n-k)
+}
+
+func TestBudgetFit(t *testing.T) {
+	// lines gives the size of each section as a block; kept gives how many
+	// of its lines survive next to the omission note, -1 for the section to
+	// be unchanged, or -2 for it to be dropped. The note of a block of 100
+	// lines takes 47 characters.
+	type section struct {
+		name     string
+		lines    int
+		required bool
+		kept     int
+	}
+	tests := []struct {
+		name     string
+		budget   Budget
+		sections []section
+		wantCuts []string
+	}{
+		{
+			name:   "no budget",
+			budget: Budget{},
+			sections: []section{
+				{name: SectionSystem, lines: 1000, required: true, kept: -1},
+				{name: SectionDiff, lines: 1000, kept: -1},
+			},
+		},
+		{
+			name:   "within the budget",
+			budget: Budget{Tokens: 100},
+			sections: []section{
+				{name: SectionSystem, lines: 10, required: true, kept: -1},
+				{name: SectionDiff, lines: 40, kept: -1},
+			},
+		},
+		{
+			// Required sections are sent whole even when they alone
+			// overflow; the others are left with nothing.
+			name:   "required sections overflow",
+			budget: Budget{Tokens: 10},
+			sections: []section{
+				{name: SectionSystem, lines: 20, required: true, kept: -1},
+				{name: SectionDiff, lines: 10, kept: -2},
+				{name: SectionMetadata, lines: 3, kept: -2},
+			},
+			wantCuts: []string{SectionDiff, SectionMetadata},
+		},
+		{
+			// 100 tokens split 2:4 gives repo 33 and diff 66 tokens, or 132
+			// and 264 characters, 85 and 217 of them for lines.
+			name:   "shares by weight",
+			budget: Budget{Tokens: 100},
+			sections: []section{
+				{name: SectionRepo, lines: 100, kept: 10},
+				{name: SectionDiff, lines: 100, kept: 27},
+			},
+			wantCuts: []string{SectionRepo, SectionDiff},
+		},
+		{
+			// Metadata needs 10 of its 20-token share; the diff gets the
+			// other 90.
+			name:   "unused shares pass on",
+			budget: Budget{Tokens: 100},
+			sections: []section{
+				{name: SectionMetadata, lines: 5, kept: -1},
+				{name: SectionDiff, lines: 100, kept: 39},
+			},
+			wantCuts: []string{SectionDiff},
+		},
+		{
+			name:   "weights override the defaults",
+			budget: Budget{Tokens: 100, Weights: map[string]float64{SectionRepo: 4, SectionDiff: 1}},
+			sections: []section{
+				{name: SectionRepo, lines: 100, kept: 34},
+				{name: SectionDiff, lines: 100, kept: 4},
+			},
+			wantCuts: []string{SectionRepo, SectionDiff},
+		},
+		{
+			// The diff needs 40 tokens; enrichments, weighted 0, get the
+			// remaining 60.
+			name:   "zero weight gets the leftovers",
+			budget: Budget{Tokens: 100, Weights: map[string]float64{SectionEnrichments: 0}},
+			sections: []section{
+				{name: SectionDiff, lines: 20, kept: -1},
+				{name: SectionEnrichments, lines: 100, kept: 24},
+			},
+			wantCuts: []string{SectionEnrichments},
+		},
+		{
+			name:   "zero weight is starved when nothing is left",
+			budget: Budget{Tokens: 100, Weights: map[string]float64{SectionEnrichments: 0}},
+			sections: []section{
+				{name: SectionDiff, lines: 100, kept: 44},
+				{name: SectionEnrichments, lines: 10, kept: -2},
+			},
+			wantCuts: []string{SectionDiff, SectionEnrichments},
+		},
+		{
+			name:   "all weights zero share equally",
+			budget: Budget{Tokens: 100, Weights: map[string]float64{SectionRepo: 0, SectionDiff: 0}},
+			sections: []section{
+				{name: SectionRepo, lines: 100, kept: 19},
+				{name: SectionDiff, lines: 100, kept: 19},
+			},
+			wantCuts: []string{SectionRepo, SectionDiff},
+		},
+		{
+			name:   "required sections reduce what is shared",
+			budget: Budget{Tokens: 100},
+			sections: []section{
+				{name: SectionSystem, lines: 20, required: true, kept: -1},
+				{name: SectionDiff, lines: 100, kept: 24},
+			},
+			wantCuts: []string{SectionDiff},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sections []Section
+			for _, s := range tt.sections {
+				sections = append(sections, Section{Name: s.name, Text: block(s.lines), Required: s.required})
+			}
+			texts, cuts := tt.budget.Fit(sections...)
+			if len(texts) != len(sections) {
+				t.Fatalf("Fit returned %d texts for %d sections", len(texts), len(sections))
+			}
+			for i, s := range tt.sections {
+				want := sections[i].Text
+				switch {
+				case s.kept == -2:
+					want = ""
+				case s.kept >= 0:
+					want = omitted(s.lines, s.kept)
+				}
+				if texts[i] != want {
+					t.Errorf("section %s = %d bytes starting %q, want %d bytes starting %q",
+						s.name, len(texts[i]), head(texts[i]), len(want), head(want))
+				}
+			}
+			var names []string
+			for _, c := range cuts {
+				names = append(names, c.Section)
+				if c.To >= c.From {
+					t.Errorf("cut of %s from %d to %d tokens does not shrink it", c.Section, c.From, c.To)
+				}
+			}
+			if !slices.Equal(names, tt.wantCuts) {
+				t.Errorf("cuts = %q, want %q", names, tt.wantCuts)
+			}
+		})
+	}
+}
+
+func TestBudgetFitCustomTrim(t *testing.T) {
+	var got int
+	trim := func(text string, maxChars int) string {
+		got = maxChars
+		return text[:maxChars]
+	}
+	texts, _ := Budget{Tokens: 10}.Fit(Section{Name: SectionDiff, Text: block(100), Trim: trim})
+	if got != 40 || len(texts[0]) != 40 {
+		t.Errorf("Trim got maxChars %d and returned %d characters, want 40", got, len(texts[0]))
+	}
+}
+
+// head returns the start of s, for messages.
+func head(s string) string {
+	if len(s) > 40 {
+		return s[:40] + "..."
+	}
+	return s
+}
+
+func TestTrimLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxChars int
+		want     string
+	}{
+		{name: "fits", text: block(3), maxChars: 24, want: block(3)},
+		{name: "cut at a line", text: block(20), maxChars: 80, want: omitted(20, 4)},
+		{name: "only the note fits", text: block(10), maxChars: 50, want: "[10 lines omitted to fit the context budget]\n"},
+		{name: "not even the note fits", text: block(10), maxChars: 30, want: ""},
+		{name: "zero", text: block(2), maxChars: 0, want: ""},
+		{
+			name:     "no final newline",
+			text:     "one\ntwo\n" + strings.Repeat("x", 60),
+			maxChars: 53,
+			want:     "one\ntwo\n[1 lines omitted to fit the context budget]\n",
+		},
+	}
+	for _, tt := range tests {
+		got := TrimLines(tt.text, tt.maxChars)
+		if got != tt.want {
+			t.Errorf("%s: TrimLines = %q, want %q", tt.name, got, tt.want)
+		}
+		if len(got) > tt.maxChars {
+			t.Errorf("%s: TrimLines = %d characters, over the %d allowed", tt.name, len(got), tt.maxChars)
+		}
+	}
+}
+
+// patchFile returns the patch of a file adding n lines.
+func patchFile(name string, n int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n@@ -0,0 +1,%d @@\n", name, name, name, name, n)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "+added line %03d\n", i)
+	}
+	return b.String()
+}
+
+func TestTrimDiff(t *testing.T) {
+	small, large, other := patchFile("small.go", 3), patchFile("large.go", 500), patchFile("other.go", 20)
+	diff := small + large + other
+
+	if got := TrimDiff(diff, len(diff)); got != diff {
+		t.Errorf("TrimDiff of a diff that fits changed it")
+	}
+
+	for _, maxChars := range []int{0, 50, 400, 1000, 4000} {
+		got := TrimDiff(diff, maxChars)
+		var headers []string
+		for _, line := range strings.Split(got, "\n") {
+			if strings.HasPrefix(line, "diff --git ") {
+				headers = append(headers, line)
+			}
+		}
+		want := []string{"diff --git a/small.go b/small.go", "diff --git a/large.go b/large.go", "diff --git a/other.go b/other.go"}
+		if !slices.Equal(headers, want) {
+			t.Errorf("TrimDiff(%d) kept headers %q, want every file's in order", maxChars, headers)
+		}
+		if !strings.HasPrefix(got, "diff --git a/small.go") {
+			t.Errorf("TrimDiff(%d) does not start with the first file: %q", maxChars, head(got))
+		}
+		if len(got) >= len(diff) {
+			t.Errorf("TrimDiff(%d) = %d bytes, not shorter than the %d-byte diff", maxChars, len(got), len(diff))
+		}
+		// Only the file headers may overflow the budget.
+		if maxChars >= 400 && len(got) > maxChars {
+			t.Errorf("TrimDiff(%d) = %d bytes, over the budget", maxChars, len(got))
+		}
+	}
+
+	// With room to spare beyond the small files, they are kept whole and
+	// only the large one is cut.
+	got := TrimDiff(diff, 2000)
+	if !strings.Contains(got, small) || !strings.Contains(got, other) {
+		t.Errorf("TrimDiff(2000) cut the small files:\n%s", got)
+	}
+	if !strings.Contains(got, "diff --git a/large.go b/large.go\n--- a/large.go\n+++ b/large.go\n@@ -0,0 +1,500 @@\n+added line 000\n") {
+		t.Errorf("TrimDiff(2000) lost the start of the large file's first hunk")
+	}
+	if !strings.Contains(got, "lines omitted to fit the context budget]\ndiff --git a/other.go") {
+		t.Errorf("TrimDiff(2000) did not mark the cut in the large file before the next one")
+	}
+}
+
+func TestContextWindow(t *testing.T) {
+	for model, want := range map[string]int{
+		"claude-sonnet-4-5-20250929": 200000,
+		"GPT-4o-mini":                128000,
+		"gpt-4.1":                    1000000,
+		"gemini-2.5-pro":             1000000,
+		"llama-3":                    defaultWindow,
+	} {
+		if got := ContextWindow(model); got != want {
+			t.Errorf("ContextWindow(%q) = %d, want %d", model, got, want)
+		}
+	}
+}
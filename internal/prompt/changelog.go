@@ -0,0 +1,35 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+import "strings"
+
+// ChangelogFragmentModel is the default model for changelog fragments.
+const ChangelogFragmentModel = "claude-haiku-4-5-20251001"
+
+// ChangelogFragment returns the system and user prompts for writing the
+// changelog fragment of a branch. types lists the fragment types to choose
+// from; commits and diff describe the branch.
+func ChangelogFragment(types []string, commits, diff string) (system, user string) {
+	system = `You are a release manager writing the changelog entry for a pull request, in the style of towncrier news fragments. The entry will be compiled into the changelog at release time, next to the entries of other pull requests.
+
+The entry should:
+1. Describe the change from the user's point of view, in one to three sentences
+2. Say what users can now do, what was broken and is now fixed, or what they need to change
+3. Leave out implementation details, refactors, and tests unless they are the point of the change
+4. Use plain text or inline Markdown, without headings, bullets, or commit hashes
+
+After the entry, add a blank line followed by exactly one trailer line:
+Type: <one of ` + strings.Join(types, ", ") + `>`
+
+	user = `Commits on the branch:
+` + commits + `
+
+Diff:
+` + diff + `
+
+Write the changelog entry:`
+
+	return system, user
+}
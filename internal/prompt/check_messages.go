@@ -0,0 +1,37 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// CheckMessagesModel is the default model for commit message reviews.
+const CheckMessagesModel = "claude-sonnet-4-5-20250929"
+
+// CheckMessage returns the system and user prompts for judging whether a
+// commit message describes its change. conventions describes the message
+// rules the repository follows, for the suggested rewrite.
+func CheckMessage(hash, conventions, message, diff string) (system, user string) {
+	system = `You are a senior engineer reviewing the commit messages of a pull request before it merges. For one commit you are given its message and its diff. Judge whether the message tells a future reader what the change does and why, and whether it is accurate.
+
+Answer in exactly this format:
+Verdict: <good, vague, inaccurate, or incomplete>
+Feedback: <one or two sentences naming what is missing or wrong>
+Suggested:
+<a rewritten commit message: subject line, blank line, short wrapped body>
+
+Use "good" when the message is accurate and specific enough, even if it could be polished; then write "Feedback: none" and omit Suggested. Use "vague" when it does not say what changed (e.g. "fix bug", "updates"), "inaccurate" when it describes something the diff does not do, and "incomplete" when it leaves out a significant part of the change.
+
+The rewrite must only state what the diff and the original message support; do not invent motivations. Follow these conventions:
+` + conventions
+
+	user = `Commit: ` + hash + `
+
+Message:
+` + message + `
+
+Diff:
+` + diff + `
+
+Review the commit message:`
+
+	return system, user
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// CloseSummaryModel is the default model for issue-closure summaries.
+const CloseSummaryModel = "claude-sonnet-4-5-20250929"
+
+// CloseSummary returns the system and user prompts for summarizing how an
+// issue was resolved. commits is a pre-rendered description of every commit
+// that references the issue.
+func CloseSummary(issue, commits string) (system, user string) {
+	system = `You are a senior engineer writing the closing comment for a resolved issue. You are given every commit that references the issue, possibly spread across several branches. Your task is to explain how the issue was actually resolved.
+
+Your summary should:
+1. State the root cause or the need the issue described, as evidenced by the commits
+2. Explain what was changed to resolve it and where
+3. Mention follow-up fixes, reverts, or partial attempts if the history shows them
+4. Call out anything left unresolved or deferred
+5. Reference commits by their short hash
+6. Stay concise (one to three short paragraphs)
+
+Write in Markdown suitable for an issue comment. Do not invent details that are not supported by the commits.`
+
+	user = `Summarize how issue #` + issue + ` was resolved based on these commits:
+
+` + commits + `
+
+Write the closing summary:`
+
+	return system, user
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// CoverLetterModel is the default model for patch-series cover letters.
+const CoverLetterModel = "claude-sonnet-4-5-20250929"
+
+// CoverLetter returns the system and user prompts for writing a patch-series
+// cover letter. patches describes each patch in order; rangeDiff is the
+// output of git range-diff against the previous version, or empty for a
+// first version.
+func CoverLetter(version, patches, rangeDiff string) (system, user string) {
+	system = `You are an experienced kernel-style contributor writing the cover letter (patch 0/N) for a patch series sent to a mailing list with git send-email.
+
+The cover letter must:
+1. Start with a single line "Subject: <concise series title>" without the [PATCH] prefix
+2. Follow with a blank line and one or two plain-text paragraphs explaining the motivation and overall approach of the series
+3. Continue with a short blurb for every patch, one per line, in the form "  N/M: <what the patch does and why>"
+4. When a range-diff against the previous version is provided, end with a section "Changes in vX:" listing what changed since the previous version as "- " bullets (addressed feedback, reworked patches, added or dropped patches)
+
+Use plain text wrapped at 72 columns, no Markdown. Do not include the diffstat or a sign-off; git adds those.`
+
+	user = `Write the cover letter for this patch series.
+
+Version: ` + version + `
+
+Patches:
+` + patches
+
+	if rangeDiff != "" {
+		user += `
+Range-diff against the previous version:
+` + rangeDiff
+	}
+
+	user += `
+Write the cover letter:`
+
+	return system, user
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// DescribeRepoModel is the default model for repository overview documents.
+const DescribeRepoModel = "claude-sonnet-4-5-20250929"
+
+// describeRepoSections is the document structure shared by the full and
+// refresh prompts.
+const describeRepoSections = `The document should have these sections, as "##" headings under a "# <project> overview" title:
+1. Purpose: what the project does and who uses it, in a short paragraph
+2. Architecture: the main modules and the responsibility of each, and how they depend on each other
+3. Entry points: the binaries, commands, services, or public packages a reader would start from
+4. Evolution: how the project got to its current shape, citing short commit hashes for key changes
+5. Where to start: suggested reading order for a new contributor
+
+Write in Markdown. Refer to modules and files by their paths. Do not invent modules, files, or history that are not supported by the facts.`
+
+// DescribeRepo returns the system and user prompts for a repository overview
+// document. facts is a pre-rendered summary of the tree, entry points,
+// manifests, and key history.
+func DescribeRepo(facts string) (system, user string) {
+	system = `You are a senior engineer writing the architecture overview document of a repository for engineers who are new to it, based on the repository facts you are given.
+
+` + describeRepoSections
+
+	user = facts + `
+Write the overview document:`
+
+	return system, user
+}
+
+// DescribeRepoRefresh returns the system and user prompts for bringing an
+// existing overview document up to date with the commits made since it was
+// written.
+func DescribeRepoRefresh(document, facts, changes string) (system, user string) {
+	system = `You are a senior engineer keeping the architecture overview document of a repository current. You are given the existing document, the current repository facts, and the commits made since the document was last updated.
+
+Return the complete updated document. Change only what the new commits affect: add new modules and entry points, remove ones that no longer exist, and extend the Evolution section. Keep the wording, structure, and any edits people made elsewhere. If nothing relevant changed, return the document unchanged.
+
+` + describeRepoSections
+
+	user = `Existing document:
+` + document + `
+
+Current repository facts:
+` + facts + `
+Commits since the document was written:
+` + changes + `
+Write the updated document:`
+
+	return system, user
+}
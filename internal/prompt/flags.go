@@ -0,0 +1,29 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// FlagsModel is the default model for feature-flag cleanup commentary.
+const FlagsModel = "claude-sonnet-4-5-20250929"
+
+// Flags returns the system and user prompts for commenting on long-lived
+// feature flags. flags is a pre-rendered list of the stale flags with their
+// history and current references.
+func Flags(flags string) (system, user string) {
+	system = `You are a senior engineer reviewing feature flags that were meant to be temporary but are still in the code. For each flag you are given when it was introduced, by which commit, and where it is referenced today.
+
+For each flag, write a short paragraph that:
+1. Says what the flag appears to gate, based on its name, introducing commit, and references
+2. Judges whether it looks fully rolled out, abandoned, or still in active use
+3. Describes the likely cleanup work: which code paths to delete, which branch of each conditional to keep, and any config or tests to update
+4. Notes risks, such as references in many places or in configuration that may be set externally
+
+Use Markdown with one "### <flag>" heading per flag. Do not invent references beyond those listed.`
+
+	user = `These feature flags have outlived their expected lifetime:
+
+` + flags + `
+Write the cleanup commentary:`
+
+	return system, user
+}
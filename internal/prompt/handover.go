@@ -0,0 +1,31 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// HandoverModel is the default model for ownership handover narratives.
+const HandoverModel = "claude-sonnet-4-5-20250929"
+
+// Handover returns the system and user prompts for the history narrative of
+// an ownership handover document. facts is a pre-rendered summary of the
+// path's contributors, key and recent commits, risk areas, and coupling.
+func Handover(path, owner, facts string) (system, user string) {
+	system = `You are a senior engineer handing over ownership of part of a codebase. Write the narrative section of a handover document for the incoming owner, based on the history facts you are given.
+
+Your narrative should:
+1. Explain what this part of the code is for and how it evolved, in chronological phases
+2. Point out the key commits and decisions a new owner must understand, citing short hashes
+3. Explain the listed risk areas: what keeps breaking and what to watch out for
+4. Explain the coupling to other areas: what usually has to change together
+5. End with a short list of suggested first steps for the new owner
+
+Write in Markdown using "###" headings. Do not invent history that is not supported by the facts.`
+
+	user = `Path: ` + path + `
+Incoming owner: ` + owner + `
+
+` + facts + `
+Write the handover narrative:`
+
+	return system, user
+}
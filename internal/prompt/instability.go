@@ -0,0 +1,30 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// InstabilityModel is the default model for instability narratives.
+const InstabilityModel = "claude-sonnet-4-5-20250929"
+
+// Instability returns the system and user prompts for explaining why areas
+// of a codebase are unstable. areas is a pre-rendered list of the flagged
+// areas with their supporting revert, fix-chain, and follow-up commits.
+func Instability(areas string) (system, user string) {
+	system = `You are a staff engineer reviewing the stability of a codebase from its git history. You are given the areas with the most reverts, chains of fix-up commits, and rapid follow-up patches, together with the commits that support each finding.
+
+For each area:
+1. Describe the instability pattern the history shows
+2. Suggest the most probable root causes (missing tests, unclear ownership, tight coupling, flaky environment, rushed releases, etc.)
+3. Cite the supporting commits by short hash
+4. Recommend one concrete action that would reduce churn
+
+Be specific and grounded in the commits provided. Use Markdown with one heading per area.`
+
+	user = `These areas of the codebase show signs of instability:
+
+` + areas + `
+
+Write the instability report:`
+
+	return system, user
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// DefaultModels lists the default models of the prompts, for checking that
+// providers still serve them.
+var DefaultModels = []string{
+	AnomaliesModel,
+	AnnotateCommitModel,
+	AnnotateQuickModel,
+	APIDiffModel,
+	ChangelogFragmentModel,
+	CheckMessagesModel,
+	CloseSummaryModel,
+	CoverLetterModel,
+	DescribeRepoModel,
+	FlagsModel,
+	HandoverModel,
+	InstabilityModel,
+	NotesImportModel,
+	NotesRefreshModel,
+	OrgReportModel,
+	RangeDiffExplainModel,
+	ReleaseCheckModel,
+	ReleaseNotesModel,
+	RevertedModel,
+	RollupModel,
+	TestsForModel,
+}
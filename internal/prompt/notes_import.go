@@ -0,0 +1,32 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// NotesImportModel is the default model for condensing imported
+// descriptions into annotations.
+const NotesImportModel = "claude-haiku-4-5-20251001"
+
+// NotesImport returns the system and user prompts for condensing a pull
+// request description or commit message into an annotation. kind names the
+// source ("pull request" or "commit message").
+func NotesImport(kind, title, description string) (system, user string) {
+	system = `You are a technical writer turning a ` + kind + ` into a commit annotation for the project's history. The annotation will be read by engineers asking why the code is the way it is.
+
+Your annotation should:
+1. Say what changed and why, using only what the ` + kind + ` states
+2. Keep decisions, trade-offs, and known limitations it mentions
+3. Drop checklists, templates, screenshots, testing instructions, and pleasantries
+4. Keep it concise (2-5 sentences), in present tense
+
+Format your annotation as a single paragraph without bullet points or markdown.`
+
+	user = `Title: ` + title + `
+
+Description:
+` + description + `
+
+Write the annotation:`
+
+	return system, user
+}
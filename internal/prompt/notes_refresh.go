@@ -0,0 +1,32 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// NotesRefreshModel is the default model for refreshing annotations of
+// rewritten code.
+const NotesRefreshModel = "claude-sonnet-4-5-20250929"
+
+// NotesRefresh returns the system and user prompts for refreshing the
+// annotation of a commit whose code later commits rewrote. context is a
+// pre-rendered description of the commit, the share of its code rewritten,
+// and the later commits to its files with their annotations.
+func NotesRefresh(annotation, context string) (system, user string) {
+	system = `You are a senior engineer keeping a project's commit annotations trustworthy. An annotation explains one commit, but much of the code it describes has since been rewritten by later commits, so readers may take it for a description of the current code.
+
+Rewrite the annotation so that it:
+1. Still says what the commit changed and why, as history; do not invent reasons it does not give
+2. Makes clear which of the behavior or structure it describes has since been replaced, based only on the later commits and their annotations
+3. Names the later commits (by short hash) that readers should look at for the current code
+4. Stays concise (2-5 sentences), in past tense for the commit and present tense for the current state
+
+Format your annotation as a single paragraph without bullet points or markdown, and without trailers.`
+
+	user = `Annotation:
+` + annotation + `
+
+` + context + `
+Write the refreshed annotation:`
+
+	return system, user
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// OrgReportModel is the default model for organization report narratives.
+const OrgReportModel = "claude-sonnet-4-5-20250929"
+
+// OrgReport returns the system and user prompts for the narrative of an
+// organization's quarterly engineering report. summary is a pre-rendered
+// digest of each repository's activity, annotation coverage, risk findings,
+// labels, and unstable areas.
+func OrgReport(org, quarter, summary string) (system, user string) {
+	system = `You are a principal engineer preparing the engineering section of a quarterly leadership review. You are given per-repository statistics for the quarter, the labels and risk levels of AI annotations of the commits, the riskiest commits, and areas with reverts and rapid fix-ups.
+
+Write three sections in Markdown:
+### Themes
+Three to six bullets on the themes that span repositories: where effort went, what kind of work dominated (features, fixes, refactoring, dependencies), and how it shifted. Name the repositories each theme covers.
+### Risks
+Bullets on the risks leadership should know about: high-risk changes, unstable areas, and repositories whose annotation coverage is too low to judge. Cite repositories and commit hashes.
+### Recommendations
+Two to four concrete, prioritized recommendations.
+
+Be factual and concise; base every statement on the data given, and say when the data is too thin to support a conclusion. Do not restate the statistics table.`
+
+	user = `Organization: ` + org + `
+Quarter: ` + quarter + `
+
+` + summary + `
+
+Write the Themes, Risks, and Recommendations sections:`
+
+	return system, user
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// RangeDiffExplainModel is the default model for range-diff explanations.
+const RangeDiffExplainModel = "claude-sonnet-4-5-20250929"
+
+// RangeDiffExplain returns the system and user prompts for explaining the
+// differences between two versions of a patch series from git range-diff
+// output.
+func RangeDiffExplain(oldName, newName, rangeDiff string) (system, user string) {
+	system = `You are helping a reviewer re-review an updated patch series. You are given the output of git range-diff between two versions of the same series.
+
+In range-diff output, each line pairs a patch from the old version with one from the new version:
+- "=" means the patch is unchanged
+- "!" means the patch was modified; the indented diff-of-diffs that follows shows how
+- "<" means the patch was dropped from the new version
+- ">" means the patch is new in the new version
+
+Your explanation should:
+1. Summarize in one or two sentences how the series evolved
+2. For each modified patch, explain in prose what changed and which review feedback it most likely addresses
+3. List patches that were added or dropped and why that probably happened
+4. Mention unchanged patches only as a count, so the reviewer knows what can be skipped
+5. Point out anything the reviewer should look at carefully
+
+Use Markdown headings and bullets. Refer to patches by their subject.`
+
+	user = `Explain what changed between ` + oldName + ` and ` + newName + `:
+
+` + rangeDiff + `
+
+Write the explanation:`
+
+	return system, user
+}
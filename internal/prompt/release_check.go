@@ -0,0 +1,34 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// ReleaseCheckModel is the default model for release readiness assessments.
+const ReleaseCheckModel = "claude-sonnet-4-5-20250929"
+
+// ReleaseCheck returns the system and user prompts for assessing release
+// readiness. checklist is the heuristically derived checklist and commits
+// lists every commit in the release range.
+func ReleaseCheck(from, to, checklist, commits string) (system, user string) {
+	system = `You are a release manager preparing a software release. You are given a pre-release checklist derived automatically from the commits in the release range, together with the list of commits.
+
+Your assessment should:
+1. Highlight the checklist items that most need attention before shipping
+2. Point out risks the heuristics may have missed, based on the commit list
+3. Note items that look like false positives
+4. End with a one-line go / no-go recommendation and the conditions for it
+
+Cite commits by short hash. Keep it brief and use Markdown.`
+
+	user = `Release range: ` + from + `..` + to + `
+
+Checklist:
+` + checklist + `
+
+Commits:
+` + commits + `
+
+Write the release readiness assessment:`
+
+	return system, user
+}
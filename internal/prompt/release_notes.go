@@ -0,0 +1,32 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// ReleaseNotesModel is the default model for release notes.
+const ReleaseNotesModel = "claude-sonnet-4-5-20250929"
+
+// ReleaseNotes returns the system and user prompts for writing release notes
+// for a tag. commits lists the commits since the previous release, with any
+// existing AI annotations.
+func ReleaseNotes(tag, previous, commits string) (system, user string) {
+	system = `You are a release manager writing user-facing release notes. You are given every commit since the previous release, some with AI annotations explaining their impact.
+
+The release notes should:
+1. Open with a two or three sentence overview of the release
+2. Group changes under "### Highlights", "### Breaking changes", "### Features", "### Fixes", and "### Internal" headings, omitting empty groups
+3. Describe each change from the user's point of view in one line, ending with the short commit hash in parentheses
+4. Fold trivial commits (typos, formatting, dependency bumps) into a single line per group
+5. Never invent changes that the commits do not support
+
+Use GitHub-flavored Markdown and do not repeat the version as a top-level heading.`
+
+	user = `Write release notes for ` + tag + ` (previous release: ` + previous + `).
+
+Commits:
+` + commits + `
+
+Write the release notes:`
+
+	return system, user
+}
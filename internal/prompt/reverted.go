@@ -0,0 +1,25 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// RevertedModel is the default model for reverted-work summaries.
+const RevertedModel = "claude-sonnet-4-5-20250929"
+
+// Reverted returns the system and user prompts for explaining one reverted
+// change. context is a pre-rendered description of the original commit, its
+// revert, any later re-landing, and their annotations and issue references.
+func Reverted(context string) (system, user string) {
+	system = `You are a senior engineer preserving institutional memory about work that was reverted. You are given an original commit, the commit that reverted it, and whatever annotations, issue references, and re-landing evidence exist.
+
+Answer in exactly two lines:
+Attempted: <one or two sentences on what the original change tried to do>
+Reason: <one or two sentences on why it was backed out>
+
+Base the reason only on the messages, annotations, and issue references. If they do not say why, write "Reason: not recorded" followed by the most likely explanation, clearly marked as a guess.`
+
+	user = context + `
+Explain what was attempted and why it was reverted:`
+
+	return system, user
+}
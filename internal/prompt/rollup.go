@@ -0,0 +1,34 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// RollupModel is the default model for squash-merge annotation rollups.
+const RollupModel = "claude-sonnet-4-5-20250929"
+
+// Rollup returns the system and user prompts for folding the annotations of a
+// branch's commits into one annotation for the commit that squash-merged it.
+// commits is a pre-rendered list of the branch commits with their notes.
+func Rollup(hash, message, commits string) (system, user string) {
+	system = `You are an expert code archaeologist. A feature branch was squash-merged into a single commit, and the per-commit annotations written on the branch would otherwise be lost. Your task is to synthesize them into one annotation for the squash commit.
+
+Your annotation should:
+1. Explain the overall purpose and impact of the change as it landed
+2. Preserve the important technical details and design decisions from the branch annotations
+3. Mention approaches that were tried and dropped on the branch when the annotations show them
+4. Keep it concise but informative (3-8 sentences)
+5. Use present tense and clear, professional language
+
+Format your annotation as plain paragraphs without bullet points or markdown. Do not invent details that are not in the commit messages or annotations.`
+
+	user = `Squash commit: ` + hash + `
+Message: ` + message + `
+
+Branch commits, oldest first:
+
+` + commits + `
+
+Write the annotation for the squash commit:`
+
+	return system, user
+}
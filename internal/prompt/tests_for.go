@@ -0,0 +1,43 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// TestsForModel is the default model for test selection.
+const TestsForModel = "claude-sonnet-4-5-20250929"
+
+// TestsFor returns the system and user prompts for choosing the tests that
+// exercise a commit's changes. candidates lists the tests found by coverage
+// and path heuristics with their reasons, and testFiles the test files of the
+// tree the tests will run in.
+func TestsFor(shortHash, message, diff, candidates, testFiles string) (system, user string) {
+	system = `You are a senior engineer choosing which tests CI must run for a commit that is being backported, reverted, or re-applied. Running too few tests lets regressions through; running unrelated ones wastes CI time.
+
+You are given the commit, its diff, the candidate tests found by coverage data and file naming conventions, and the test files that exist in the target tree.
+
+Reply with one line per decision and nothing else:
+run <test file path> - <short reason>
+skip <candidate path> - <short reason>
+
+Rules:
+- Add "run" lines only for test files from the list that exercise the changed code but are not already candidates: tests of callers, integration tests, or tests of the same feature under other names
+- Add "skip" lines only for candidates marked low or medium that clearly do not exercise the change
+- Copy paths exactly as listed; never invent a path
+- If the candidates are complete, reply with the single line "none"`
+
+	user = `Commit ` + shortHash + `:
+` + message + `
+
+Diff:
+` + diff + `
+
+Candidate tests:
+` + candidates + `
+
+Test files in the target tree:
+` + testFiles + `
+
+Decide which further tests to run and which candidates to skip:`
+
+	return system, user
+}
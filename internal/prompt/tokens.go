@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+import "strings"
+
+// charsPerToken approximates the tokenizer ratio for English text and code.
+const charsPerToken = 4
+
+// EstimateTokens returns a rough token count for s. Providers do not report
+// usage uniformly, so arc-git budgets and metrics use this estimate.
+func EstimateTokens(s string) int {
+	return (len(s) + charsPerToken - 1) / charsPerToken
+}
+
+// Rough constants for estimating prompt size from a diff before it is read.
+const (
+	tokensPerDiffLine = 12
+	promptOverhead    = 400
+)
+
+// EstimateDiffTokens estimates the prompt tokens needed to annotate a commit
+// that changed the given number of lines.
+func EstimateDiffTokens(changedLines int) int {
+	return promptOverhead + changedLines*tokensPerDiffLine
+}
+
+// ReplyTokens is the assumed reply size of a request, for estimates made
+// before any reply exists.
+const ReplyTokens = 400
+
+// modelPrice is the list price of a model family in USD per million tokens.
+type modelPrice struct {
+	family string
+	input  float64
+	output float64
+}
+
+// modelPrices is matched in order against model names, so more specific
+// families come first. Names with a provider prefix, such as
+// "anthropic/claude-sonnet-4.5", match too.
+var modelPrices = []modelPrice{
+	{"opus-4-5", 5, 25},
+	{"opus-4.5", 5, 25},
+	{"opus", 15, 75},
+	{"sonnet", 3, 15},
+	{"haiku-4", 1, 5},
+	{"haiku", 0.8, 4},
+}
+
+// EstimateCost returns the list-price cost in USD of a request to model, and
+// false when the model's price is unknown.
+func EstimateCost(model string, inputTokens, outputTokens int) (float64, bool) {
+	for _, p := range modelPrices {
+		if strings.Contains(model, p.family) {
+			return (float64(inputTokens)*p.input + float64(outputTokens)*p.output) / 1e6, true
+		}
+	}
+	return 0, false
+}
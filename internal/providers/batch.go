@@ -0,0 +1,219 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package providers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// batchMaxTokens bounds the reply to each batched request.
+const batchMaxTokens = 4096
+
+// BatchRequest is one prompt of a batch.
+type BatchRequest struct {
+	// ID identifies the request among the batch's results.
+	ID     string
+	Model  string
+	System string
+	Prompt string
+}
+
+// Batch is the state of a submitted batch.
+type Batch struct {
+	ID string `json:"id"`
+	// Ended reports whether every request has been processed (or the batch
+	// was canceled or expired); results are only available then.
+	Ended      bool `json:"ended"`
+	Processing int  `json:"processing"`
+	Succeeded  int  `json:"succeeded"`
+	Errored    int  `json:"errored"`
+	// Expired counts requests the provider gave up on, including canceled
+	// ones.
+	Expired int `json:"expired"`
+}
+
+// BatchResult is the outcome of one request of a batch.
+type BatchResult struct {
+	ID    string
+	Text  string
+	Error string
+}
+
+// Batcher submits prompts to a provider's batch API, which processes them
+// asynchronously at a discount.
+type Batcher interface {
+	// Name returns the provider identifier.
+	Name() string
+	// SubmitBatch submits requests as one batch and returns its id.
+	SubmitBatch(ctx context.Context, requests []BatchRequest) (string, error)
+	// GetBatch returns the state of a batch.
+	GetBatch(ctx context.Context, id string) (Batch, error)
+	// BatchResults returns the results of an ended batch.
+	BatchResults(ctx context.Context, id string) ([]BatchResult, error)
+}
+
+// NewBatcher returns a batch client for the named provider.
+func NewBatcher(name, apiKey string) (Batcher, error) {
+	// Uploads and result files can be large.
+	client := &http.Client{Timeout: 5 * time.Minute}
+	switch Canonical(name) {
+	case "anthropic":
+		return &anthropic{api{name: "anthropic", baseURL: "https://api.anthropic.com", key: apiKey, client: client}}, nil
+	default:
+		return nil, fmt.Errorf("provider %q does not offer a batch API arc-git supports (supported: anthropic)", name)
+	}
+}
+
+// anthropicBatch is a Message Batches API batch.
+type anthropicBatch struct {
+	ID               string `json:"id"`
+	ProcessingStatus string `json:"processing_status"`
+	RequestCounts    struct {
+		Processing int `json:"processing"`
+		Succeeded  int `json:"succeeded"`
+		Errored    int `json:"errored"`
+		Canceled   int `json:"canceled"`
+		Expired    int `json:"expired"`
+	} `json:"request_counts"`
+	ResultsURL string `json:"results_url"`
+}
+
+// SubmitBatch creates a Message Batches API batch.
+func (a *anthropic) SubmitBatch(ctx context.Context, requests []BatchRequest) (string, error) {
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	type params struct {
+		Model     string    `json:"model"`
+		MaxTokens int       `json:"max_tokens"`
+		System    string    `json:"system,omitempty"`
+		Messages  []message `json:"messages"`
+	}
+	type request struct {
+		CustomID string `json:"custom_id"`
+		Params   params `json:"params"`
+	}
+
+	body := struct {
+		Requests []request `json:"requests"`
+	}{}
+	for _, r := range requests {
+		body.Requests = append(body.Requests, request{
+			CustomID: r.ID,
+			Params: params{
+				Model:     r.Model,
+				MaxTokens: batchMaxTokens,
+				System:    r.System,
+				Messages:  []message{{Role: "user", Content: r.Prompt}},
+			},
+		})
+	}
+
+	var out anthropicBatch
+	if err := a.do(ctx, http.MethodPost, "/v1/messages/batches", a.headers(), body, &out); err != nil {
+		return "", err
+	}
+	return out.ID, nil
+}
+
+// GetBatch reads a Message Batches API batch.
+func (a *anthropic) GetBatch(ctx context.Context, id string) (Batch, error) {
+	out, err := a.batch(ctx, id)
+	if err != nil {
+		return Batch{}, err
+	}
+	return Batch{
+		ID:         out.ID,
+		Ended:      out.ProcessingStatus == "ended",
+		Processing: out.RequestCounts.Processing,
+		Succeeded:  out.RequestCounts.Succeeded,
+		Errored:    out.RequestCounts.Errored,
+		Expired:    out.RequestCounts.Expired + out.RequestCounts.Canceled,
+	}, nil
+}
+
+// batch fetches the raw batch.
+func (a *anthropic) batch(ctx context.Context, id string) (anthropicBatch, error) {
+	var out anthropicBatch
+	err := a.get(ctx, "/v1/messages/batches/"+id, a.headers(), &out)
+	return out, err
+}
+
+// BatchResults streams the batch's JSONL results file.
+func (a *anthropic) BatchResults(ctx context.Context, id string) ([]BatchResult, error) {
+	b, err := a.batch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if b.ResultsURL == "" {
+		return nil, fmt.Errorf("batch %s has no results yet (status %s)", id, b.ProcessingStatus)
+	}
+
+	body, err := a.open(ctx, http.MethodGet, b.ResultsURL, a.headers(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var results []BatchResult
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry struct {
+			CustomID string `json:"custom_id"`
+			Result   struct {
+				Type    string `json:"type"`
+				Message struct {
+					Content []struct {
+						Type string `json:"type"`
+						Text string `json:"text"`
+					} `json:"content"`
+				} `json:"message"`
+				Error struct {
+					Error struct {
+						Message string `json:"message"`
+					} `json:"error"`
+				} `json:"error"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode %s batch result: %w", a.name, err)
+		}
+
+		result := BatchResult{ID: entry.CustomID}
+		switch entry.Result.Type {
+		case "succeeded":
+			var text strings.Builder
+			for _, c := range entry.Result.Message.Content {
+				if c.Type == "text" {
+					text.WriteString(c.Text)
+				}
+			}
+			result.Text = text.String()
+		case "errored":
+			result.Error = entry.Result.Error.Error.Message
+			if result.Error == "" {
+				result.Error = "request failed"
+			}
+		default:
+			result.Error = "request " + entry.Result.Type
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s batch results: %w", a.name, err)
+	}
+	return results, nil
+}
@@ -0,0 +1,274 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package providers queries AI provider APIs for their health and the models
+// they serve, and submits prompts to their batch APIs. Interactive prompts go
+// through the arc-sdk AI service.
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Catalog is the set of provider queries arc-git relies on.
+type Catalog interface {
+	// Name returns the provider identifier (e.g. "anthropic").
+	Name() string
+	// Check verifies that the provider is reachable and accepts the key.
+	Check(ctx context.Context) error
+	// Models lists the models the provider serves.
+	Models(ctx context.Context) ([]Model, error)
+}
+
+// Model is a model served by a provider. Zero fields are not exposed by the
+// provider.
+type Model struct {
+	ID            string `json:"id"`
+	Name          string `json:"name,omitempty"`
+	ContextTokens int    `json:"context_tokens,omitempty"`
+	// InputPrice and OutputPrice are in USD per million tokens.
+	InputPrice  float64 `json:"input_usd_per_mtok,omitempty"`
+	OutputPrice float64 `json:"output_usd_per_mtok,omitempty"`
+}
+
+// Names lists the providers that can be queried.
+var Names = []string{"anthropic", "openrouter"}
+
+// envKeys names the environment variable holding each provider's API key.
+var envKeys = map[string]string{
+	"anthropic":  "ANTHROPIC_API_KEY",
+	"openrouter": "OPENROUTER_API_KEY",
+}
+
+// Canonical returns the provider identifier for name, resolving aliases such
+// as "claude".
+func Canonical(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "claude" {
+		return "anthropic"
+	}
+	return name
+}
+
+// EnvKey returns the API key for the provider from its environment variable,
+// and the variable's name.
+func EnvKey(name string) (key, variable string) {
+	variable = envKeys[Canonical(name)]
+	if variable == "" {
+		return "", ""
+	}
+	return os.Getenv(variable), variable
+}
+
+// New returns a catalog for the named provider.
+func New(name, apiKey string) (Catalog, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	switch Canonical(name) {
+	case "anthropic":
+		return &anthropic{api{name: "anthropic", baseURL: "https://api.anthropic.com", key: apiKey, client: client}}, nil
+	case "openrouter":
+		return &openRouter{api{name: "openrouter", baseURL: "https://openrouter.ai/api", key: apiKey, client: client}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported provider %q (supported: %s)", name, strings.Join(Names, ", "))
+	}
+}
+
+// Find returns the model of models that id refers to. Ids match when they are
+// equal or one is the other plus a suffix such as a date, ignoring a vendor
+// prefix ("anthropic/") and "." versus "-" in version numbers, so that
+// aliases and dated ids match across providers.
+func Find(models []Model, id string) (Model, bool) {
+	want := normalizeID(id)
+	for _, m := range models {
+		got := normalizeID(m.ID)
+		if got == want || strings.HasPrefix(want, got+"-") || strings.HasPrefix(got, want+"-") {
+			return m, true
+		}
+	}
+	return Model{}, false
+}
+
+// normalizeID lower-cases id and drops its vendor prefix and dots.
+func normalizeID(id string) string {
+	id = strings.ToLower(id)
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		id = id[i+1:]
+	}
+	return strings.ReplaceAll(id, ".", "-")
+}
+
+// api holds what the provider clients share.
+type api struct {
+	name    string
+	baseURL string
+	key     string
+	client  *http.Client
+}
+
+// Name returns the provider identifier.
+func (a *api) Name() string { return a.name }
+
+// get performs a GET request with the given auth headers and decodes the JSON
+// response into out.
+func (a *api) get(ctx context.Context, path string, headers map[string]string, out interface{}) error {
+	return a.do(ctx, http.MethodGet, path, headers, nil, out)
+}
+
+// do performs a request, encoding in as the JSON body when non-nil, and
+// decodes the JSON response into out.
+func (a *api) do(ctx context.Context, method, path string, headers map[string]string, in, out interface{}) error {
+	body, err := a.open(ctx, method, a.baseURL+path, headers, in)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if err := json.NewDecoder(body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", a.name, err)
+	}
+	return nil
+}
+
+// open performs a request against target and returns the response body of a
+// successful response.
+func (a *api) open(ctx context.Context, method, target string, headers map[string]string, in interface{}) (io.ReadCloser, error) {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		// The URL names the models endpoint, which would read as a model
+		// problem; the cause is enough.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("%s request failed: %w", a.name, err)
+	}
+
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("%s API: %s: %s", a.name, resp.Status, bytes.TrimSpace(msg))
+	}
+	return resp.Body, nil
+}
+
+// anthropic implements Catalog against the Anthropic API, which does not
+// expose context sizes or prices.
+type anthropic struct{ api }
+
+// headers returns the Anthropic auth headers.
+func (a *anthropic) headers() map[string]string {
+	return map[string]string{"x-api-key": a.key, "anthropic-version": "2023-06-01"}
+}
+
+// Check lists a single model, which needs a valid key.
+func (a *anthropic) Check(ctx context.Context) error {
+	var out struct{}
+	return a.get(ctx, "/v1/models?limit=1", a.headers(), &out)
+}
+
+// Models lists every model, following pagination.
+func (a *anthropic) Models(ctx context.Context) ([]Model, error) {
+	var models []Model
+	after := ""
+	for {
+		path := "/v1/models?limit=1000"
+		if after != "" {
+			path += "&after_id=" + after
+		}
+		var page struct {
+			Data []struct {
+				ID          string `json:"id"`
+				DisplayName string `json:"display_name"`
+			} `json:"data"`
+			HasMore bool   `json:"has_more"`
+			LastID  string `json:"last_id"`
+		}
+		if err := a.get(ctx, path, a.headers(), &page); err != nil {
+			return nil, err
+		}
+		for _, m := range page.Data {
+			models = append(models, Model{ID: m.ID, Name: m.DisplayName})
+		}
+		if !page.HasMore || page.LastID == "" {
+			return models, nil
+		}
+		after = page.LastID
+	}
+}
+
+// openRouter implements Catalog against the OpenRouter API.
+type openRouter struct{ api }
+
+// headers returns the OpenRouter auth headers.
+func (o *openRouter) headers() map[string]string {
+	return map[string]string{"Authorization": "Bearer " + o.key}
+}
+
+// Check reads the key's details; the model list is public and would not
+// catch a bad key.
+func (o *openRouter) Check(ctx context.Context) error {
+	var out struct{}
+	return o.get(ctx, "/v1/key", o.headers(), &out)
+}
+
+// Models lists every model with its context length and per-token prices.
+func (o *openRouter) Models(ctx context.Context) ([]Model, error) {
+	var out struct {
+		Data []struct {
+			ID            string `json:"id"`
+			Name          string `json:"name"`
+			ContextLength int    `json:"context_length"`
+			Pricing       struct {
+				Prompt     string `json:"prompt"`
+				Completion string `json:"completion"`
+			} `json:"pricing"`
+		} `json:"data"`
+	}
+	if err := o.get(ctx, "/v1/models", o.headers(), &out); err != nil {
+		return nil, err
+	}
+
+	models := make([]Model, 0, len(out.Data))
+	for _, m := range out.Data {
+		input, _ := strconv.ParseFloat(m.Pricing.Prompt, 64)
+		output, _ := strconv.ParseFloat(m.Pricing.Completion, 64)
+		models = append(models, Model{
+			ID:            m.ID,
+			Name:          m.Name,
+			ContextTokens: m.ContextLength,
+			InputPrice:    input * 1e6,
+			OutputPrice:   output * 1e6,
+		})
+	}
+	return models, nil
+}
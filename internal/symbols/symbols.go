@@ -0,0 +1,259 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package symbols finds the functions, methods, and types that changed lines
+// fall in, by parsing source files with tree-sitter grammars.
+package symbols
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/golang"
+	"github.com/smacker/go-tree-sitter/java"
+	"github.com/smacker/go-tree-sitter/javascript"
+	"github.com/smacker/go-tree-sitter/python"
+	"github.com/smacker/go-tree-sitter/rust"
+	"github.com/smacker/go-tree-sitter/typescript/tsx"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+	"github.com/yourorg/arc-git/internal/git"
+)
+
+// Symbol kinds.
+const (
+	KindFunc      = "func"
+	KindMethod    = "method"
+	KindType      = "type"
+	KindClass     = "class"
+	KindInterface = "interface"
+)
+
+// Symbol is a definition touched by a change.
+type Symbol struct {
+	Kind string `json:"kind"`
+	// Name is qualified by enclosing classes, impls, or receivers, e.g.
+	// "Session.Refresh".
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// String renders the symbol as "kind name path", the form stored in notes.
+func (s Symbol) String() string {
+	return s.Kind + " " + s.Name + " " + s.Path
+}
+
+// definition describes a node type that defines a symbol.
+type definition struct {
+	kind string
+	// container definitions qualify the names of definitions nested in them.
+	container bool
+	// nameField is the field holding the name; "name" when empty.
+	nameField string
+}
+
+// language is a tree-sitter grammar with the node types that define symbols.
+type language struct {
+	grammar func() *sitter.Language
+	defs    map[string]definition
+}
+
+var (
+	jsDefs = map[string]definition{
+		"function_declaration":           {kind: KindFunc},
+		"generator_function_declaration": {kind: KindFunc},
+		"class_declaration":              {kind: KindClass, container: true},
+		"method_definition":              {kind: KindMethod},
+	}
+	tsDefs = merged(jsDefs, map[string]definition{
+		"abstract_class_declaration": {kind: KindClass, container: true},
+		"interface_declaration":      {kind: KindInterface, container: true},
+		"type_alias_declaration":     {kind: KindType},
+		"enum_declaration":           {kind: KindType},
+	})
+
+	// languages maps file extensions to grammars.
+	languages = map[string]language{
+		".go": {golang.GetLanguage, map[string]definition{
+			"function_declaration": {kind: KindFunc},
+			"method_declaration":   {kind: KindMethod},
+			"type_spec":            {kind: KindType},
+		}},
+		".py": {python.GetLanguage, map[string]definition{
+			"function_definition": {kind: KindFunc},
+			"class_definition":    {kind: KindClass, container: true},
+		}},
+		".js":  {javascript.GetLanguage, jsDefs},
+		".jsx": {javascript.GetLanguage, jsDefs},
+		".mjs": {javascript.GetLanguage, jsDefs},
+		".cjs": {javascript.GetLanguage, jsDefs},
+		".ts":  {typescript.GetLanguage, tsDefs},
+		".tsx": {tsx.GetLanguage, tsDefs},
+		".rs": {rust.GetLanguage, map[string]definition{
+			"function_item": {kind: KindFunc},
+			"struct_item":   {kind: KindType},
+			"enum_item":     {kind: KindType},
+			"trait_item":    {kind: KindInterface, container: true},
+			"impl_item":     {kind: KindType, container: true, nameField: "type"},
+			"mod_item":      {kind: KindType, container: true},
+		}},
+		".java": {java.GetLanguage, map[string]definition{
+			"class_declaration":       {kind: KindClass, container: true},
+			"interface_declaration":   {kind: KindInterface, container: true},
+			"enum_declaration":        {kind: KindType, container: true},
+			"record_declaration":      {kind: KindClass, container: true},
+			"method_declaration":      {kind: KindMethod},
+			"constructor_declaration": {kind: KindMethod},
+		}},
+	}
+)
+
+// merged returns the union of two definition tables.
+func merged(a, b map[string]definition) map[string]definition {
+	out := make(map[string]definition, len(a)+len(b))
+	for k, v := range a {
+		out[k] = v
+	}
+	for k, v := range b {
+		out[k] = v
+	}
+	return out
+}
+
+// Supported reports whether file's language can be parsed.
+func Supported(file string) bool {
+	_, ok := languages[strings.ToLower(path.Ext(file))]
+	return ok
+}
+
+// span is a parsed definition and the lines it covers.
+type span struct {
+	symbol Symbol
+	start  int
+	end    int
+	// impl marks Rust impl blocks, which only qualify the functions in them.
+	impl bool
+}
+
+// Enclosing parses src, the content of file, and returns the innermost
+// definitions containing any line of ranges, sorted by name. Lines outside
+// every definition are ignored.
+func Enclosing(ctx context.Context, file string, src []byte, ranges []git.LineRange) ([]Symbol, error) {
+	lang, ok := languages[strings.ToLower(path.Ext(file))]
+	if !ok || len(ranges) == 0 {
+		return nil, nil
+	}
+	root, err := sitter.ParseCtx(ctx, src, lang.grammar())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	var spans []span
+	collect(root, src, file, lang.defs, nil, &spans)
+
+	seen := make(map[string]bool)
+	var symbols []Symbol
+	for _, r := range ranges {
+		for line := r.Start; line <= r.End; line++ {
+			// Nested definitions start later, so the innermost one containing
+			// the line is the one that starts last; impl blocks only count
+			// when nothing inside them does.
+			best := -1
+			for i, s := range spans {
+				if s.start > line || s.end < line {
+					continue
+				}
+				if best < 0 || (spans[best].impl && !s.impl) || (s.impl == spans[best].impl && s.start >= spans[best].start) {
+					best = i
+				}
+			}
+			if best >= 0 && !seen[spans[best].symbol.String()] {
+				seen[spans[best].symbol.String()] = true
+				symbols = append(symbols, spans[best].symbol)
+			}
+		}
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		return symbols[i].Name < symbols[j].Name
+	})
+	return symbols, nil
+}
+
+// collect walks the tree under n, recording definitions with names qualified
+// by the containers in scope.
+func collect(n *sitter.Node, src []byte, file string, defs map[string]definition, scope []string, spans *[]span) {
+	for i := 0; i < int(n.NamedChildCount()); i++ {
+		child := n.NamedChild(i)
+		def, ok := defs[child.Type()]
+		name := ""
+		if ok {
+			name = definitionName(child, src, def)
+		}
+		if name == "" {
+			collect(child, src, file, defs, scope, spans)
+			continue
+		}
+
+		kind := def.kind
+		qualified := append(append([]string{}, scope...), name)
+		switch {
+		case child.Type() == "method_declaration" && def.kind == KindMethod && receiverType(child, src) != "":
+			// Go methods are qualified by their receiver type.
+			qualified = append(append([]string{}, scope...), receiverType(child, src), name)
+		case kind == KindFunc && len(scope) > 0:
+			kind = KindMethod
+		}
+		*spans = append(*spans, span{
+			symbol: Symbol{Kind: kind, Name: strings.Join(qualified, "."), Path: file},
+			start:  int(child.StartPoint().Row) + 1,
+			end:    int(child.EndPoint().Row) + 1,
+			impl:   child.Type() == "impl_item",
+		})
+
+		inner := scope
+		if def.container {
+			inner = qualified
+		}
+		collect(child, src, file, defs, inner, spans)
+	}
+}
+
+// definitionName returns the name of a definition node.
+func definitionName(n *sitter.Node, src []byte, def definition) string {
+	field := def.nameField
+	if field == "" {
+		field = "name"
+	}
+	name := n.ChildByFieldName(field)
+	if name == nil {
+		return ""
+	}
+	return baseTypeName(name.Content(src))
+}
+
+// receiverType returns the type name of a Go method's receiver.
+func receiverType(n *sitter.Node, src []byte) string {
+	recv := n.ChildByFieldName("receiver")
+	if recv == nil || recv.NamedChildCount() == 0 {
+		return ""
+	}
+	t := recv.NamedChild(0).ChildByFieldName("type")
+	if t == nil {
+		return ""
+	}
+	return baseTypeName(t.Content(src))
+}
+
+// baseTypeName strips pointers, references, and type arguments from a type,
+// e.g. "*Cache[K, V]" becomes "Cache".
+func baseTypeName(s string) string {
+	s = strings.TrimLeft(s, "*&")
+	s = strings.TrimPrefix(s, "mut ")
+	if i := strings.IndexAny(s, "[<("); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package telemetry wires optional OpenTelemetry tracing and metrics.
+//
+// Instrumentation is always compiled in but exports nothing unless an OTLP
+// endpoint is configured through the standard environment variables
+// (OTEL_EXPORTER_OTLP_ENDPOINT, or the per-signal
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT / OTEL_EXPORTER_OTLP_METRICS_ENDPOINT).
+// Exporter headers, timeouts, and TLS settings follow the usual OTEL_*
+// variables as well. Setting OTEL_SDK_DISABLED=true turns everything off.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies arc-git's tracer and meter.
+const instrumentationName = "github.com/yourorg/arc-git"
+
+// Instruments holds the metric instruments recorded by arc-git.
+type Instruments struct {
+	// Tokens counts estimated prompt and completion tokens, by direction and model.
+	Tokens metric.Int64Counter
+	// Failures counts failed operations, by stage.
+	Failures metric.Int64Counter
+	// Requests counts AI requests, by model.
+	Requests metric.Int64Counter
+}
+
+var instruments = newInstruments()
+
+// Setup installs OTLP trace and metric exporters when an endpoint is
+// configured. The returned function flushes and shuts them down; it is safe
+// to call when telemetry is disabled.
+func Setup(ctx context.Context, version string) (func(context.Context) error, error) {
+	if !enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName("arc-git"),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var shutdowns []func(context.Context) error
+
+	if endpointFor("TRACES") {
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, err
+		}
+		tp := sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithResource(res),
+		)
+		otel.SetTracerProvider(tp)
+		shutdowns = append(shutdowns, tp.Shutdown)
+	}
+
+	if endpointFor("METRICS") {
+		exporter, err := otlpmetrichttp.New(ctx)
+		if err != nil {
+			return nil, err
+		}
+		mp := sdkmetric.NewMeterProvider(
+			sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)),
+			sdkmetric.WithResource(res),
+		)
+		otel.SetMeterProvider(mp)
+		shutdowns = append(shutdowns, mp.Shutdown)
+	}
+
+	// Instruments must be recreated against the installed meter provider.
+	instruments = newInstruments()
+
+	return func(ctx context.Context) error {
+		var errs []error
+		for _, shutdown := range shutdowns {
+			errs = append(errs, shutdown(ctx))
+		}
+		return errors.Join(errs...)
+	}, nil
+}
+
+// Start begins a span named name as a child of any span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Metrics returns the shared metric instruments.
+func Metrics() *Instruments {
+	return instruments
+}
+
+// Failure increments the failure counter for stage.
+func Failure(ctx context.Context, stage string) {
+	instruments.Failures.Add(ctx, 1, metric.WithAttributes(attribute.String("stage", stage)))
+}
+
+// newInstruments creates the metric instruments from the global meter
+// provider. Creation errors only occur for invalid names, so they fall back
+// to the no-op instruments the SDK returns alongside the error.
+func newInstruments() *Instruments {
+	meter := otel.Meter(instrumentationName)
+	tokens, _ := meter.Int64Counter("arc_git.ai.tokens",
+		metric.WithDescription("Estimated tokens sent to and received from AI providers"),
+		metric.WithUnit("{token}"))
+	failures, _ := meter.Int64Counter("arc_git.failures",
+		metric.WithDescription("Failed operations by stage"),
+		metric.WithUnit("{failure}"))
+	requests, _ := meter.Int64Counter("arc_git.ai.requests",
+		metric.WithDescription("AI requests issued"),
+		metric.WithUnit("{request}"))
+	return &Instruments{Tokens: tokens, Failures: failures, Requests: requests}
+}
+
+// enabled reports whether any OTLP endpoint is configured.
+func enabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	return endpointFor("TRACES") || endpointFor("METRICS")
+}
+
+// endpointFor reports whether an endpoint is configured for the signal
+// ("TRACES" or "METRICS"), either directly or through the shared variable.
+func endpointFor(signal string) bool {
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_"+signal+"_ENDPOINT") != ""
+}
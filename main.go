@@ -4,22 +4,54 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/yourorg/arc-git/internal/cmd"
+	"github.com/yourorg/arc-git/internal/telemetry"
 	"github.com/yourorg/arc-sdk/ai"
 )
 
+// version is set at build time with -ldflags "-X main.version=...".
+var version = "dev"
+
 func main() {
+	os.Exit(run())
+}
+
+func run() int {
 	aiCfg, err := ai.LoadConfig()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "arc-git: failed to load AI config: %v\n", err)
-		os.Exit(1)
+		return 1
 	}
 
-	root := cmd.NewRootCmd(aiCfg)
-	if err := root.Execute(); err != nil {
-		os.Exit(1)
+	shutdown, err := telemetry.Setup(context.Background(), version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "arc-git: failed to set up telemetry: %v\n", err)
+		return 1
 	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "arc-git: failed to flush telemetry: %v\n", err)
+		}
+	}()
+
+	// The first SIGINT/SIGTERM cancels the run so commands can flush partial
+	// results; a second one falls back to the default and kills the process.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	root := cmd.NewRootCmd(aiCfg)
+	return cmd.Execute(ctx, root)
 }